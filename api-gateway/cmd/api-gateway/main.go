@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v4"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"todo-project/api-gateway/internal/config"
 	"todo-project/api-gateway/internal/handlers"
+	"todo-project/api-gateway/internal/jwks"
+	"todo-project/api-gateway/internal/middleware"
+	"todo-project/api-gateway/internal/revocation"
 	todopb "todo-project/api-gateway/proto/todo"
 	userpb "todo-project/api-gateway/proto/user"
 )
@@ -16,8 +23,14 @@ import (
 func main() {
 	// 加载配置
 	cfg := config.LoadConfig()
-	if string(cfg.JWTSecretKey) == "" {
-		log.Fatal("JWT_SECRET_KEY 环境变量未设置!")
+	switch cfg.JWTSigningAlg {
+	case "HS256":
+		if string(cfg.JWTSecretKey) == "" {
+			log.Fatal("JWT_SECRET_KEY 环境变量未设置!")
+		}
+	case "RS256", "EdDSA":
+	default:
+		log.Fatalf("不支持的 JWT_SIGNING_ALG: %s", cfg.JWTSigningAlg)
 	}
 
 	// 建立gRPC连接
@@ -40,10 +53,16 @@ func main() {
 	log.Printf("已连接到Todo Service at %s", cfg.TodoServiceAddr)
 
 	// 设置Gin HTTP服务器
-	router := gin.Default()
+	// 不使用 gin.Default()：默认的访问日志会记录 SSE/WebSocket 请求 URL 中的 access_token
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
+	// 客户端 IP 会传给 user-service 用于登录限制，只信任明确配置的反向代理
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("无效的 TRUSTED_PROXIES: %v", err)
+	}
 
 	// 设置路由
-	handlers.SetupRouter(router, userClient, todoClient, cfg.JWTSecretKey)
+	handlers.SetupRouter(router, userClient, todoClient, initKeyfunc(cfg, userClient), initRevocationChecker(cfg), cfg.WSAllowedOrigins)
 
 	// 启动HTTP服务器
 	log.Printf("API Gateway listening on port %s", cfg.Port)
@@ -51,3 +70,33 @@ func main() {
 		log.Fatalf("Failed to run API Gateway: %v", err)
 	}
 }
+
+// initKeyfunc 按 JWT_SIGNING_ALG 选择校验访问令牌的方式：HS256 使用共享密钥，其他算法使用 user-service 发布的公钥
+func initKeyfunc(cfg *config.Config, userClient userpb.UserServiceClient) jwt.Keyfunc {
+	if cfg.JWTSigningAlg == "HS256" {
+		log.Printf("使用 HS256 共享密钥校验访问令牌")
+		return middleware.HMACKeyfunc(cfg.JWTSecretKey)
+	}
+	keySet := jwks.New(userClient, cfg.JWKSRefreshInterval)
+	go keySet.Run(context.Background())
+	log.Printf("使用 user-service 发布的公钥 (%s) 校验访问令牌，每 %s 刷新一次", cfg.JWTSigningAlg, cfg.JWKSRefreshInterval)
+	return keySet.Keyfunc
+}
+
+// initRevocationChecker 连接保存令牌撤销记录的 Redis，未配置时返回 nil，不检查令牌是否已被撤销。
+// 启动时无法连接 Redis 仍然启用检查，Redis 恢复前所有令牌放行 (此时还没有本地保留的撤销记录)
+func initRevocationChecker(cfg *config.Config) *revocation.Checker {
+	if cfg.RedisAddr == "" {
+		log.Printf("警告: 未配置 REDIS_ADDR，不检查令牌是否已被撤销，登出和修改密码后旧的访问令牌在过期前仍然有效")
+		return nil
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPass, DB: cfg.RedisDB})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Printf("警告: 连接 Redis (%s) 失败: %v。Redis 恢复前不会拒绝已撤销的令牌", cfg.RedisAddr, err)
+	} else {
+		log.Printf("已连接到 Redis (%s, DB=%d)，检查令牌撤销状态，本地缓存 %s", cfg.RedisAddr, cfg.RedisDB, cfg.RevocationCacheTTL)
+	}
+	return revocation.NewChecker(rdb, cfg.RevocationCacheTTL)
+}
@@ -1,22 +1,52 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config 包含应用程序配置
 type Config struct {
-	Port            string
-	JWTSecretKey    []byte
-	UserServiceAddr string
-	TodoServiceAddr string
+	Port string
+	// 访问令牌的签名算法，与 user-service 一致。HS256 使用共享的 JWTSecretKey 校验，
+	// RS256 和 EdDSA 从 user-service 获取公钥 (JWKS) 校验，不需要 JWTSecretKey
+	JWTSigningAlg string
+	JWTSecretKey  []byte
+	// JWKSRefreshInterval 定期刷新公钥的间隔，遇到未知 kid 时会立即刷新
+	JWKSRefreshInterval time.Duration
+	UserServiceAddr     string
+	TodoServiceAddr     string
+	// Redis 中保存了 user-service 写入的令牌撤销记录，为空时不检查令牌是否已被撤销
+	RedisAddr string
+	RedisPass string
+	RedisDB   int
+	// RevocationCacheTTL 令牌撤销检查结果在本地缓存的时间，撤销最多延迟这么久生效
+	RevocationCacheTTL time.Duration
+	// TrustedProxies 网关前面的反向代理 (IP 或 CIDR)，只有来自它们的 X-Forwarded-For 才被用于确定客户端 IP。
+	// 为空时直接使用连接的对端地址，防止客户端伪造 IP 绕过 user-service 的登录限制
+	TrustedProxies []string
+	// WSAllowedOrigins 允许建立 WebSocket 连接的页面来源，例如 https://todo.example.com。
+	// 为空时只允许与网关同一主机的页面
+	WSAllowedOrigins []string
 }
 
 // LoadConfig 从环境变量加载配置
 func LoadConfig() *Config {
 	return &Config{
-		Port:            getEnvOrDefault("PORT", "8080"),
-		JWTSecretKey:    []byte(getEnvOrDefault("JWT_SECRET_KEY", "")),
-		UserServiceAddr: getEnvOrDefault("USER_SERVICE_ADDR", "user-service:50051"),
-		TodoServiceAddr: getEnvOrDefault("TODO_SERVICE_ADDR", "todo-service:50052"),
+		Port:                getEnvOrDefault("PORT", "8080"),
+		JWTSigningAlg:       getEnvOrDefault("JWT_SIGNING_ALG", "HS256"),
+		JWTSecretKey:        []byte(getEnvOrDefault("JWT_SECRET_KEY", "")),
+		JWKSRefreshInterval: getEnvOrDefaultDuration("JWKS_REFRESH_INTERVAL", 5*time.Minute),
+		UserServiceAddr:     getEnvOrDefault("USER_SERVICE_ADDR", "user-service:50051"),
+		TodoServiceAddr:     getEnvOrDefault("TODO_SERVICE_ADDR", "todo-service:50052"),
+		RedisAddr:           getEnvOrDefault("REDIS_ADDR", ""),
+		RedisPass:           getEnvOrDefault("REDIS_PASSWORD", ""),
+		RedisDB:             getEnvOrDefaultInt("REDIS_DB", 0),
+		RevocationCacheTTL:  getEnvOrDefaultDuration("REVOCATION_CACHE_TTL", 10*time.Second),
+		TrustedProxies:      getEnvList("TRUSTED_PROXIES"),
+		WSAllowedOrigins:    getEnvList("WS_ALLOWED_ORIGINS"),
 	}
 }
 
@@ -28,3 +58,32 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvList 获取逗号分隔的环境变量，忽略空项
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvOrDefaultInt 获取整数类型的环境变量，不存在或无法解析时返回默认值
+func getEnvOrDefaultInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvOrDefaultDuration 获取时长类型的环境变量 (如 10s、1m)，不存在或无法解析时返回默认值
+func getEnvOrDefaultDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// ArchiveTodoHandler 处理归档待办事项请求，子任务会一并归档
+func ArchiveTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return archiveHandler(todoClient.ArchiveTodo, "归档待办事项失败")
+}
+
+// UnarchiveTodoHandler 处理恢复已归档待办事项请求，子任务和已归档的上级任务会一并恢复
+func UnarchiveTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return archiveHandler(todoClient.UnarchiveTodo, "恢复待办事项失败")
+}
+
+type archiveFunc func(ctx context.Context, in *todopb.ArchiveTodoRequest, opts ...grpc.CallOption) (*todopb.ArchiveTodoResponse, error)
+
+func archiveHandler(call archiveFunc, defaultMessage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的待办事项ID"})
+			return
+		}
+
+		grpcReq := &todopb.ArchiveTodoRequest{
+			UserId: userID.(uint32),
+			TodoId: uint32(todoID),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := call(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, defaultMessage)
+			return
+		}
+
+		responseList := make([]models.TodoResponse, len(res.Todos))
+		for i, protoTodo := range res.Todos {
+			responseList[i] = models.ConvertProtoTodoToResponse(protoTodo)
+		}
+		c.JSON(http.StatusOK, responseList)
+	}
+}
+
+// GetArchivePolicyHandler 处理获取自动归档策略请求
+func GetArchivePolicyHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetArchivePolicy(ctx, &todopb.GetArchivePolicyRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "获取自动归档策略失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoArchivePolicyToResponse(res))
+	}
+}
+
+// UpdateArchivePolicyHandler 处理设置自动归档策略请求
+func UpdateArchivePolicyHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Enabled          *bool  `json:"enabled" binding:"required"`
+			ArchiveAfterDays uint32 `json:"archive_after_days"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.UpdateArchivePolicyRequest{
+			UserId:           userID.(uint32),
+			Enabled:          *reqBody.Enabled,
+			ArchiveAfterDays: reqBody.ArchiveAfterDays,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.UpdateArchivePolicy(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "更新自动归档策略失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoArchivePolicyToResponse(res))
+	}
+}
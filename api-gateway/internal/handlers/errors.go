@@ -3,8 +3,10 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -27,10 +29,39 @@ func HandleGrpcError(c *gin.Context, err error, defaultMessage string) {
 			httpCode = http.StatusNotFound
 		case codes.AlreadyExists:
 			httpCode = http.StatusConflict
+		case codes.FailedPrecondition:
+			httpCode = http.StatusForbidden
+		case codes.ResourceExhausted:
+			httpCode = http.StatusTooManyRequests
+		case codes.Unavailable:
+			httpCode = http.StatusServiceUnavailable
 		case codes.DeadlineExceeded:
 			httpCode = http.StatusGatewayTimeout
 		}
-		c.JSON(httpCode, gin.H{"error": st.Message()})
+		body := gin.H{"error": st.Message()}
+		// 查询表达式等输入错误会在 ErrorInfo 中附带出错位置，原样透传给客户端
+		for _, detail := range st.Details() {
+			// 密码策略等按字段校验的错误逐字段列出原因，例如 {"password": ["...", "..."]}
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				fieldErrors := map[string][]string{}
+				for _, v := range badRequest.GetFieldViolations() {
+					fieldErrors[v.GetField()] = append(fieldErrors[v.GetField()], v.GetDescription())
+				}
+				body["field_errors"] = fieldErrors
+			}
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				body["reason"] = info.Reason
+				if position, err := strconv.Atoi(info.Metadata["position"]); err == nil {
+					body["position"] = position
+				}
+				// 登录限制附带需要等待的秒数
+				if retryAfter, err := strconv.Atoi(info.Metadata["retry_after"]); err == nil {
+					body["retry_after"] = retryAfter
+					c.Header("Retry-After", strconv.Itoa(retryAfter))
+				}
+			}
+		}
+		c.JSON(httpCode, body)
 	} else {
 		// 如果不是标准的gRPC错误
 		c.JSON(http.StatusInternalServerError, gin.H{"error": defaultMessage, "details": err.Error()})
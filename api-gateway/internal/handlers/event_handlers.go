@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	"todo-project/api-gateway/internal/realtime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// 心跳间隔，需小于反向代理的空闲超时 (如 nginx 默认 60s)
+	eventsHeartbeatInterval = 25 * time.Second
+	// 浏览器 EventSource 断线后的重连间隔
+	sseRetryInterval = 3 * time.Second
+	// WebSocket 客户端需在此时间内响应 ping
+	wsPongWait  = 60 * time.Second
+	wsWriteWait = 10 * time.Second
+)
+
+// newUpgrader 创建只接受 allowedOrigins 中来源的 WebSocket 升级器，allowedOrigins 为空时只接受与网关同一主机的页面
+func newUpgrader(allowedOrigins []string) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     originChecker(allowedOrigins),
+	}
+}
+
+// originChecker 返回检查 WebSocket 握手 Origin 请求头的函数。
+// 没有 Origin 的请求来自非浏览器客户端，与 gorilla/websocket 的默认行为一样放行
+func originChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if len(allowed) > 0 {
+			return allowed[strings.ToLower(origin)]
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// TodoEventsHandler 通过 Server-Sent Events 推送当前用户的 Todo 变更。
+// 浏览器断线重连时会自动带上 Last-Event-ID 请求头，也可以通过 last_event_id 查询参数指定
+func TodoEventsHandler(hub *realtime.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+
+		ctx := c.Request.Context()
+		stream, err := hub.Open(ctx, userID.(uint32), lastEventID)
+		if err != nil {
+			handleOpenEventsError(c, err)
+			return
+		}
+		defer stream.Close()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // 禁止 nginx 缓冲响应
+		c.Status(http.StatusOK)
+		fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetryInterval.Milliseconds())
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(eventsHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			event, err := stream.Next(ctx, ticker.C)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("用户 %d 的 SSE 变更流中断: %v", userID, err)
+				}
+				return
+			}
+			if event == nil {
+				_, err = io.WriteString(c.Writer, ": heartbeat\n\n")
+			} else {
+				err = writeSSEEvent(c.Writer, models.ConvertProtoTodoEventToResponse(event))
+			}
+			if err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// TodoEventsWebSocketHandler 通过 WebSocket 推送当前用户的 Todo 变更，每条消息为一个 JSON 事件。
+// 浏览器无法为 WebSocket 设置请求头，重连时通过 last_event_id 查询参数传入最后收到的事件 ID。
+// 浏览器会带上已保存在页面中的令牌发起跨站连接，只接受 allowedOrigins 中的来源，为空时只接受同一主机
+func TodoEventsWebSocketHandler(hub *realtime.Hub, allowedOrigins []string) gin.HandlerFunc {
+	upgrader := newUpgrader(allowedOrigins)
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		stream, err := hub.Open(ctx, userID.(uint32), c.Query("last_event_id"))
+		if err != nil {
+			handleOpenEventsError(c, err)
+			return
+		}
+		defer stream.Close()
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade 已向客户端返回错误
+			log.Printf("用户 %d 的 WebSocket 升级失败: %v", userID, err)
+			return
+		}
+		defer conn.Close()
+
+		// 客户端不发送业务消息，读循环只用于处理 pong 和关闭帧；客户端断开或心跳超时时结束连接
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(eventsHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			event, err := stream.Next(ctx, ticker.C)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("用户 %d 的 WebSocket 变更流中断: %v", userID, err)
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "变更流中断，请稍后重连"),
+						time.Now().Add(wsWriteWait))
+				}
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if event == nil {
+				err = conn.WriteMessage(websocket.PingMessage, nil)
+			} else {
+				err = conn.WriteJSON(models.ConvertProtoTodoEventToResponse(event))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeSSEEvent(w io.Writer, event models.TodoEventResponse) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+	return err
+}
+
+func handleOpenEventsError(c *gin.Context, err error) {
+	if errors.Is(err, realtime.ErrInvalidEventID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 Last-Event-ID"})
+		return
+	}
+	HandleGrpcError(c, err, "订阅变更失败")
+}
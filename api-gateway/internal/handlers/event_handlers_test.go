@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginChecker(t *testing.T) {
+	allowList := []string{"https://todo.example.com", "http://localhost:3000/"}
+	tests := []struct {
+		name    string
+		allowed []string
+		host    string
+		origin  string
+		want    bool
+	}{
+		{name: "没有 Origin 的非浏览器客户端", host: "api.example.com", want: true},
+		{name: "同一主机", host: "api.example.com", origin: "https://api.example.com", want: true},
+		{name: "同一主机，大小写不同", host: "API.example.com", origin: "https://api.EXAMPLE.com", want: true},
+		{name: "其他站点", host: "api.example.com", origin: "https://evil.example", want: false},
+		{name: "端口不同", host: "api.example.com", origin: "https://api.example.com:8443", want: false},
+		{name: "无法解析的 Origin", host: "api.example.com", origin: "://api.example.com", want: false},
+		{name: "在允许列表中", allowed: allowList, host: "api.example.com", origin: "https://todo.example.com", want: true},
+		{name: "允许列表中的条目带斜杠", allowed: allowList, host: "api.example.com", origin: "http://localhost:3000", want: true},
+		{name: "协议不同", allowed: allowList, host: "api.example.com", origin: "http://todo.example.com", want: false},
+		{name: "配置允许列表后不再接受同一主机", allowed: allowList, host: "api.example.com", origin: "https://api.example.com", want: false},
+		{name: "配置允许列表后没有 Origin", allowed: allowList, host: "api.example.com", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/todos/events/ws", nil)
+			r.Host = tt.host
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := originChecker(tt.allowed)(r); got != tt.want {
+				t.Errorf("Origin %q, Host %q: 允许 = %t, 期望 %t", tt.origin, tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	userpb "todo-project/api-gateway/proto/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// profileJSON 将用户资料转换为 JSON 响应
+func profileJSON(u *userpb.User) gin.H {
+	return gin.H{
+		"id":             u.GetId(),
+		"username":       u.GetUsername(),
+		"email":          u.GetEmail(),
+		"email_verified": u.GetEmailVerified(),
+		"display_name":   u.GetDisplayName(),
+		"avatar_url":     u.GetAvatarUrl(),
+		"time_zone":      u.GetTimeZone(),
+		"locale":         u.GetLocale(),
+	}
+}
+
+// GetProfileHandler 返回当前用户的资料
+func GetProfileHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.GetProfile(ctx, &userpb.GetProfileRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "获取用户资料失败")
+			return
+		}
+		c.JSON(http.StatusOK, profileJSON(res.GetUser()))
+	}
+}
+
+// UpdateProfileHandler 修改当前用户的资料。请求体中省略的字段保持不变，空字符串表示清除
+func UpdateProfileHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			DisplayName *string `json:"display_name"`
+			AvatarURL   *string `json:"avatar_url"`
+			TimeZone    *string `json:"time_zone"`
+			Locale      *string `json:"locale"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.UpdateProfile(ctx, &userpb.UpdateProfileRequest{
+			UserId:      userID.(uint32),
+			DisplayName: reqBody.DisplayName,
+			AvatarUrl:   reqBody.AvatarURL,
+			TimeZone:    reqBody.TimeZone,
+			Locale:      reqBody.Locale,
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "更新用户资料失败")
+			return
+		}
+		c.JSON(http.StatusOK, profileJSON(res.GetUser()))
+	}
+}
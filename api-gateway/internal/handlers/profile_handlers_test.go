@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	userpb "todo-project/api-gateway/proto/user"
+)
+
+// fakeProfileClient 只实现 GetProfile 和 UpdateProfile，记录收到的请求并返回 err 或 user
+type fakeProfileClient struct {
+	userpb.UserServiceClient
+
+	user          *userpb.User
+	err           error
+	getRequest    *userpb.GetProfileRequest
+	updateRequest *userpb.UpdateProfileRequest
+}
+
+func (f *fakeProfileClient) GetProfile(ctx context.Context, in *userpb.GetProfileRequest, opts ...grpc.CallOption) (*userpb.GetProfileResponse, error) {
+	f.getRequest = in
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &userpb.GetProfileResponse{User: f.user}, nil
+}
+
+func (f *fakeProfileClient) UpdateProfile(ctx context.Context, in *userpb.UpdateProfileRequest, opts ...grpc.CallOption) (*userpb.UpdateProfileResponse, error) {
+	f.updateRequest = in
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &userpb.UpdateProfileResponse{User: f.user}, nil
+}
+
+var testProfile = &userpb.User{
+	Id:            7,
+	Username:      "alice",
+	Email:         "alice@example.com",
+	EmailVerified: true,
+	DisplayName:   "Alice",
+	AvatarUrl:     "https://example.com/alice.png",
+	TimeZone:      "Asia/Shanghai",
+	Locale:        "zh-CN",
+}
+
+// serveProfile 以用户 7 的身份请求 /api/me，返回状态码和解析后的响应体
+func serveProfile(t *testing.T, client userpb.UserServiceClient, method, body string) (int, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// 代替 AuthMiddleware 设置当前用户
+	router.Use(func(c *gin.Context) { c.Set("user_id", uint32(7)) })
+	router.GET("/api/me", GetProfileHandler(client))
+	router.PATCH("/api/me", UpdateProfileHandler(client))
+
+	req := httptest.NewRequest(method, "/api/me", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("响应不是 JSON: %s", w.Body.String())
+	}
+	return w.Code, res
+}
+
+func TestGetProfileHandler(t *testing.T) {
+	client := &fakeProfileClient{user: testProfile}
+	code, res := serveProfile(t, client, http.MethodGet, "")
+	if code != http.StatusOK {
+		t.Fatalf("状态码 = %d, 期望 200: %v", code, res)
+	}
+	if client.getRequest.GetUserId() != 7 {
+		t.Errorf("GetProfile 的 user_id = %d, 期望 7", client.getRequest.GetUserId())
+	}
+	want := map[string]interface{}{
+		"id":             float64(7),
+		"username":       "alice",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"display_name":   "Alice",
+		"avatar_url":     "https://example.com/alice.png",
+		"time_zone":      "Asia/Shanghai",
+		"locale":         "zh-CN",
+	}
+	for key, value := range want {
+		if res[key] != value {
+			t.Errorf("%s = %v, 期望 %v", key, res[key], value)
+		}
+	}
+	if _, ok := res["password"]; ok {
+		t.Error("响应包含 password")
+	}
+
+	client.err = status.Error(codes.NotFound, "用户不存在")
+	if code, _ := serveProfile(t, client, http.MethodGet, ""); code != http.StatusNotFound {
+		t.Errorf("用户不存在时状态码 = %d, 期望 404", code)
+	}
+}
+
+func TestUpdateProfileHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		// want 为转发给 user-service 的字段，nil 表示请求中省略
+		want [4]*string
+	}{
+		{name: "省略的字段保持不变", body: `{"display_name":"Alice"}`, want: [4]*string{stringPtr("Alice"), nil, nil, nil}},
+		{name: "空字符串表示清除", body: `{"avatar_url":"","time_zone":""}`, want: [4]*string{nil, stringPtr(""), stringPtr(""), nil}},
+		{name: "null 与省略相同", body: `{"locale":null,"time_zone":"UTC"}`, want: [4]*string{nil, nil, stringPtr("UTC"), nil}},
+		{
+			name: "全部字段",
+			body: `{"display_name":"A","avatar_url":"https://example.com/a.png","time_zone":"UTC","locale":"en-US"}`,
+			want: [4]*string{stringPtr("A"), stringPtr("https://example.com/a.png"), stringPtr("UTC"), stringPtr("en-US")},
+		},
+		{name: "空对象", body: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeProfileClient{user: testProfile}
+			code, res := serveProfile(t, client, http.MethodPatch, tt.body)
+			if code != http.StatusOK {
+				t.Fatalf("状态码 = %d, 期望 200: %v", code, res)
+			}
+			req := client.updateRequest
+			if req.GetUserId() != 7 {
+				t.Errorf("UpdateProfile 的 user_id = %d, 期望 7", req.GetUserId())
+			}
+			got := [4]*string{req.DisplayName, req.AvatarUrl, req.TimeZone, req.Locale}
+			for i, field := range []string{"display_name", "avatar_url", "time_zone", "locale"} {
+				if (got[i] == nil) != (tt.want[i] == nil) || (got[i] != nil && *got[i] != *tt.want[i]) {
+					t.Errorf("%s = %v, 期望 %v", field, deref(got[i]), deref(tt.want[i]))
+				}
+			}
+			if res["display_name"] != "Alice" {
+				t.Errorf("响应没有返回 user-service 的资料: %v", res)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileHandlerErrors(t *testing.T) {
+	invalid, err := status.New(codes.InvalidArgument, "用户资料无效").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "time_zone", Description: "未知的时区"},
+			{Field: "locale", Description: "无效的语言标签"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("请求体不是 JSON", func(t *testing.T) {
+		client := &fakeProfileClient{user: testProfile}
+		if code, _ := serveProfile(t, client, http.MethodPatch, `display_name=Alice`); code != http.StatusBadRequest {
+			t.Errorf("状态码 = %d, 期望 400", code)
+		}
+		if client.updateRequest != nil {
+			t.Error("无效的请求被转发给了 user-service")
+		}
+	})
+
+	t.Run("字段类型错误", func(t *testing.T) {
+		client := &fakeProfileClient{user: testProfile}
+		if code, _ := serveProfile(t, client, http.MethodPatch, `{"display_name":42}`); code != http.StatusBadRequest {
+			t.Errorf("状态码 = %d, 期望 400", code)
+		}
+	})
+
+	t.Run("逐字段返回校验错误", func(t *testing.T) {
+		client := &fakeProfileClient{err: invalid.Err()}
+		code, res := serveProfile(t, client, http.MethodPatch, `{"time_zone":"Nowhere","locale":"??"}`)
+		if code != http.StatusBadRequest {
+			t.Fatalf("状态码 = %d, 期望 400", code)
+		}
+		fieldErrors, _ := res["field_errors"].(map[string]interface{})
+		for _, field := range []string{"time_zone", "locale"} {
+			if messages, _ := fieldErrors[field].([]interface{}); len(messages) != 1 {
+				t.Errorf("field_errors[%s] = %v, 期望 1 条原因", field, fieldErrors[field])
+			}
+		}
+	})
+
+	t.Run("用户不存在", func(t *testing.T) {
+		client := &fakeProfileClient{err: status.Error(codes.NotFound, "用户不存在")}
+		if code, _ := serveProfile(t, client, http.MethodPatch, `{}`); code != http.StatusNotFound {
+			t.Errorf("状态码 = %d, 期望 404", code)
+		}
+	})
+}
+
+func stringPtr(s string) *string { return &s }
+
+func deref(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
@@ -2,37 +2,123 @@ package handlers
 
 import (
 	"todo-project/api-gateway/internal/middleware"
+	"todo-project/api-gateway/internal/realtime"
+	"todo-project/api-gateway/internal/revocation"
 	todopb "todo-project/api-gateway/proto/todo"
 	userpb "todo-project/api-gateway/proto/user"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 )
 
-// SetupRouter 配置API路由
-func SetupRouter(router *gin.Engine, userClient userpb.UserServiceClient, todoClient todopb.TodoServiceClient, jwtKey []byte) {
+// SetupRouter 配置API路由，keyfunc 返回校验访问令牌签名的密钥。revoked 为 nil 时不检查令牌是否已被撤销。
+// wsAllowedOrigins 为允许建立 WebSocket 连接的页面来源，为空时只允许与网关同一主机
+func SetupRouter(router *gin.Engine, userClient userpb.UserServiceClient, todoClient todopb.TodoServiceClient, keyfunc jwt.Keyfunc, revoked *revocation.Checker, wsAllowedOrigins []string) {
+	// 校验访问令牌的公钥集合 (JWKS)，供其他服务使用
+	router.GET("/.well-known/jwks.json", JWKSHandler(userClient))
+
 	// API路由组
 	api := router.Group("/api")
+	hub := realtime.NewHub(todoClient)
 	{
 		// 公开路由
 		api.POST("/register", RegisterHandler(userClient))
 		api.POST("/login", LoginHandler(userClient))
+		// 刷新令牌本身即凭证，访问令牌过期后也能调用
+		api.POST("/token/refresh", RefreshTokenHandler(userClient))
+		api.POST("/logout", LogoutHandler(userClient))
+		// 邮箱验证，未验证的用户可能无法登录，因此不需要认证
+		api.POST("/verify-email", VerifyEmailHandler(userClient))
+		api.POST("/verify-email/resend", ResendVerificationEmailHandler(userClient))
+		// 找回密码
+		api.POST("/password/forgot", RequestPasswordResetHandler(userClient))
+		api.POST("/password/reset", ResetPasswordHandler(userClient))
+		// 两步验证登录的第二步，凭 /api/login 返回的挑战令牌调用
+		api.POST("/2fa/verify", VerifySecondFactorHandler(userClient))
+
+		// 实时变更推送，EventSource 和 WebSocket 无法设置请求头，允许通过 access_token 查询参数认证
+		events := api.Group("/todos/events")
+		events.Use(middleware.StreamAuthMiddleware(keyfunc, revoked))
+		{
+			events.GET("", TodoEventsHandler(hub))
+			events.GET("/ws", TodoEventsWebSocketHandler(hub, wsAllowedOrigins))
+		}
 
 		// 需要认证的路由组
 		auth := api.Group("")
-		auth.Use(middleware.AuthMiddleware(jwtKey))
+		auth.Use(middleware.AuthMiddleware(keyfunc, revoked))
 		{
 			// 用户相关认证路由
 			auth.POST("/change-password", ChangePasswordHandler(userClient))
+			auth.GET("/me", GetProfileHandler(userClient))
+			auth.PATCH("/me", UpdateProfileHandler(userClient))
+
+			// 两步验证设置
+			twoFactor := auth.Group("/2fa")
+			{
+				twoFactor.GET("", GetTwoFactorStatusHandler(userClient))
+				twoFactor.POST("/enroll", BeginTOTPEnrollmentHandler(userClient))
+				twoFactor.POST("/confirm", ConfirmTOTPEnrollmentHandler(userClient))
+				twoFactor.POST("/disable", DisableTwoFactorHandler(userClient))
+				twoFactor.POST("/recovery-codes", RegenerateRecoveryCodesHandler(userClient))
+			}
 
 			// Todo相关认证路由
 			todos := auth.Group("/todos")
 			{
 				todos.POST("", CreateTodoHandler(todoClient))
+				todos.POST("/quick", QuickAddTodoHandler(todoClient))
 				todos.GET("", GetTodosHandler(todoClient))
 				todos.GET("/:id", GetTodoByIDHandler(todoClient))
 				todos.PUT("/:id", UpdateTodoHandler(todoClient))
 				todos.DELETE("/:id", DeleteTodoHandler(todoClient))
 				todos.PATCH("/batch", BatchUpdateTodosHandler(todoClient))
+				todos.POST("/:id/archive", ArchiveTodoHandler(todoClient))
+				todos.POST("/:id/unarchive", UnarchiveTodoHandler(todoClient))
+				todos.POST("/:id/timer/start", StartTimerHandler(todoClient))
+			}
+
+			// 计时器与时间记录
+			auth.GET("/timer", GetRunningTimerHandler(todoClient))
+			auth.POST("/timer/stop", StopTimerHandler(todoClient))
+			timeEntries := auth.Group("/time-entries")
+			{
+				timeEntries.POST("", CreateTimeEntryHandler(todoClient))
+				timeEntries.GET("", ListTimeEntriesHandler(todoClient))
+				timeEntries.PUT("/:id", UpdateTimeEntryHandler(todoClient))
+				timeEntries.DELETE("/:id", DeleteTimeEntryHandler(todoClient))
+			}
+			auth.GET("/reports/time", GetTimeReportHandler(todoClient))
+
+			// 完成情况统计
+			auth.GET("/stats", GetStatsHandler(todoClient))
+
+			// 离线同步
+			auth.POST("/sync", SyncTodosHandler(todoClient))
+
+			// 自动归档策略
+			auth.GET("/archive-policy", GetArchivePolicyHandler(todoClient))
+			auth.PUT("/archive-policy", UpdateArchivePolicyHandler(todoClient))
+
+			// Todo模板相关认证路由
+			templates := auth.Group("/templates")
+			{
+				templates.POST("", CreateTemplateFromTodoHandler(todoClient))
+				templates.GET("", ListTemplatesHandler(todoClient))
+				templates.GET("/:id", GetTemplateHandler(todoClient))
+				templates.DELETE("/:id", DeleteTemplateHandler(todoClient))
+				templates.POST("/:id/instantiate", InstantiateTemplateHandler(todoClient))
+			}
+
+			// 保存搜索 (智能列表) 相关认证路由
+			searches := auth.Group("/searches")
+			{
+				searches.POST("", CreateSavedSearchHandler(todoClient))
+				searches.GET("", ListSavedSearchesHandler(todoClient))
+				searches.GET("/:id", GetSavedSearchHandler(todoClient))
+				searches.PUT("/:id", UpdateSavedSearchHandler(todoClient))
+				searches.DELETE("/:id", DeleteSavedSearchHandler(todoClient))
+				searches.GET("/:id/todos", RunSavedSearchHandler(todoClient))
 			}
 		}
 	}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// savedSearchRequest 创建和更新保存搜索的请求体
+type savedSearchRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Query string `json:"query" binding:"required"`
+}
+
+// CreateSavedSearchHandler 处理创建保存搜索请求，查询表达式有误时响应中包含出错位置 (position)
+func CreateSavedSearchHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody savedSearchRequest
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.CreateSavedSearchRequest{
+			UserId: userID.(uint32),
+			Name:   reqBody.Name,
+			Query:  reqBody.Query,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.CreateSavedSearch(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "创建保存搜索失败")
+			return
+		}
+		c.JSON(http.StatusCreated, models.ConvertProtoSavedSearchToResponse(res))
+	}
+}
+
+// ListSavedSearchesHandler 处理获取所有保存搜索请求
+func ListSavedSearchesHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.ListSavedSearches(ctx, &todopb.ListSavedSearchesRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "获取保存搜索失败")
+			return
+		}
+
+		responseList := make([]models.SavedSearchResponse, len(res.SavedSearches))
+		for i, protoSearch := range res.SavedSearches {
+			responseList[i] = models.ConvertProtoSavedSearchToResponse(protoSearch)
+		}
+		c.JSON(http.StatusOK, responseList)
+	}
+}
+
+// GetSavedSearchHandler 处理获取单个保存搜索请求
+func GetSavedSearchHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的保存搜索ID"})
+			return
+		}
+
+		grpcReq := &todopb.GetSavedSearchRequest{
+			UserId:        userID.(uint32),
+			SavedSearchId: uint32(searchID),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetSavedSearch(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "获取保存搜索失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoSavedSearchToResponse(res))
+	}
+}
+
+// UpdateSavedSearchHandler 处理更新保存搜索请求
+func UpdateSavedSearchHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的保存搜索ID"})
+			return
+		}
+
+		var reqBody savedSearchRequest
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		grpcReq := &todopb.UpdateSavedSearchRequest{
+			UserId:        userID.(uint32),
+			SavedSearchId: uint32(searchID),
+			Name:          reqBody.Name,
+			Query:         reqBody.Query,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.UpdateSavedSearch(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "更新保存搜索失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoSavedSearchToResponse(res))
+	}
+}
+
+// DeleteSavedSearchHandler 处理删除保存搜索请求
+func DeleteSavedSearchHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的保存搜索ID"})
+			return
+		}
+
+		grpcReq := &todopb.DeleteSavedSearchRequest{
+			UserId:        userID.(uint32),
+			SavedSearchId: uint32(searchID),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		_, err = todoClient.DeleteSavedSearch(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "删除保存搜索失败")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// RunSavedSearchHandler 处理执行保存搜索请求，可通过 time_zone 查询参数指定 today 等相对日期所用的时区，
+// include_archived=true 时同时搜索已归档的待办事项
+func RunSavedSearchHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的保存搜索ID"})
+			return
+		}
+		includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
+
+		grpcReq := &todopb.RunSavedSearchRequest{
+			UserId:          userID.(uint32),
+			SavedSearchId:   uint32(searchID),
+			TimeZone:        c.Query("time_zone"),
+			IncludeArchived: includeArchived,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		res, err := todoClient.RunSavedSearch(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "执行保存搜索失败")
+			return
+		}
+
+		todos := make([]models.TodoResponse, len(res.Todos))
+		for i, protoTodo := range res.Todos {
+			todos[i] = models.ConvertProtoTodoToResponse(protoTodo)
+		}
+		c.JSON(http.StatusOK, models.SavedSearchResultResponse{
+			SavedSearch: models.ConvertProtoSavedSearchToResponse(res.SavedSearch),
+			Todos:       todos,
+		})
+	}
+}
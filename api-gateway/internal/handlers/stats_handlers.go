@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatsHandler 处理完成情况统计请求，可通过 time_zone 指定日期边界所用的时区，
+// days/weeks 指定每日和每周完成数的范围
+func GetStatsHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.GetTodoStatsRequest{
+			UserId:   userID.(uint32),
+			TimeZone: c.Query("time_zone"),
+		}
+		if value := c.Query("days"); value != "" {
+			days, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 days 参数"})
+				return
+			}
+			grpcReq.Days = uint32(days)
+		}
+		if value := c.Query("weeks"); value != "" {
+			weeks, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 weeks 参数"})
+				return
+			}
+			grpcReq.Weeks = uint32(weeks)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetTodoStats(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "获取统计失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTodoStatsToResponse(res))
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncTodosHandler 处理离线同步请求：按顺序应用客户端上传的离线修改，
+// 返回 sync_token 之后服务端的全部变更、删除的 Todo 以及每条修改的处理结果
+func SyncTodosHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody models.SyncRequest
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.SyncTodosRequest{
+			UserId:    userID.(uint32),
+			SyncToken: reqBody.SyncToken,
+			Mutations: make([]*todopb.SyncMutation, len(reqBody.Mutations)),
+		}
+		for i, mutation := range reqBody.Mutations {
+			protoMutation, err := models.ConvertSyncMutationToProto(mutation)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("第 %d 条修改无效: %v", i+1, err)})
+				return
+			}
+			grpcReq.Mutations[i] = protoMutation
+		}
+
+		// 一次同步可能包含数百条修改，超时时间比普通请求长
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		res, err := todoClient.SyncTodos(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "同步失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoSyncResponseToResponse(res))
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateTemplateFromTodoHandler 处理以已有待办事项创建模板的请求
+func CreateTemplateFromTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			TodoID      uint32 `json:"todo_id" binding:"required"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.CreateTemplateFromTodoRequest{
+			UserId:      userID.(uint32),
+			TodoId:      reqBody.TodoID,
+			Name:        reqBody.Name,
+			Description: reqBody.Description,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		res, err := todoClient.CreateTemplateFromTodo(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "创建模板失败")
+			return
+		}
+		c.JSON(http.StatusCreated, models.ConvertProtoTemplateToResponse(res))
+	}
+}
+
+// ListTemplatesHandler 处理获取所有模板请求
+func ListTemplatesHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.ListTemplates(ctx, &todopb.ListTemplatesRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "获取模板失败")
+			return
+		}
+
+		responseList := make([]models.TemplateResponse, len(res.Templates))
+		for i, protoTemplate := range res.Templates {
+			responseList[i] = models.ConvertProtoTemplateToResponse(protoTemplate)
+		}
+		c.JSON(http.StatusOK, responseList)
+	}
+}
+
+// GetTemplateHandler 处理获取单个模板请求
+func GetTemplateHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的模板ID"})
+			return
+		}
+
+		grpcReq := &todopb.GetTemplateRequest{
+			UserId:     userID.(uint32),
+			TemplateId: uint32(templateID),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetTemplate(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "获取模板失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTemplateToResponse(res))
+	}
+}
+
+// DeleteTemplateHandler 处理删除模板请求
+func DeleteTemplateHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的模板ID"})
+			return
+		}
+
+		grpcReq := &todopb.DeleteTemplateRequest{
+			UserId:     userID.(uint32),
+			TemplateId: uint32(templateID),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		_, err = todoClient.DeleteTemplate(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "删除模板失败")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// InstantiateTemplateHandler 处理将模板实例化为待办事项的请求
+func InstantiateTemplateHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的模板ID"})
+			return
+		}
+
+		// 请求体可选，base_time 缺省时由服务端使用当前时间
+		var reqBody struct {
+			BaseTime *time.Time `json:"base_time"`
+		}
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&reqBody); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+				return
+			}
+		}
+
+		grpcReq := &todopb.InstantiateTemplateRequest{
+			UserId:     userID.(uint32),
+			TemplateId: uint32(templateID),
+		}
+		if reqBody.BaseTime != nil {
+			grpcReq.BaseTime = timestamppb.New(*reqBody.BaseTime)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		res, err := todoClient.InstantiateTemplate(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "实例化模板失败")
+			return
+		}
+
+		responseList := make([]models.TodoResponse, len(res.Todos))
+		for i, protoTodo := range res.Todos {
+			responseList[i] = models.ConvertProtoTodoToResponse(protoTodo)
+		}
+		c.JSON(http.StatusCreated, responseList)
+	}
+}
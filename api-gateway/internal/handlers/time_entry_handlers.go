@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-project/api-gateway/internal/models"
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timeEntryRequest 手动创建和修改时间记录的请求体
+type timeEntryRequest struct {
+	TodoId    uint32     `json:"todo_id"`
+	StartedAt *time.Time `json:"started_at" binding:"required"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      string     `json:"note"`
+}
+
+// StartTimerHandler 处理启动计时器请求，stop_running=true 时先停止正在运行的计时器
+func StartTimerHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的待办事项ID"})
+			return
+		}
+
+		var reqBody struct {
+			Note        string `json:"note"`
+			StopRunning bool   `json:"stop_running"`
+		}
+		// 请求体可以为空
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&reqBody); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+				return
+			}
+		}
+
+		grpcReq := &todopb.StartTimerRequest{
+			UserId:      userID.(uint32),
+			TodoId:      uint32(todoID),
+			Note:        reqBody.Note,
+			StopRunning: reqBody.StopRunning,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.StartTimer(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "启动计时器失败")
+			return
+		}
+		c.JSON(http.StatusCreated, models.ConvertProtoTimeEntryToResponse(res))
+	}
+}
+
+// StopTimerHandler 处理停止当前计时器请求
+func StopTimerHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.StopTimer(ctx, &todopb.StopTimerRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "停止计时器失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTimeEntryToResponse(res))
+	}
+}
+
+// GetRunningTimerHandler 处理获取当前计时器请求，没有正在运行的计时器时返回 404
+func GetRunningTimerHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetRunningTimer(ctx, &todopb.GetRunningTimerRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "获取计时器失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTimeEntryToResponse(res))
+	}
+}
+
+// CreateTimeEntryHandler 处理手动补录时间记录请求
+func CreateTimeEntryHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody timeEntryRequest
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		if reqBody.TodoId == 0 || reqBody.EndedAt == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "todo_id 和 ended_at 不能为空"})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.CreateTimeEntryRequest{
+			UserId:    userID.(uint32),
+			TodoId:    reqBody.TodoId,
+			StartedAt: timestamppb.New(*reqBody.StartedAt),
+			EndedAt:   timestamppb.New(*reqBody.EndedAt),
+			Note:      reqBody.Note,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.CreateTimeEntry(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "创建时间记录失败")
+			return
+		}
+		c.JSON(http.StatusCreated, models.ConvertProtoTimeEntryToResponse(res))
+	}
+}
+
+// ListTimeEntriesHandler 处理获取时间记录请求，可按 todo_id 和 from/to (RFC3339) 过滤
+func ListTimeEntriesHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.ListTimeEntriesRequest{UserId: userID.(uint32)}
+		if value := c.Query("todo_id"); value != "" {
+			todoID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的待办事项ID"})
+				return
+			}
+			grpcReq.TodoId = uint32(todoID)
+		}
+		var ok bool
+		if grpcReq.From, ok = parseTimeQuery(c, "from"); !ok {
+			return
+		}
+		if grpcReq.To, ok = parseTimeQuery(c, "to"); !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.ListTimeEntries(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "获取时间记录失败")
+			return
+		}
+
+		responseList := make([]models.TimeEntryResponse, len(res.TimeEntries))
+		for i, protoEntry := range res.TimeEntries {
+			responseList[i] = models.ConvertProtoTimeEntryToResponse(protoEntry)
+		}
+		c.JSON(http.StatusOK, responseList)
+	}
+}
+
+// UpdateTimeEntryHandler 处理修改时间记录请求，正在运行的计时器只能修改开始时间和备注
+func UpdateTimeEntryHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的时间记录ID"})
+			return
+		}
+
+		var reqBody timeEntryRequest
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		grpcReq := &todopb.UpdateTimeEntryRequest{
+			UserId:      userID.(uint32),
+			TimeEntryId: uint32(entryID),
+			StartedAt:   timestamppb.New(*reqBody.StartedAt),
+			Note:        reqBody.Note,
+		}
+		if reqBody.EndedAt != nil {
+			grpcReq.EndedAt = timestamppb.New(*reqBody.EndedAt)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.UpdateTimeEntry(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "修改时间记录失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTimeEntryToResponse(res))
+	}
+}
+
+// DeleteTimeEntryHandler 处理删除时间记录请求
+func DeleteTimeEntryHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的时间记录ID"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		_, err = todoClient.DeleteTimeEntry(ctx, &todopb.DeleteTimeEntryRequest{
+			UserId:      userID.(uint32),
+			TimeEntryId: uint32(entryID),
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "删除时间记录失败")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetTimeReportHandler 处理时间报表请求，from/to 为 RFC3339 时间，group_by 为 day/tag/project，
+// 按天分组时使用 time_zone 查询参数指定的时区划分日期
+func GetTimeReportHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+
+		groupBy, ok := models.ParseGroupBy(c.Query("group_by"))
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的分组方式，可选值为 day、tag、project"})
+			return
+		}
+		grpcReq := &todopb.GetTimeReportRequest{
+			UserId:   userID.(uint32),
+			GroupBy:  groupBy,
+			TimeZone: c.Query("time_zone"),
+		}
+		if grpcReq.From, ok = parseTimeQuery(c, "from"); !ok {
+			return
+		}
+		if grpcReq.To, ok = parseTimeQuery(c, "to"); !ok {
+			return
+		}
+		if grpcReq.From == nil || grpcReq.To == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from 和 to 不能为空"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.GetTimeReport(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "生成时间报表失败")
+			return
+		}
+		c.JSON(http.StatusOK, models.ConvertProtoTimeReportToResponse(res))
+	}
+}
+
+// parseTimeQuery 解析 RFC3339 格式的查询参数，参数为空时返回 nil，格式错误时写入 400 响应并返回 false
+func parseTimeQuery(c *gin.Context, name string) (*timestamppb.Timestamp, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 " + name + " 参数，应为 RFC3339 格式的时间"})
+		return nil, false
+	}
+	return timestamppb.New(t), true
+}
@@ -10,19 +10,36 @@ import (
 	todopb "todo-project/api-gateway/proto/todo"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // CreateTodoHandler 处理创建待办事项请求
 func CreateTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var reqBody struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
+			Title       string                         `json:"title"`
+			Description string                         `json:"description"`
+			ParentID    uint32                         `json:"parent_id"`
+			DueAt       *time.Time                     `json:"due_at"`
+			Tags        []string                       `json:"tags"`
+			Checklist   []models.ChecklistItemResponse `json:"checklist"`
+			Priority    string                         `json:"priority"`
+			Recurrence  *models.RecurrenceResponse     `json:"recurrence"`
 		}
 		if err := c.ShouldBindJSON(&reqBody); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
 			return
 		}
+		priority, ok := models.ParsePriority(reqBody.Priority)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的优先级，可选值: none, low, medium, high, urgent"})
+			return
+		}
+		recurrence, ok := models.ConvertRecurrenceToProto(reqBody.Recurrence)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的重复频率，可选值: daily, weekly, monthly, yearly"})
+			return
+		}
 
 		userID, _ := c.Get("user_id")
 
@@ -30,6 +47,14 @@ func CreateTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
 			UserId:      userID.(uint32),
 			Title:       reqBody.Title,
 			Description: reqBody.Description,
+			ParentId:    reqBody.ParentID,
+			Tags:        reqBody.Tags,
+			Checklist:   models.ConvertChecklistToProto(reqBody.Checklist),
+			Priority:    priority,
+			Recurrence:  recurrence,
+		}
+		if reqBody.DueAt != nil {
+			grpcReq.DueAt = timestamppb.New(*reqBody.DueAt)
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -44,12 +69,45 @@ func CreateTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
 	}
 }
 
-// GetTodosHandler 处理获取所有待办事项请求
+// QuickAddTodoHandler 处理快速添加待办事项请求，从自然语言文本中解析截止时间、优先级、标签和重复规则
+func QuickAddTodoHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Text     string `json:"text" binding:"required"`
+			TimeZone string `json:"time_zone"` // IANA 时区名，例如 "Asia/Shanghai"
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+
+		grpcReq := &todopb.QuickAddTodoRequest{
+			UserId:   userID.(uint32),
+			Text:     reqBody.Text,
+			TimeZone: reqBody.TimeZone,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, err := todoClient.QuickAddTodo(ctx, grpcReq)
+		if err != nil {
+			HandleGrpcError(c, err, "快速添加待办事项失败")
+			return
+		}
+		c.JSON(http.StatusCreated, models.ConvertProtoQuickAddToResponse(res))
+	}
+}
+
+// GetTodosHandler 处理获取所有待办事项请求，默认不包含已归档的待办事项 (?include_archived=true 时包含)
 func GetTodosHandler(todoClient todopb.TodoServiceClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, _ := c.Get("user_id")
+		includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
 
-		req := &todopb.GetTodosRequest{UserId: userID.(uint32)}
+		req := &todopb.GetTodosRequest{UserId: userID.(uint32), IncludeArchived: includeArchived}
 		res, err := todoClient.GetTodos(c.Request.Context(), req)
 
 		if err != nil {
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	userpb "todo-project/api-gateway/proto/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifySecondFactorHandler 完成两步验证登录，成功时的响应与 /api/login 相同
+func VerifySecondFactorHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			ChallengeToken string `json:"challenge_token" binding:"required"`
+			Code           string `json:"code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.VerifySecondFactor(withClientIP(ctx, c), &userpb.VerifySecondFactorRequest{
+			ChallengeToken: reqBody.ChallengeToken,
+			Code:           reqBody.Code,
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "两步验证失败")
+			return
+		}
+		writeLoginResponse(c, res)
+	}
+}
+
+// GetTwoFactorStatusHandler 查询当前用户是否启用了两步验证
+func GetTwoFactorStatusHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.GetTwoFactorStatus(ctx, &userpb.GetTwoFactorStatusRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "查询两步验证状态失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"enabled":                  res.GetEnabled(),
+			"recovery_codes_remaining": res.GetRecoveryCodesRemaining(),
+		})
+	}
+}
+
+// BeginTOTPEnrollmentHandler 开始绑定身份验证器，返回密钥和 otpauth URI
+func BeginTOTPEnrollmentHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.BeginTOTPEnrollment(ctx, &userpb.BeginTOTPEnrollmentRequest{UserId: userID.(uint32)})
+		if err != nil {
+			HandleGrpcError(c, err, "绑定身份验证器失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"secret":      res.GetSecret(),
+			"otpauth_uri": res.GetOtpauthUri(),
+		})
+	}
+}
+
+// ConfirmTOTPEnrollmentHandler 使用第一个验证码确认绑定，返回恢复码
+func ConfirmTOTPEnrollmentHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Code string `json:"code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.ConfirmTOTPEnrollment(ctx, &userpb.ConfirmTOTPEnrollmentRequest{
+			UserId: userID.(uint32),
+			Code:   reqBody.Code,
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "启用两步验证失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"recovery_codes": res.GetRecoveryCodes()})
+	}
+}
+
+// DisableTwoFactorHandler 停用两步验证，需要密码和验证码 (或恢复码)
+func DisableTwoFactorHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Password string `json:"password" binding:"required"`
+			Code     string `json:"code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		_, err := userClient.DisableTwoFactor(ctx, &userpb.DisableTwoFactorRequest{
+			UserId:   userID.(uint32),
+			Password: reqBody.Password,
+			Code:     reqBody.Code,
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "停用两步验证失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "两步验证已停用"})
+	}
+}
+
+// RegenerateRecoveryCodesHandler 重新生成恢复码，需要身份验证器中的验证码
+func RegenerateRecoveryCodesHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Code string `json:"code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		userID, _ := c.Get("user_id")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.RegenerateRecoveryCodes(ctx, &userpb.RegenerateRecoveryCodesRequest{
+			UserId: userID.(uint32),
+			Code:   reqBody.Code,
+		})
+		if err != nil {
+			HandleGrpcError(c, err, "重新生成恢复码失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"recovery_codes": res.GetRecoveryCodes()})
+	}
+}
@@ -2,14 +2,14 @@ package handlers
 
 import (
 	"context"
-	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	userpb "todo-project/api-gateway/proto/user"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/metadata"
 )
 
 // RegisterHandler 处理用户注册请求
@@ -41,37 +41,172 @@ func LoginHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
-		res, err := userClient.Login(ctx, &req)
+		res, err := userClient.Login(withClientIP(ctx, c), &req)
 		if err != nil {
 			HandleGrpcError(c, err, "登录失败")
 			return
 		}
 
-		tokenString := res.GetToken()
+		writeLoginResponse(c, res)
+	}
+}
 
-		// 解析Token获取username
-		var username string
-		token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
-		if err == nil {
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				if name, ok := claims["username"].(string); ok {
-					username = name
-					log.Printf("从登录token中解析到用户名: %s", username)
-				} else {
-					log.Println("警告：登录token中username claim类型不正确或不存在")
-				}
-			} else {
-				log.Println("警告：无法断言登录token的claims为MapClaims")
-			}
-		} else {
-			log.Printf("警告：解析登录token (unverified) 失败: %v", err)
-		}
+// withClientIP 在 gRPC 元数据中附带客户端 IP，user-service 据此按 IP 限制登录尝试
+func withClientIP(ctx context.Context, c *gin.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-client-ip", c.ClientIP())
+}
 
-		// 返回token和username
+// writeLoginResponse 返回登录结果。启用了两步验证时只返回挑战令牌，客户端随后调用 /api/2fa/verify
+func writeLoginResponse(c *gin.Context, res *userpb.LoginResponse) {
+	if res.GetTwoFactorRequired() {
 		c.JSON(http.StatusOK, gin.H{
-			"token":    tokenString,
-			"username": username,
+			"two_factor_required": true,
+			"challenge_token":     res.GetChallengeToken(),
+			"expires_in":          res.GetChallengeExpiresIn(),
 		})
+		return
+	}
+
+	// username 与 user.username 相同，保留给只读取 username 的旧客户端
+	c.JSON(http.StatusOK, gin.H{
+		"token":         res.GetToken(),
+		"refresh_token": res.GetRefreshToken(),
+		"expires_in":    res.GetExpiresIn(),
+		"username":      res.GetUser().GetUsername(),
+		"user":          profileJSON(res.GetUser()),
+	})
+}
+
+// RefreshTokenHandler 处理刷新令牌请求，返回新的访问令牌和轮换后的刷新令牌
+func RefreshTokenHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			RefreshToken string `json:"refresh_token" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		res, err := userClient.RefreshToken(ctx, &userpb.RefreshTokenRequest{RefreshToken: reqBody.RefreshToken})
+		if err != nil {
+			HandleGrpcError(c, err, "刷新令牌失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"token":         res.GetToken(),
+			"refresh_token": res.GetRefreshToken(),
+			"expires_in":    res.GetExpiresIn(),
+		})
+	}
+}
+
+// LogoutHandler 处理登出请求，撤销刷新令牌所在的会话。
+// 请求带有 Authorization 请求头时一并撤销当前的访问令牌，该路由不校验访问令牌，过期的令牌由 user-service 忽略
+func LogoutHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			RefreshToken string `json:"refresh_token" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		grpcReq := &userpb.LogoutRequest{
+			RefreshToken: reqBody.RefreshToken,
+			AccessToken:  strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "),
+		}
+		if _, err := userClient.Logout(ctx, grpcReq); err != nil {
+			HandleGrpcError(c, err, "登出失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "已登出"})
+	}
+}
+
+// VerifyEmailHandler 处理邮箱验证请求，token 来自验证邮件中的链接
+func VerifyEmailHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Token string `json:"token" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		resp, err := userClient.VerifyEmail(ctx, &userpb.VerifyEmailRequest{Token: reqBody.Token})
+		if err != nil {
+			HandleGrpcError(c, err, "验证邮箱失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "邮箱已验证", "username": resp.GetUsername()})
+	}
+}
+
+// ResendVerificationEmailHandler 处理重新发送验证邮件的请求
+func ResendVerificationEmailHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Username string `json:"username" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if _, err := userClient.ResendVerificationEmail(ctx, &userpb.ResendVerificationEmailRequest{Username: reqBody.Username}); err != nil {
+			HandleGrpcError(c, err, "发送验证邮件失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "如果该账号存在且尚未验证，验证邮件已发送"})
+	}
+}
+
+// RequestPasswordResetHandler 处理找回密码请求，无论邮箱是否注册都返回相同的响应
+func RequestPasswordResetHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Email string `json:"email" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if _, err := userClient.RequestPasswordReset(ctx, &userpb.RequestPasswordResetRequest{Email: reqBody.Email}); err != nil {
+			HandleGrpcError(c, err, "申请重置密码失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册，重置密码的链接已发送到该邮箱"})
+	}
+}
+
+// ResetPasswordHandler 处理重置密码请求，token 来自重置邮件中的链接
+func ResetPasswordHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Token       string `json:"token" binding:"required"`
+			NewPassword string `json:"new_password" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据: " + err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		grpcReq := &userpb.ResetPasswordRequest{Token: reqBody.Token, NewPassword: reqBody.NewPassword}
+		if _, err := userClient.ResetPassword(ctx, grpcReq); err != nil {
+			HandleGrpcError(c, err, "重置密码失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "密码已重置，请使用新密码登录"})
 	}
 }
 
@@ -109,3 +244,42 @@ func ChangePasswordHandler(userClient userpb.UserServiceClient) gin.HandlerFunc
 		c.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 	}
 }
+
+// JWKSHandler 返回校验访问令牌签名的公钥集合 (RFC 7517)。user-service 使用 HS256 时 keys 为空
+func JWKSHandler(userClient userpb.UserServiceClient) gin.HandlerFunc {
+	type jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		resp, err := userClient.GetJWKS(ctx, &userpb.GetJWKSRequest{})
+		if err != nil {
+			HandleGrpcError(c, err, "获取公钥失败")
+			return
+		}
+		keys := make([]jwk, 0, len(resp.GetKeys()))
+		for _, key := range resp.GetKeys() {
+			keys = append(keys, jwk{
+				Kty: key.GetKty(),
+				Kid: key.GetKid(),
+				Use: key.GetUse(),
+				Alg: key.GetAlg(),
+				N:   key.GetN(),
+				E:   key.GetE(),
+				Crv: key.GetCrv(),
+				X:   key.GetX(),
+			})
+		}
+		// 轮换后的新公钥在缓存过期前不可见，客户端遇到未知 kid 时应重新获取
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, gin.H{"keys": keys})
+	}
+}
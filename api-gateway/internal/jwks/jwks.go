@@ -0,0 +1,177 @@
+// Package jwks 缓存 user-service 发布的公钥集合 (JWKS)，用于校验 RS256/EdDSA 签名的访问令牌。
+//
+// 公钥定期刷新；遇到未知的 kid 时 (user-service 刚刚轮换了密钥) 立即刷新一次，
+// 两次按需刷新之间至少间隔 minRefetchInterval，防止伪造的 kid 导致频繁请求 user-service。
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	userpb "todo-project/api-gateway/proto/user"
+)
+
+const (
+	minRefetchInterval = 10 * time.Second
+	fetchTimeout       = 5 * time.Second
+	// 启动时获取失败后的重试间隔
+	retryInterval = 5 * time.Second
+)
+
+// ErrUnknownKey 令牌头部的 kid 不对应任何已知公钥
+var ErrUnknownKey = errors.New("未知的签名密钥")
+
+type publicKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+}
+
+// KeySet 公钥缓存，可并发使用
+type KeySet struct {
+	client          userpb.UserServiceClient
+	refreshInterval time.Duration
+
+	mu   sync.RWMutex
+	keys map[string]publicKey
+
+	// 保证同一时间只有一个刷新请求，lastFetch 用于限制按需刷新的频率
+	fetchMu   sync.Mutex
+	lastFetch time.Time
+}
+
+// New 创建公钥缓存，每隔 refreshInterval 从 user-service 刷新一次
+func New(client userpb.UserServiceClient, refreshInterval time.Duration) *KeySet {
+	return &KeySet{client: client, refreshInterval: refreshInterval, keys: make(map[string]publicKey)}
+}
+
+// Run 获取公钥并定期刷新，直到 ctx 被取消。首次获取失败时每隔 retryInterval 重试
+func (k *KeySet) Run(ctx context.Context) {
+	for k.fetch(ctx) != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+	ticker := time.NewTicker(k.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		k.fetch(ctx)
+	}
+}
+
+// Keyfunc 按令牌头部的 kid 返回校验签名的公钥，用于 jwt.Parse。令牌的签名算法必须与公钥声明的算法一致
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("令牌缺少 kid")
+	}
+	key, ok := k.lookup(kid)
+	if !ok && k.refetch() {
+		key, ok = k.lookup(kid)
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("非预期的签名方法: %v", token.Header["alg"])
+	}
+	return key.key, nil
+}
+
+func (k *KeySet) lookup(kid string) (publicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// refetch 按需刷新公钥，距离上次刷新不足 minRefetchInterval 时跳过，返回是否刷新成功
+func (k *KeySet) refetch() bool {
+	k.fetchMu.Lock()
+	recent := time.Since(k.lastFetch) < minRefetchInterval
+	k.fetchMu.Unlock()
+	if recent {
+		return false
+	}
+	return k.fetch(context.Background()) == nil
+}
+
+// fetch 从 user-service 获取公钥集合并替换缓存，无法解析的公钥被忽略
+func (k *KeySet) fetch(ctx context.Context) error {
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+	k.lastFetch = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	resp, err := k.client.GetJWKS(ctx, &userpb.GetJWKSRequest{})
+	if err != nil {
+		log.Printf("警告: 获取公钥集合失败: %v", err)
+		return err
+	}
+	keys := make(map[string]publicKey, len(resp.GetKeys()))
+	for _, jwk := range resp.GetKeys() {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			log.Printf("警告: 忽略无法解析的公钥 %s: %v", jwk.GetKid(), err)
+			continue
+		}
+		keys[jwk.GetKid()] = key
+	}
+	if len(keys) == 0 {
+		log.Printf("警告: user-service 没有发布公钥，请确认其 JWT_SIGNING_ALG 不是 HS256")
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	return nil
+}
+
+func parseJWK(jwk *userpb.JSONWebKey) (publicKey, error) {
+	switch {
+	case jwk.GetKty() == "RSA" && jwk.GetAlg() == jwt.SigningMethodRS256.Alg():
+		n, err := base64.RawURLEncoding.DecodeString(jwk.GetN())
+		if err != nil {
+			return publicKey{}, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.GetE())
+		if err != nil {
+			return publicKey{}, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return publicKey{}, errors.New("RSA 公共指数过大")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+		return publicKey{method: jwt.SigningMethodRS256, key: key}, nil
+	case jwk.GetKty() == "OKP" && jwk.GetCrv() == "Ed25519" && jwk.GetAlg() == jwt.SigningMethodEdDSA.Alg():
+		x, err := base64.RawURLEncoding.DecodeString(jwk.GetX())
+		if err != nil {
+			return publicKey{}, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("Ed25519 公钥长度错误")
+		}
+		return publicKey{method: jwt.SigningMethodEdDSA, key: ed25519.PublicKey(x)}, nil
+	default:
+		return publicKey{}, fmt.Errorf("不支持的公钥类型 kty=%s alg=%s", jwk.GetKty(), jwk.GetAlg())
+	}
+}
@@ -0,0 +1,234 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc"
+
+	userpb "todo-project/api-gateway/proto/user"
+)
+
+// fakeUserService 只实现 GetJWKS，返回当前发布的公钥并记录请求次数
+type fakeUserService struct {
+	userpb.UserServiceClient
+
+	mu    sync.Mutex
+	keys  []*userpb.JSONWebKey
+	calls int
+}
+
+func (f *fakeUserService) GetJWKS(ctx context.Context, in *userpb.GetJWKSRequest, opts ...grpc.CallOption) (*userpb.GetJWKSResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return &userpb.GetJWKSResponse{Keys: f.keys}, nil
+}
+
+func (f *fakeUserService) publish(keys ...*userpb.JSONWebKey) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = keys
+}
+
+func (f *fakeUserService) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// testKey 签名用的私钥及其发布的 JWK，编码方式与 user-service 的 keys.newJWK 相同
+type testKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	jwk     *userpb.JSONWebKey
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, method: jwt.SigningMethodRS256, private: private, jwk: &userpb.JSONWebKey{
+		Kty: "RSA", Kid: kid, Use: "sig", Alg: "RS256",
+		N: base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+	}}
+}
+
+func newEd25519Key(t *testing.T, kid string) testKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, method: jwt.SigningMethodEdDSA, private: private, jwk: &userpb.JSONWebKey{
+		Kty: "OKP", Kid: kid, Use: "sig", Alg: "EdDSA", Crv: "Ed25519",
+		X: base64.RawURLEncoding.EncodeToString(public),
+	}}
+}
+
+func (k testKey) sign(t *testing.T) string {
+	t.Helper()
+	token := jwt.NewWithClaims(k.method, jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// newTestKeySet 创建已获取过一次公钥的 KeySet
+func newTestKeySet(t *testing.T, keys ...testKey) (*KeySet, *fakeUserService) {
+	t.Helper()
+	client := &fakeUserService{}
+	for _, key := range keys {
+		client.keys = append(client.keys, key.jwk)
+	}
+	k := New(client, time.Hour)
+	if err := k.fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return k, client
+}
+
+func TestKeyfuncRoundTrip(t *testing.T) {
+	rsaKey, edKey := newRSAKey(t, "rsa-1"), newEd25519Key(t, "ed-1")
+	k, _ := newTestKeySet(t, rsaKey, edKey)
+	for _, key := range []testKey{rsaKey, edKey} {
+		t.Run(key.method.Alg(), func(t *testing.T) {
+			token, err := jwt.Parse(key.sign(t), k.Keyfunc)
+			if err != nil || !token.Valid {
+				t.Fatalf("校验 %s 令牌失败: %v", key.method.Alg(), err)
+			}
+		})
+	}
+}
+
+func TestKeyfuncRejects(t *testing.T) {
+	rsaKey, edKey := newRSAKey(t, "rsa-1"), newEd25519Key(t, "ed-1")
+	k, _ := newTestKeySet(t, rsaKey, edKey)
+	// 以公开的 RSA 公钥作为 HMAC 密钥伪造令牌 (算法混淆)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1})
+	forged.Header["kid"] = rsaKey.kid
+	forgedHS256, err := forged.SignedString([]byte(rsaKey.jwk.N))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 用 Ed25519 私钥签名，却声明为 RSA 公钥的 kid
+	mismatched := edKey
+	mismatched.kid = rsaKey.kid
+	unknown := newRSAKey(t, "rsa-unknown")
+	// 另一把私钥签名，kid 与已发布的公钥相同
+	impostor := newRSAKey(t, rsaKey.kid)
+	noKid := rsaKey
+	noKid.kid = ""
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "HS256 使用 RSA 的 kid", token: forgedHS256},
+		{name: "EdDSA 使用 RSA 的 kid", token: mismatched.sign(t)},
+		{name: "未知的 kid", token: unknown.sign(t)},
+		{name: "缺少 kid", token: noKid.sign(t)},
+		{name: "签名不匹配", token: impostor.sign(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if token, err := jwt.Parse(tt.token, k.Keyfunc); err == nil && token.Valid {
+				t.Errorf("令牌通过了校验")
+			}
+		})
+	}
+
+	if _, err := jwt.Parse(unknown.sign(t), k.Keyfunc); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("未知 kid 的错误 = %v, 期望 ErrUnknownKey", err)
+	}
+}
+
+func TestKeyfuncRefetchesOnRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "rsa-1")
+	k, client := newTestKeySet(t, oldKey)
+
+	// user-service 轮换了密钥，旧密钥在其令牌过期前继续发布
+	newKey := newEd25519Key(t, "ed-2")
+	client.publish(newKey.jwk, oldKey.jwk)
+
+	// 距离上次获取不足 minRefetchInterval，不会按需刷新
+	if _, err := jwt.Parse(newKey.sign(t), k.Keyfunc); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("刚获取过公钥时 err = %v, 期望 ErrUnknownKey", err)
+	}
+	if got := client.callCount(); got != 1 {
+		t.Fatalf("GetJWKS 调用了 %d 次, 期望 1 次", got)
+	}
+
+	k.lastFetch = time.Now().Add(-minRefetchInterval)
+	for _, key := range []testKey{newKey, oldKey} {
+		if _, err := jwt.Parse(key.sign(t), k.Keyfunc); err != nil {
+			t.Errorf("轮换后校验 %s 的令牌失败: %v", key.kid, err)
+		}
+	}
+	if got := client.callCount(); got != 2 {
+		t.Errorf("GetJWKS 调用了 %d 次, 期望 2 次", got)
+	}
+
+	// 伪造的 kid 在间隔内只触发一次刷新
+	k.lastFetch = time.Now().Add(-minRefetchInterval)
+	for i := 0; i < 5; i++ {
+		jwt.Parse(newRSAKey(t, "forged").sign(t), k.Keyfunc)
+	}
+	if got := client.callCount(); got != 3 {
+		t.Errorf("GetJWKS 调用了 %d 次, 期望 3 次", got)
+	}
+
+	// 旧密钥不再发布后，它签发的令牌被拒绝
+	client.publish(newKey.jwk)
+	if err := k.fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(oldKey.sign(t), k.Keyfunc); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("旧密钥停止发布后 err = %v, 期望 ErrUnknownKey", err)
+	}
+}
+
+func TestParseJWKInvalid(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-1")
+	tests := []struct {
+		name string
+		jwk  *userpb.JSONWebKey
+	}{
+		{name: "不支持的类型", jwk: &userpb.JSONWebKey{Kty: "EC", Kid: "ec", Alg: "ES256"}},
+		{name: "RSA 声明为 EdDSA", jwk: &userpb.JSONWebKey{Kty: "RSA", Kid: "rsa", Alg: "EdDSA", N: rsaKey.jwk.N, E: rsaKey.jwk.E}},
+		{name: "RSA 模数不是 Base64URL", jwk: &userpb.JSONWebKey{Kty: "RSA", Kid: "rsa", Alg: "RS256", N: "!!!", E: rsaKey.jwk.E}},
+		{name: "RSA 公共指数过大", jwk: &userpb.JSONWebKey{Kty: "RSA", Kid: "rsa", Alg: "RS256", N: rsaKey.jwk.N, E: base64.RawURLEncoding.EncodeToString([]byte{1, 0, 0, 0, 0, 0, 0, 0, 1})}},
+		{name: "Ed25519 公钥长度错误", jwk: &userpb.JSONWebKey{Kty: "OKP", Kid: "ed", Alg: "EdDSA", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(make([]byte, 16))}},
+		{name: "不支持的曲线", jwk: &userpb.JSONWebKey{Kty: "OKP", Kid: "ed", Alg: "EdDSA", Crv: "X25519", X: base64.RawURLEncoding.EncodeToString(make([]byte, 32))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJWK(tt.jwk); err == nil {
+				t.Errorf("parseJWK 接受了无效的公钥")
+			}
+		})
+	}
+
+	// 无法解析的公钥被忽略，不影响其他公钥
+	k, _ := newTestKeySet(t, rsaKey, testKey{jwk: tests[0].jwk})
+	if _, err := jwt.Parse(rsaKey.sign(t), k.Keyfunc); err != nil {
+		t.Errorf("存在无效公钥时校验失败: %v", err)
+	}
+}
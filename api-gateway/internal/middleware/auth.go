@@ -1,18 +1,37 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+
+	"todo-project/api-gateway/internal/revocation"
 )
 
-// AuthMiddleware 实现JWT认证中间件
-func AuthMiddleware(jwtKey []byte) gin.HandlerFunc {
+// revocationCheckTimeout 查询令牌撤销状态的超时时间，超时后按本地保留的撤销记录判断
+const revocationCheckTimeout = time.Second
+
+// HMACKeyfunc 返回使用共享密钥 (HS256) 校验令牌的 jwt.Keyfunc
+func HMACKeyfunc(jwtKey []byte) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		// 确保签名方法是HS256
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("非预期的签名方法: %v", token.Header["alg"])
+		}
+		return jwtKey, nil
+	}
+}
+
+// AuthMiddleware 实现JWT认证中间件，keyfunc 返回校验令牌签名的密钥。revoked 为 nil 时不检查令牌是否已被撤销
+func AuthMiddleware(keyfunc jwt.Keyfunc, revoked *revocation.Checker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -20,53 +39,89 @@ func AuthMiddleware(jwtKey []byte) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		authenticate(c, authHeader, keyfunc, revoked)
+	}
+}
 
-		// 检查是否为Bearer Token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "认证令牌格式错误"})
+// StreamAuthMiddleware 用于 SSE 和 WebSocket 的JWT认证中间件。
+// 浏览器的 EventSource 和 WebSocket 无法设置请求头，因此在没有 Authorization 请求头时
+// 也接受 access_token 查询参数，令牌的校验方式与 AuthMiddleware 相同
+func StreamAuthMiddleware(keyfunc jwt.Keyfunc, revoked *revocation.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "未提供认证令牌"})
 			c.Abort()
 			return
 		}
+		authenticate(c, authHeader, keyfunc, revoked)
+	}
+}
 
-		tokenString := parts[1]
+// authenticate 校验 Bearer 令牌，成功时将 user_id 和 username 存入上下文
+func authenticate(c *gin.Context, authHeader string, keyfunc jwt.Keyfunc, revoked *revocation.Checker) {
+	// 检查是否为Bearer Token
+	parts := strings.SplitN(authHeader, " ", 2)
+	if !(len(parts) == 2 && parts[0] == "Bearer") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "认证令牌格式错误"})
+		c.Abort()
+		return
+	}
 
-		// 解析Token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// 确保签名方法是HS256
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("非预期的签名方法: %v", token.Header["alg"])
-			}
-			return jwtKey, nil
-		})
+	tokenString := parts[1]
 
-		if err != nil {
-			log.Printf("Token解析错误: %v", err)
-			errorMsg := "无效的认证令牌"
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				errorMsg = "认证令牌已过期"
-			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": errorMsg})
+	// 解析Token
+	token, err := jwt.Parse(tokenString, keyfunc)
+
+	if err != nil {
+		log.Printf("Token解析错误: %v", err)
+		errorMsg := "无效的认证令牌"
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			errorMsg = "认证令牌已过期"
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errorMsg})
+		c.Abort()
+		return
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		// 从claims中提取user_id
+		userIDFloat, okUserID := claims["user_id"].(float64)
+		username, _ := claims["username"].(string)
+		if !okUserID {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的令牌声明(缺少user_id)"})
 			c.Abort()
 			return
 		}
-
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// 从claims中提取user_id
-			userIDFloat, okUserID := claims["user_id"].(float64)
-			username, _ := claims["username"].(string)
-			if !okUserID {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的令牌声明(缺少user_id)"})
-				c.Abort()
-				return
-			}
-			// 将user_id存储到Gin上下文中，供后续处理器使用
-			c.Set("user_id", uint32(userIDFloat))
-			c.Set("username", username)
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的认证令牌"})
+		if revoked != nil && isRevoked(c.Request.Context(), revoked, claims, uint32(userIDFloat)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "认证令牌已被撤销"})
 			c.Abort()
+			return
 		}
+		// 将user_id存储到Gin上下文中，供后续处理器使用
+		c.Set("user_id", uint32(userIDFloat))
+		c.Set("username", username)
+		c.Next()
+	} else {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的认证令牌"})
+		c.Abort()
+	}
+}
+
+// isRevoked 检查令牌是否已被撤销 (登出或修改密码)。iat 为精确到毫秒的秒数，exp 为秒数
+func isRevoked(ctx context.Context, revoked *revocation.Checker, claims jwt.MapClaims, userID uint32) bool {
+	jti, _ := claims["jti"].(string)
+	iat, _ := claims["iat"].(float64)
+	var expiresAt time.Time
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = time.Unix(int64(exp), 0)
 	}
+	ctx, cancel := context.WithTimeout(ctx, revocationCheckTimeout)
+	defer cancel()
+	return revoked.IsRevoked(ctx, jti, userID, time.UnixMilli(int64(math.Round(iat*1000))), expiresAt)
 }
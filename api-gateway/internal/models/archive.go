@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// ArchivePolicyResponse 定义用于API响应的自动归档策略
+type ArchivePolicyResponse struct {
+	Enabled          bool   `json:"enabled"`
+	ArchiveAfterDays uint32 `json:"archive_after_days"`
+	LastRunAt        string `json:"last_run_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// ConvertProtoArchivePolicyToResponse 将protobuf的自动归档策略转换为ArchivePolicyResponse
+func ConvertProtoArchivePolicyToResponse(protoPolicy *todopb.ArchivePolicy) ArchivePolicyResponse {
+	lastRunAt := ""
+	if protoPolicy.LastRunAt != nil && protoPolicy.LastRunAt.IsValid() {
+		lastRunAt = protoPolicy.LastRunAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	updatedAt := ""
+	if protoPolicy.UpdatedAt != nil && protoPolicy.UpdatedAt.IsValid() {
+		updatedAt = protoPolicy.UpdatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	return ArchivePolicyResponse{
+		Enabled:          protoPolicy.Enabled,
+		ArchiveAfterDays: protoPolicy.ArchiveAfterDays,
+		LastRunAt:        lastRunAt,
+		UpdatedAt:        updatedAt,
+	}
+}
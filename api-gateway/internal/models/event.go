@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// TodoEventResponse 定义通过 SSE / WebSocket 推送的 Todo 变更事件。
+// type 为 ready/created/updated/deleted/reset，id 即 SSE 的事件 ID，断线重连时作为 Last-Event-ID
+type TodoEventResponse struct {
+	Id         string        `json:"id"`
+	Type       string        `json:"type"`
+	TodoId     uint32        `json:"todo_id,omitempty"`
+	Todo       *TodoResponse `json:"todo,omitempty"`
+	OccurredAt string        `json:"occurred_at,omitempty"`
+}
+
+// ConvertProtoTodoEventToResponse 将protobuf的变更事件转换为TodoEventResponse
+func ConvertProtoTodoEventToResponse(protoEvent *todopb.TodoEvent) TodoEventResponse {
+	occurredAt := ""
+	if protoEvent.OccurredAt != nil && protoEvent.OccurredAt.IsValid() {
+		occurredAt = protoEvent.OccurredAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	var todo *TodoResponse
+	if protoEvent.Todo != nil {
+		response := ConvertProtoTodoToResponse(protoEvent.Todo)
+		todo = &response
+	}
+	return TodoEventResponse{
+		Id:         protoEvent.ResumeToken,
+		Type:       strings.ToLower(protoEvent.Type.String()),
+		TodoId:     protoEvent.TodoId,
+		Todo:       todo,
+		OccurredAt: occurredAt,
+	}
+}
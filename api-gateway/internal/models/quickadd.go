@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// QuickAddTokenResponse 定义快速添加文本中被识别的片段，start/end 为 Unicode 码点偏移
+type QuickAddTokenResponse struct {
+	Kind  string `json:"kind"`
+	Text  string `json:"text"`
+	Start uint32 `json:"start"`
+	End   uint32 `json:"end"`
+}
+
+// QuickAddInterpretationResponse 定义快速添加文本的解析结果
+type QuickAddInterpretationResponse struct {
+	Title      string                  `json:"title"`
+	DueAt      string                  `json:"due_at"`
+	AllDay     bool                    `json:"all_day"`
+	Priority   string                  `json:"priority"`
+	Tags       []string                `json:"tags"`
+	Recurrence *RecurrenceResponse     `json:"recurrence"`
+	Tokens     []QuickAddTokenResponse `json:"tokens"`
+}
+
+// QuickAddResponse 定义快速添加接口的响应
+type QuickAddResponse struct {
+	Todo           TodoResponse                   `json:"todo"`
+	Interpretation QuickAddInterpretationResponse `json:"interpretation"`
+}
+
+// ConvertProtoQuickAddToResponse 将protobuf的快速添加响应转换为QuickAddResponse
+func ConvertProtoQuickAddToResponse(protoRes *todopb.QuickAddTodoResponse) QuickAddResponse {
+	interpretation := protoRes.GetInterpretation()
+	dueAt := ""
+	if interpretation.GetDueAt() != nil && interpretation.GetDueAt().IsValid() {
+		dueAt = interpretation.GetDueAt().AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	tags := interpretation.GetTags()
+	if tags == nil {
+		tags = []string{}
+	}
+	tokens := make([]QuickAddTokenResponse, len(interpretation.GetTokens()))
+	for i, token := range interpretation.GetTokens() {
+		tokens[i] = QuickAddTokenResponse{Kind: token.Kind, Text: token.Text, Start: token.Start, End: token.End}
+	}
+	return QuickAddResponse{
+		Todo: ConvertProtoTodoToResponse(protoRes.GetTodo()),
+		Interpretation: QuickAddInterpretationResponse{
+			Title:      interpretation.GetTitle(),
+			DueAt:      dueAt,
+			AllDay:     interpretation.GetAllDay(),
+			Priority:   ConvertProtoPriorityToString(interpretation.GetPriority()),
+			Tags:       tags,
+			Recurrence: ConvertProtoRecurrenceToResponse(interpretation.GetRecurrence()),
+			Tokens:     tokens,
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// SavedSearchResponse 定义用于API响应的保存搜索结构体
+type SavedSearchResponse struct {
+	Id        uint32 `json:"id"`
+	UserId    uint32 `json:"user_id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// SavedSearchResultResponse 定义执行保存搜索的响应
+type SavedSearchResultResponse struct {
+	SavedSearch SavedSearchResponse `json:"saved_search"`
+	Todos       []TodoResponse      `json:"todos"`
+}
+
+// ConvertProtoSavedSearchToResponse 将protobuf的保存搜索转换为SavedSearchResponse
+func ConvertProtoSavedSearchToResponse(protoSearch *todopb.SavedSearch) SavedSearchResponse {
+	createdAt := ""
+	if protoSearch.CreatedAt != nil && protoSearch.CreatedAt.IsValid() {
+		createdAt = protoSearch.CreatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	updatedAt := ""
+	if protoSearch.UpdatedAt != nil && protoSearch.UpdatedAt.IsValid() {
+		updatedAt = protoSearch.UpdatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	return SavedSearchResponse{
+		Id:        protoSearch.Id,
+		UserId:    protoSearch.UserId,
+		Name:      protoSearch.Name,
+		Query:     protoSearch.Query,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// CompletionCountResponse 定义某一天或某一周 (date 为周一) 的完成数
+type CompletionCountResponse struct {
+	Date  string `json:"date"`
+	Count uint32 `json:"count"`
+}
+
+// TodoStatsResponse 定义用于API响应的完成情况统计
+type TodoStatsResponse struct {
+	Total                        uint32                    `json:"total"`
+	Open                         uint32                    `json:"open"`
+	Completed                    uint32                    `json:"completed"`
+	Overdue                      uint32                    `json:"overdue"`
+	Archived                     uint32                    `json:"archived"`
+	CompletionsPerDay            []CompletionCountResponse `json:"completions_per_day"`
+	CompletionsPerWeek           []CompletionCountResponse `json:"completions_per_week"`
+	AverageTimeToCompleteSeconds *int64                    `json:"average_time_to_complete_seconds"` // 没有完成记录时为 null
+	CurrentStreak                uint32                    `json:"current_streak"`
+	LongestStreak                uint32                    `json:"longest_streak"`
+	TimeZone                     string                    `json:"time_zone"`
+	GeneratedAt                  string                    `json:"generated_at"`
+}
+
+// ConvertProtoTodoStatsToResponse 将protobuf的统计转换为TodoStatsResponse
+func ConvertProtoTodoStatsToResponse(protoStats *todopb.TodoStats) TodoStatsResponse {
+	generatedAt := ""
+	if protoStats.GeneratedAt != nil && protoStats.GeneratedAt.IsValid() {
+		generatedAt = protoStats.GeneratedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	var averageSeconds *int64
+	if protoStats.AverageTimeToComplete != nil {
+		seconds := int64(protoStats.AverageTimeToComplete.AsDuration() / time.Second)
+		averageSeconds = &seconds
+	}
+	return TodoStatsResponse{
+		Total:                        protoStats.Total,
+		Open:                         protoStats.Open,
+		Completed:                    protoStats.Completed,
+		Overdue:                      protoStats.Overdue,
+		Archived:                     protoStats.Archived,
+		CompletionsPerDay:            convertProtoCompletionCounts(protoStats.CompletionsPerDay),
+		CompletionsPerWeek:           convertProtoCompletionCounts(protoStats.CompletionsPerWeek),
+		AverageTimeToCompleteSeconds: averageSeconds,
+		CurrentStreak:                protoStats.CurrentStreak,
+		LongestStreak:                protoStats.LongestStreak,
+		TimeZone:                     protoStats.TimeZone,
+		GeneratedAt:                  generatedAt,
+	}
+}
+
+func convertProtoCompletionCounts(protoCounts []*todopb.CompletionCount) []CompletionCountResponse {
+	counts := make([]CompletionCountResponse, len(protoCounts))
+	for i, count := range protoCounts {
+		counts[i] = CompletionCountResponse{Date: count.Date, Count: count.Count}
+	}
+	return counts
+}
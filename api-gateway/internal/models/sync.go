@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SyncRequest 定义离线同步请求
+type SyncRequest struct {
+	SyncToken string                `json:"sync_token"`
+	Mutations []SyncMutationRequest `json:"mutations"`
+}
+
+// SyncMutationRequest 定义客户端的一条离线修改
+type SyncMutationRequest struct {
+	MutationId     string          `json:"mutation_id"`
+	Op             string          `json:"op"` // create / update / delete
+	TodoId         uint32          `json:"todo_id"`
+	ClientId       string          `json:"client_id"`
+	BaseChangeSeq  uint64          `json:"base_change_seq"`
+	ParentClientId string          `json:"parent_client_id"`
+	Fields         []string        `json:"fields"` // update 要修改的字段，为空时修改全部字段
+	Todo           *SyncTodoFields `json:"todo"`
+}
+
+// SyncTodoFields 定义离线修改中的 Todo 字段值
+type SyncTodoFields struct {
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	Completed   bool                    `json:"completed"`
+	ParentId    uint32                  `json:"parent_id"`
+	DueAt       *time.Time              `json:"due_at"`
+	Tags        []string                `json:"tags"`
+	Checklist   []ChecklistItemResponse `json:"checklist"`
+	Priority    string                  `json:"priority"`
+	Recurrence  *RecurrenceResponse     `json:"recurrence"`
+}
+
+// SyncMutationResultResponse 定义一条离线修改的处理结果
+type SyncMutationResultResponse struct {
+	MutationId string `json:"mutation_id"`
+	Status     string `json:"status"` // applied / duplicate / conflict / rejected
+	TodoId     uint32 `json:"todo_id"`
+	Message    string `json:"message,omitempty"`
+}
+
+// SyncResponse 定义用于API响应的同步结果
+type SyncResponse struct {
+	SyncToken      string                       `json:"sync_token"`
+	Todos          []TodoResponse               `json:"todos"`
+	DeletedTodoIds []uint32                     `json:"deleted_todo_ids"`
+	Results        []SyncMutationResultResponse `json:"results"`
+	FullResync     bool                         `json:"full_resync"`
+}
+
+var syncOpNames = map[todopb.SyncMutation_Op]string{
+	todopb.SyncMutation_CREATE: "create",
+	todopb.SyncMutation_UPDATE: "update",
+	todopb.SyncMutation_DELETE: "delete",
+}
+
+var syncStatusNames = map[todopb.SyncMutationResult_Status]string{
+	todopb.SyncMutationResult_APPLIED:   "applied",
+	todopb.SyncMutationResult_DUPLICATE: "duplicate",
+	todopb.SyncMutationResult_CONFLICT:  "conflict",
+	todopb.SyncMutationResult_REJECTED:  "rejected",
+}
+
+// ConvertSyncMutationToProto 将请求中的离线修改转换为protobuf结构
+func ConvertSyncMutationToProto(mutation SyncMutationRequest) (*todopb.SyncMutation, error) {
+	op := todopb.SyncMutation_OP_UNSPECIFIED
+	name := strings.ToLower(strings.TrimSpace(mutation.Op))
+	for o, n := range syncOpNames {
+		if n == name {
+			op = o
+		}
+	}
+	if op == todopb.SyncMutation_OP_UNSPECIFIED {
+		return nil, errors.New("无效的操作类型，可选值: create, update, delete")
+	}
+	protoMutation := &todopb.SyncMutation{
+		MutationId:     mutation.MutationId,
+		Op:             op,
+		TodoId:         mutation.TodoId,
+		ClientId:       mutation.ClientId,
+		BaseChangeSeq:  mutation.BaseChangeSeq,
+		UpdateFields:   mutation.Fields,
+		ParentClientId: mutation.ParentClientId,
+	}
+	if fields := mutation.Todo; fields != nil {
+		priority, ok := ParsePriority(fields.Priority)
+		if !ok {
+			return nil, errors.New("无效的优先级，可选值: none, low, medium, high, urgent")
+		}
+		recurrence, ok := ConvertRecurrenceToProto(fields.Recurrence)
+		if !ok {
+			return nil, errors.New("无效的重复频率，可选值: daily, weekly, monthly, yearly")
+		}
+		protoMutation.Todo = &todopb.Todo{
+			Title:       fields.Title,
+			Description: fields.Description,
+			Completed:   fields.Completed,
+			ParentId:    fields.ParentId,
+			Tags:        fields.Tags,
+			Checklist:   ConvertChecklistToProto(fields.Checklist),
+			Priority:    priority,
+			Recurrence:  recurrence,
+		}
+		if fields.DueAt != nil {
+			protoMutation.Todo.DueAt = timestamppb.New(*fields.DueAt)
+		}
+	}
+	return protoMutation, nil
+}
+
+// ConvertProtoSyncResponseToResponse 将protobuf的同步结果转换为SyncResponse
+func ConvertProtoSyncResponseToResponse(protoRes *todopb.SyncTodosResponse) SyncResponse {
+	todos := make([]TodoResponse, len(protoRes.Todos))
+	for i, todo := range protoRes.Todos {
+		todos[i] = ConvertProtoTodoToResponse(todo)
+	}
+	results := make([]SyncMutationResultResponse, len(protoRes.Results))
+	for i, result := range protoRes.Results {
+		results[i] = SyncMutationResultResponse{
+			MutationId: result.MutationId,
+			Status:     syncStatusNames[result.Status],
+			TodoId:     result.TodoId,
+			Message:    result.Message,
+		}
+	}
+	deletedIDs := protoRes.DeletedTodoIds
+	if deletedIDs == nil {
+		deletedIDs = []uint32{}
+	}
+	return SyncResponse{
+		SyncToken:      protoRes.SyncToken,
+		Todos:          todos,
+		DeletedTodoIds: deletedIDs,
+		Results:        results,
+		FullResync:     protoRes.FullResync,
+	}
+}
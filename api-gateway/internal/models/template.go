@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// TemplateItemResponse 定义用于API响应的模板条目
+type TemplateItemResponse struct {
+	Id               uint32                  `json:"id"`
+	ParentItemId     uint32                  `json:"parent_item_id"`
+	Title            string                  `json:"title"`
+	Description      string                  `json:"description"`
+	DueOffsetSeconds *int64                  `json:"due_offset_seconds"` // 为 null 表示无截止时间
+	Tags             []string                `json:"tags"`
+	Checklist        []ChecklistItemResponse `json:"checklist"`
+}
+
+// TemplateResponse 定义用于API响应的模板结构体
+type TemplateResponse struct {
+	Id          uint32                 `json:"id"`
+	UserId      uint32                 `json:"user_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Items       []TemplateItemResponse `json:"items"`
+	CreatedAt   string                 `json:"created_at"`
+	UpdatedAt   string                 `json:"updated_at"`
+}
+
+// ConvertProtoTemplateToResponse 将protobuf的模板转换为TemplateResponse
+func ConvertProtoTemplateToResponse(protoTemplate *todopb.TodoTemplate) TemplateResponse {
+	createdAt := ""
+	if protoTemplate.CreatedAt != nil && protoTemplate.CreatedAt.IsValid() {
+		createdAt = protoTemplate.CreatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	updatedAt := ""
+	if protoTemplate.UpdatedAt != nil && protoTemplate.UpdatedAt.IsValid() {
+		updatedAt = protoTemplate.UpdatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	items := make([]TemplateItemResponse, len(protoTemplate.Items))
+	for i, protoItem := range protoTemplate.Items {
+		var dueOffset *int64
+		if protoItem.DueOffset != nil && protoItem.DueOffset.IsValid() {
+			seconds := int64(protoItem.DueOffset.AsDuration() / time.Second)
+			dueOffset = &seconds
+		}
+		tags := protoItem.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		items[i] = TemplateItemResponse{
+			Id:               protoItem.Id,
+			ParentItemId:     protoItem.ParentItemId,
+			Title:            protoItem.Title,
+			Description:      protoItem.Description,
+			DueOffsetSeconds: dueOffset,
+			Tags:             tags,
+			Checklist:        ConvertProtoChecklistToResponse(protoItem.Checklist),
+		}
+	}
+	return TemplateResponse{
+		Id:          protoTemplate.Id,
+		UserId:      protoTemplate.UserId,
+		Name:        protoTemplate.Name,
+		Description: protoTemplate.Description,
+		Items:       items,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
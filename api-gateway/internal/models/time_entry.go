@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+)
+
+// TimeEntryResponse 定义用于API响应的时间记录，正在运行的计时器 ended_at 为空，duration_seconds 计算到当前时间
+type TimeEntryResponse struct {
+	Id              uint32 `json:"id"`
+	TodoId          uint32 `json:"todo_id"`
+	StartedAt       string `json:"started_at"`
+	EndedAt         string `json:"ended_at"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	Note            string `json:"note"`
+	Running         bool   `json:"running"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// TimeReportRowResponse 定义时间报表中的一行
+type TimeReportRowResponse struct {
+	Key          string `json:"key"`
+	Label        string `json:"label"`
+	TotalSeconds int64  `json:"total_seconds"`
+	EntryCount   uint32 `json:"entry_count"`
+}
+
+// TimeReportResponse 定义用于API响应的时间报表
+type TimeReportResponse struct {
+	GroupBy      string                  `json:"group_by"`
+	From         string                  `json:"from"`
+	To           string                  `json:"to"`
+	TotalSeconds int64                   `json:"total_seconds"`
+	Rows         []TimeReportRowResponse `json:"rows"`
+}
+
+var groupByNames = map[todopb.GetTimeReportRequest_GroupBy]string{
+	todopb.GetTimeReportRequest_DAY:     "day",
+	todopb.GetTimeReportRequest_TAG:     "tag",
+	todopb.GetTimeReportRequest_PROJECT: "project",
+}
+
+// ParseGroupBy 将报表分组方式字符串转换为protobuf枚举，空字符串表示按天分组
+func ParseGroupBy(name string) (todopb.GetTimeReportRequest_GroupBy, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return todopb.GetTimeReportRequest_DAY, true
+	}
+	for groupBy, n := range groupByNames {
+		if n == name {
+			return groupBy, true
+		}
+	}
+	return todopb.GetTimeReportRequest_GROUP_BY_UNSPECIFIED, false
+}
+
+// ConvertProtoTimeEntryToResponse 将protobuf的时间记录转换为TimeEntryResponse
+func ConvertProtoTimeEntryToResponse(protoEntry *todopb.TimeEntry) TimeEntryResponse {
+	startedAt := ""
+	if protoEntry.StartedAt != nil && protoEntry.StartedAt.IsValid() {
+		startedAt = protoEntry.StartedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	endedAt := ""
+	if protoEntry.EndedAt != nil && protoEntry.EndedAt.IsValid() {
+		endedAt = protoEntry.EndedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	createdAt := ""
+	if protoEntry.CreatedAt != nil && protoEntry.CreatedAt.IsValid() {
+		createdAt = protoEntry.CreatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	updatedAt := ""
+	if protoEntry.UpdatedAt != nil && protoEntry.UpdatedAt.IsValid() {
+		updatedAt = protoEntry.UpdatedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	return TimeEntryResponse{
+		Id:              protoEntry.Id,
+		TodoId:          protoEntry.TodoId,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(protoEntry.Duration.AsDuration() / time.Second),
+		Note:            protoEntry.Note,
+		Running:         protoEntry.Running,
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+	}
+}
+
+// ConvertProtoTimeReportToResponse 将protobuf的时间报表转换为TimeReportResponse
+func ConvertProtoTimeReportToResponse(protoReport *todopb.TimeReport) TimeReportResponse {
+	rows := make([]TimeReportRowResponse, len(protoReport.Rows))
+	for i, row := range protoReport.Rows {
+		rows[i] = TimeReportRowResponse{
+			Key:          row.Key,
+			Label:        row.Label,
+			TotalSeconds: int64(row.Total.AsDuration() / time.Second),
+			EntryCount:   row.EntryCount,
+		}
+	}
+	return TimeReportResponse{
+		GroupBy:      groupByNames[protoReport.GroupBy],
+		From:         protoReport.From.AsTime().UTC().Format(time.RFC3339Nano),
+		To:           protoReport.To.AsTime().UTC().Format(time.RFC3339Nano),
+		TotalSeconds: int64(protoReport.Total.AsDuration() / time.Second),
+		Rows:         rows,
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	todopb "todo-project/api-gateway/proto/todo"
@@ -8,13 +9,30 @@ import (
 
 // TodoResponse 定义用于API响应的Todo结构体
 type TodoResponse struct {
-	Id          uint32 `json:"id"`
-	UserId      uint32 `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	Id             uint32                  `json:"id"`
+	UserId         uint32                  `json:"user_id"`
+	ParentId       uint32                  `json:"parent_id"`
+	Title          string                  `json:"title"`
+	Description    string                  `json:"description"`
+	Completed      bool                    `json:"completed"`
+	DueAt          string                  `json:"due_at"`
+	Tags           []string                `json:"tags"`
+	Checklist      []ChecklistItemResponse `json:"checklist"`
+	Priority       string                  `json:"priority"`
+	Recurrence     *RecurrenceResponse     `json:"recurrence"`
+	CompletedAt    string                  `json:"completed_at"`
+	ArchivedAt     string                  `json:"archived_at"`
+	TrackedSeconds int64                   `json:"tracked_seconds"`
+	ChangeSeq      uint64                  `json:"change_seq"`
+	ClientId       string                  `json:"client_id"`
+	CreatedAt      string                  `json:"created_at"`
+	UpdatedAt      string                  `json:"updated_at"`
+}
+
+// ChecklistItemResponse 定义检查清单条目，同时用于请求和响应
+type ChecklistItemResponse struct {
+	Content string `json:"content"`
+	Done    bool   `json:"done"`
 }
 
 // ConvertProtoTodoToResponse 将protobuf的Todo转换为TodoResponse
@@ -27,13 +45,125 @@ func ConvertProtoTodoToResponse(protoTodo *todopb.Todo) TodoResponse {
 	if protoTodo.UpdatedAt != nil && protoTodo.UpdatedAt.IsValid() {
 		updatedAt = protoTodo.UpdatedAt.AsTime().UTC().Format(time.RFC3339Nano)
 	}
+	dueAt := ""
+	if protoTodo.DueAt != nil && protoTodo.DueAt.IsValid() {
+		dueAt = protoTodo.DueAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	completedAt := ""
+	if protoTodo.CompletedAt != nil && protoTodo.CompletedAt.IsValid() {
+		completedAt = protoTodo.CompletedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	archivedAt := ""
+	if protoTodo.ArchivedAt != nil && protoTodo.ArchivedAt.IsValid() {
+		archivedAt = protoTodo.ArchivedAt.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	tags := protoTodo.Tags
+	if tags == nil {
+		tags = []string{}
+	}
 	return TodoResponse{
-		Id:          protoTodo.Id,
-		UserId:      protoTodo.UserId,
-		Title:       protoTodo.Title,
-		Description: protoTodo.Description,
-		Completed:   protoTodo.Completed,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		Id:             protoTodo.Id,
+		UserId:         protoTodo.UserId,
+		ParentId:       protoTodo.ParentId,
+		Title:          protoTodo.Title,
+		Description:    protoTodo.Description,
+		Completed:      protoTodo.Completed,
+		DueAt:          dueAt,
+		Tags:           tags,
+		Checklist:      ConvertProtoChecklistToResponse(protoTodo.Checklist),
+		Priority:       ConvertProtoPriorityToString(protoTodo.Priority),
+		Recurrence:     ConvertProtoRecurrenceToResponse(protoTodo.Recurrence),
+		CompletedAt:    completedAt,
+		ArchivedAt:     archivedAt,
+		TrackedSeconds: int64(protoTodo.TrackedTime.AsDuration() / time.Second),
+		ChangeSeq:      protoTodo.ChangeSeq,
+		ClientId:       protoTodo.ClientId,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// ConvertProtoChecklistToResponse 将protobuf的检查清单转换为响应结构
+func ConvertProtoChecklistToResponse(protoItems []*todopb.ChecklistItem) []ChecklistItemResponse {
+	items := make([]ChecklistItemResponse, len(protoItems))
+	for i, item := range protoItems {
+		items[i] = ChecklistItemResponse{Content: item.Content, Done: item.Done}
+	}
+	return items
+}
+
+// ConvertChecklistToProto 将请求中的检查清单转换为protobuf结构
+func ConvertChecklistToProto(items []ChecklistItemResponse) []*todopb.ChecklistItem {
+	protoItems := make([]*todopb.ChecklistItem, len(items))
+	for i, item := range items {
+		protoItems[i] = &todopb.ChecklistItem{Content: item.Content, Done: item.Done}
+	}
+	return protoItems
+}
+
+// RecurrenceResponse 定义重复规则，同时用于请求和响应
+type RecurrenceResponse struct {
+	Frequency string `json:"frequency"` // daily / weekly / monthly / yearly
+	Interval  uint32 `json:"interval"`
+}
+
+// 优先级在 API 中以小写字符串表示，"none" 表示无优先级
+var priorityNames = map[todopb.Priority]string{
+	todopb.Priority_PRIORITY_UNSPECIFIED: "none",
+	todopb.Priority_PRIORITY_LOW:         "low",
+	todopb.Priority_PRIORITY_MEDIUM:      "medium",
+	todopb.Priority_PRIORITY_HIGH:        "high",
+	todopb.Priority_PRIORITY_URGENT:      "urgent",
+}
+
+var frequencyNames = map[todopb.Recurrence_Frequency]string{
+	todopb.Recurrence_DAILY:   "daily",
+	todopb.Recurrence_WEEKLY:  "weekly",
+	todopb.Recurrence_MONTHLY: "monthly",
+	todopb.Recurrence_YEARLY:  "yearly",
+}
+
+// ConvertProtoPriorityToString 将protobuf的优先级转换为字符串
+func ConvertProtoPriorityToString(priority todopb.Priority) string {
+	if name, ok := priorityNames[priority]; ok {
+		return name
+	}
+	return priorityNames[todopb.Priority_PRIORITY_UNSPECIFIED]
+}
+
+// ParsePriority 将请求中的优先级字符串转换为protobuf枚举，空字符串视为无优先级
+func ParsePriority(name string) (todopb.Priority, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return todopb.Priority_PRIORITY_UNSPECIFIED, true
+	}
+	for priority, n := range priorityNames {
+		if n == name {
+			return priority, true
+		}
+	}
+	return todopb.Priority_PRIORITY_UNSPECIFIED, false
+}
+
+// ConvertProtoRecurrenceToResponse 将protobuf的重复规则转换为响应结构，不重复时返回 nil
+func ConvertProtoRecurrenceToResponse(recurrence *todopb.Recurrence) *RecurrenceResponse {
+	name, ok := frequencyNames[recurrence.GetFrequency()]
+	if !ok {
+		return nil
+	}
+	return &RecurrenceResponse{Frequency: name, Interval: recurrence.GetInterval()}
+}
+
+// ConvertRecurrenceToProto 将请求中的重复规则转换为protobuf结构
+func ConvertRecurrenceToProto(recurrence *RecurrenceResponse) (*todopb.Recurrence, bool) {
+	if recurrence == nil {
+		return nil, true
+	}
+	name := strings.ToLower(strings.TrimSpace(recurrence.Frequency))
+	for frequency, n := range frequencyNames {
+		if n == name {
+			return &todopb.Recurrence{Frequency: frequency, Interval: recurrence.Interval}, true
+		}
 	}
+	return nil, false
 }
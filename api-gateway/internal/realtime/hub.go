@@ -0,0 +1,305 @@
+// Package realtime 将 todo-service 的 WatchTodos 变更流转发给网关的 SSE 和 WebSocket 连接。
+//
+// 同一用户的所有连接共享一个上游 WatchTodos 调用 (Hub 按用户引用计数)，最后一个连接断开时立即取消上游调用，
+// 因此大量空闲连接不会各自占用一个 gRPC 流，连接断开后也不会遗留 goroutine。
+// 带 Last-Event-ID 的连接以及消费过慢的连接通过一次短暂的 WatchTodos 调用补发遗漏的事件。
+package realtime
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	todopb "todo-project/api-gateway/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// 每个连接缓冲的事件数，缓冲满时改为补读
+	subscriberBuffer = 32
+	// 补发事件的超时时间
+	catchUpTimeout = 10 * time.Second
+	// 上游调用失败后的重试间隔
+	retryMinDelay = time.Second
+	retryMaxDelay = 30 * time.Second
+)
+
+// ErrInvalidEventID Last-Event-ID 格式错误
+var ErrInvalidEventID = errors.New("invalid event id")
+
+// Hub 管理所有用户的共享上游变更流
+type Hub struct {
+	client todopb.TodoServiceClient
+
+	mu    sync.Mutex
+	users map[uint32]*userStream
+}
+
+// userStream 一个用户的上游 WatchTodos 调用及其本地订阅者
+type userStream struct {
+	userID uint32
+	cancel context.CancelFunc
+	subs   map[*subscription]struct{}
+	token  string // 上游最近的恢复令牌，收到 READY 之前为空
+}
+
+type subscription struct {
+	events chan *todopb.TodoEvent
+	lagged chan struct{}
+}
+
+// NewHub 创建 Hub，上游调用在第一个连接订阅时才会建立
+func NewHub(client todopb.TodoServiceClient) *Hub {
+	return &Hub{client: client, users: make(map[uint32]*userStream)}
+}
+
+func (h *Hub) subscribe(userID uint32) (*userStream, *subscription) {
+	sub := &subscription{
+		events: make(chan *todopb.TodoEvent, subscriberBuffer),
+		lagged: make(chan struct{}, 1),
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	us := h.users[userID]
+	if us == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		us = &userStream{userID: userID, cancel: cancel, subs: make(map[*subscription]struct{})}
+		h.users[userID] = us
+		go h.run(ctx, us)
+	}
+	us.subs[sub] = struct{}{}
+	if us.token != "" {
+		// 上游已就绪，新连接直接以上游当前位置作为起点
+		sub.events <- &todopb.TodoEvent{Type: todopb.TodoEvent_READY, ResumeToken: us.token}
+	}
+	return us, sub
+}
+
+func (h *Hub) unsubscribe(us *userStream, sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(us.subs, sub)
+	if len(us.subs) == 0 && h.users[us.userID] == us {
+		us.cancel()
+		delete(h.users, us.userID)
+	}
+}
+
+func (h *Hub) token(us *userStream) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return us.token
+}
+
+// run 维持用户的上游 WatchTodos 调用，中断后从最近的恢复令牌重新订阅，直到 ctx 被取消
+func (h *Hub) run(ctx context.Context, us *userStream) {
+	token := ""
+	delay := retryMinDelay
+	for {
+		stream, err := h.client.WatchTodos(ctx, &todopb.WatchTodosRequest{UserId: us.userID, ResumeToken: token})
+		for err == nil {
+			var event *todopb.TodoEvent
+			if event, err = stream.Recv(); err == nil {
+				if event.ResumeToken != "" {
+					token = event.ResumeToken
+				}
+				delay = retryMinDelay
+				h.broadcast(us, event)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.InvalidArgument {
+			token = ""
+		}
+		log.Printf("警告: 用户 %d 的上游变更流中断: %v，%s 后重连", us.userID, err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+func (h *Hub) broadcast(us *userStream, event *todopb.TodoEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if event.ResumeToken != "" {
+		us.token = event.ResumeToken
+	}
+	for sub := range us.subs {
+		select {
+		case sub.events <- event:
+		default:
+			select {
+			case sub.lagged <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Stream 单个客户端连接的事件来源，负责补发、按恢复令牌去重以及处理缺口
+type Stream struct {
+	hub       *Hub
+	userID    uint32
+	us        *userStream
+	sub       *subscription
+	last      string // 已发送给客户端的最后一个恢复令牌
+	readySent bool
+	pending   []*todopb.TodoEvent
+	closeOnce sync.Once
+}
+
+// Open 为连接订阅用户的变更。lastEventID 不为空时先补发其之后的事件，使用完毕后必须调用 Close
+func (h *Hub) Open(ctx context.Context, userID uint32, lastEventID string) (*Stream, error) {
+	if lastEventID != "" && !validToken(lastEventID) {
+		return nil, ErrInvalidEventID
+	}
+	us, sub := h.subscribe(userID)
+	st := &Stream{hub: h, userID: userID, us: us, sub: sub, last: lastEventID}
+	if lastEventID != "" {
+		if err := st.catchUp(ctx); err != nil {
+			st.Close()
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// Close 取消订阅，用户的最后一个连接关闭时同时取消上游调用
+func (st *Stream) Close() {
+	st.closeOnce.Do(func() { st.hub.unsubscribe(st.us, st.sub) })
+}
+
+// Next 返回下一个要发送给客户端的事件。为了不为心跳单独启动 goroutine，
+// tick 触发时返回 nil 事件，调用方据此发送心跳
+func (st *Stream) Next(ctx context.Context, tick <-chan time.Time) (*todopb.TodoEvent, error) {
+	for {
+		if len(st.pending) > 0 {
+			event := st.pending[0]
+			st.pending = st.pending[1:]
+			return event, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick:
+			return nil, nil
+		case <-st.sub.lagged:
+			if st.last == "" {
+				// 丢弃的事件中可能包含 READY，以上游当前位置作为起点
+				if st.last = st.hub.token(st.us); st.last == "" {
+					continue
+				}
+			}
+			if err := st.catchUp(ctx); err != nil {
+				return nil, err
+			}
+		case event := <-st.sub.events:
+			switch event.Type {
+			case todopb.TodoEvent_READY:
+				// 上游 (重新) 就绪的位置晚于本连接时，说明中间可能有遗漏
+				if st.last != "" && compareTokens(event.ResumeToken, st.last) > 0 {
+					if err := st.catchUp(ctx); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				if !st.readySent {
+					st.readySent = true
+					if st.last == "" {
+						st.last = event.ResumeToken
+					}
+					return &todopb.TodoEvent{Type: todopb.TodoEvent_READY, ResumeToken: st.last, OccurredAt: event.OccurredAt}, nil
+				}
+			case todopb.TodoEvent_RESET:
+				st.last = event.ResumeToken
+				return event, nil
+			default:
+				// 尚未就绪的连接由客户端在 ready 之后加载完整列表，无需之前的事件
+				if st.last == "" || compareTokens(event.ResumeToken, st.last) <= 0 {
+					continue
+				}
+				st.last = event.ResumeToken
+				return event, nil
+			}
+		}
+	}
+}
+
+// catchUp 通过一次短暂的 WatchTodos 调用补读 st.last 之后的事件，放入待发送队列
+func (st *Stream) catchUp(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, catchUpTimeout)
+	defer cancel()
+	stream, err := st.hub.client.WatchTodos(ctx, &todopb.WatchTodosRequest{UserId: st.userID, ResumeToken: st.last})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch event.Type {
+		case todopb.TodoEvent_READY:
+			if compareTokens(event.ResumeToken, st.last) > 0 {
+				st.last = event.ResumeToken
+			}
+			if !st.readySent {
+				st.readySent = true
+				st.pending = append(st.pending, &todopb.TodoEvent{Type: todopb.TodoEvent_READY, ResumeToken: st.last, OccurredAt: event.OccurredAt})
+			}
+			return nil
+		case todopb.TodoEvent_RESET:
+			st.last = event.ResumeToken
+			st.pending = append(st.pending, event)
+		default:
+			if compareTokens(event.ResumeToken, st.last) > 0 {
+				st.last = event.ResumeToken
+				st.pending = append(st.pending, event)
+			}
+		}
+	}
+}
+
+func parseToken(token string) (uint64, uint64, bool) {
+	ms, seq, found := strings.Cut(token, "-")
+	if !found {
+		return 0, 0, false
+	}
+	msValue, err1 := strconv.ParseUint(ms, 10, 64)
+	seqValue, err2 := strconv.ParseUint(seq, 10, 64)
+	return msValue, seqValue, err1 == nil && err2 == nil
+}
+
+func validToken(token string) bool {
+	_, _, ok := parseToken(token)
+	return ok
+}
+
+// compareTokens 比较两个恢复令牌的先后，a 早于 b 时返回负数
+func compareTokens(a, b string) int {
+	aMs, aSeq, _ := parseToken(a)
+	bMs, bSeq, _ := parseToken(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	}
+	return 0
+}
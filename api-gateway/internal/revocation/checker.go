@@ -0,0 +1,203 @@
+// Package revocation 检查访问令牌是否已被 user-service 撤销 (登出或修改密码)。
+//
+// 撤销记录由 user-service 写入 Redis：
+//   - revoked_token:<jti> 单个令牌被撤销
+//   - revoked_user:<user_id> 该用户在此时间 (Unix 毫秒) 之前签发的令牌全部失效
+//
+// 检查结果在本地缓存一段时间，避免每个请求都访问 Redis，因此撤销最多延迟一个缓存周期后生效。
+// 从 Redis 读到的撤销记录另外保留到相应令牌过期；Redis 出错后一个缓存周期内不再访问 Redis，
+// 期间按保留的记录拒绝已知被撤销的令牌，其余令牌放行。
+package revocation
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	tokenKeyPrefix = "revoked_token:"
+	userKeyPrefix  = "revoked_user:"
+	// 本地缓存的最大条目数，超出时先清理过期条目，仍然超出则清空
+	maxCacheEntries = 10000
+)
+
+type cacheEntry struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+// userRevocation 用户的 revoked_user 记录，keepUntil 之后可能仍在使用的旧令牌都已过期
+type userRevocation struct {
+	before    int64
+	keepUntil time.Time
+}
+
+// Checker 带本地缓存的令牌撤销检查，可并发使用
+type Checker struct {
+	rdb      *redis.Client
+	cacheTTL time.Duration
+	now      func() time.Time // 测试中可替换
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+	// 从 Redis 读到的撤销记录，Redis 不可用时据此拒绝已知被撤销的令牌
+	revokedTokens map[string]time.Time
+	revokedUsers  map[uint32]userRevocation
+	// Redis 出错后在此之前不再访问 Redis，避免每个请求都等待超时
+	unavailableUntil time.Time
+}
+
+// NewChecker 创建撤销检查，cacheTTL 为检查结果在本地缓存的时间，也是 Redis 出错后暂停访问的时间
+func NewChecker(rdb *redis.Client, cacheTTL time.Duration) *Checker {
+	return &Checker{
+		rdb:           rdb,
+		cacheTTL:      cacheTTL,
+		now:           time.Now,
+		cache:         make(map[string]cacheEntry),
+		revokedTokens: make(map[string]time.Time),
+		revokedUsers:  make(map[uint32]userRevocation),
+	}
+}
+
+// IsRevoked 判断用户 userID 在 issuedAt 签发、expiresAt 过期的令牌 jti 是否已被撤销。
+// 旧版本签发的令牌没有 jti，只按签发时间检查。Redis 不可用时只拒绝本地记录中已被撤销的令牌，
+// 其余令牌放行，令牌签名和有效期仍然有效
+func (c *Checker) IsRevoked(ctx context.Context, jti string, userID uint32, issuedAt, expiresAt time.Time) bool {
+	cacheKey := jti
+	if cacheKey == "" {
+		cacheKey = strconv.FormatUint(uint64(userID), 10) + "@" + strconv.FormatInt(issuedAt.UnixMilli(), 10)
+	}
+	now := c.now()
+	if revoked, ok := c.cached(cacheKey, now); ok {
+		return revoked
+	}
+	if c.unavailable(now) {
+		return c.knownRevoked(jti, userID, issuedAt, now)
+	}
+
+	keys := []string{userKeyPrefix + strconv.FormatUint(uint64(userID), 10)}
+	if jti != "" {
+		keys = append(keys, tokenKeyPrefix+jti)
+	}
+	values, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.markUnavailable(err, now)
+		return c.knownRevoked(jti, userID, issuedAt, now)
+	}
+	// 令牌没有过期时间时，撤销记录至少保留一个缓存周期
+	keepUntil := expiresAt
+	if keepUntil.Before(now.Add(c.cacheTTL)) {
+		keepUntil = now.Add(c.cacheTTL)
+	}
+	revoked := false
+	if before, ok := values[0].(string); ok {
+		if ms, err := strconv.ParseInt(before, 10, 64); err == nil {
+			c.rememberUser(userID, ms, keepUntil)
+			revoked = issuedAt.UnixMilli() < ms
+		}
+	}
+	if len(values) > 1 && values[1] != nil {
+		c.rememberToken(jti, keepUntil)
+		revoked = true
+	}
+	c.store(cacheKey, revoked, now)
+	return revoked
+}
+
+func (c *Checker) unavailable(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return now.Before(c.unavailableUntil)
+}
+
+func (c *Checker) markUnavailable(err error, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Before(c.unavailableUntil) {
+		return
+	}
+	c.unavailableUntil = now.Add(c.cacheTTL)
+	log.Printf("警告: 检查令牌撤销状态失败: %v。%s 内只按本地记录拒绝已撤销的令牌，其余令牌放行。", err, c.cacheTTL)
+}
+
+// knownRevoked 按本地保留的撤销记录判断令牌是否已被撤销
+func (c *Checker) knownRevoked(jti string, userID uint32, issuedAt, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if jti != "" {
+		if keepUntil, ok := c.revokedTokens[jti]; ok && now.Before(keepUntil) {
+			return true
+		}
+	}
+	user, ok := c.revokedUsers[userID]
+	return ok && now.Before(user.keepUntil) && issuedAt.UnixMilli() < user.before
+}
+
+func (c *Checker) rememberToken(jti string, keepUntil time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if keepUntil.After(c.revokedTokens[jti]) {
+		c.revokedTokens[jti] = keepUntil
+	}
+	if len(c.revokedTokens) >= maxCacheEntries {
+		now := c.now()
+		for k, until := range c.revokedTokens {
+			if !now.Before(until) {
+				delete(c.revokedTokens, k)
+			}
+		}
+	}
+}
+
+// rememberUser 记录用户最新的 revoked_user 时间，保留到用户在这之前签发的令牌中最晚过期的一个
+func (c *Checker) rememberUser(userID uint32, before int64, keepUntil time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	user := c.revokedUsers[userID]
+	if before > user.before {
+		user.before = before
+	}
+	if keepUntil.After(user.keepUntil) {
+		user.keepUntil = keepUntil
+	}
+	c.revokedUsers[userID] = user
+	if len(c.revokedUsers) >= maxCacheEntries {
+		now := c.now()
+		for k, u := range c.revokedUsers {
+			if !now.Before(u.keepUntil) {
+				delete(c.revokedUsers, k)
+			}
+		}
+	}
+}
+
+func (c *Checker) cached(key string, now time.Time) (revoked, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.cache[key]
+	if !ok || now.After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.revoked, true
+}
+
+func (c *Checker) store(key string, revoked bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cache) >= maxCacheEntries {
+		for k, entry := range c.cache {
+			if now.After(entry.expiresAt) {
+				delete(c.cache, k)
+			}
+		}
+		if len(c.cache) >= maxCacheEntries {
+			c.cache = make(map[string]cacheEntry)
+		}
+	}
+	c.cache[key] = cacheEntry{revoked: revoked, expiresAt: now.Add(c.cacheTTL)}
+}
@@ -0,0 +1,165 @@
+package revocation
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+const testCacheTTL = 5 * time.Second
+
+// testClock 可手动推进的时钟
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// newTestChecker 创建连接到 miniredis、使用 testClock 的撤销检查
+func newTestChecker(t *testing.T) (*Checker, *miniredis.Miniredis, *testClock) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	clock := &testClock{t: time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)}
+	c := NewChecker(rdb, testCacheTTL)
+	c.now = clock.now
+	return c, mr, clock
+}
+
+func revokeUser(mr *miniredis.Miniredis, userID uint32, before time.Time) {
+	mr.Set(userKeyPrefix+strconv.FormatUint(uint64(userID), 10), strconv.FormatInt(before.UnixMilli(), 10))
+}
+
+func TestIsRevoked(t *testing.T) {
+	ctx := context.Background()
+	// 用户 2 在 revokedAt 修改了密码，令牌 jti-revoked 已登出
+	revokedAt := time.Date(2026, time.March, 20, 11, 0, 0, 0, time.UTC).Add(123 * time.Millisecond)
+	tests := []struct {
+		name     string
+		jti      string
+		userID   uint32
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "没有撤销记录", jti: "jti-ok", userID: 1, issuedAt: revokedAt, want: false},
+		{name: "令牌已撤销", jti: "jti-revoked", userID: 1, issuedAt: revokedAt, want: true},
+		{name: "撤销时间之前签发", jti: "jti-a", userID: 2, issuedAt: revokedAt.Add(-time.Millisecond), want: true},
+		{name: "撤销时间同一毫秒签发", jti: "jti-b", userID: 2, issuedAt: revokedAt, want: false},
+		{name: "撤销时间之后签发", jti: "jti-c", userID: 2, issuedAt: revokedAt.Add(time.Millisecond), want: false},
+		{name: "撤销时间同一秒但更早签发", jti: "jti-d", userID: 2, issuedAt: revokedAt.Truncate(time.Second), want: true},
+		{name: "其他用户不受影响", jti: "jti-e", userID: 3, issuedAt: revokedAt.Add(-time.Hour), want: false},
+		{name: "旧令牌没有 jti，撤销时间之前签发", userID: 2, issuedAt: revokedAt.Add(-time.Second), want: true},
+		{name: "旧令牌没有 jti，撤销时间之后签发", userID: 2, issuedAt: revokedAt.Add(time.Second), want: false},
+		{name: "旧令牌没有 jti，没有撤销记录", userID: 1, issuedAt: revokedAt, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mr, clock := newTestChecker(t)
+			mr.Set(tokenKeyPrefix+"jti-revoked", "1")
+			// 空 jti 对应的键不应被读取
+			mr.Set(tokenKeyPrefix, "1")
+			revokeUser(mr, 2, revokedAt)
+			if got := c.IsRevoked(ctx, tt.jti, tt.userID, tt.issuedAt, clock.now().Add(time.Hour)); got != tt.want {
+				t.Errorf("IsRevoked = %t, 期望 %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRevokedCachesResult(t *testing.T) {
+	ctx := context.Background()
+	c, mr, clock := newTestChecker(t)
+	issuedAt := clock.now().Add(-time.Minute)
+	expiresAt := clock.now().Add(time.Hour)
+
+	if c.IsRevoked(ctx, "jti-1", 1, issuedAt, expiresAt) {
+		t.Fatal("没有撤销记录时 IsRevoked 为 true")
+	}
+	mr.Set(tokenKeyPrefix+"jti-1", "1")
+	revokeUser(mr, 2, clock.now())
+
+	// 缓存周期内沿用上次的结果，旧令牌按 user_id 和 iat 缓存
+	clock.advance(testCacheTTL - time.Second)
+	if c.IsRevoked(ctx, "jti-1", 1, issuedAt, expiresAt) {
+		t.Error("缓存周期内重新读取了 Redis")
+	}
+	// 缓存过期后重新读取 Redis
+	clock.advance(2 * time.Second)
+	if !c.IsRevoked(ctx, "jti-1", 1, issuedAt, expiresAt) {
+		t.Error("缓存过期后仍未发现令牌已撤销")
+	}
+	// 没有缓存的旧令牌立即读取 Redis
+	if !c.IsRevoked(ctx, "", 2, issuedAt, expiresAt) {
+		t.Error("旧令牌未按 revoked_user 撤销")
+	}
+}
+
+func TestIsRevokedFallsBackToKnownRevocations(t *testing.T) {
+	ctx := context.Background()
+	c, mr, clock := newTestChecker(t)
+	issuedAt := clock.now().Add(-time.Minute)
+	expiresAt := clock.now().Add(time.Minute)
+	mr.Set(tokenKeyPrefix+"jti-revoked", "1")
+	revokeUser(mr, 2, clock.now())
+
+	// 从 Redis 读到的撤销记录保留在本地
+	if !c.IsRevoked(ctx, "jti-revoked", 1, issuedAt, expiresAt) || !c.IsRevoked(ctx, "jti-2", 2, issuedAt, expiresAt) {
+		t.Fatal("Redis 中的撤销记录没有生效")
+	}
+
+	mr.Close()
+	clock.advance(testCacheTTL + time.Second)
+	tests := []struct {
+		name     string
+		jti      string
+		userID   uint32
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "已知被撤销的令牌", jti: "jti-revoked", userID: 1, issuedAt: issuedAt, want: true},
+		{name: "已知被撤销的用户的其他旧令牌", jti: "jti-3", userID: 2, issuedAt: issuedAt, want: true},
+		{name: "已知被撤销的用户的没有 jti 的旧令牌", userID: 2, issuedAt: issuedAt, want: true},
+		{name: "已知被撤销的用户撤销后签发的令牌", jti: "jti-4", userID: 2, issuedAt: clock.now(), want: false},
+		{name: "没有本地记录的令牌放行", jti: "jti-5", userID: 3, issuedAt: issuedAt, want: false},
+	}
+	for _, tt := range tests {
+		if got := c.IsRevoked(ctx, tt.jti, tt.userID, tt.issuedAt, expiresAt); got != tt.want {
+			t.Errorf("Redis 不可用时 %s: IsRevoked = %t, 期望 %t", tt.name, got, tt.want)
+		}
+	}
+
+	// 出错后一个缓存周期内不再访问 Redis，即使 Redis 已经恢复
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	mr.Set(tokenKeyPrefix+"jti-6", "1")
+	if c.IsRevoked(ctx, "jti-6", 3, issuedAt, expiresAt) {
+		t.Error("暂停访问期间读取了 Redis")
+	}
+	clock.advance(testCacheTTL)
+	if !c.IsRevoked(ctx, "jti-6", 3, issuedAt, expiresAt) {
+		t.Error("暂停期结束后没有重新读取 Redis")
+	}
+}
+
+func TestKnownRevocationsExpireWithTokens(t *testing.T) {
+	ctx := context.Background()
+	c, mr, clock := newTestChecker(t)
+	issuedAt := clock.now().Add(-time.Minute)
+	expiresAt := clock.now().Add(time.Minute)
+	mr.Set(tokenKeyPrefix+"jti-revoked", "1")
+	revokeUser(mr, 2, clock.now())
+	c.IsRevoked(ctx, "jti-revoked", 1, issuedAt, expiresAt)
+	c.IsRevoked(ctx, "jti-2", 2, issuedAt, expiresAt)
+
+	// 令牌过期后不再保留撤销记录，过期的令牌本身会被签名校验拒绝
+	mr.Close()
+	clock.advance(2 * time.Minute)
+	if c.IsRevoked(ctx, "jti-revoked", 1, issuedAt, expiresAt) || c.IsRevoked(ctx, "jti-2", 2, issuedAt, expiresAt) {
+		t.Error("令牌过期后仍保留撤销记录")
+	}
+}
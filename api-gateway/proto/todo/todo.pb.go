@@ -13,6 +13,7 @@ package todo
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -27,6 +28,117 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 优先级
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0 // 无优先级
+	Priority_PRIORITY_LOW         Priority = 1
+	Priority_PRIORITY_MEDIUM      Priority = 2
+	Priority_PRIORITY_HIGH        Priority = 3
+	Priority_PRIORITY_URGENT      Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_MEDIUM",
+		3: "PRIORITY_HIGH",
+		4: "PRIORITY_URGENT",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_LOW":         1,
+		"PRIORITY_MEDIUM":      2,
+		"PRIORITY_HIGH":        3,
+		"PRIORITY_URGENT":      4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[0]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{0}
+}
+
+type Recurrence_Frequency int32
+
+const (
+	Recurrence_FREQUENCY_UNSPECIFIED Recurrence_Frequency = 0
+	Recurrence_DAILY                 Recurrence_Frequency = 1
+	Recurrence_WEEKLY                Recurrence_Frequency = 2
+	Recurrence_MONTHLY               Recurrence_Frequency = 3
+	Recurrence_YEARLY                Recurrence_Frequency = 4
+)
+
+// Enum value maps for Recurrence_Frequency.
+var (
+	Recurrence_Frequency_name = map[int32]string{
+		0: "FREQUENCY_UNSPECIFIED",
+		1: "DAILY",
+		2: "WEEKLY",
+		3: "MONTHLY",
+		4: "YEARLY",
+	}
+	Recurrence_Frequency_value = map[string]int32{
+		"FREQUENCY_UNSPECIFIED": 0,
+		"DAILY":                 1,
+		"WEEKLY":                2,
+		"MONTHLY":               3,
+		"YEARLY":                4,
+	}
+)
+
+func (x Recurrence_Frequency) Enum() *Recurrence_Frequency {
+	p := new(Recurrence_Frequency)
+	*p = x
+	return p
+}
+
+func (x Recurrence_Frequency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Recurrence_Frequency) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (Recurrence_Frequency) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[1]
+}
+
+func (x Recurrence_Frequency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Recurrence_Frequency.Descriptor instead.
+func (Recurrence_Frequency) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1, 0}
+}
+
 type BatchUpdateTodosRequest_ActionType int32
 
 const (
@@ -60,11 +172,11 @@ func (x BatchUpdateTodosRequest_ActionType) String() string {
 }
 
 func (BatchUpdateTodosRequest_ActionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_todo_proto_enumTypes[0].Descriptor()
+	return file_todo_proto_enumTypes[2].Descriptor()
 }
 
 func (BatchUpdateTodosRequest_ActionType) Type() protoreflect.EnumType {
-	return &file_todo_proto_enumTypes[0]
+	return &file_todo_proto_enumTypes[2]
 }
 
 func (x BatchUpdateTodosRequest_ActionType) Number() protoreflect.EnumNumber {
@@ -73,7 +185,224 @@ func (x BatchUpdateTodosRequest_ActionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use BatchUpdateTodosRequest_ActionType.Descriptor instead.
 func (BatchUpdateTodosRequest_ActionType) EnumDescriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{7, 0}
+	return file_todo_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type GetTimeReportRequest_GroupBy int32
+
+const (
+	GetTimeReportRequest_GROUP_BY_UNSPECIFIED GetTimeReportRequest_GroupBy = 0 // 按天
+	GetTimeReportRequest_DAY                  GetTimeReportRequest_GroupBy = 1
+	GetTimeReportRequest_TAG                  GetTimeReportRequest_GroupBy = 2 // 有多个标签的 Todo 的时长会计入每个标签
+	GetTimeReportRequest_PROJECT              GetTimeReportRequest_GroupBy = 3 // 项目为 Todo 所在任务树的根任务
+)
+
+// Enum value maps for GetTimeReportRequest_GroupBy.
+var (
+	GetTimeReportRequest_GroupBy_name = map[int32]string{
+		0: "GROUP_BY_UNSPECIFIED",
+		1: "DAY",
+		2: "TAG",
+		3: "PROJECT",
+	}
+	GetTimeReportRequest_GroupBy_value = map[string]int32{
+		"GROUP_BY_UNSPECIFIED": 0,
+		"DAY":                  1,
+		"TAG":                  2,
+		"PROJECT":              3,
+	}
+)
+
+func (x GetTimeReportRequest_GroupBy) Enum() *GetTimeReportRequest_GroupBy {
+	p := new(GetTimeReportRequest_GroupBy)
+	*p = x
+	return p
+}
+
+func (x GetTimeReportRequest_GroupBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetTimeReportRequest_GroupBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (GetTimeReportRequest_GroupBy) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[3]
+}
+
+func (x GetTimeReportRequest_GroupBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetTimeReportRequest_GroupBy.Descriptor instead.
+func (GetTimeReportRequest_GroupBy) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{46, 0}
+}
+
+type TodoEvent_Type int32
+
+const (
+	TodoEvent_TYPE_UNSPECIFIED TodoEvent_Type = 0
+	TodoEvent_READY            TodoEvent_Type = 1 // 补发完成，之后为实时事件。首次订阅的客户端应在收到 READY 后再加载完整列表
+	TodoEvent_CREATED          TodoEvent_Type = 2
+	TodoEvent_UPDATED          TodoEvent_Type = 3 // 包括完成、归档、子任务移动等所有修改
+	TodoEvent_DELETED          TodoEvent_Type = 4 // 只有 todo_id，没有 todo
+	TodoEvent_RESET            TodoEvent_Type = 5 // resume_token 之前的事件已被清理，客户端需要重新加载完整列表
+)
+
+// Enum value maps for TodoEvent_Type.
+var (
+	TodoEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "READY",
+		2: "CREATED",
+		3: "UPDATED",
+		4: "DELETED",
+		5: "RESET",
+	}
+	TodoEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"READY":            1,
+		"CREATED":          2,
+		"UPDATED":          3,
+		"DELETED":          4,
+		"RESET":            5,
+	}
+)
+
+func (x TodoEvent_Type) Enum() *TodoEvent_Type {
+	p := new(TodoEvent_Type)
+	*p = x
+	return p
+}
+
+func (x TodoEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TodoEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[4].Descriptor()
+}
+
+func (TodoEvent_Type) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[4]
+}
+
+func (x TodoEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TodoEvent_Type.Descriptor instead.
+func (TodoEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{53, 0}
+}
+
+type SyncMutation_Op int32
+
+const (
+	SyncMutation_OP_UNSPECIFIED SyncMutation_Op = 0
+	SyncMutation_CREATE         SyncMutation_Op = 1
+	SyncMutation_UPDATE         SyncMutation_Op = 2
+	SyncMutation_DELETE         SyncMutation_Op = 3
+)
+
+// Enum value maps for SyncMutation_Op.
+var (
+	SyncMutation_Op_name = map[int32]string{
+		0: "OP_UNSPECIFIED",
+		1: "CREATE",
+		2: "UPDATE",
+		3: "DELETE",
+	}
+	SyncMutation_Op_value = map[string]int32{
+		"OP_UNSPECIFIED": 0,
+		"CREATE":         1,
+		"UPDATE":         2,
+		"DELETE":         3,
+	}
+)
+
+func (x SyncMutation_Op) Enum() *SyncMutation_Op {
+	p := new(SyncMutation_Op)
+	*p = x
+	return p
+}
+
+func (x SyncMutation_Op) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyncMutation_Op) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[5].Descriptor()
+}
+
+func (SyncMutation_Op) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[5]
+}
+
+func (x SyncMutation_Op) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyncMutation_Op.Descriptor instead.
+func (SyncMutation_Op) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{55, 0}
+}
+
+type SyncMutationResult_Status int32
+
+const (
+	SyncMutationResult_STATUS_UNSPECIFIED SyncMutationResult_Status = 0
+	SyncMutationResult_APPLIED            SyncMutationResult_Status = 1
+	SyncMutationResult_DUPLICATE          SyncMutationResult_Status = 2 // 相同 client_id 的 CREATE 已经应用过
+	SyncMutationResult_CONFLICT           SyncMutationResult_Status = 3 // 与服务端的修改冲突，服务端版本保留，最新状态见 SyncTodosResponse
+	SyncMutationResult_REJECTED           SyncMutationResult_Status = 4 // 修改无效，message 中说明原因
+)
+
+// Enum value maps for SyncMutationResult_Status.
+var (
+	SyncMutationResult_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "APPLIED",
+		2: "DUPLICATE",
+		3: "CONFLICT",
+		4: "REJECTED",
+	}
+	SyncMutationResult_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"APPLIED":            1,
+		"DUPLICATE":          2,
+		"CONFLICT":           3,
+		"REJECTED":           4,
+	}
+)
+
+func (x SyncMutationResult_Status) Enum() *SyncMutationResult_Status {
+	p := new(SyncMutationResult_Status)
+	*p = x
+	return p
+}
+
+func (x SyncMutationResult_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyncMutationResult_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[6].Descriptor()
+}
+
+func (SyncMutationResult_Status) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[6]
+}
+
+func (x SyncMutationResult_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyncMutationResult_Status.Descriptor instead.
+func (SyncMutationResult_Status) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{56, 0}
 }
 
 // Todo 消息结构
@@ -86,6 +415,17 @@ type Todo struct {
 	Completed     bool                   `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ParentId      uint32                 `protobuf:"varint,8,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`          // 父任务 ID，0 表示顶层任务
+	DueAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`                    // 截止时间，未设置表示无截止时间
+	Tags          []string               `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`                                  // 标签
+	Checklist     []*ChecklistItem       `protobuf:"bytes,11,rep,name=checklist,proto3" json:"checklist,omitempty"`                        // 检查清单
+	Priority      Priority               `protobuf:"varint,12,opt,name=priority,proto3,enum=todo.Priority" json:"priority,omitempty"`      // 优先级
+	Recurrence    *Recurrence            `protobuf:"bytes,13,opt,name=recurrence,proto3" json:"recurrence,omitempty"`                      // 重复规则，未设置表示不重复
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"` // 完成时间，未完成时不设置
+	ArchivedAt    *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`    // 归档时间，未归档时不设置
+	TrackedTime   *durationpb.Duration   `protobuf:"bytes,16,opt,name=tracked_time,json=trackedTime,proto3" json:"tracked_time,omitempty"` // 已结束的时间记录的总时长，不含正在运行的计时器
+	ChangeSeq     uint64                 `protobuf:"varint,17,opt,name=change_seq,json=changeSeq,proto3" json:"change_seq,omitempty"`      // 最近一次修改时分配的变更序号，离线同步时作为 base_change_seq
+	ClientId      string                 `protobuf:"bytes,18,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`          // 通过 SyncTodos 离线创建时客户端生成的 ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,90 +509,3387 @@ func (x *Todo) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// 创建 Todo 请求
-type CreateTodoRequest struct {
+func (x *Todo) GetParentId() uint32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Todo) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *Todo) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Todo) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *Todo) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Todo) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *Todo) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetTrackedTime() *durationpb.Duration {
+	if x != nil {
+		return x.TrackedTime
+	}
+	return nil
+}
+
+func (x *Todo) GetChangeSeq() uint64 {
+	if x != nil {
+		return x.ChangeSeq
+	}
+	return 0
+}
+
+func (x *Todo) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+// 重复规则
+type Recurrence struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"` // 'completed' 默认为 false
+	Frequency     Recurrence_Frequency   `protobuf:"varint,1,opt,name=frequency,proto3,enum=todo.Recurrence_Frequency" json:"frequency,omitempty"`
+	Interval      uint32                 `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"` // 每隔几个周期重复一次，0 视为 1
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTodoRequest) Reset() {
-	*x = CreateTodoRequest{}
+func (x *Recurrence) Reset() {
+	*x = Recurrence{}
 	mi := &file_todo_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTodoRequest) String() string {
+func (x *Recurrence) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTodoRequest) ProtoMessage() {}
+func (*Recurrence) ProtoMessage() {}
 
-func (x *CreateTodoRequest) ProtoReflect() protoreflect.Message {
+func (x *Recurrence) ProtoReflect() protoreflect.Message {
 	mi := &file_todo_proto_msgTypes[1]
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recurrence.ProtoReflect.Descriptor instead.
+func (*Recurrence) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Recurrence) GetFrequency() Recurrence_Frequency {
+	if x != nil {
+		return x.Frequency
+	}
+	return Recurrence_FREQUENCY_UNSPECIFIED
+}
+
+func (x *Recurrence) GetInterval() uint32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+// 检查清单条目
+type ChecklistItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistItem) Reset() {
+	*x = ChecklistItem{}
+	mi := &file_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistItem) ProtoMessage() {}
+
+func (x *ChecklistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItem.ProtoReflect.Descriptor instead.
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChecklistItem) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+// 创建 Todo 请求
+type CreateTodoRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// 'completed' 默认为 false
+	ParentId      uint32                 `protobuf:"varint,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 可选，父任务 ID
+	DueAt         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`           // 可选，截止时间
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Checklist     []*ChecklistItem       `protobuf:"bytes,7,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	Priority      Priority               `protobuf:"varint,8,opt,name=priority,proto3,enum=todo.Priority" json:"priority,omitempty"` // 可选，优先级
+	Recurrence    *Recurrence            `protobuf:"bytes,9,opt,name=recurrence,proto3" json:"recurrence,omitempty"`                 // 可选，重复规则
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTodoRequest) Reset() {
+	*x = CreateTodoRequest{}
+	mi := &file_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoRequest) ProtoMessage() {}
+
+func (x *CreateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateTodoRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetParentId() uint32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateTodoRequest) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *CreateTodoRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateTodoRequest) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *CreateTodoRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateTodoRequest) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+// 获取用户所有 Todo 请求 (需要用户 ID)
+type GetTodosRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                            // 需要从认证信息中获取
+	IncludeArchived bool                   `protobuf:"varint,2,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // 是否包含已归档的 Todo，默认不包含
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetTodosRequest) Reset() {
+	*x = GetTodosRequest{}
+	mi := &file_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodosRequest) ProtoMessage() {}
+
+func (x *GetTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodosRequest.ProtoReflect.Descriptor instead.
+func (*GetTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTodosRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetTodosRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+// 获取用户所有 Todo 响应
+type GetTodosResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"` // 返回 Todo 列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodosResponse) Reset() {
+	*x = GetTodosResponse{}
+	mi := &file_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodosResponse) ProtoMessage() {}
+
+func (x *GetTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodosResponse.ProtoReflect.Descriptor instead.
+func (*GetTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetTodosResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+// 获取单个 Todo 请求
+type GetTodoByIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoByIDRequest) Reset() {
+	*x = GetTodoByIDRequest{}
+	mi := &file_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoByIDRequest) ProtoMessage() {}
+
+func (x *GetTodoByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoByIDRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoByIDRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetTodoByIDRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetTodoByIDRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+// 更新 Todo 请求
+type UpdateTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"` // 发送需要更新的字段
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Completed     bool                   `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoRequest) Reset() {
+	*x = UpdateTodoRequest{}
+	mi := &file_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoRequest) ProtoMessage() {}
+
+func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+// 删除 Todo 请求
+type DeleteTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoRequest) Reset() {
+	*x = DeleteTodoRequest{}
+	mi := &file_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoRequest) ProtoMessage() {}
+
+func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteTodoRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+// --- 新增：批量更新 Todos 请求 --- //
+type BatchUpdateTodosRequest struct {
+	state         protoimpl.MessageState             `protogen:"open.v1"`
+	UserId        uint32                             `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                // 需要从认证信息中获取，用于权限检查
+	TodoIds       []uint32                           `protobuf:"varint,2,rep,packed,name=todo_ids,json=todoIds,proto3" json:"todo_ids,omitempty"`                      // 要操作的 Todo ID 列表
+	Action        BatchUpdateTodosRequest_ActionType `protobuf:"varint,3,opt,name=action,proto3,enum=todo.BatchUpdateTodosRequest_ActionType" json:"action,omitempty"` // 执行的具体操作
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateTodosRequest) Reset() {
+	*x = BatchUpdateTodosRequest{}
+	mi := &file_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateTodosRequest) ProtoMessage() {}
+
+func (x *BatchUpdateTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateTodosRequest.ProtoReflect.Descriptor instead.
+func (*BatchUpdateTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchUpdateTodosRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *BatchUpdateTodosRequest) GetTodoIds() []uint32 {
+	if x != nil {
+		return x.TodoIds
+	}
+	return nil
+}
+
+func (x *BatchUpdateTodosRequest) GetAction() BatchUpdateTodosRequest_ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return BatchUpdateTodosRequest_ACTION_TYPE_UNSPECIFIED
+}
+
+// 模板条目，条目之间通过 parent_item_id 组成一棵树
+type TemplateItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentItemId  uint32                 `protobuf:"varint,2,opt,name=parent_item_id,json=parentItemId,proto3" json:"parent_item_id,omitempty"` // 0 表示根条目
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	DueOffset     *durationpb.Duration   `protobuf:"bytes,5,opt,name=due_offset,json=dueOffset,proto3" json:"due_offset,omitempty"` // 相对实例化基准时间的截止偏移，未设置表示无截止时间
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Checklist     []*ChecklistItem       `protobuf:"bytes,7,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemplateItem) Reset() {
+	*x = TemplateItem{}
+	mi := &file_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemplateItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateItem) ProtoMessage() {}
+
+func (x *TemplateItem) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateItem.ProtoReflect.Descriptor instead.
+func (*TemplateItem) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TemplateItem) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TemplateItem) GetParentItemId() uint32 {
+	if x != nil {
+		return x.ParentItemId
+	}
+	return 0
+}
+
+func (x *TemplateItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TemplateItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TemplateItem) GetDueOffset() *durationpb.Duration {
+	if x != nil {
+		return x.DueOffset
+	}
+	return nil
+}
+
+func (x *TemplateItem) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TemplateItem) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+// Todo 模板
+type TodoTemplate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 所属用户 ID
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Items         []*TemplateItem        `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"` // 按树的先序遍历顺序排列，第一个为根条目
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoTemplate) Reset() {
+	*x = TodoTemplate{}
+	mi := &file_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoTemplate) ProtoMessage() {}
+
+func (x *TodoTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoTemplate.ProtoReflect.Descriptor instead.
+func (*TodoTemplate) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TodoTemplate) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TodoTemplate) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TodoTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TodoTemplate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TodoTemplate) GetItems() []*TemplateItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *TodoTemplate) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoTemplate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 以已有 Todo 创建模板请求
+type CreateTemplateFromTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"` // 作为模板根条目的 Todo
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                    // 模板名称，为空时使用 Todo 标题
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTemplateFromTodoRequest) Reset() {
+	*x = CreateTemplateFromTodoRequest{}
+	mi := &file_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTemplateFromTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTemplateFromTodoRequest) ProtoMessage() {}
+
+func (x *CreateTemplateFromTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTemplateFromTodoRequest.ProtoReflect.Descriptor instead.
+func (*CreateTemplateFromTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateTemplateFromTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateTemplateFromTodoRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *CreateTemplateFromTodoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTemplateFromTodoRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// 获取用户所有模板请求
+type ListTemplatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTemplatesRequest) Reset() {
+	*x = ListTemplatesRequest{}
+	mi := &file_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesRequest) ProtoMessage() {}
+
+func (x *ListTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListTemplatesRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 获取用户所有模板响应
+type ListTemplatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Templates     []*TodoTemplate        `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTemplatesResponse) Reset() {
+	*x = ListTemplatesResponse{}
+	mi := &file_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesResponse) ProtoMessage() {}
+
+func (x *ListTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListTemplatesResponse) GetTemplates() []*TodoTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+// 获取单个模板请求
+type GetTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TemplateId    uint32                 `protobuf:"varint,2,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplateRequest) Reset() {
+	*x = GetTemplateRequest{}
+	mi := &file_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplateRequest) ProtoMessage() {}
+
+func (x *GetTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplateRequest.ProtoReflect.Descriptor instead.
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTemplateRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetTemplateRequest) GetTemplateId() uint32 {
+	if x != nil {
+		return x.TemplateId
+	}
+	return 0
+}
+
+// 删除模板请求
+type DeleteTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TemplateId    uint32                 `protobuf:"varint,2,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTemplateRequest) Reset() {
+	*x = DeleteTemplateRequest{}
+	mi := &file_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTemplateRequest) ProtoMessage() {}
+
+func (x *DeleteTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTemplateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteTemplateRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteTemplateRequest) GetTemplateId() uint32 {
+	if x != nil {
+		return x.TemplateId
+	}
+	return 0
+}
+
+// 实例化模板请求
+type InstantiateTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TemplateId    uint32                 `protobuf:"varint,2,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	BaseTime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=base_time,json=baseTime,proto3" json:"base_time,omitempty"` // 截止偏移的基准时间，未设置时使用当前时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstantiateTemplateRequest) Reset() {
+	*x = InstantiateTemplateRequest{}
+	mi := &file_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstantiateTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstantiateTemplateRequest) ProtoMessage() {}
+
+func (x *InstantiateTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstantiateTemplateRequest.ProtoReflect.Descriptor instead.
+func (*InstantiateTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InstantiateTemplateRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *InstantiateTemplateRequest) GetTemplateId() uint32 {
+	if x != nil {
+		return x.TemplateId
+	}
+	return 0
+}
+
+func (x *InstantiateTemplateRequest) GetBaseTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BaseTime
+	}
+	return nil
+}
+
+// 实例化模板响应
+type InstantiateTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"` // 新创建的 Todo，按模板条目顺序排列，第一个为根 Todo
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstantiateTemplateResponse) Reset() {
+	*x = InstantiateTemplateResponse{}
+	mi := &file_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstantiateTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstantiateTemplateResponse) ProtoMessage() {}
+
+func (x *InstantiateTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstantiateTemplateResponse.ProtoReflect.Descriptor instead.
+func (*InstantiateTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InstantiateTemplateResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+// 快速添加请求
+type QuickAddTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`      // 需要从认证信息中获取
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                         // 例如 "Pay rent tomorrow 9am !high #home every month"
+	TimeZone      string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA 时区名，例如 "Asia/Shanghai"，为空时使用服务端时区
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuickAddTodoRequest) Reset() {
+	*x = QuickAddTodoRequest{}
+	mi := &file_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddTodoRequest) ProtoMessage() {}
+
+func (x *QuickAddTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddTodoRequest.ProtoReflect.Descriptor instead.
+func (*QuickAddTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QuickAddTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *QuickAddTodoRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *QuickAddTodoRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// 快速添加文本中被识别的片段
+type QuickAddToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`    // date / time / priority / tag / recurrence
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`    // 原文
+	Start         uint32                 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"` // 在原文中的起始位置 (Unicode 码点偏移)
+	End           uint32                 `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`     // 结束位置 (不含)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuickAddToken) Reset() {
+	*x = QuickAddToken{}
+	mi := &file_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddToken) ProtoMessage() {}
+
+func (x *QuickAddToken) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddToken.ProtoReflect.Descriptor instead.
+func (*QuickAddToken) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *QuickAddToken) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *QuickAddToken) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *QuickAddToken) GetStart() uint32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *QuickAddToken) GetEnd() uint32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+// 快速添加文本的解析结果
+type QuickAddInterpretation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	DueAt         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	AllDay        bool                   `protobuf:"varint,3,opt,name=all_day,json=allDay,proto3" json:"all_day,omitempty"` // 只识别到日期，截止时间取当天 23:59
+	Priority      Priority               `protobuf:"varint,4,opt,name=priority,proto3,enum=todo.Priority" json:"priority,omitempty"`
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Recurrence    *Recurrence            `protobuf:"bytes,6,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	Tokens        []*QuickAddToken       `protobuf:"bytes,7,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuickAddInterpretation) Reset() {
+	*x = QuickAddInterpretation{}
+	mi := &file_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddInterpretation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddInterpretation) ProtoMessage() {}
+
+func (x *QuickAddInterpretation) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddInterpretation.ProtoReflect.Descriptor instead.
+func (*QuickAddInterpretation) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *QuickAddInterpretation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *QuickAddInterpretation) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *QuickAddInterpretation) GetAllDay() bool {
+	if x != nil {
+		return x.AllDay
+	}
+	return false
+}
+
+func (x *QuickAddInterpretation) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *QuickAddInterpretation) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *QuickAddInterpretation) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *QuickAddInterpretation) GetTokens() []*QuickAddToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// 快速添加响应
+type QuickAddTodoResponse struct {
+	state          protoimpl.MessageState  `protogen:"open.v1"`
+	Todo           *Todo                   `protobuf:"bytes,1,opt,name=todo,proto3" json:"todo,omitempty"` // 新创建的 Todo
+	Interpretation *QuickAddInterpretation `protobuf:"bytes,2,opt,name=interpretation,proto3" json:"interpretation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QuickAddTodoResponse) Reset() {
+	*x = QuickAddTodoResponse{}
+	mi := &file_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddTodoResponse) ProtoMessage() {}
+
+func (x *QuickAddTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddTodoResponse.ProtoReflect.Descriptor instead.
+func (*QuickAddTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *QuickAddTodoResponse) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
+	}
+	return nil
+}
+
+func (x *QuickAddTodoResponse) GetInterpretation() *QuickAddInterpretation {
+	if x != nil {
+		return x.Interpretation
+	}
+	return nil
+}
+
+// 保存搜索
+type SavedSearch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 所属用户 ID
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"` // 过滤表达式
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedSearch) Reset() {
+	*x = SavedSearch{}
+	mi := &file_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedSearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedSearch) ProtoMessage() {}
+
+func (x *SavedSearch) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedSearch.ProtoReflect.Descriptor instead.
+func (*SavedSearch) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SavedSearch) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SavedSearch) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SavedSearch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SavedSearch) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SavedSearch) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SavedSearch) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 创建保存搜索请求
+type CreateSavedSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                    // 同一用户下名称唯一
+	Query         string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSavedSearchRequest) Reset() {
+	*x = CreateSavedSearchRequest{}
+	mi := &file_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSavedSearchRequest) ProtoMessage() {}
+
+func (x *CreateSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*CreateSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateSavedSearchRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateSavedSearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateSavedSearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// 获取用户所有保存搜索请求
+type ListSavedSearchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSavedSearchesRequest) Reset() {
+	*x = ListSavedSearchesRequest{}
+	mi := &file_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSavedSearchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSavedSearchesRequest) ProtoMessage() {}
+
+func (x *ListSavedSearchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSavedSearchesRequest.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListSavedSearchesRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 获取用户所有保存搜索响应
+type ListSavedSearchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SavedSearches []*SavedSearch         `protobuf:"bytes,1,rep,name=saved_searches,json=savedSearches,proto3" json:"saved_searches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSavedSearchesResponse) Reset() {
+	*x = ListSavedSearchesResponse{}
+	mi := &file_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSavedSearchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSavedSearchesResponse) ProtoMessage() {}
+
+func (x *ListSavedSearchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSavedSearchesResponse.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListSavedSearchesResponse) GetSavedSearches() []*SavedSearch {
+	if x != nil {
+		return x.SavedSearches
+	}
+	return nil
+}
+
+// 获取单个保存搜索请求
+type GetSavedSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SavedSearchId uint32                 `protobuf:"varint,2,opt,name=saved_search_id,json=savedSearchId,proto3" json:"saved_search_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSavedSearchRequest) Reset() {
+	*x = GetSavedSearchRequest{}
+	mi := &file_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSavedSearchRequest) ProtoMessage() {}
+
+func (x *GetSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*GetSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetSavedSearchRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetSavedSearchRequest) GetSavedSearchId() uint32 {
+	if x != nil {
+		return x.SavedSearchId
+	}
+	return 0
+}
+
+// 更新保存搜索请求，名称和表达式均为必填
+type UpdateSavedSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SavedSearchId uint32                 `protobuf:"varint,2,opt,name=saved_search_id,json=savedSearchId,proto3" json:"saved_search_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSavedSearchRequest) Reset() {
+	*x = UpdateSavedSearchRequest{}
+	mi := &file_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSavedSearchRequest) ProtoMessage() {}
+
+func (x *UpdateSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateSavedSearchRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateSavedSearchRequest) GetSavedSearchId() uint32 {
+	if x != nil {
+		return x.SavedSearchId
+	}
+	return 0
+}
+
+func (x *UpdateSavedSearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateSavedSearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// 删除保存搜索请求
+type DeleteSavedSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SavedSearchId uint32                 `protobuf:"varint,2,opt,name=saved_search_id,json=savedSearchId,proto3" json:"saved_search_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSavedSearchRequest) Reset() {
+	*x = DeleteSavedSearchRequest{}
+	mi := &file_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSavedSearchRequest) ProtoMessage() {}
+
+func (x *DeleteSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteSavedSearchRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteSavedSearchRequest) GetSavedSearchId() uint32 {
+	if x != nil {
+		return x.SavedSearchId
+	}
+	return 0
+}
+
+// 执行保存搜索请求
+type RunSavedSearchRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SavedSearchId   uint32                 `protobuf:"varint,2,opt,name=saved_search_id,json=savedSearchId,proto3" json:"saved_search_id,omitempty"`
+	TimeZone        string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`                       // IANA 时区名，决定 today 等相对日期，为空时使用服务端时区
+	IncludeArchived bool                   `protobuf:"varint,4,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // 是否在已归档的 Todo 中搜索，默认不包含
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RunSavedSearchRequest) Reset() {
+	*x = RunSavedSearchRequest{}
+	mi := &file_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSavedSearchRequest) ProtoMessage() {}
+
+func (x *RunSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*RunSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RunSavedSearchRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RunSavedSearchRequest) GetSavedSearchId() uint32 {
+	if x != nil {
+		return x.SavedSearchId
+	}
+	return 0
+}
+
+func (x *RunSavedSearchRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *RunSavedSearchRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+// 执行保存搜索响应
+type RunSavedSearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SavedSearch   *SavedSearch           `protobuf:"bytes,1,opt,name=saved_search,json=savedSearch,proto3" json:"saved_search,omitempty"`
+	Todos         []*Todo                `protobuf:"bytes,2,rep,name=todos,proto3" json:"todos,omitempty"` // 满足条件的 Todo，按 ID 排列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSavedSearchResponse) Reset() {
+	*x = RunSavedSearchResponse{}
+	mi := &file_todo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSavedSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSavedSearchResponse) ProtoMessage() {}
+
+func (x *RunSavedSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSavedSearchResponse.ProtoReflect.Descriptor instead.
+func (*RunSavedSearchResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RunSavedSearchResponse) GetSavedSearch() *SavedSearch {
+	if x != nil {
+		return x.SavedSearch
+	}
+	return nil
+}
+
+func (x *RunSavedSearchResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+// 归档/恢复 Todo 请求
+type ArchiveTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveTodoRequest) Reset() {
+	*x = ArchiveTodoRequest{}
+	mi := &file_todo_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTodoRequest) ProtoMessage() {}
+
+func (x *ArchiveTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTodoRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ArchiveTodoRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ArchiveTodoRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+// 归档/恢复 Todo 响应
+type ArchiveTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"` // 归档状态发生变化的 Todo
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveTodoResponse) Reset() {
+	*x = ArchiveTodoResponse{}
+	mi := &file_todo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTodoResponse) ProtoMessage() {}
+
+func (x *ArchiveTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTodoResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ArchiveTodoResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+// 自动归档策略：已完成超过 archive_after_days 天的 Todo 由后台任务自动归档
+type ArchivePolicy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Enabled          bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ArchiveAfterDays uint32                 `protobuf:"varint,3,opt,name=archive_after_days,json=archiveAfterDays,proto3" json:"archive_after_days,omitempty"`
+	LastRunAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"` // 后台任务最近一次执行的时间
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ArchivePolicy) Reset() {
+	*x = ArchivePolicy{}
+	mi := &file_todo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivePolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePolicy) ProtoMessage() {}
+
+func (x *ArchivePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePolicy.ProtoReflect.Descriptor instead.
+func (*ArchivePolicy) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ArchivePolicy) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ArchivePolicy) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ArchivePolicy) GetArchiveAfterDays() uint32 {
+	if x != nil {
+		return x.ArchiveAfterDays
+	}
+	return 0
+}
+
+func (x *ArchivePolicy) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *ArchivePolicy) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 获取自动归档策略请求
+type GetArchivePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArchivePolicyRequest) Reset() {
+	*x = GetArchivePolicyRequest{}
+	mi := &file_todo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArchivePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArchivePolicyRequest) ProtoMessage() {}
+
+func (x *GetArchivePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArchivePolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetArchivePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetArchivePolicyRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 设置自动归档策略请求
+type UpdateArchivePolicyRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Enabled          bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ArchiveAfterDays uint32                 `protobuf:"varint,3,opt,name=archive_after_days,json=archiveAfterDays,proto3" json:"archive_after_days,omitempty"` // 1-3650，启用时必填
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateArchivePolicyRequest) Reset() {
+	*x = UpdateArchivePolicyRequest{}
+	mi := &file_todo_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateArchivePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateArchivePolicyRequest) ProtoMessage() {}
+
+func (x *UpdateArchivePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateArchivePolicyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateArchivePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UpdateArchivePolicyRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateArchivePolicyRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UpdateArchivePolicyRequest) GetArchiveAfterDays() uint32 {
+	if x != nil {
+		return x.ArchiveAfterDays
+	}
+	return 0
+}
+
+// 时间记录
+type TimeEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TodoId        uint32                 `protobuf:"varint,3,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"` // 正在运行的计时器不设置
+	Duration      *durationpb.Duration   `protobuf:"bytes,6,opt,name=duration,proto3" json:"duration,omitempty"`              // 正在运行的计时器为截至当前的时长
+	Note          string                 `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`
+	Running       bool                   `protobuf:"varint,8,opt,name=running,proto3" json:"running,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeEntry) Reset() {
+	*x = TimeEntry{}
+	mi := &file_todo_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeEntry) ProtoMessage() {}
+
+func (x *TimeEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeEntry.ProtoReflect.Descriptor instead.
+func (*TimeEntry) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TimeEntry) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TimeEntry) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TimeEntry) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *TimeEntry) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TimeEntry) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *TimeEntry) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *TimeEntry) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *TimeEntry) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *TimeEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TimeEntry) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 启动计时器请求
+type StartTimerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	StopRunning   bool                   `protobuf:"varint,4,opt,name=stop_running,json=stopRunning,proto3" json:"stop_running,omitempty"` // 已有正在运行的计时器时先将其停止，否则返回 FAILED_PRECONDITION
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartTimerRequest) Reset() {
+	*x = StartTimerRequest{}
+	mi := &file_todo_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartTimerRequest) ProtoMessage() {}
+
+func (x *StartTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartTimerRequest.ProtoReflect.Descriptor instead.
+func (*StartTimerRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StartTimerRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *StartTimerRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *StartTimerRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *StartTimerRequest) GetStopRunning() bool {
+	if x != nil {
+		return x.StopRunning
+	}
+	return false
+}
+
+// 停止计时器请求
+type StopTimerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopTimerRequest) Reset() {
+	*x = StopTimerRequest{}
+	mi := &file_todo_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopTimerRequest) ProtoMessage() {}
+
+func (x *StopTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopTimerRequest.ProtoReflect.Descriptor instead.
+func (*StopTimerRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *StopTimerRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 获取正在运行的计时器请求
+type GetRunningTimerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunningTimerRequest) Reset() {
+	*x = GetRunningTimerRequest{}
+	mi := &file_todo_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunningTimerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunningTimerRequest) ProtoMessage() {}
+
+func (x *GetRunningTimerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunningTimerRequest.ProtoReflect.Descriptor instead.
+func (*GetRunningTimerRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetRunningTimerRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 手动添加时间记录请求
+type CreateTimeEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // 必填
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`       // 必填，晚于 started_at
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTimeEntryRequest) Reset() {
+	*x = CreateTimeEntryRequest{}
+	mi := &file_todo_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTimeEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTimeEntryRequest) ProtoMessage() {}
+
+func (x *CreateTimeEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTimeEntryRequest.ProtoReflect.Descriptor instead.
+func (*CreateTimeEntryRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateTimeEntryRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateTimeEntryRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *CreateTimeEntryRequest) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *CreateTimeEntryRequest) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *CreateTimeEntryRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// 查询时间记录请求，结果按开始时间排列
+type ListTimeEntriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"` // 可选，只返回指定 Todo 的记录
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                    // 可选，只返回在此之后结束 (或仍在运行) 的记录
+	To            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                        // 可选，只返回在此之前开始的记录
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTimeEntriesRequest) Reset() {
+	*x = ListTimeEntriesRequest{}
+	mi := &file_todo_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTimeEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimeEntriesRequest) ProtoMessage() {}
+
+func (x *ListTimeEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimeEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListTimeEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListTimeEntriesRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListTimeEntriesRequest) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *ListTimeEntriesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListTimeEntriesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// 查询时间记录响应
+type ListTimeEntriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeEntries   []*TimeEntry           `protobuf:"bytes,1,rep,name=time_entries,json=timeEntries,proto3" json:"time_entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTimeEntriesResponse) Reset() {
+	*x = ListTimeEntriesResponse{}
+	mi := &file_todo_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTimeEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTimeEntriesResponse) ProtoMessage() {}
+
+func (x *ListTimeEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTimeEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ListTimeEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListTimeEntriesResponse) GetTimeEntries() []*TimeEntry {
+	if x != nil {
+		return x.TimeEntries
+	}
+	return nil
+}
+
+// 修改时间记录请求。正在运行的计时器只能修改开始时间和备注
+type UpdateTimeEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TimeEntryId   uint32                 `protobuf:"varint,2,opt,name=time_entry_id,json=timeEntryId,proto3" json:"time_entry_id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // 必填
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`       // 已结束的记录必填
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTimeEntryRequest) Reset() {
+	*x = UpdateTimeEntryRequest{}
+	mi := &file_todo_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTimeEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTimeEntryRequest) ProtoMessage() {}
+
+func (x *UpdateTimeEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTimeEntryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTimeEntryRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UpdateTimeEntryRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateTimeEntryRequest) GetTimeEntryId() uint32 {
+	if x != nil {
+		return x.TimeEntryId
+	}
+	return 0
+}
+
+func (x *UpdateTimeEntryRequest) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *UpdateTimeEntryRequest) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *UpdateTimeEntryRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// 删除时间记录请求
+type DeleteTimeEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TimeEntryId   uint32                 `protobuf:"varint,2,opt,name=time_entry_id,json=timeEntryId,proto3" json:"time_entry_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTimeEntryRequest) Reset() {
+	*x = DeleteTimeEntryRequest{}
+	mi := &file_todo_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTimeEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTimeEntryRequest) ProtoMessage() {}
+
+func (x *DeleteTimeEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTimeEntryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTimeEntryRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DeleteTimeEntryRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteTimeEntryRequest) GetTimeEntryId() uint32 {
+	if x != nil {
+		return x.TimeEntryId
+	}
+	return 0
+}
+
+// 时间报表请求
+type GetTimeReportRequest struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	UserId        uint32                       `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          *timestamppb.Timestamp       `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // 必填，时间范围 [from, to)
+	To            *timestamppb.Timestamp       `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // 必填
+	GroupBy       GetTimeReportRequest_GroupBy `protobuf:"varint,4,opt,name=group_by,json=groupBy,proto3,enum=todo.GetTimeReportRequest_GroupBy" json:"group_by,omitempty"`
+	TimeZone      string                       `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA 时区名，决定按天分组的日期边界，为空时使用服务端时区
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimeReportRequest) Reset() {
+	*x = GetTimeReportRequest{}
+	mi := &file_todo_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimeReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimeReportRequest) ProtoMessage() {}
+
+func (x *GetTimeReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimeReportRequest.ProtoReflect.Descriptor instead.
+func (*GetTimeReportRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetTimeReportRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetTimeReportRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetTimeReportRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetTimeReportRequest) GetGroupBy() GetTimeReportRequest_GroupBy {
+	if x != nil {
+		return x.GroupBy
+	}
+	return GetTimeReportRequest_GROUP_BY_UNSPECIFIED
+}
+
+func (x *GetTimeReportRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// 时间报表中的一行
+type TimeReportRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // 日期 (2006-01-02)、标签或项目 ID
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"` // 展示名称
+	Total         *durationpb.Duration   `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	EntryCount    uint32                 `protobuf:"varint,4,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"` // 计入该行的时间记录数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeReportRow) Reset() {
+	*x = TimeReportRow{}
+	mi := &file_todo_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeReportRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeReportRow) ProtoMessage() {}
+
+func (x *TimeReportRow) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeReportRow.ProtoReflect.Descriptor instead.
+func (*TimeReportRow) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *TimeReportRow) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TimeReportRow) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *TimeReportRow) GetTotal() *durationpb.Duration {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *TimeReportRow) GetEntryCount() uint32 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+// 时间报表，只统计时间范围内的部分，正在运行的计时器计算到当前时间
+type TimeReport struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	GroupBy       GetTimeReportRequest_GroupBy `protobuf:"varint,1,opt,name=group_by,json=groupBy,proto3,enum=todo.GetTimeReportRequest_GroupBy" json:"group_by,omitempty"`
+	From          *timestamppb.Timestamp       `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp       `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Rows          []*TimeReportRow             `protobuf:"bytes,4,rep,name=rows,proto3" json:"rows,omitempty"`
+	Total         *durationpb.Duration         `protobuf:"bytes,5,opt,name=total,proto3" json:"total,omitempty"` // 范围内的总时长 (按标签分组时不等于各行之和)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeReport) Reset() {
+	*x = TimeReport{}
+	mi := &file_todo_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeReport) ProtoMessage() {}
+
+func (x *TimeReport) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeReport.ProtoReflect.Descriptor instead.
+func (*TimeReport) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *TimeReport) GetGroupBy() GetTimeReportRequest_GroupBy {
+	if x != nil {
+		return x.GroupBy
+	}
+	return GetTimeReportRequest_GROUP_BY_UNSPECIFIED
+}
+
+func (x *TimeReport) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TimeReport) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TimeReport) GetRows() []*TimeReportRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *TimeReport) GetTotal() *durationpb.Duration {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+type GetTodoStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,2,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA 时区名，决定按天/周统计的日期边界，为空时使用服务端时区
+	Days          uint32                 `protobuf:"varint,3,opt,name=days,proto3" json:"days,omitempty"`                        // 每日完成数的天数 (含今天)，为 0 时为 30，最大 366
+	Weeks         uint32                 `protobuf:"varint,4,opt,name=weeks,proto3" json:"weeks,omitempty"`                      // 每周完成数的周数 (含本周)，为 0 时为 12，最大 104
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoStatsRequest) Reset() {
+	*x = GetTodoStatsRequest{}
+	mi := &file_todo_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoStatsRequest) ProtoMessage() {}
+
+func (x *GetTodoStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoStatsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetTodoStatsRequest) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetTodoStatsRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *GetTodoStatsRequest) GetDays() uint32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *GetTodoStatsRequest) GetWeeks() uint32 {
+	if x != nil {
+		return x.Weeks
+	}
+	return 0
+}
+
+// 某一天或某一周的完成数
+type CompletionCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // 日期 (2006-01-02)，按周统计时为该周的周一
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompletionCount) Reset() {
+	*x = CompletionCount{}
+	mi := &file_todo_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompletionCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompletionCount) ProtoMessage() {}
+
+func (x *CompletionCount) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompletionCount.ProtoReflect.Descriptor instead.
+func (*CompletionCount) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CompletionCount) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *CompletionCount) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 用户的完成情况统计。
+// 与完成时间相关的指标只统计记录了完成时间 (completed_at) 的 Todo，
+// 已归档的 Todo 计入完成数和连续天数，但不计入 open/completed/overdue
+type TodoStats struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Total                 uint32                 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`         // 未归档的 Todo 总数
+	Open                  uint32                 `protobuf:"varint,2,opt,name=open,proto3" json:"open,omitempty"`           // 未归档且未完成
+	Completed             uint32                 `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"` // 未归档且已完成
+	Overdue               uint32                 `protobuf:"varint,4,opt,name=overdue,proto3" json:"overdue,omitempty"`     // 未归档、未完成且已过截止时间
+	Archived              uint32                 `protobuf:"varint,5,opt,name=archived,proto3" json:"archived,omitempty"`
+	CompletionsPerDay     []*CompletionCount     `protobuf:"bytes,6,rep,name=completions_per_day,json=completionsPerDay,proto3" json:"completions_per_day,omitempty"`               // 按日期升序，没有完成的日期计数为 0
+	CompletionsPerWeek    []*CompletionCount     `protobuf:"bytes,7,rep,name=completions_per_week,json=completionsPerWeek,proto3" json:"completions_per_week,omitempty"`            // 按周升序 (周一为一周的第一天)
+	AverageTimeToComplete *durationpb.Duration   `protobuf:"bytes,8,opt,name=average_time_to_complete,json=averageTimeToComplete,proto3" json:"average_time_to_complete,omitempty"` // 从创建到完成的平均时长，没有完成记录时为空
+	CurrentStreak         uint32                 `protobuf:"varint,9,opt,name=current_streak,json=currentStreak,proto3" json:"current_streak,omitempty"`                            // 截至今天连续有完成的天数，今天还没有完成时从昨天算起
+	LongestStreak         uint32                 `protobuf:"varint,10,opt,name=longest_streak,json=longestStreak,proto3" json:"longest_streak,omitempty"`                           // 历史最长连续天数
+	GeneratedAt           *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`                                  // 统计生成时间，命中缓存时早于请求时间
+	TimeZone              string                 `protobuf:"bytes,12,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`                                           // 统计使用的时区
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TodoStats) Reset() {
+	*x = TodoStats{}
+	mi := &file_todo_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoStats) ProtoMessage() {}
+
+func (x *TodoStats) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoStats.ProtoReflect.Descriptor instead.
+func (*TodoStats) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *TodoStats) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *TodoStats) GetOpen() uint32 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *TodoStats) GetCompleted() uint32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *TodoStats) GetOverdue() uint32 {
+	if x != nil {
+		return x.Overdue
+	}
+	return 0
+}
+
+func (x *TodoStats) GetArchived() uint32 {
+	if x != nil {
+		return x.Archived
+	}
+	return 0
+}
+
+func (x *TodoStats) GetCompletionsPerDay() []*CompletionCount {
+	if x != nil {
+		return x.CompletionsPerDay
+	}
+	return nil
+}
+
+func (x *TodoStats) GetCompletionsPerWeek() []*CompletionCount {
+	if x != nil {
+		return x.CompletionsPerWeek
+	}
+	return nil
+}
+
+func (x *TodoStats) GetAverageTimeToComplete() *durationpb.Duration {
+	if x != nil {
+		return x.AverageTimeToComplete
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CreateTodoRequest.ProtoReflect.Descriptor instead.
-func (*CreateTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{1}
+func (x *TodoStats) GetCurrentStreak() uint32 {
+	if x != nil {
+		return x.CurrentStreak
+	}
+	return 0
 }
 
-func (x *CreateTodoRequest) GetUserId() uint32 {
+func (x *TodoStats) GetLongestStreak() uint32 {
 	if x != nil {
-		return x.UserId
+		return x.LongestStreak
 	}
 	return 0
 }
 
-func (x *CreateTodoRequest) GetTitle() string {
+func (x *TodoStats) GetGeneratedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Title
+		return x.GeneratedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateTodoRequest) GetDescription() string {
+func (x *TodoStats) GetTimeZone() string {
 	if x != nil {
-		return x.Description
+		return x.TimeZone
 	}
 	return ""
 }
 
-// 获取用户所有 Todo 请求 (需要用户 ID)
-type GetTodosRequest struct {
+type WatchTodosRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // 上次收到的事件的 resume_token，为空时只接收之后的实时事件
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodosRequest) Reset() {
-	*x = GetTodosRequest{}
-	mi := &file_todo_proto_msgTypes[2]
+func (x *WatchTodosRequest) Reset() {
+	*x = WatchTodosRequest{}
+	mi := &file_todo_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodosRequest) String() string {
+func (x *WatchTodosRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodosRequest) ProtoMessage() {}
+func (*WatchTodosRequest) ProtoMessage() {}
 
-func (x *GetTodosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[2]
+func (x *WatchTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -263,41 +3900,52 @@ func (x *GetTodosRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodosRequest.ProtoReflect.Descriptor instead.
-func (*GetTodosRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use WatchTodosRequest.ProtoReflect.Descriptor instead.
+func (*WatchTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *GetTodosRequest) GetUserId() uint32 {
+func (x *WatchTodosRequest) GetUserId() uint32 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-// 获取用户所有 Todo 响应
-type GetTodosResponse struct {
+func (x *WatchTodosRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// Todo 变更事件
+type TodoEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"` // 返回 Todo 列表
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // 重连时传给 WatchTodosRequest.resume_token
+	Type          TodoEvent_Type         `protobuf:"varint,2,opt,name=type,proto3,enum=todo.TodoEvent_Type" json:"type,omitempty"`
+	TodoId        uint32                 `protobuf:"varint,3,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Todo          *Todo                  `protobuf:"bytes,4,opt,name=todo,proto3" json:"todo,omitempty"` // CREATED/UPDATED 时为变更后的 Todo
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodosResponse) Reset() {
-	*x = GetTodosResponse{}
-	mi := &file_todo_proto_msgTypes[3]
+func (x *TodoEvent) Reset() {
+	*x = TodoEvent{}
+	mi := &file_todo_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodosResponse) String() string {
+func (x *TodoEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodosResponse) ProtoMessage() {}
+func (*TodoEvent) ProtoMessage() {}
 
-func (x *GetTodosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[3]
+func (x *TodoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -308,42 +3956,70 @@ func (x *GetTodosResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodosResponse.ProtoReflect.Descriptor instead.
-func (*GetTodosResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use TodoEvent.ProtoReflect.Descriptor instead.
+func (*TodoEvent) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GetTodosResponse) GetTodos() []*Todo {
+func (x *TodoEvent) GetResumeToken() string {
 	if x != nil {
-		return x.Todos
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *TodoEvent) GetType() TodoEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TodoEvent_TYPE_UNSPECIFIED
+}
+
+func (x *TodoEvent) GetTodoId() uint32 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *TodoEvent) GetTodo() *Todo {
+	if x != nil {
+		return x.Todo
 	}
 	return nil
 }
 
-// 获取单个 Todo 请求
-type GetTodoByIDRequest struct {
+func (x *TodoEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type SyncTodosRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
-	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SyncToken     string                 `protobuf:"bytes,2,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"` // 上次同步返回的 sync_token，为空时返回全部 Todo
+	Mutations     []*SyncMutation        `protobuf:"bytes,3,rep,name=mutations,proto3" json:"mutations,omitempty"`                  // 客户端离线期间的修改，按顺序逐条应用，每条单独成功或失败
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTodoByIDRequest) Reset() {
-	*x = GetTodoByIDRequest{}
-	mi := &file_todo_proto_msgTypes[4]
+func (x *SyncTodosRequest) Reset() {
+	*x = SyncTodosRequest{}
+	mi := &file_todo_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTodoByIDRequest) String() string {
+func (x *SyncTodosRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTodoByIDRequest) ProtoMessage() {}
+func (*SyncTodosRequest) ProtoMessage() {}
 
-func (x *GetTodoByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[4]
+func (x *SyncTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -354,52 +4030,67 @@ func (x *GetTodoByIDRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTodoByIDRequest.ProtoReflect.Descriptor instead.
-func (*GetTodoByIDRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use SyncTodosRequest.ProtoReflect.Descriptor instead.
+func (*SyncTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *GetTodoByIDRequest) GetUserId() uint32 {
+func (x *SyncTodosRequest) GetUserId() uint32 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-func (x *GetTodoByIDRequest) GetTodoId() uint32 {
+func (x *SyncTodosRequest) GetSyncToken() string {
 	if x != nil {
-		return x.TodoId
+		return x.SyncToken
 	}
-	return 0
+	return ""
 }
 
-// 更新 Todo 请求
-type UpdateTodoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
-	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"` // 发送需要更新的字段
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Completed     bool                   `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SyncTodosRequest) GetMutations() []*SyncMutation {
+	if x != nil {
+		return x.Mutations
+	}
+	return nil
 }
 
-func (x *UpdateTodoRequest) Reset() {
-	*x = UpdateTodoRequest{}
-	mi := &file_todo_proto_msgTypes[5]
+// 客户端的一条离线修改。冲突处理规则：
+//   - CREATE 按 client_id 去重，重复提交返回 DUPLICATE 和已创建的 todo_id
+//   - 目标已被删除时删除优先：UPDATE 返回 CONFLICT，DELETE 视为成功
+//   - base_change_seq 小于目标当前的 change_seq 时 (服务端在客户端离线期间修改过)，服务端的版本优先，
+//     UPDATE 和 DELETE 均返回 CONFLICT 且不做任何修改；目标的最近一次修改来自本次请求中前面的修改时不做此检查
+//   - 其他错误 (Todo 不存在、字段无效等) 返回 REJECTED
+type SyncMutation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MutationId     string                 `protobuf:"bytes,1,opt,name=mutation_id,json=mutationId,proto3" json:"mutation_id,omitempty"` // 客户端生成，原样返回到 SyncMutationResult
+	Op             SyncMutation_Op        `protobuf:"varint,2,opt,name=op,proto3,enum=todo.SyncMutation_Op" json:"op,omitempty"`
+	TodoId         uint32                 `protobuf:"varint,3,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`                          // UPDATE/DELETE 的目标
+	ClientId       string                 `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`                     // CREATE 时为新 Todo 的客户端 ID (必填)；UPDATE/DELETE 时可代替 todo_id 引用离线创建的 Todo
+	BaseChangeSeq  uint64                 `protobuf:"varint,5,opt,name=base_change_seq,json=baseChangeSeq,proto3" json:"base_change_seq,omitempty"`   // UPDATE/DELETE 时客户端所看到的目标的 change_seq
+	Todo           *Todo                  `protobuf:"bytes,6,opt,name=todo,proto3" json:"todo,omitempty"`                                             // CREATE/UPDATE 的字段值，只使用 title、description、completed、due_at、tags、checklist、priority、recurrence 和 parent_id
+	UpdateFields   []string               `protobuf:"bytes,7,rep,name=update_fields,json=updateFields,proto3" json:"update_fields,omitempty"`         // UPDATE 要修改的字段名 (如 "title"、"due_at")，为空时修改上述全部字段 (parent_id 除外)
+	ParentClientId string                 `protobuf:"bytes,8,opt,name=parent_client_id,json=parentClientId,proto3" json:"parent_client_id,omitempty"` // CREATE 时父任务为离线创建的 Todo，优先于 todo.parent_id
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncMutation) Reset() {
+	*x = SyncMutation{}
+	mi := &file_todo_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTodoRequest) String() string {
+func (x *SyncMutation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTodoRequest) ProtoMessage() {}
+func (*SyncMutation) ProtoMessage() {}
 
-func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[5]
+func (x *SyncMutation) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,70 +4101,92 @@ func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTodoRequest.ProtoReflect.Descriptor instead.
-func (*UpdateTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SyncMutation.ProtoReflect.Descriptor instead.
+func (*SyncMutation) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *UpdateTodoRequest) GetUserId() uint32 {
+func (x *SyncMutation) GetMutationId() string {
 	if x != nil {
-		return x.UserId
+		return x.MutationId
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdateTodoRequest) GetTodoId() uint32 {
+func (x *SyncMutation) GetOp() SyncMutation_Op {
+	if x != nil {
+		return x.Op
+	}
+	return SyncMutation_OP_UNSPECIFIED
+}
+
+func (x *SyncMutation) GetTodoId() uint32 {
 	if x != nil {
 		return x.TodoId
 	}
 	return 0
 }
 
-func (x *UpdateTodoRequest) GetTitle() string {
+func (x *SyncMutation) GetClientId() string {
 	if x != nil {
-		return x.Title
+		return x.ClientId
 	}
 	return ""
 }
 
-func (x *UpdateTodoRequest) GetDescription() string {
+func (x *SyncMutation) GetBaseChangeSeq() uint64 {
 	if x != nil {
-		return x.Description
+		return x.BaseChangeSeq
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateTodoRequest) GetCompleted() bool {
+func (x *SyncMutation) GetTodo() *Todo {
 	if x != nil {
-		return x.Completed
+		return x.Todo
 	}
-	return false
+	return nil
 }
 
-// 删除 Todo 请求
-type DeleteTodoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 需要从认证信息中获取，用于权限检查
-	TodoId        uint32                 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+func (x *SyncMutation) GetUpdateFields() []string {
+	if x != nil {
+		return x.UpdateFields
+	}
+	return nil
+}
+
+func (x *SyncMutation) GetParentClientId() string {
+	if x != nil {
+		return x.ParentClientId
+	}
+	return ""
+}
+
+type SyncMutationResult struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	MutationId    string                    `protobuf:"bytes,1,opt,name=mutation_id,json=mutationId,proto3" json:"mutation_id,omitempty"`
+	Status        SyncMutationResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=todo.SyncMutationResult_Status" json:"status,omitempty"`
+	TodoId        uint32                    `protobuf:"varint,3,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"` // 目标 Todo 的 ID (CREATE 时为新 Todo 的 ID)
+	Message       string                    `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTodoRequest) Reset() {
-	*x = DeleteTodoRequest{}
-	mi := &file_todo_proto_msgTypes[6]
+func (x *SyncMutationResult) Reset() {
+	*x = SyncMutationResult{}
+	mi := &file_todo_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTodoRequest) String() string {
+func (x *SyncMutationResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTodoRequest) ProtoMessage() {}
+func (*SyncMutationResult) ProtoMessage() {}
 
-func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[6]
+func (x *SyncMutationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -484,50 +4197,65 @@ func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
-func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SyncMutationResult.ProtoReflect.Descriptor instead.
+func (*SyncMutationResult) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *DeleteTodoRequest) GetUserId() uint32 {
+func (x *SyncMutationResult) GetMutationId() string {
 	if x != nil {
-		return x.UserId
+		return x.MutationId
 	}
-	return 0
+	return ""
 }
 
-func (x *DeleteTodoRequest) GetTodoId() uint32 {
+func (x *SyncMutationResult) GetStatus() SyncMutationResult_Status {
+	if x != nil {
+		return x.Status
+	}
+	return SyncMutationResult_STATUS_UNSPECIFIED
+}
+
+func (x *SyncMutationResult) GetTodoId() uint32 {
 	if x != nil {
 		return x.TodoId
 	}
 	return 0
 }
 
-// --- 新增：批量更新 Todos 请求 --- //
-type BatchUpdateTodosRequest struct {
-	state         protoimpl.MessageState             `protogen:"open.v1"`
-	UserId        uint32                             `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                // 需要从认证信息中获取，用于权限检查
-	TodoIds       []uint32                           `protobuf:"varint,2,rep,packed,name=todo_ids,json=todoIds,proto3" json:"todo_ids,omitempty"`                      // 要操作的 Todo ID 列表
-	Action        BatchUpdateTodosRequest_ActionType `protobuf:"varint,3,opt,name=action,proto3,enum=todo.BatchUpdateTodosRequest_ActionType" json:"action,omitempty"` // 执行的具体操作
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SyncMutationResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
 
-func (x *BatchUpdateTodosRequest) Reset() {
-	*x = BatchUpdateTodosRequest{}
-	mi := &file_todo_proto_msgTypes[7]
+type SyncTodosResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SyncToken      string                 `protobuf:"bytes,1,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"`                          // 下次同步时传入
+	Todos          []*Todo                `protobuf:"bytes,2,rep,name=todos,proto3" json:"todos,omitempty"`                                                   // sync_token 之后新建或修改的 Todo (含已归档)，包括本次请求应用的修改
+	DeletedTodoIds []uint32               `protobuf:"varint,3,rep,packed,name=deleted_todo_ids,json=deletedTodoIds,proto3" json:"deleted_todo_ids,omitempty"` // sync_token 之后删除的 Todo
+	Results        []*SyncMutationResult  `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                                               // 与 mutations 一一对应
+	FullResync     bool                   `protobuf:"varint,5,opt,name=full_resync,json=fullResync,proto3" json:"full_resync,omitempty"`                      // 为 true 时 todos 为完整列表，客户端应丢弃本地副本
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncTodosResponse) Reset() {
+	*x = SyncTodosResponse{}
+	mi := &file_todo_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BatchUpdateTodosRequest) String() string {
+func (x *SyncTodosResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BatchUpdateTodosRequest) ProtoMessage() {}
+func (*SyncTodosResponse) ProtoMessage() {}
 
-func (x *BatchUpdateTodosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[7]
+func (x *SyncTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -538,30 +4266,44 @@ func (x *BatchUpdateTodosRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BatchUpdateTodosRequest.ProtoReflect.Descriptor instead.
-func (*BatchUpdateTodosRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SyncTodosResponse.ProtoReflect.Descriptor instead.
+func (*SyncTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *BatchUpdateTodosRequest) GetUserId() uint32 {
+func (x *SyncTodosResponse) GetSyncToken() string {
 	if x != nil {
-		return x.UserId
+		return x.SyncToken
 	}
-	return 0
+	return ""
 }
 
-func (x *BatchUpdateTodosRequest) GetTodoIds() []uint32 {
+func (x *SyncTodosResponse) GetTodos() []*Todo {
 	if x != nil {
-		return x.TodoIds
+		return x.Todos
 	}
 	return nil
 }
 
-func (x *BatchUpdateTodosRequest) GetAction() BatchUpdateTodosRequest_ActionType {
+func (x *SyncTodosResponse) GetDeletedTodoIds() []uint32 {
 	if x != nil {
-		return x.Action
+		return x.DeletedTodoIds
 	}
-	return BatchUpdateTodosRequest_ACTION_TYPE_UNSPECIFIED
+	return nil
+}
+
+func (x *SyncTodosResponse) GetResults() []*SyncMutationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SyncTodosResponse) GetFullResync() bool {
+	if x != nil {
+		return x.FullResync
+	}
+	return false
 }
 
 var File_todo_proto protoreflect.FileDescriptor
@@ -569,7 +4311,7 @@ var File_todo_proto protoreflect.FileDescriptor
 const file_todo_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"todo.proto\x12\x04todo\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xfb\x01\n" +
+	"todo.proto\x12\x04todo\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/duration.proto\"\xe6\x05\n" +
 	"\x04Todo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x14\n" +
@@ -579,13 +4321,53 @@ const file_todo_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"d\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\tparent_id\x18\b \x01(\rR\bparentId\x121\n" +
+	"\x06due_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x121\n" +
+	"\tchecklist\x18\v \x03(\v2\x13.todo.ChecklistItemR\tchecklist\x12*\n" +
+	"\bpriority\x18\f \x01(\x0e2\x0e.todo.PriorityR\bpriority\x120\n" +
+	"\n" +
+	"recurrence\x18\r \x01(\v2\x10.todo.RecurrenceR\n" +
+	"recurrence\x12=\n" +
+	"\fcompleted_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12;\n" +
+	"\varchived_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12<\n" +
+	"\ftracked_time\x18\x10 \x01(\v2\x19.google.protobuf.DurationR\vtrackedTime\x12\x1d\n" +
+	"\n" +
+	"change_seq\x18\x11 \x01(\x04R\tchangeSeq\x12\x1b\n" +
+	"\tclient_id\x18\x12 \x01(\tR\bclientId\"\xba\x01\n" +
+	"\n" +
+	"Recurrence\x128\n" +
+	"\tfrequency\x18\x01 \x01(\x0e2\x1a.todo.Recurrence.FrequencyR\tfrequency\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\rR\binterval\"V\n" +
+	"\tFrequency\x12\x19\n" +
+	"\x15FREQUENCY_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\n" +
+	"\n" +
+	"\x06WEEKLY\x10\x02\x12\v\n" +
+	"\aMONTHLY\x10\x03\x12\n" +
+	"\n" +
+	"\x06YEARLY\x10\x04\"=\n" +
+	"\rChecklistItem\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\"\xd9\x02\n" +
 	"\x11CreateTodoRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"*\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tparent_id\x18\x04 \x01(\rR\bparentId\x121\n" +
+	"\x06due_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x121\n" +
+	"\tchecklist\x18\a \x03(\v2\x13.todo.ChecklistItemR\tchecklist\x12*\n" +
+	"\bpriority\x18\b \x01(\x0e2\x0e.todo.PriorityR\bpriority\x120\n" +
+	"\n" +
+	"recurrence\x18\t \x01(\v2\x10.todo.RecurrenceR\n" +
+	"recurrence\"U\n" +
 	"\x0fGetTodosRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\rR\x06userId\"4\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12)\n" +
+	"\x10include_archived\x18\x02 \x01(\bR\x0fincludeArchived\"4\n" +
 	"\x10GetTodosResponse\x12 \n" +
 	"\x05todos\x18\x01 \x03(\v2\n" +
 	".todo.TodoR\x05todos\"F\n" +
@@ -609,7 +4391,293 @@ const file_todo_proto_rawDesc = "" +
 	"ActionType\x12\x1b\n" +
 	"\x17ACTION_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11MARK_AS_COMPLETED\x10\x01\x12\x16\n" +
-	"\x12MARK_AS_INCOMPLETE\x10\x022\xed\x02\n" +
+	"\x12MARK_AS_INCOMPLETE\x10\x02\"\xfd\x01\n" +
+	"\fTemplateItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12$\n" +
+	"\x0eparent_item_id\x18\x02 \x01(\rR\fparentItemId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x128\n" +
+	"\n" +
+	"due_offset\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\tdueOffset\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x121\n" +
+	"\tchecklist\x18\a \x03(\v2\x13.todo.ChecklistItemR\tchecklist\"\x8d\x02\n" +
+	"\fTodoTemplate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12(\n" +
+	"\x05items\x18\x05 \x03(\v2\x12.todo.TemplateItemR\x05items\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x87\x01\n" +
+	"\x1dCreateTemplateFromTodoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\rR\x06todoId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"/\n" +
+	"\x14ListTemplatesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"I\n" +
+	"\x15ListTemplatesResponse\x120\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x12.todo.TodoTemplateR\ttemplates\"N\n" +
+	"\x12GetTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1f\n" +
+	"\vtemplate_id\x18\x02 \x01(\rR\n" +
+	"templateId\"Q\n" +
+	"\x15DeleteTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1f\n" +
+	"\vtemplate_id\x18\x02 \x01(\rR\n" +
+	"templateId\"\x8f\x01\n" +
+	"\x1aInstantiateTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1f\n" +
+	"\vtemplate_id\x18\x02 \x01(\rR\n" +
+	"templateId\x127\n" +
+	"\tbase_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bbaseTime\"?\n" +
+	"\x1bInstantiateTemplateResponse\x12 \n" +
+	"\x05todos\x18\x01 \x03(\v2\n" +
+	".todo.TodoR\x05todos\"_\n" +
+	"\x13QuickAddTodoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\"_\n" +
+	"\rQuickAddToken\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\rR\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\rR\x03end\"\x99\x02\n" +
+	"\x16QuickAddInterpretation\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x121\n" +
+	"\x06due_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x17\n" +
+	"\aall_day\x18\x03 \x01(\bR\x06allDay\x12*\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x0e.todo.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x120\n" +
+	"\n" +
+	"recurrence\x18\x06 \x01(\v2\x10.todo.RecurrenceR\n" +
+	"recurrence\x12+\n" +
+	"\x06tokens\x18\a \x03(\v2\x13.todo.QuickAddTokenR\x06tokens\"|\n" +
+	"\x14QuickAddTodoResponse\x12\x1e\n" +
+	"\x04todo\x18\x01 \x01(\v2\n" +
+	".todo.TodoR\x04todo\x12D\n" +
+	"\x0einterpretation\x18\x02 \x01(\v2\x1c.todo.QuickAddInterpretationR\x0einterpretation\"\xd6\x01\n" +
+	"\vSavedSearch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"]\n" +
+	"\x18CreateSavedSearchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\"3\n" +
+	"\x18ListSavedSearchesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"U\n" +
+	"\x19ListSavedSearchesResponse\x128\n" +
+	"\x0esaved_searches\x18\x01 \x03(\v2\x11.todo.SavedSearchR\rsavedSearches\"X\n" +
+	"\x15GetSavedSearchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12&\n" +
+	"\x0fsaved_search_id\x18\x02 \x01(\rR\rsavedSearchId\"\x85\x01\n" +
+	"\x18UpdateSavedSearchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12&\n" +
+	"\x0fsaved_search_id\x18\x02 \x01(\rR\rsavedSearchId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\"[\n" +
+	"\x18DeleteSavedSearchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12&\n" +
+	"\x0fsaved_search_id\x18\x02 \x01(\rR\rsavedSearchId\"\xa0\x01\n" +
+	"\x15RunSavedSearchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12&\n" +
+	"\x0fsaved_search_id\x18\x02 \x01(\rR\rsavedSearchId\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12)\n" +
+	"\x10include_archived\x18\x04 \x01(\bR\x0fincludeArchived\"p\n" +
+	"\x16RunSavedSearchResponse\x124\n" +
+	"\fsaved_search\x18\x01 \x01(\v2\x11.todo.SavedSearchR\vsavedSearch\x12 \n" +
+	"\x05todos\x18\x02 \x03(\v2\n" +
+	".todo.TodoR\x05todos\"F\n" +
+	"\x12ArchiveTodoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\rR\x06todoId\"7\n" +
+	"\x13ArchiveTodoResponse\x12 \n" +
+	"\x05todos\x18\x01 \x03(\v2\n" +
+	".todo.TodoR\x05todos\"\xe7\x01\n" +
+	"\rArchivePolicy\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12,\n" +
+	"\x12archive_after_days\x18\x03 \x01(\rR\x10archiveAfterDays\x12:\n" +
+	"\vlast_run_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"2\n" +
+	"\x17GetArchivePolicyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"}\n" +
+	"\x1aUpdateArchivePolicyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\x12,\n" +
+	"\x12archive_after_days\x18\x03 \x01(\rR\x10archiveAfterDays\"\x9a\x03\n" +
+	"\tTimeEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x03 \x01(\rR\x06todoId\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x125\n" +
+	"\bended_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x125\n" +
+	"\bduration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x12\n" +
+	"\x04note\x18\a \x01(\tR\x04note\x12\x18\n" +
+	"\arunning\x18\b \x01(\bR\arunning\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"|\n" +
+	"\x11StartTimerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\rR\x06todoId\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12!\n" +
+	"\fstop_running\x18\x04 \x01(\bR\vstopRunning\"+\n" +
+	"\x10StopTimerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"1\n" +
+	"\x16GetRunningTimerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"\xd0\x01\n" +
+	"\x16CreateTimeEntryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\rR\x06todoId\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x125\n" +
+	"\bended_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\"\xa6\x01\n" +
+	"\x16ListTimeEntriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\rR\x06todoId\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"M\n" +
+	"\x17ListTimeEntriesResponse\x122\n" +
+	"\ftime_entries\x18\x01 \x03(\v2\x0f.todo.TimeEntryR\vtimeEntries\"\xdb\x01\n" +
+	"\x16UpdateTimeEntryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\"\n" +
+	"\rtime_entry_id\x18\x02 \x01(\rR\vtimeEntryId\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x125\n" +
+	"\bended_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\"U\n" +
+	"\x16DeleteTimeEntryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\"\n" +
+	"\rtime_entry_id\x18\x02 \x01(\rR\vtimeEntryId\"\xab\x02\n" +
+	"\x14GetTimeReportRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12=\n" +
+	"\bgroup_by\x18\x04 \x01(\x0e2\".todo.GetTimeReportRequest.GroupByR\agroupBy\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\"B\n" +
+	"\aGroupBy\x12\x18\n" +
+	"\x14GROUP_BY_UNSPECIFIED\x10\x00\x12\a\n" +
+	"\x03DAY\x10\x01\x12\a\n" +
+	"\x03TAG\x10\x02\x12\v\n" +
+	"\aPROJECT\x10\x03\"\x89\x01\n" +
+	"\rTimeReportRow\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12/\n" +
+	"\x05total\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x05total\x12\x1f\n" +
+	"\ventry_count\x18\x04 \x01(\rR\n" +
+	"entryCount\"\x81\x02\n" +
+	"\n" +
+	"TimeReport\x12=\n" +
+	"\bgroup_by\x18\x01 \x01(\x0e2\".todo.GetTimeReportRequest.GroupByR\agroupBy\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12'\n" +
+	"\x04rows\x18\x04 \x03(\v2\x13.todo.TimeReportRowR\x04rows\x12/\n" +
+	"\x05total\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x05total\"u\n" +
+	"\x13GetTodoStatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1b\n" +
+	"\ttime_zone\x18\x02 \x01(\tR\btimeZone\x12\x12\n" +
+	"\x04days\x18\x03 \x01(\rR\x04days\x12\x14\n" +
+	"\x05weeks\x18\x04 \x01(\rR\x05weeks\";\n" +
+	"\x0fCompletionCount\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\x97\x04\n" +
+	"\tTodoStats\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\rR\x05total\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\rR\x04open\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\rR\tcompleted\x12\x18\n" +
+	"\aoverdue\x18\x04 \x01(\rR\aoverdue\x12\x1a\n" +
+	"\barchived\x18\x05 \x01(\rR\barchived\x12E\n" +
+	"\x13completions_per_day\x18\x06 \x03(\v2\x15.todo.CompletionCountR\x11completionsPerDay\x12G\n" +
+	"\x14completions_per_week\x18\a \x03(\v2\x15.todo.CompletionCountR\x12completionsPerWeek\x12R\n" +
+	"\x18average_time_to_complete\x18\b \x01(\v2\x19.google.protobuf.DurationR\x15averageTimeToComplete\x12%\n" +
+	"\x0ecurrent_streak\x18\t \x01(\rR\rcurrentStreak\x12%\n" +
+	"\x0elongest_streak\x18\n" +
+	" \x01(\rR\rlongestStreak\x12=\n" +
+	"\fgenerated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\x12\x1b\n" +
+	"\ttime_zone\x18\f \x01(\tR\btimeZone\"O\n" +
+	"\x11WatchTodosRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"\xa9\x02\n" +
+	"\tTodoEvent\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.todo.TodoEvent.TypeR\x04type\x12\x17\n" +
+	"\atodo_id\x18\x03 \x01(\rR\x06todoId\x12\x1e\n" +
+	"\x04todo\x18\x04 \x01(\v2\n" +
+	".todo.TodoR\x04todo\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"Y\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05READY\x10\x01\x12\v\n" +
+	"\aCREATED\x10\x02\x12\v\n" +
+	"\aUPDATED\x10\x03\x12\v\n" +
+	"\aDELETED\x10\x04\x12\t\n" +
+	"\x05RESET\x10\x05\"|\n" +
+	"\x10SyncTodosRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x02 \x01(\tR\tsyncToken\x120\n" +
+	"\tmutations\x18\x03 \x03(\v2\x12.todo.SyncMutationR\tmutations\"\xe1\x02\n" +
+	"\fSyncMutation\x12\x1f\n" +
+	"\vmutation_id\x18\x01 \x01(\tR\n" +
+	"mutationId\x12%\n" +
+	"\x02op\x18\x02 \x01(\x0e2\x15.todo.SyncMutation.OpR\x02op\x12\x17\n" +
+	"\atodo_id\x18\x03 \x01(\rR\x06todoId\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12&\n" +
+	"\x0fbase_change_seq\x18\x05 \x01(\x04R\rbaseChangeSeq\x12\x1e\n" +
+	"\x04todo\x18\x06 \x01(\v2\n" +
+	".todo.TodoR\x04todo\x12#\n" +
+	"\rupdate_fields\x18\a \x03(\tR\fupdateFields\x12(\n" +
+	"\x10parent_client_id\x18\b \x01(\tR\x0eparentClientId\"<\n" +
+	"\x02Op\x12\x12\n" +
+	"\x0eOP_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06CREATE\x10\x01\x12\n" +
+	"\n" +
+	"\x06UPDATE\x10\x02\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x03\"\xfb\x01\n" +
+	"\x12SyncMutationResult\x12\x1f\n" +
+	"\vmutation_id\x18\x01 \x01(\tR\n" +
+	"mutationId\x127\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1f.todo.SyncMutationResult.StatusR\x06status\x12\x17\n" +
+	"\atodo_id\x18\x03 \x01(\rR\x06todoId\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"X\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aAPPLIED\x10\x01\x12\r\n" +
+	"\tDUPLICATE\x10\x02\x12\f\n" +
+	"\bCONFLICT\x10\x03\x12\f\n" +
+	"\bREJECTED\x10\x04\"\xd3\x01\n" +
+	"\x11SyncTodosResponse\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x01 \x01(\tR\tsyncToken\x12 \n" +
+	"\x05todos\x18\x02 \x03(\v2\n" +
+	".todo.TodoR\x05todos\x12(\n" +
+	"\x10deleted_todo_ids\x18\x03 \x03(\rR\x0edeletedTodoIds\x122\n" +
+	"\aresults\x18\x04 \x03(\v2\x18.todo.SyncMutationResultR\aresults\x12\x1f\n" +
+	"\vfull_resync\x18\x05 \x01(\bR\n" +
+	"fullResync*s\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_MEDIUM\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x03\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x042\xd3\x11\n" +
 	"\vTodoService\x121\n" +
 	"\n" +
 	"CreateTodo\x12\x17.todo.CreateTodoRequest\x1a\n" +
@@ -622,7 +4690,36 @@ const file_todo_proto_rawDesc = "" +
 	".todo.Todo\x12=\n" +
 	"\n" +
 	"DeleteTodo\x12\x17.todo.DeleteTodoRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
-	"\x10BatchUpdateTodos\x12\x1d.todo.BatchUpdateTodosRequest\x1a\x16.google.protobuf.EmptyB\bZ\x06.;todob\x06proto3"
+	"\x10BatchUpdateTodos\x12\x1d.todo.BatchUpdateTodosRequest\x1a\x16.google.protobuf.Empty\x12Q\n" +
+	"\x16CreateTemplateFromTodo\x12#.todo.CreateTemplateFromTodoRequest\x1a\x12.todo.TodoTemplate\x12H\n" +
+	"\rListTemplates\x12\x1a.todo.ListTemplatesRequest\x1a\x1b.todo.ListTemplatesResponse\x12;\n" +
+	"\vGetTemplate\x12\x18.todo.GetTemplateRequest\x1a\x12.todo.TodoTemplate\x12E\n" +
+	"\x0eDeleteTemplate\x12\x1b.todo.DeleteTemplateRequest\x1a\x16.google.protobuf.Empty\x12Z\n" +
+	"\x13InstantiateTemplate\x12 .todo.InstantiateTemplateRequest\x1a!.todo.InstantiateTemplateResponse\x12E\n" +
+	"\fQuickAddTodo\x12\x19.todo.QuickAddTodoRequest\x1a\x1a.todo.QuickAddTodoResponse\x12F\n" +
+	"\x11CreateSavedSearch\x12\x1e.todo.CreateSavedSearchRequest\x1a\x11.todo.SavedSearch\x12T\n" +
+	"\x11ListSavedSearches\x12\x1e.todo.ListSavedSearchesRequest\x1a\x1f.todo.ListSavedSearchesResponse\x12@\n" +
+	"\x0eGetSavedSearch\x12\x1b.todo.GetSavedSearchRequest\x1a\x11.todo.SavedSearch\x12F\n" +
+	"\x11UpdateSavedSearch\x12\x1e.todo.UpdateSavedSearchRequest\x1a\x11.todo.SavedSearch\x12K\n" +
+	"\x11DeleteSavedSearch\x12\x1e.todo.DeleteSavedSearchRequest\x1a\x16.google.protobuf.Empty\x12K\n" +
+	"\x0eRunSavedSearch\x12\x1b.todo.RunSavedSearchRequest\x1a\x1c.todo.RunSavedSearchResponse\x12B\n" +
+	"\vArchiveTodo\x12\x18.todo.ArchiveTodoRequest\x1a\x19.todo.ArchiveTodoResponse\x12D\n" +
+	"\rUnarchiveTodo\x12\x18.todo.ArchiveTodoRequest\x1a\x19.todo.ArchiveTodoResponse\x12F\n" +
+	"\x10GetArchivePolicy\x12\x1d.todo.GetArchivePolicyRequest\x1a\x13.todo.ArchivePolicy\x12L\n" +
+	"\x13UpdateArchivePolicy\x12 .todo.UpdateArchivePolicyRequest\x1a\x13.todo.ArchivePolicy\x126\n" +
+	"\n" +
+	"StartTimer\x12\x17.todo.StartTimerRequest\x1a\x0f.todo.TimeEntry\x124\n" +
+	"\tStopTimer\x12\x16.todo.StopTimerRequest\x1a\x0f.todo.TimeEntry\x12@\n" +
+	"\x0fGetRunningTimer\x12\x1c.todo.GetRunningTimerRequest\x1a\x0f.todo.TimeEntry\x12@\n" +
+	"\x0fCreateTimeEntry\x12\x1c.todo.CreateTimeEntryRequest\x1a\x0f.todo.TimeEntry\x12N\n" +
+	"\x0fListTimeEntries\x12\x1c.todo.ListTimeEntriesRequest\x1a\x1d.todo.ListTimeEntriesResponse\x12@\n" +
+	"\x0fUpdateTimeEntry\x12\x1c.todo.UpdateTimeEntryRequest\x1a\x0f.todo.TimeEntry\x12G\n" +
+	"\x0fDeleteTimeEntry\x12\x1c.todo.DeleteTimeEntryRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
+	"\rGetTimeReport\x12\x1a.todo.GetTimeReportRequest\x1a\x10.todo.TimeReport\x12:\n" +
+	"\fGetTodoStats\x12\x19.todo.GetTodoStatsRequest\x1a\x0f.todo.TodoStats\x128\n" +
+	"\n" +
+	"WatchTodos\x12\x17.todo.WatchTodosRequest\x1a\x0f.todo.TodoEvent0\x01\x12<\n" +
+	"\tSyncTodos\x12\x16.todo.SyncTodosRequest\x1a\x17.todo.SyncTodosResponseB\bZ\x06.;todob\x06proto3"
 
 var (
 	file_todo_proto_rawDescOnce sync.Once
@@ -636,43 +4733,222 @@ func file_todo_proto_rawDescGZIP() []byte {
 	return file_todo_proto_rawDescData
 }
 
-var file_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
 var file_todo_proto_goTypes = []any{
-	(BatchUpdateTodosRequest_ActionType)(0), // 0: todo.BatchUpdateTodosRequest.ActionType
-	(*Todo)(nil),                            // 1: todo.Todo
-	(*CreateTodoRequest)(nil),               // 2: todo.CreateTodoRequest
-	(*GetTodosRequest)(nil),                 // 3: todo.GetTodosRequest
-	(*GetTodosResponse)(nil),                // 4: todo.GetTodosResponse
-	(*GetTodoByIDRequest)(nil),              // 5: todo.GetTodoByIDRequest
-	(*UpdateTodoRequest)(nil),               // 6: todo.UpdateTodoRequest
-	(*DeleteTodoRequest)(nil),               // 7: todo.DeleteTodoRequest
-	(*BatchUpdateTodosRequest)(nil),         // 8: todo.BatchUpdateTodosRequest
-	(*timestamppb.Timestamp)(nil),           // 9: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                   // 10: google.protobuf.Empty
+	(Priority)(0),                           // 0: todo.Priority
+	(Recurrence_Frequency)(0),               // 1: todo.Recurrence.Frequency
+	(BatchUpdateTodosRequest_ActionType)(0), // 2: todo.BatchUpdateTodosRequest.ActionType
+	(GetTimeReportRequest_GroupBy)(0),       // 3: todo.GetTimeReportRequest.GroupBy
+	(TodoEvent_Type)(0),                     // 4: todo.TodoEvent.Type
+	(SyncMutation_Op)(0),                    // 5: todo.SyncMutation.Op
+	(SyncMutationResult_Status)(0),          // 6: todo.SyncMutationResult.Status
+	(*Todo)(nil),                            // 7: todo.Todo
+	(*Recurrence)(nil),                      // 8: todo.Recurrence
+	(*ChecklistItem)(nil),                   // 9: todo.ChecklistItem
+	(*CreateTodoRequest)(nil),               // 10: todo.CreateTodoRequest
+	(*GetTodosRequest)(nil),                 // 11: todo.GetTodosRequest
+	(*GetTodosResponse)(nil),                // 12: todo.GetTodosResponse
+	(*GetTodoByIDRequest)(nil),              // 13: todo.GetTodoByIDRequest
+	(*UpdateTodoRequest)(nil),               // 14: todo.UpdateTodoRequest
+	(*DeleteTodoRequest)(nil),               // 15: todo.DeleteTodoRequest
+	(*BatchUpdateTodosRequest)(nil),         // 16: todo.BatchUpdateTodosRequest
+	(*TemplateItem)(nil),                    // 17: todo.TemplateItem
+	(*TodoTemplate)(nil),                    // 18: todo.TodoTemplate
+	(*CreateTemplateFromTodoRequest)(nil),   // 19: todo.CreateTemplateFromTodoRequest
+	(*ListTemplatesRequest)(nil),            // 20: todo.ListTemplatesRequest
+	(*ListTemplatesResponse)(nil),           // 21: todo.ListTemplatesResponse
+	(*GetTemplateRequest)(nil),              // 22: todo.GetTemplateRequest
+	(*DeleteTemplateRequest)(nil),           // 23: todo.DeleteTemplateRequest
+	(*InstantiateTemplateRequest)(nil),      // 24: todo.InstantiateTemplateRequest
+	(*InstantiateTemplateResponse)(nil),     // 25: todo.InstantiateTemplateResponse
+	(*QuickAddTodoRequest)(nil),             // 26: todo.QuickAddTodoRequest
+	(*QuickAddToken)(nil),                   // 27: todo.QuickAddToken
+	(*QuickAddInterpretation)(nil),          // 28: todo.QuickAddInterpretation
+	(*QuickAddTodoResponse)(nil),            // 29: todo.QuickAddTodoResponse
+	(*SavedSearch)(nil),                     // 30: todo.SavedSearch
+	(*CreateSavedSearchRequest)(nil),        // 31: todo.CreateSavedSearchRequest
+	(*ListSavedSearchesRequest)(nil),        // 32: todo.ListSavedSearchesRequest
+	(*ListSavedSearchesResponse)(nil),       // 33: todo.ListSavedSearchesResponse
+	(*GetSavedSearchRequest)(nil),           // 34: todo.GetSavedSearchRequest
+	(*UpdateSavedSearchRequest)(nil),        // 35: todo.UpdateSavedSearchRequest
+	(*DeleteSavedSearchRequest)(nil),        // 36: todo.DeleteSavedSearchRequest
+	(*RunSavedSearchRequest)(nil),           // 37: todo.RunSavedSearchRequest
+	(*RunSavedSearchResponse)(nil),          // 38: todo.RunSavedSearchResponse
+	(*ArchiveTodoRequest)(nil),              // 39: todo.ArchiveTodoRequest
+	(*ArchiveTodoResponse)(nil),             // 40: todo.ArchiveTodoResponse
+	(*ArchivePolicy)(nil),                   // 41: todo.ArchivePolicy
+	(*GetArchivePolicyRequest)(nil),         // 42: todo.GetArchivePolicyRequest
+	(*UpdateArchivePolicyRequest)(nil),      // 43: todo.UpdateArchivePolicyRequest
+	(*TimeEntry)(nil),                       // 44: todo.TimeEntry
+	(*StartTimerRequest)(nil),               // 45: todo.StartTimerRequest
+	(*StopTimerRequest)(nil),                // 46: todo.StopTimerRequest
+	(*GetRunningTimerRequest)(nil),          // 47: todo.GetRunningTimerRequest
+	(*CreateTimeEntryRequest)(nil),          // 48: todo.CreateTimeEntryRequest
+	(*ListTimeEntriesRequest)(nil),          // 49: todo.ListTimeEntriesRequest
+	(*ListTimeEntriesResponse)(nil),         // 50: todo.ListTimeEntriesResponse
+	(*UpdateTimeEntryRequest)(nil),          // 51: todo.UpdateTimeEntryRequest
+	(*DeleteTimeEntryRequest)(nil),          // 52: todo.DeleteTimeEntryRequest
+	(*GetTimeReportRequest)(nil),            // 53: todo.GetTimeReportRequest
+	(*TimeReportRow)(nil),                   // 54: todo.TimeReportRow
+	(*TimeReport)(nil),                      // 55: todo.TimeReport
+	(*GetTodoStatsRequest)(nil),             // 56: todo.GetTodoStatsRequest
+	(*CompletionCount)(nil),                 // 57: todo.CompletionCount
+	(*TodoStats)(nil),                       // 58: todo.TodoStats
+	(*WatchTodosRequest)(nil),               // 59: todo.WatchTodosRequest
+	(*TodoEvent)(nil),                       // 60: todo.TodoEvent
+	(*SyncTodosRequest)(nil),                // 61: todo.SyncTodosRequest
+	(*SyncMutation)(nil),                    // 62: todo.SyncMutation
+	(*SyncMutationResult)(nil),              // 63: todo.SyncMutationResult
+	(*SyncTodosResponse)(nil),               // 64: todo.SyncTodosResponse
+	(*timestamppb.Timestamp)(nil),           // 65: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),             // 66: google.protobuf.Duration
+	(*emptypb.Empty)(nil),                   // 67: google.protobuf.Empty
 }
 var file_todo_proto_depIdxs = []int32{
-	9,  // 0: todo.Todo.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 1: todo.Todo.updated_at:type_name -> google.protobuf.Timestamp
-	1,  // 2: todo.GetTodosResponse.todos:type_name -> todo.Todo
-	0,  // 3: todo.BatchUpdateTodosRequest.action:type_name -> todo.BatchUpdateTodosRequest.ActionType
-	2,  // 4: todo.TodoService.CreateTodo:input_type -> todo.CreateTodoRequest
-	3,  // 5: todo.TodoService.GetTodos:input_type -> todo.GetTodosRequest
-	5,  // 6: todo.TodoService.GetTodoByID:input_type -> todo.GetTodoByIDRequest
-	6,  // 7: todo.TodoService.UpdateTodo:input_type -> todo.UpdateTodoRequest
-	7,  // 8: todo.TodoService.DeleteTodo:input_type -> todo.DeleteTodoRequest
-	8,  // 9: todo.TodoService.BatchUpdateTodos:input_type -> todo.BatchUpdateTodosRequest
-	1,  // 10: todo.TodoService.CreateTodo:output_type -> todo.Todo
-	4,  // 11: todo.TodoService.GetTodos:output_type -> todo.GetTodosResponse
-	1,  // 12: todo.TodoService.GetTodoByID:output_type -> todo.Todo
-	1,  // 13: todo.TodoService.UpdateTodo:output_type -> todo.Todo
-	10, // 14: todo.TodoService.DeleteTodo:output_type -> google.protobuf.Empty
-	10, // 15: todo.TodoService.BatchUpdateTodos:output_type -> google.protobuf.Empty
-	10, // [10:16] is the sub-list for method output_type
-	4,  // [4:10] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	65,  // 0: todo.Todo.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 1: todo.Todo.updated_at:type_name -> google.protobuf.Timestamp
+	65,  // 2: todo.Todo.due_at:type_name -> google.protobuf.Timestamp
+	9,   // 3: todo.Todo.checklist:type_name -> todo.ChecklistItem
+	0,   // 4: todo.Todo.priority:type_name -> todo.Priority
+	8,   // 5: todo.Todo.recurrence:type_name -> todo.Recurrence
+	65,  // 6: todo.Todo.completed_at:type_name -> google.protobuf.Timestamp
+	65,  // 7: todo.Todo.archived_at:type_name -> google.protobuf.Timestamp
+	66,  // 8: todo.Todo.tracked_time:type_name -> google.protobuf.Duration
+	1,   // 9: todo.Recurrence.frequency:type_name -> todo.Recurrence.Frequency
+	65,  // 10: todo.CreateTodoRequest.due_at:type_name -> google.protobuf.Timestamp
+	9,   // 11: todo.CreateTodoRequest.checklist:type_name -> todo.ChecklistItem
+	0,   // 12: todo.CreateTodoRequest.priority:type_name -> todo.Priority
+	8,   // 13: todo.CreateTodoRequest.recurrence:type_name -> todo.Recurrence
+	7,   // 14: todo.GetTodosResponse.todos:type_name -> todo.Todo
+	2,   // 15: todo.BatchUpdateTodosRequest.action:type_name -> todo.BatchUpdateTodosRequest.ActionType
+	66,  // 16: todo.TemplateItem.due_offset:type_name -> google.protobuf.Duration
+	9,   // 17: todo.TemplateItem.checklist:type_name -> todo.ChecklistItem
+	17,  // 18: todo.TodoTemplate.items:type_name -> todo.TemplateItem
+	65,  // 19: todo.TodoTemplate.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 20: todo.TodoTemplate.updated_at:type_name -> google.protobuf.Timestamp
+	18,  // 21: todo.ListTemplatesResponse.templates:type_name -> todo.TodoTemplate
+	65,  // 22: todo.InstantiateTemplateRequest.base_time:type_name -> google.protobuf.Timestamp
+	7,   // 23: todo.InstantiateTemplateResponse.todos:type_name -> todo.Todo
+	65,  // 24: todo.QuickAddInterpretation.due_at:type_name -> google.protobuf.Timestamp
+	0,   // 25: todo.QuickAddInterpretation.priority:type_name -> todo.Priority
+	8,   // 26: todo.QuickAddInterpretation.recurrence:type_name -> todo.Recurrence
+	27,  // 27: todo.QuickAddInterpretation.tokens:type_name -> todo.QuickAddToken
+	7,   // 28: todo.QuickAddTodoResponse.todo:type_name -> todo.Todo
+	28,  // 29: todo.QuickAddTodoResponse.interpretation:type_name -> todo.QuickAddInterpretation
+	65,  // 30: todo.SavedSearch.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 31: todo.SavedSearch.updated_at:type_name -> google.protobuf.Timestamp
+	30,  // 32: todo.ListSavedSearchesResponse.saved_searches:type_name -> todo.SavedSearch
+	30,  // 33: todo.RunSavedSearchResponse.saved_search:type_name -> todo.SavedSearch
+	7,   // 34: todo.RunSavedSearchResponse.todos:type_name -> todo.Todo
+	7,   // 35: todo.ArchiveTodoResponse.todos:type_name -> todo.Todo
+	65,  // 36: todo.ArchivePolicy.last_run_at:type_name -> google.protobuf.Timestamp
+	65,  // 37: todo.ArchivePolicy.updated_at:type_name -> google.protobuf.Timestamp
+	65,  // 38: todo.TimeEntry.started_at:type_name -> google.protobuf.Timestamp
+	65,  // 39: todo.TimeEntry.ended_at:type_name -> google.protobuf.Timestamp
+	66,  // 40: todo.TimeEntry.duration:type_name -> google.protobuf.Duration
+	65,  // 41: todo.TimeEntry.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 42: todo.TimeEntry.updated_at:type_name -> google.protobuf.Timestamp
+	65,  // 43: todo.CreateTimeEntryRequest.started_at:type_name -> google.protobuf.Timestamp
+	65,  // 44: todo.CreateTimeEntryRequest.ended_at:type_name -> google.protobuf.Timestamp
+	65,  // 45: todo.ListTimeEntriesRequest.from:type_name -> google.protobuf.Timestamp
+	65,  // 46: todo.ListTimeEntriesRequest.to:type_name -> google.protobuf.Timestamp
+	44,  // 47: todo.ListTimeEntriesResponse.time_entries:type_name -> todo.TimeEntry
+	65,  // 48: todo.UpdateTimeEntryRequest.started_at:type_name -> google.protobuf.Timestamp
+	65,  // 49: todo.UpdateTimeEntryRequest.ended_at:type_name -> google.protobuf.Timestamp
+	65,  // 50: todo.GetTimeReportRequest.from:type_name -> google.protobuf.Timestamp
+	65,  // 51: todo.GetTimeReportRequest.to:type_name -> google.protobuf.Timestamp
+	3,   // 52: todo.GetTimeReportRequest.group_by:type_name -> todo.GetTimeReportRequest.GroupBy
+	66,  // 53: todo.TimeReportRow.total:type_name -> google.protobuf.Duration
+	3,   // 54: todo.TimeReport.group_by:type_name -> todo.GetTimeReportRequest.GroupBy
+	65,  // 55: todo.TimeReport.from:type_name -> google.protobuf.Timestamp
+	65,  // 56: todo.TimeReport.to:type_name -> google.protobuf.Timestamp
+	54,  // 57: todo.TimeReport.rows:type_name -> todo.TimeReportRow
+	66,  // 58: todo.TimeReport.total:type_name -> google.protobuf.Duration
+	57,  // 59: todo.TodoStats.completions_per_day:type_name -> todo.CompletionCount
+	57,  // 60: todo.TodoStats.completions_per_week:type_name -> todo.CompletionCount
+	66,  // 61: todo.TodoStats.average_time_to_complete:type_name -> google.protobuf.Duration
+	65,  // 62: todo.TodoStats.generated_at:type_name -> google.protobuf.Timestamp
+	4,   // 63: todo.TodoEvent.type:type_name -> todo.TodoEvent.Type
+	7,   // 64: todo.TodoEvent.todo:type_name -> todo.Todo
+	65,  // 65: todo.TodoEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	62,  // 66: todo.SyncTodosRequest.mutations:type_name -> todo.SyncMutation
+	5,   // 67: todo.SyncMutation.op:type_name -> todo.SyncMutation.Op
+	7,   // 68: todo.SyncMutation.todo:type_name -> todo.Todo
+	6,   // 69: todo.SyncMutationResult.status:type_name -> todo.SyncMutationResult.Status
+	7,   // 70: todo.SyncTodosResponse.todos:type_name -> todo.Todo
+	63,  // 71: todo.SyncTodosResponse.results:type_name -> todo.SyncMutationResult
+	10,  // 72: todo.TodoService.CreateTodo:input_type -> todo.CreateTodoRequest
+	11,  // 73: todo.TodoService.GetTodos:input_type -> todo.GetTodosRequest
+	13,  // 74: todo.TodoService.GetTodoByID:input_type -> todo.GetTodoByIDRequest
+	14,  // 75: todo.TodoService.UpdateTodo:input_type -> todo.UpdateTodoRequest
+	15,  // 76: todo.TodoService.DeleteTodo:input_type -> todo.DeleteTodoRequest
+	16,  // 77: todo.TodoService.BatchUpdateTodos:input_type -> todo.BatchUpdateTodosRequest
+	19,  // 78: todo.TodoService.CreateTemplateFromTodo:input_type -> todo.CreateTemplateFromTodoRequest
+	20,  // 79: todo.TodoService.ListTemplates:input_type -> todo.ListTemplatesRequest
+	22,  // 80: todo.TodoService.GetTemplate:input_type -> todo.GetTemplateRequest
+	23,  // 81: todo.TodoService.DeleteTemplate:input_type -> todo.DeleteTemplateRequest
+	24,  // 82: todo.TodoService.InstantiateTemplate:input_type -> todo.InstantiateTemplateRequest
+	26,  // 83: todo.TodoService.QuickAddTodo:input_type -> todo.QuickAddTodoRequest
+	31,  // 84: todo.TodoService.CreateSavedSearch:input_type -> todo.CreateSavedSearchRequest
+	32,  // 85: todo.TodoService.ListSavedSearches:input_type -> todo.ListSavedSearchesRequest
+	34,  // 86: todo.TodoService.GetSavedSearch:input_type -> todo.GetSavedSearchRequest
+	35,  // 87: todo.TodoService.UpdateSavedSearch:input_type -> todo.UpdateSavedSearchRequest
+	36,  // 88: todo.TodoService.DeleteSavedSearch:input_type -> todo.DeleteSavedSearchRequest
+	37,  // 89: todo.TodoService.RunSavedSearch:input_type -> todo.RunSavedSearchRequest
+	39,  // 90: todo.TodoService.ArchiveTodo:input_type -> todo.ArchiveTodoRequest
+	39,  // 91: todo.TodoService.UnarchiveTodo:input_type -> todo.ArchiveTodoRequest
+	42,  // 92: todo.TodoService.GetArchivePolicy:input_type -> todo.GetArchivePolicyRequest
+	43,  // 93: todo.TodoService.UpdateArchivePolicy:input_type -> todo.UpdateArchivePolicyRequest
+	45,  // 94: todo.TodoService.StartTimer:input_type -> todo.StartTimerRequest
+	46,  // 95: todo.TodoService.StopTimer:input_type -> todo.StopTimerRequest
+	47,  // 96: todo.TodoService.GetRunningTimer:input_type -> todo.GetRunningTimerRequest
+	48,  // 97: todo.TodoService.CreateTimeEntry:input_type -> todo.CreateTimeEntryRequest
+	49,  // 98: todo.TodoService.ListTimeEntries:input_type -> todo.ListTimeEntriesRequest
+	51,  // 99: todo.TodoService.UpdateTimeEntry:input_type -> todo.UpdateTimeEntryRequest
+	52,  // 100: todo.TodoService.DeleteTimeEntry:input_type -> todo.DeleteTimeEntryRequest
+	53,  // 101: todo.TodoService.GetTimeReport:input_type -> todo.GetTimeReportRequest
+	56,  // 102: todo.TodoService.GetTodoStats:input_type -> todo.GetTodoStatsRequest
+	59,  // 103: todo.TodoService.WatchTodos:input_type -> todo.WatchTodosRequest
+	61,  // 104: todo.TodoService.SyncTodos:input_type -> todo.SyncTodosRequest
+	7,   // 105: todo.TodoService.CreateTodo:output_type -> todo.Todo
+	12,  // 106: todo.TodoService.GetTodos:output_type -> todo.GetTodosResponse
+	7,   // 107: todo.TodoService.GetTodoByID:output_type -> todo.Todo
+	7,   // 108: todo.TodoService.UpdateTodo:output_type -> todo.Todo
+	67,  // 109: todo.TodoService.DeleteTodo:output_type -> google.protobuf.Empty
+	67,  // 110: todo.TodoService.BatchUpdateTodos:output_type -> google.protobuf.Empty
+	18,  // 111: todo.TodoService.CreateTemplateFromTodo:output_type -> todo.TodoTemplate
+	21,  // 112: todo.TodoService.ListTemplates:output_type -> todo.ListTemplatesResponse
+	18,  // 113: todo.TodoService.GetTemplate:output_type -> todo.TodoTemplate
+	67,  // 114: todo.TodoService.DeleteTemplate:output_type -> google.protobuf.Empty
+	25,  // 115: todo.TodoService.InstantiateTemplate:output_type -> todo.InstantiateTemplateResponse
+	29,  // 116: todo.TodoService.QuickAddTodo:output_type -> todo.QuickAddTodoResponse
+	30,  // 117: todo.TodoService.CreateSavedSearch:output_type -> todo.SavedSearch
+	33,  // 118: todo.TodoService.ListSavedSearches:output_type -> todo.ListSavedSearchesResponse
+	30,  // 119: todo.TodoService.GetSavedSearch:output_type -> todo.SavedSearch
+	30,  // 120: todo.TodoService.UpdateSavedSearch:output_type -> todo.SavedSearch
+	67,  // 121: todo.TodoService.DeleteSavedSearch:output_type -> google.protobuf.Empty
+	38,  // 122: todo.TodoService.RunSavedSearch:output_type -> todo.RunSavedSearchResponse
+	40,  // 123: todo.TodoService.ArchiveTodo:output_type -> todo.ArchiveTodoResponse
+	40,  // 124: todo.TodoService.UnarchiveTodo:output_type -> todo.ArchiveTodoResponse
+	41,  // 125: todo.TodoService.GetArchivePolicy:output_type -> todo.ArchivePolicy
+	41,  // 126: todo.TodoService.UpdateArchivePolicy:output_type -> todo.ArchivePolicy
+	44,  // 127: todo.TodoService.StartTimer:output_type -> todo.TimeEntry
+	44,  // 128: todo.TodoService.StopTimer:output_type -> todo.TimeEntry
+	44,  // 129: todo.TodoService.GetRunningTimer:output_type -> todo.TimeEntry
+	44,  // 130: todo.TodoService.CreateTimeEntry:output_type -> todo.TimeEntry
+	50,  // 131: todo.TodoService.ListTimeEntries:output_type -> todo.ListTimeEntriesResponse
+	44,  // 132: todo.TodoService.UpdateTimeEntry:output_type -> todo.TimeEntry
+	67,  // 133: todo.TodoService.DeleteTimeEntry:output_type -> google.protobuf.Empty
+	55,  // 134: todo.TodoService.GetTimeReport:output_type -> todo.TimeReport
+	58,  // 135: todo.TodoService.GetTodoStats:output_type -> todo.TodoStats
+	60,  // 136: todo.TodoService.WatchTodos:output_type -> todo.TodoEvent
+	64,  // 137: todo.TodoService.SyncTodos:output_type -> todo.SyncTodosResponse
+	105, // [105:138] is the sub-list for method output_type
+	72,  // [72:105] is the sub-list for method input_type
+	72,  // [72:72] is the sub-list for extension type_name
+	72,  // [72:72] is the sub-list for extension extendee
+	0,   // [0:72] is the sub-list for field type_name
 }
 
 func init() { file_todo_proto_init() }
@@ -685,8 +4961,8 @@ func file_todo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_proto_rawDesc), len(file_todo_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      7,
+			NumMessages:   58,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -38,7 +38,7 @@ func main() {
 		log.Fatalf("启动消息消费失败: %v", err)
 	}
 
-	log.Printf(" [*] 等待队列 '%s' 上的消息。按 CTRL+C 退出", mq.UserRegisteredQueue)
+	log.Printf(" [*] 等待队列 '%s'、'%s'、'%s' 和 '%s' 上的消息。按 CTRL+C 退出", mq.UserRegisteredQueue, mq.EmailVerificationQueue, mq.PasswordResetQueue, mq.AccountLockedQueue)
 
 	// 优雅关闭处理
 	sigChan := make(chan os.Signal, 1)
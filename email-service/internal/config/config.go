@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 // Config 包含应用程序配置
 type Config struct {
@@ -11,6 +14,8 @@ type Config struct {
 	SMTPUser     string
 	SMTPPassword string
 	SMTPSender   string
+	// AppBaseURL 前端的访问地址，用于生成邮件中的链接
+	AppBaseURL string
 }
 
 // LoadConfig 从环境变量加载配置
@@ -23,6 +28,7 @@ func LoadConfig() *Config {
 		SMTPUser:     getEnvOrDefault("SMTP_USER", ""),
 		SMTPPassword: getEnvOrDefault("SMTP_PASSWORD", ""),
 		SMTPSender:   getEnvOrDefault("SMTP_SENDER", ""),
+		AppBaseURL:   strings.TrimRight(getEnvOrDefault("APP_BASE_URL", "http://localhost"), "/"),
 	}
 }
 
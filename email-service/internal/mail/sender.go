@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/smtp"
+	"net/url"
 	"strings"
+	"time"
 
 	"todo-project/email-service/internal/config"
 )
@@ -14,6 +16,7 @@ type Sender struct {
 	smtpAuth   smtp.Auth
 	smtpServer string
 	smtpSender string
+	appBaseURL string
 }
 
 // NewSender 创建新的邮件发送器
@@ -22,6 +25,7 @@ func NewSender(cfg *config.Config) *Sender {
 		smtpAuth:   smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost),
 		smtpServer: fmt.Sprintf("%s:%s", cfg.SMTPHost, cfg.SMTPPort),
 		smtpSender: cfg.SMTPSender,
+		appBaseURL: cfg.AppBaseURL,
 	}
 }
 
@@ -29,6 +33,37 @@ func NewSender(cfg *config.Config) *Sender {
 func (s *Sender) SendWelcomeEmail(username string, email string, userID uint) error {
 	subject := "欢迎加入我们的Todo应用！"
 	body := fmt.Sprintf("你好 %s,\n\n欢迎加入！我们很高兴有你。\n\n你的用户ID是: %d\n\n谢谢,\nTodo团队", username, userID)
+	return s.send(email, subject, body, "欢迎邮件")
+}
+
+// SendVerificationEmail 发送邮箱验证邮件，链接指向前端的 /verify-email 页面
+func (s *Sender) SendVerificationEmail(username string, email string, token string, expiresAt time.Time) error {
+	link := s.appBaseURL + "/verify-email?token=" + url.QueryEscape(token)
+	subject := "请验证你的邮箱"
+	body := fmt.Sprintf("你好 %s,\n\n请点击下面的链接验证你的邮箱：\n\n%s\n\n链接将于 %s 失效。如果这不是你本人的操作，请忽略此邮件。\n\n谢谢,\nTodo团队",
+		username, link, expiresAt.Local().Format("2006-01-02 15:04"))
+	return s.send(email, subject, body, "验证邮件")
+}
+
+// SendPasswordResetEmail 发送重置密码邮件，链接指向前端的 /reset-password 页面
+func (s *Sender) SendPasswordResetEmail(username string, email string, token string, expiresAt time.Time) error {
+	link := s.appBaseURL + "/reset-password?token=" + url.QueryEscape(token)
+	subject := "重置你的密码"
+	body := fmt.Sprintf("你好 %s,\n\n我们收到了重置你的账号密码的申请，请点击下面的链接设置新密码：\n\n%s\n\n链接将于 %s 失效，且只能使用一次。重置后所有设备上的登录都会失效。如果这不是你本人的操作，请忽略此邮件，你的密码不会改变。\n\n谢谢,\nTodo团队",
+		username, link, expiresAt.Local().Format("2006-01-02 15:04"))
+	return s.send(email, subject, body, "重置密码邮件")
+}
+
+// SendAccountLockedEmail 发送账号锁定通知，提醒用户可能有人在尝试猜测密码
+func (s *Sender) SendAccountLockedEmail(username string, email string, lockedUntil time.Time, ip string) error {
+	subject := "你的账号已被临时锁定"
+	body := fmt.Sprintf("你好 %s,\n\n你的账号连续多次登录失败 (最后一次来自 %s)，为了保护账号安全，已被临时锁定到 %s。\n\n如果这是你本人的操作，请在锁定结束后重试，或者通过登录页面的“忘记密码”重置密码。如果不是你本人的操作，说明有人可能在尝试猜测你的密码，建议在锁定结束后修改密码并启用两步验证。\n\n谢谢,\nTodo团队",
+		username, ip, lockedUntil.Local().Format("2006-01-02 15:04"))
+	return s.send(email, subject, body, "账号锁定通知邮件")
+}
+
+// send 发送纯文本邮件，kind 为邮件类型，用于日志
+func (s *Sender) send(email, subject, body, kind string) error {
 	to := []string{email} // 收件人列表
 
 	// 根据RFC 822标准构造邮件消息
@@ -38,7 +73,7 @@ func (s *Sender) SendWelcomeEmail(username string, email string, userID uint) er
 		"\r\n"+
 		"%s\r\n", strings.Join(to, ","), s.smtpSender, subject, body))
 
-	log.Printf("尝试发送欢迎邮件到 %s", email)
+	log.Printf("尝试发送%s到 %s", kind, email)
 	// 发送邮件
 	err := smtp.SendMail(s.smtpServer, s.smtpAuth, s.smtpSender, to, emailMsg)
 	if err != nil {
@@ -56,6 +91,6 @@ func (s *Sender) SendWelcomeEmail(username string, email string, userID uint) er
 		return fmt.Errorf("发送邮件到 %s 失败: %w", email, err)
 	}
 
-	log.Printf("成功发送欢迎邮件到 %s", email)
+	log.Printf("成功发送%s到 %s", kind, email)
 	return nil
 }
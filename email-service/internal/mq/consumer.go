@@ -2,6 +2,7 @@ package mq
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
@@ -47,18 +48,20 @@ func NewConsumer(cfg *config.Config, mailSender *mail.Sender) (*Consumer, error)
 	}
 
 	// 声明队列
-	_, err = channel.QueueDeclare(
-		UserRegisteredQueue,
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
+	for _, queue := range []string{UserRegisteredQueue, EmailVerificationQueue, PasswordResetQueue, AccountLockedQueue} {
+		_, err = channel.QueueDeclare(
+			queue,
+			true,  // durable
+			false, // delete when unused
+			false, // exclusive
+			false, // no-wait
+			nil,   // arguments
+		)
+		if err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, err
+		}
 	}
 
 	// 设置QoS
@@ -80,49 +83,102 @@ func NewConsumer(cfg *config.Config, mailSender *mail.Sender) (*Consumer, error)
 	}, nil
 }
 
-// Start 开始消费消息
+// Start 开始消费所有队列的消息
 func (c *Consumer) Start() (<-chan bool, error) {
-	msgs, err := c.channel.Consume(
-		UserRegisteredQueue,
-		"",    // consumer
-		false, // auto-ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,   // args
-	)
-	if err != nil {
-		return nil, err
+	handlers := map[string]func(body []byte) error{
+		UserRegisteredQueue:    c.handleUserRegistered,
+		EmailVerificationQueue: c.handleEmailVerification,
+		PasswordResetQueue:     c.handlePasswordReset,
+		AccountLockedQueue:     c.handleAccountLocked,
 	}
-
 	done := make(chan bool)
+	for queue, handle := range handlers {
+		msgs, err := c.channel.Consume(
+			queue,
+			"",    // consumer
+			false, // auto-ack
+			false, // exclusive
+			false, // no-local
+			false, // no-wait
+			nil,   // args
+		)
+		if err != nil {
+			return nil, err
+		}
+		go consume(queue, msgs, handle)
+	}
+	return done, nil
+}
 
-	go func() {
-		for d := range msgs {
-			log.Printf("收到消息: %s", d.Body)
-			var msg UserRegisteredMessage
-			// 解析JSON消息体
-			err := json.Unmarshal(d.Body, &msg)
-			if err != nil {
-				log.Printf("解析消息错误: %s。可能发送到死信队列或Nack处理。", err)
-				// 处理错误消息: 发送Nack且不重新入队
-				d.Nack(false, false)
-				continue
-			}
-
-			// 发送欢迎邮件
-			err = c.mailSender.SendWelcomeEmail(msg.Username, msg.Email, msg.UserID)
-			if err != nil {
-				log.Printf("发送邮件失败: %v。将消息Nack (不重新入队)。", err)
-				d.Nack(false, false)
-			} else {
-				// 确认消息已成功处理
-				d.Ack(false)
-			}
+// consume 逐条处理队列 queue 的消息，处理成功后确认，失败时 Nack 且不重新入队
+func consume(queue string, msgs <-chan amqp091.Delivery, handle func(body []byte) error) {
+	for d := range msgs {
+		// 验证邮件和重置密码的消息体中有令牌明文，日志中只记录用户 ID
+		log.Printf("收到队列 %s 的消息: user_id=%d", queue, messageUserID(d.Body))
+		if err := handle(d.Body); err != nil {
+			log.Printf("处理消息失败: %v。将消息Nack (不重新入队)。", err)
+			d.Nack(false, false)
+			continue
 		}
-	}()
+		// 确认消息已成功处理
+		d.Ack(false)
+	}
+}
 
-	return done, nil
+// messageUserID 返回消息体中的 user_id，无法解析时返回 0
+func messageUserID(body []byte) uint {
+	var msg struct {
+		UserID uint `json:"user_id"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return 0
+	}
+	return msg.UserID
+}
+
+// handleUserRegistered 发送欢迎邮件
+func (c *Consumer) handleUserRegistered(body []byte) error {
+	var msg UserRegisteredMessage
+	// 解析JSON消息体
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("解析消息错误: %w", err)
+	}
+	return c.mailSender.SendWelcomeEmail(msg.Username, msg.Email, msg.UserID)
+}
+
+// handleEmailVerification 发送带验证链接的邮件，链接已过期的消息直接丢弃
+func (c *Consumer) handleEmailVerification(body []byte) error {
+	var msg EmailVerificationMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("解析消息错误: %w", err)
+	}
+	if !msg.ExpiresAt.IsZero() && time.Now().After(msg.ExpiresAt) {
+		log.Printf("用户 %d 的验证链接已过期，不再发送", msg.UserID)
+		return nil
+	}
+	return c.mailSender.SendVerificationEmail(msg.Username, msg.Email, msg.Token, msg.ExpiresAt)
+}
+
+// handlePasswordReset 发送带重置链接的邮件，链接已过期的消息直接丢弃
+func (c *Consumer) handlePasswordReset(body []byte) error {
+	var msg PasswordResetMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("解析消息错误: %w", err)
+	}
+	if !msg.ExpiresAt.IsZero() && time.Now().After(msg.ExpiresAt) {
+		log.Printf("用户 %d 的重置链接已过期，不再发送", msg.UserID)
+		return nil
+	}
+	return c.mailSender.SendPasswordResetEmail(msg.Username, msg.Email, msg.Token, msg.ExpiresAt)
+}
+
+// handleAccountLocked 通知用户账号因登录失败次数过多被临时锁定
+func (c *Consumer) handleAccountLocked(body []byte) error {
+	var msg AccountLockedMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("解析消息错误: %w", err)
+	}
+	return c.mailSender.SendAccountLockedEmail(msg.Username, msg.Email, msg.LockedUntil, msg.IP)
 }
 
 // Close 关闭连接
@@ -0,0 +1,62 @@
+package mq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// captureLog 在 fn 执行期间收集标准 logger 的输出
+func captureLog(t *testing.T, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(original)
+	fn()
+	return buf.String()
+}
+
+func TestConsumeDoesNotLogTokens(t *testing.T) {
+	const token = "reset-token-that-must-stay-secret"
+	body, err := json.Marshal(PasswordResetMessage{
+		UserID:    42,
+		Username:  "alice",
+		Email:     "alice@example.com",
+		Token:     token,
+		ExpiresAt: time.Now().Add(-time.Minute), // 已过期，handlePasswordReset 不会真正发送邮件
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Consumer{}
+	tests := []struct {
+		name   string
+		handle func(body []byte) error
+	}{
+		{"处理成功", c.handlePasswordReset},
+		{"处理失败", func([]byte) error { return errors.New("smtp unavailable") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := make(chan amqp091.Delivery, 1)
+			msgs <- amqp091.Delivery{Body: body}
+			close(msgs)
+
+			output := captureLog(t, func() { consume(PasswordResetQueue, msgs, tt.handle) })
+			if strings.Contains(output, token) {
+				t.Fatalf("日志中出现了重置令牌明文:\n%s", output)
+			}
+			if !strings.Contains(output, "user_id=42") {
+				t.Errorf("日志中没有记录用户 ID:\n%s", output)
+			}
+		})
+	}
+}
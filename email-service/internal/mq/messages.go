@@ -1,5 +1,7 @@
 package mq
 
+import "time"
+
 // UserRegisteredMessage 定义了预期的消息体结构
 type UserRegisteredMessage struct {
 	UserID   uint   `json:"user_id"`
@@ -7,7 +9,37 @@ type UserRegisteredMessage struct {
 	Email    string `json:"email"`
 }
 
+// EmailVerificationMessage 邮箱验证消息，Token 为验证令牌明文
+type EmailVerificationMessage struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PasswordResetMessage 重置密码消息，Token 为重置令牌明文
+type PasswordResetMessage struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AccountLockedMessage 账号因登录失败次数过多被锁定的安全事件，IP 为最后一次失败登录的来源
+type AccountLockedMessage struct {
+	UserID      uint      `json:"user_id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	LockedUntil time.Time `json:"locked_until"`
+	IP          string    `json:"ip"`
+}
+
 // 队列名称常量
 const (
-	UserRegisteredQueue = "user_registered_queue" // 应与user-service中的队列名称匹配
+	UserRegisteredQueue    = "user_registered_queue"    // 应与user-service中的队列名称匹配
+	EmailVerificationQueue = "email_verification_queue" // 应与user-service中的队列名称匹配
+	PasswordResetQueue     = "password_reset_queue"     // 应与user-service中的队列名称匹配
+	AccountLockedQueue     = "account_locked_queue"     // 应与user-service中的队列名称匹配
 )
@@ -3,3 +3,5 @@ module password-hasher
 go 1.24.1
 
 require golang.org/x/crypto v0.37.0
+
+require golang.org/x/sys v0.32.0 // indirect
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 哈希格式与 user-service 的 internal/password 包一致：
+// argon2id 为 PHC 格式 $argon2id$v=19$m=<KiB>,t=<迭代次数>,p=<并行度>$<盐>$<哈希>，
+// 盐和哈希使用不带填充的标准 Base64；bcrypt 为标准的 $2a$<成本>$... 格式
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// hashArgon2id 用随机盐生成 argon2id 哈希
+func hashArgon2id(password string, memory, iterations uint32, parallelism uint8) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, memory, iterations, parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verify 根据哈希的前缀识别算法并验证密码，返回识别出的算法
+func verify(encoded, password string) (alg string, ok bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		ok, err = verifyArgon2id(encoded, password)
+		return "argon2id", ok, err
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return "bcrypt", false, nil
+		}
+		return "bcrypt", err == nil, err
+	default:
+		return "", false, errors.New("无法识别的哈希格式")
+	}
+}
+
+// verifyArgon2id 使用哈希中记录的参数重新计算并比较
+func verifyArgon2id(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, errors.New("无效的 argon2id 哈希")
+	}
+	var (
+		version           int
+		memory, iteration uint32
+		parallelism       uint8
+	)
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, fmt.Errorf("不支持的 argon2 版本: %s", parts[2])
+	}
+	// 参数为 0 时 argon2.IDKey 会 panic 或得到无意义的结果，与 user-service 一样视为格式错误
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iteration, &parallelism); err != nil ||
+		memory == 0 || iteration == 0 || parallelism == 0 {
+		return false, fmt.Errorf("无效的 argon2id 参数: %s", parts[3])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("无效的 argon2id 盐: %w", err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false, errors.New("无效的 argon2id 哈希值")
+	}
+	key := argon2.IDKey([]byte(password), salt, iteration, memory, parallelism, uint32(len(want)))
+	return subtle.ConstantTimeCompare(key, want) == 1, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,25 +10,66 @@ import (
 )
 
 func main() {
-	// 1. 检查命令行参数
-	if len(os.Args) < 2 {
-		fmt.Println("用法: go run main.go <你的密码>")
-		fmt.Println("或者编译后运行: ./password-hasher <你的密码>")
+	// 1. 解析命令行参数，默认参数与 user-service 的默认配置相同
+	alg := flag.String("alg", "argon2id", "哈希算法: argon2id 或 bcrypt")
+	memory := flag.Uint("m", 64*1024, "argon2id 使用的内存 (KiB)")
+	iterations := flag.Uint("t", 3, "argon2id 迭代次数")
+	parallelism := flag.Uint("p", 4, "argon2id 并行度 (1-255)")
+	cost := flag.Int("cost", bcrypt.DefaultCost, "bcrypt 成本")
+	verifyHash := flag.String("verify", "", "验证密码是否与给定的哈希匹配 (自动识别算法)，而不是生成哈希")
+	flag.Usage = func() {
+		fmt.Println("用法: go run . [-alg argon2id|bcrypt] [-m KiB] [-t 次数] [-p 并行度] [-cost 成本] <你的密码>")
+		fmt.Println("      go run . -verify '<哈希>' <你的密码>")
+		fmt.Println("或者编译后运行: ./password-hasher [选项] <你的密码>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
 		os.Exit(1) // 参数不足，退出
 	}
 
 	// 2. 获取密码
-	password := os.Args[1]
+	password := flag.Arg(0)
+
+	// 3. 验证模式：检查密码与哈希是否匹配
+	if *verifyHash != "" {
+		name, ok, err := verify(*verifyHash, password)
+		if err != nil {
+			log.Fatalf("验证失败: %v", err)
+		}
+		if !ok {
+			fmt.Printf("密码与 %s 哈希不匹配\n", name)
+			os.Exit(1)
+		}
+		fmt.Printf("密码与 %s 哈希匹配\n", name)
+		return
+	}
 
-	// 3. 生成 bcrypt 哈希值
-	// bcrypt.DefaultCost 是推荐的计算成本（当前为 10）
+	// 4. 生成哈希值
 	// 成本越高，哈希越慢，但也越难破解
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	var (
+		hashed string
+		err    error
+	)
+	switch *alg {
+	case "argon2id":
+		if *parallelism < 1 || *parallelism > 255 || *memory < 1 || *iterations < 1 {
+			log.Fatalf("无效的 argon2id 参数: m=%d, t=%d, p=%d", *memory, *iterations, *parallelism)
+		}
+		hashed, err = hashArgon2id(password, uint32(*memory), uint32(*iterations), uint8(*parallelism))
+	case "bcrypt":
+		var b []byte
+		b, err = bcrypt.GenerateFromPassword([]byte(password), *cost)
+		hashed = string(b)
+	default:
+		log.Fatalf("不支持的算法: %s (可选 argon2id、bcrypt)", *alg)
+	}
 	if err != nil {
 		log.Fatalf("生成密码哈希失败: %v", err)
 	}
 
-	// 4. 打印哈希值
+	// 5. 打印哈希值
 	fmt.Printf("密码: %s\n", password)
-	fmt.Printf("Bcrypt 哈希: %s\n", string(hashedPassword))
+	fmt.Printf("%s 哈希: %s\n", *alg, hashed)
 }
@@ -17,10 +17,26 @@ axios.interceptors.request.use(config => {
   return config
 })
 
+// 登录、刷新和登出请求本身返回 401 时不再尝试刷新
+const authURLs = ['/login', '/2fa/verify', '/token/refresh', '/logout']
+
 // 响应拦截器 - 处理401错误（token过期）
+// 访问令牌过期时先用刷新令牌换取新令牌并重试一次，刷新失败再退出登录
 axios.interceptors.response.use(
   response => response,
-  error => {
+  async error => {
+    const config = error.config
+    if (error.response && error.response.status === 401 && config && !config._retried &&
+        !authURLs.includes(config.url) && store.state.refreshToken) {
+      config._retried = true
+      try {
+        const token = await store.dispatch('refreshToken')
+        config.headers.Authorization = `Bearer ${token}`
+        return axios(config)
+      } catch (refreshError) {
+        // 刷新失败，按下面的逻辑退出登录
+      }
+    }
     if (error.response && error.response.status === 401) {
       // 检查是否在登录页，避免无限循环
       if (router.currentRoute.value.name !== 'login') {
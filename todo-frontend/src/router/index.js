@@ -3,6 +3,11 @@ import HomeView from '../views/HomeView.vue'
 import LoginView from '../views/LoginView.vue'
 import RegisterView from '../views/RegisterView.vue'
 import ChangePasswordView from '../views/ChangePasswordView.vue'
+import TwoFactorView from '../views/TwoFactorView.vue'
+import ProfileView from '../views/ProfileView.vue'
+import VerifyEmailView from '../views/VerifyEmailView.vue'
+import ForgotPasswordView from '../views/ForgotPasswordView.vue'
+import ResetPasswordView from '../views/ResetPasswordView.vue'
 import TestLogin from '../views/TestLogin.vue'
 import store from '../store'
 
@@ -23,12 +28,39 @@ const routes = [
     name: 'register',
     component: RegisterView
   },
+  {
+    path: '/verify-email',
+    name: 'verifyEmail',
+    component: VerifyEmailView
+  },
+  {
+    path: '/forgot-password',
+    name: 'forgotPassword',
+    component: ForgotPasswordView
+  },
+  {
+    path: '/reset-password',
+    name: 'resetPassword',
+    component: ResetPasswordView
+  },
   {
     path: '/change-password',
     name: 'changePassword',
     component: ChangePasswordView,
     meta: { requiresAuth: true }
   },
+  {
+    path: '/profile',
+    name: 'profile',
+    component: ProfileView,
+    meta: { requiresAuth: true }
+  },
+  {
+    path: '/two-factor',
+    name: 'twoFactor',
+    component: TwoFactorView,
+    meta: { requiresAuth: true }
+  },
   {
     path: '/test-login',
     name: 'testLogin',
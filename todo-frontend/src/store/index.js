@@ -4,18 +4,25 @@ import axios from 'axios'
 // 不再从 localStorage 加载 user，只加载 token
 // const savedUser = localStorage.getItem('user')
 const savedToken = localStorage.getItem('token')
+const savedRefreshToken = localStorage.getItem('refresh_token')
+
+// 正在进行的刷新请求，多个请求同时收到 401 时共用一次刷新 (刷新令牌只能使用一次)
+let refreshPromise = null
 
 export default createStore({
   state: {
     // user: savedUser ? JSON.parse(savedUser) : null, // 移除 user 加载
     user: null, // 初始 user 为 null
     token: savedToken || null,
+    refreshToken: savedRefreshToken || null,
     todos: []
   },
   getters: {
     isAuthenticated: state => !!state.token,
     // 如果 state.user 为 null，返回默认游客信息
     currentUser: state => state.user || { username: '游客' },
+    // 导航栏等处显示的名称，未设置显示名称时使用用户名
+    displayName: state => (state.user && (state.user.display_name || state.user.username)) || '游客',
     todos: state => state.todos
   },
   mutations: {
@@ -27,10 +34,16 @@ export default createStore({
       state.token = token
       localStorage.setItem('token', token)
     },
+    SET_REFRESH_TOKEN(state, refreshToken) {
+      state.refreshToken = refreshToken
+      localStorage.setItem('refresh_token', refreshToken)
+    },
     CLEAR_AUTH(state) {
       state.user = null // 确保登出时清空 user
       state.token = null
+      state.refreshToken = null
       localStorage.removeItem('token')
+      localStorage.removeItem('refresh_token')
     },
     SET_TODOS(state, todos) {
       state.todos = todos
@@ -54,16 +67,43 @@ export default createStore({
       try {
         // 请求 API 网关的 /api/login
         const response = await axios.post('/login', credentials)
-        // 响应现在包含 { token: '...', username: '...' }
+        // 启用了两步验证时只返回 { two_factor_required: true, challenge_token: '...' }，由调用方继续 verifySecondFactor
+        if (response.data.two_factor_required) {
+          return response
+        }
+        // 响应现在包含 { token: '...', refresh_token: '...', expires_in: 900, user: { username, display_name, ... } }
         commit('SET_TOKEN', response.data.token)
-        // 提交 SET_USER，payload 为 { username: response.data.username }
-        commit('SET_USER', { username: response.data.username })
+        commit('SET_REFRESH_TOKEN', response.data.refresh_token)
+        commit('SET_USER', response.data.user)
         return response
       } catch (error) {
         throw error
       }
     },
 
+    // 两步验证登录的第二步，payload 为 { challenge_token, code }
+    async verifySecondFactor({ commit }, payload) {
+      const response = await axios.post('/2fa/verify', payload)
+      commit('SET_TOKEN', response.data.token)
+      commit('SET_REFRESH_TOKEN', response.data.refresh_token)
+      commit('SET_USER', response.data.user)
+      return response
+    },
+
+    // 获取当前用户的资料，页面刷新后 user 不在 localStorage 中，需要重新获取
+    async fetchProfile({ commit }) {
+      const response = await axios.get('/me')
+      commit('SET_USER', response.data)
+      return response.data
+    },
+
+    // 修改资料，payload 中省略的字段保持不变
+    async updateProfile({ commit }, profile) {
+      const response = await axios.patch('/me', profile)
+      commit('SET_USER', response.data)
+      return response.data
+    },
+
     // 用户注册
     async register({ commit }, userData) { // userData 现在应包含 email
       try {
@@ -77,8 +117,33 @@ export default createStore({
       }
     },
 
+    // 使用刷新令牌换取新的访问令牌，返回新的访问令牌
+    refreshToken({ commit, state }) {
+      if (!state.refreshToken) {
+        return Promise.reject(new Error('没有刷新令牌'))
+      }
+      if (!refreshPromise) {
+        refreshPromise = axios.post('/token/refresh', { refresh_token: state.refreshToken })
+          .then(response => {
+            commit('SET_TOKEN', response.data.token)
+            commit('SET_REFRESH_TOKEN', response.data.refresh_token)
+            return response.data.token
+          })
+          .finally(() => {
+            refreshPromise = null
+          })
+      }
+      return refreshPromise
+    },
+
     // 退出登录
-    logout({ commit }) {
+    logout({ commit, state }) {
+      // 撤销服务端的刷新令牌和当前的访问令牌，失败不影响本地登出。
+      // 请求拦截器异步执行，届时令牌已被清除，因此显式带上访问令牌
+      if (state.refreshToken) {
+        const headers = state.token ? { Authorization: `Bearer ${state.token}` } : {}
+        axios.post('/logout', { refresh_token: state.refreshToken }, { headers }).catch(() => {})
+      }
       commit('CLEAR_AUTH')
       // 可选：可以在这里清除其他状态，例如 todos
       // commit('SET_TODOS', [])
@@ -1,26 +1,80 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	_ "time/tzdata" // 内置时区数据库，保证精简镜像中也能按用户时区解析快速添加文本
 
+	"todo-project/todo-service/internal/cache"
 	"todo-project/todo-service/internal/config"
 	"todo-project/todo-service/internal/db"
+	"todo-project/todo-service/internal/events"
 	"todo-project/todo-service/internal/service"
+	"todo-project/todo-service/internal/worker"
 	pb "todo-project/todo-service/proto/todo"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+// 健康检查中报告缓存状态的服务名，Redis 熔断时为 NOT_SERVING
+const cacheHealthService = "todo.cache"
+
 func main() {
 	// 加载配置
 	cfg := config.Load()
 
+	// todo-service migrate up|down|status: 只执行数据库迁移，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("迁移失败: %v", err)
+		}
+		return
+	}
+
 	// 初始化数据库和Redis
-	dbConn := db.InitDB(cfg)
+	repo := db.InitRepository(cfg)
 	redisClient := db.InitRedis(cfg)
 
+	// 启动变更流订阅，接收所有副本发布的 Todo 变更事件并推送给 WatchTodos
+	feed := events.NewFeed(redisClient)
+	go feed.Run(context.Background())
+
+	// gRPC 健康检查，整体状态 ("") 始终为 SERVING，缓存状态单独报告
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(cacheHealthService, healthpb.HealthCheckResponse_SERVING)
+
+	// Todo 读缓存，Redis 不可用时熔断并定期探测恢复；启用进程内缓存时订阅其他副本的失效广播，
+	// 命中率等计数定期输出到日志
+	todoCache := cache.New(redisClient, cache.Options{
+		LocalSize:        cfg.CacheLocalSize,
+		LocalTTL:         cfg.CacheLocalTTL,
+		FailureThreshold: cfg.CacheFailureThreshold,
+		ProbeInterval:    cfg.CacheProbeInterval,
+		OnAvailabilityChange: func(available bool) {
+			if available {
+				healthServer.SetServingStatus(cacheHealthService, healthpb.HealthCheckResponse_SERVING)
+			} else {
+				healthServer.SetServingStatus(cacheHealthService, healthpb.HealthCheckResponse_NOT_SERVING)
+			}
+		},
+	})
+	go todoCache.Run(context.Background())
+	if cfg.CacheStatsInterval > 0 {
+		go todoCache.ReportStats(context.Background(), cfg.CacheStatsInterval)
+	}
+
+	// 启动自动归档后台任务
+	if cfg.AutoArchiveInterval > 0 {
+		go worker.NewAutoArchiver(repo, redisClient, todoCache, feed, cfg.AutoArchiveInterval).Run(context.Background())
+	} else {
+		log.Printf("AUTO_ARCHIVE_INTERVAL <= 0，自动归档任务未启动")
+	}
+
 	// 启动gRPC服务
 	lis, err := net.Listen("tcp", cfg.GRPCPort)
 	if err != nil {
@@ -28,7 +82,8 @@ func main() {
 	}
 
 	s := grpc.NewServer()
-	pb.RegisterTodoServiceServer(s, service.NewTodoService(dbConn, redisClient))
+	pb.RegisterTodoServiceServer(s, service.NewTodoService(repo, todoCache, feed))
+	healthpb.RegisterHealthServer(s, healthServer)
 	reflection.Register(s)
 
 	log.Printf("Todo service listening on %s (with reflection)", cfg.GRPCPort)
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"todo-project/todo-service/internal/config"
+	"todo-project/todo-service/internal/db"
+)
+
+const migrateUsage = `用法:
+  todo-service migrate up          执行所有未执行的迁移
+  todo-service migrate down [N]    回滚最近执行的 N 个迁移 (默认 1)
+  todo-service migrate status      显示每个迁移的执行状态`
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if cfg.DBDriver == "memory" {
+		return errors.New("DB_DRIVER=memory 不使用数据库，无需迁移")
+	}
+	migrator, err := db.NewMigrator(db.Open(cfg))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("已执行 %s\n", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("没有需要执行的迁移")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("无效的回滚数量: %s", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("已回滚 %s\n", migration)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Println("没有可以回滚的迁移")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "版本\t名称\t状态")
+		for _, s := range statuses {
+			state := "未执行"
+			if s.AppliedAt != nil {
+				state = "已执行于 " + s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if s.Unknown {
+				state += " (当前程序中没有这个迁移)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, state)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("未知的 migrate 命令: %s\n%s", args[0], migrateUsage)
+	}
+}
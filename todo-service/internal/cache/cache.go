@@ -0,0 +1,343 @@
+// Package cache 实现 todo-service 的 Redis 读缓存。
+//
+// 每个用户有一个代数 (generation) 计数器 todo_cache_gen:<user_id>，所有缓存键都带上读取时的代数，
+// 例如 todo_cache:<user_id>:g<gen>:todos。Todo 变更后调用 InvalidateUser 使代数加一，
+// 旧代数的键不会再被读取，只等待过期。读取时先取代数再查数据库，即使并发的读请求在写请求之后
+// 才写回缓存，写入的也是旧代数的键，不会覆盖新数据。
+//
+// 同一个键的并发未命中通过 singleflight 合并为一次加载，TTL 带随机抖动，避免大量键同时过期。
+// Redis 不可用时直接加载数据，不影响请求本身。
+//
+// 可选的进程内 LRU 位于 Redis 之前，热点数据不必每次访问 Redis。InvalidateUser 通过 Redis Pub/Sub
+// 频道 todo_cache_invalidate 广播失效消息，每个副本的 Run 收到后清除本地该用户的条目；
+// 订阅中断期间可能遗漏消息，因此重新订阅时会清空整个本地缓存。
+//
+// Redis 操作连续失败达到阈值后缓存熔断，熔断期间完全绕过缓存 (包括本地缓存) 直接加载数据，
+// 并定期探测 Redis。恢复后先删除熔断期间可能过期的缓存键，再重新启用缓存。
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	keyPrefix    = "todo_cache:"
+	genKeyPrefix = "todo_cache_gen:"
+	// 失效广播频道，消息内容为用户 ID
+	invalidateChannel = "todo_cache_invalidate"
+	// 订阅连接中断后的重连间隔
+	resubscribeDelay = time.Second
+	// TTL 的随机抖动比例，实际 TTL 在 [ttl, ttl*(1+jitterRatio)) 之间
+	jitterRatio = 0.1
+	// 合并后的加载不随发起请求的客户端取消，但最长执行 loadTimeout
+	loadTimeout = 10 * time.Second
+)
+
+// Stats 缓存的累计计数
+type Stats struct {
+	Hits          uint64 // 包括本地命中
+	LocalHits     uint64
+	Misses        uint64
+	Coalesced     uint64 // 未命中时合并到其他请求的加载中的次数
+	Errors        uint64 // Redis 读写或序列化失败的次数
+	Invalidations uint64
+	Bypassed      uint64 // 熔断期间直接加载的次数
+}
+
+// Options 缓存选项
+type Options struct {
+	// 进程内 LRU 最多保存的条目数，小于等于 0 时不启用本地缓存
+	LocalSize int
+	// 本地条目的存活时间，即失效广播丢失时最多返回旧数据的时长
+	LocalTTL time.Duration
+	// Redis 操作连续失败多少次后熔断，默认 3
+	FailureThreshold int
+	// 熔断期间探测 Redis 是否恢复的间隔，默认 5 秒
+	ProbeInterval time.Duration
+	// 熔断和恢复时调用，用于更新健康检查状态
+	OnAvailabilityChange func(available bool)
+}
+
+// Cache 带代数键和 singleflight 的 Redis 缓存，可并发使用
+type Cache struct {
+	rdb   *redis.Client
+	opts  Options
+	local *localCache // 未启用时为 nil
+	group singleflight.Group
+
+	down     atomic.Bool
+	failures atomic.Int64
+
+	hits          atomic.Uint64
+	localHits     atomic.Uint64
+	misses        atomic.Uint64
+	coalesced     atomic.Uint64
+	errors        atomic.Uint64
+	invalidations atomic.Uint64
+	bypassed      atomic.Uint64
+}
+
+// New 创建缓存，需要调用 Run 才能从熔断中恢复以及接收其他副本的失效广播
+func New(rdb *redis.Client, opts Options) *Cache {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultFailureThreshold
+	}
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = defaultProbeInterval
+	}
+	c := &Cache{rdb: rdb, opts: opts}
+	if opts.LocalSize > 0 && opts.LocalTTL > 0 {
+		c.local = newLocalCache(opts.LocalSize, opts.LocalTTL)
+	}
+	return c
+}
+
+func genKey(userID uint) string {
+	return genKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func dataKey(userID uint, gen int64, name string) string {
+	return fmt.Sprintf("%s%d:g%d:%s", keyPrefix, userID, gen, name)
+}
+
+// jitter 在 ttl 基础上增加 [0, ttl*jitterRatio) 的随机时长
+func jitter(ttl time.Duration) time.Duration {
+	if n := int64(float64(ttl) * jitterRatio); n > 0 {
+		return ttl + time.Duration(rand.Int64N(n))
+	}
+	return ttl
+}
+
+// generation 读取用户当前的缓存代数，计数器不存在时为 0
+func (c *Cache) generation(ctx context.Context, userID uint) (int64, error) {
+	gen, err := c.rdb.Get(ctx, genKey(userID)).Int64()
+	c.observe(err)
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// Fetch 读取用户名下名为 name 的缓存，未命中时调用 load 加载并以 JSON 写入缓存，TTL 为 ttl 加随机抖动。
+// load 返回的错误原样返回且不会被缓存。同一个键的并发未命中只会调用一次 load，
+// 每个调用方得到各自反序列化的副本，可以放心修改
+func Fetch[T any](ctx context.Context, c *Cache, userID uint, name string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if c.down.Load() {
+		c.bypassed.Add(1)
+		return load(ctx)
+	}
+	var epoch uint64
+	lkey := localKey(userID, name)
+	if c.local != nil {
+		if data, ok := c.local.get(lkey, time.Now()); ok {
+			var value T
+			if err := json.Unmarshal(data, &value); err == nil {
+				c.hits.Add(1)
+				c.localHits.Add(1)
+				return value, nil
+			}
+		}
+		epoch = c.local.epoch(userID)
+	}
+
+	gen, err := c.generation(ctx, userID)
+	if err != nil {
+		c.errors.Add(1)
+		log.Printf("警告: 读取用户 %d 的缓存代数失败: %v。将直接加载。", userID, err)
+		return load(ctx)
+	}
+	key := dataKey(userID, gen, name)
+
+	cached, err := c.rdb.Get(ctx, key).Bytes()
+	c.observe(err)
+	if err == nil {
+		var value T
+		if unmarshalErr := json.Unmarshal(cached, &value); unmarshalErr == nil {
+			c.hits.Add(1)
+			c.storeLocal(userID, lkey, cached, epoch)
+			return value, nil
+		} else {
+			c.errors.Add(1)
+			log.Printf("警告: 反序列化缓存 %s 失败: %v。将重新加载。", key, unmarshalErr)
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		c.errors.Add(1)
+		log.Printf("警告: 读取缓存 %s 失败: %v。将重新加载。", key, err)
+	}
+	c.misses.Add(1)
+
+	// 加载使用独立的上下文，避免发起加载的请求被取消时其他等待的请求一起失败。
+	// 只有发起加载的调用方的函数会执行，leader 在结果送出之前写入
+	leader := false
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		err = c.rdb.Set(loadCtx, key, data, jitter(ttl)).Err()
+		c.observe(err)
+		if err != nil {
+			c.errors.Add(1)
+			log.Printf("警告: 写入缓存 %s 失败: %v", key, err)
+		}
+		return data, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		// 结果被共享时发起加载的调用方的 Shared 也为 true，不计入合并次数
+		if res.Shared && !leader {
+			c.coalesced.Add(1)
+		}
+		data := res.Val.([]byte)
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			return zero, err
+		}
+		c.storeLocal(userID, lkey, data, epoch)
+		return value, nil
+	}
+}
+
+func (c *Cache) storeLocal(userID uint, key string, data []byte, epoch uint64) {
+	if c.local != nil {
+		c.local.set(userID, key, data, epoch, time.Now())
+	}
+}
+
+// InvalidateUser 使用户的所有缓存失效，并广播给其他副本清除本地缓存。
+// 失败时只记录日志，旧缓存会在 TTL 到期后失效
+func (c *Cache) InvalidateUser(ctx context.Context, userID uint) {
+	c.invalidations.Add(1)
+	// 熔断期间的缓存会在恢复时整体清除
+	if c.down.Load() {
+		return
+	}
+	// 未启用本地缓存的副本也要广播，其他副本可能启用了
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, genKey(userID))
+		pipe.Publish(ctx, invalidateChannel, strconv.FormatUint(uint64(userID), 10))
+		return nil
+	})
+	c.observe(err)
+	// 在代数递增之后清除本地条目，之前读到旧代数的加载不会再写入本地缓存
+	if c.local != nil {
+		c.local.evictUser(userID)
+	}
+	if err != nil {
+		c.errors.Add(1)
+		log.Printf("警告: 使用户 %d 的缓存失效失败: %v", userID, err)
+	}
+}
+
+// Run 监控 Redis 的健康状态，启用本地缓存时同时接收其他副本的失效广播，直到 ctx 被取消
+func (c *Cache) Run(ctx context.Context) {
+	if c.local != nil {
+		go c.subscribe(ctx)
+	}
+	c.monitor(ctx)
+}
+
+// subscribe 接收失效广播并清除本地缓存，订阅中断后自动重连
+func (c *Cache) subscribe(ctx context.Context) {
+	log.Printf("本地缓存失效广播订阅已启动")
+	for ctx.Err() == nil {
+		if err := c.receive(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("警告: 本地缓存失效广播订阅中断: %v，%s 后重连", err, resubscribeDelay)
+		}
+		// 连接中断期间可能遗漏了失效消息
+		c.local.purge()
+		select {
+		case <-ctx.Done():
+		case <-time.After(resubscribeDelay):
+		}
+	}
+	log.Printf("本地缓存失效广播订阅已停止")
+}
+
+func (c *Cache) receive(ctx context.Context) error {
+	pubsub := c.rdb.Subscribe(ctx, invalidateChannel)
+	defer pubsub.Close()
+	// ReceiveMessage 不会因 ctx 取消而返回，关闭连接使其退出
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	// 订阅成功前的失效消息已经遗漏
+	c.local.purge()
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		userID, err := strconv.ParseUint(msg.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		c.local.evictUser(uint(userID))
+	}
+}
+
+// Stats 返回缓存的累计计数
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		LocalHits:     c.localHits.Load(),
+		Misses:        c.misses.Load(),
+		Coalesced:     c.coalesced.Load(),
+		Errors:        c.errors.Load(),
+		Invalidations: c.invalidations.Load(),
+		Bypassed:      c.bypassed.Load(),
+	}
+}
+
+// ReportStats 每隔 interval 在日志中输出一次缓存计数和命中率，直到 ctx 被取消
+func (c *Cache) ReportStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last Stats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur := c.Stats()
+		if cur == last {
+			continue
+		}
+		hits, misses := cur.Hits-last.Hits, cur.Misses-last.Misses
+		var ratio float64
+		if hits+misses > 0 {
+			ratio = float64(hits) / float64(hits+misses) * 100
+		}
+		log.Printf("缓存统计 (最近 %s): 命中 %d (本地 %d), 未命中 %d (合并 %d), 命中率 %.1f%%, 失效 %d, 错误 %d, 熔断绕过 %d",
+			interval, hits, cur.LocalHits-last.LocalHits, misses, cur.Coalesced-last.Coalesced, ratio, cur.Invalidations-last.Invalidations, cur.Errors-last.Errors, cur.Bypassed-last.Bypassed)
+		last = cur
+	}
+}
@@ -0,0 +1,248 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestJitter(t *testing.T) {
+	ttl := 10 * time.Minute
+	max := ttl + time.Duration(float64(ttl)*jitterRatio)
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		got := jitter(ttl)
+		if got < ttl || got >= max {
+			t.Fatalf("jitter(%s) = %s, 应在 [%s, %s) 之间", ttl, got, ttl, max)
+		}
+		seen[got] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("jitter(%s) 没有产生随机抖动", ttl)
+	}
+	if got := jitter(time.Nanosecond); got != time.Nanosecond {
+		t.Errorf("jitter(1ns) = %s, 期望不变", got)
+	}
+}
+
+func TestDataKeyIncludesGeneration(t *testing.T) {
+	if a, b := dataKey(7, 1, "todos"), dataKey(7, 2, "todos"); a == b {
+		t.Errorf("不同代数的键相同: %s", a)
+	}
+	if got, want := dataKey(7, 3, "todo:42"), "todo_cache:7:g3:todo:42"; got != want {
+		t.Errorf("dataKey = %s, 期望 %s", got, want)
+	}
+}
+
+func TestLocalCache(t *testing.T) {
+	now := time.Now()
+	l := newLocalCache(2, time.Minute)
+
+	l.set(1, localKey(1, "a"), []byte("1a"), l.epoch(1), now)
+	l.set(2, localKey(2, "a"), []byte("2a"), l.epoch(2), now)
+	if _, ok := l.get(localKey(1, "a"), now); !ok {
+		t.Fatal("期望命中 1:a")
+	}
+	// 超出容量时淘汰最久未访问的 2:a
+	l.set(1, localKey(1, "b"), []byte("1b"), l.epoch(1), now)
+	if _, ok := l.get(localKey(2, "a"), now); ok {
+		t.Error("2:a 应已被淘汰")
+	}
+	if l.len() != 2 {
+		t.Errorf("len = %d, 期望 2", l.len())
+	}
+
+	if _, ok := l.get(localKey(1, "a"), now.Add(2*time.Minute)); ok {
+		t.Error("过期的条目不应命中")
+	}
+
+	l.evictUser(1)
+	if l.len() != 0 {
+		t.Errorf("清除用户 1 后 len = %d, 期望 0", l.len())
+	}
+
+	// 加载期间发生失效时放弃写入
+	epoch := l.epoch(3)
+	l.evictUser(3)
+	l.set(3, localKey(3, "a"), []byte("3a"), epoch, now)
+	if _, ok := l.get(localKey(3, "a"), now); ok {
+		t.Error("失效前开始的加载不应写入本地缓存")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []bool
+	c := New(redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}), Options{
+		LocalSize:            10,
+		LocalTTL:             time.Minute,
+		FailureThreshold:     2,
+		OnAvailabilityChange: func(available bool) { changes = append(changes, available) },
+	})
+	failure := errors.New("connection refused")
+
+	c.observe(failure)
+	c.observe(redis.Nil) // 成功的操作重置连续失败计数
+	c.observe(failure)
+	c.observe(context.Canceled)
+	if !c.Available() {
+		t.Fatal("未连续失败到阈值时不应熔断")
+	}
+	c.observe(failure)
+	if c.Available() {
+		t.Fatal("连续失败到阈值后应熔断")
+	}
+	if len(changes) != 1 || changes[0] {
+		t.Errorf("状态变化回调 = %v, 期望 [false]", changes)
+	}
+
+	// 熔断期间不访问 Redis，直接加载
+	got, err := Fetch(context.Background(), c, 1, "todos", time.Minute, func(context.Context) (string, error) { return "db", nil })
+	if err != nil || got != "db" {
+		t.Fatalf("Fetch = %q, %v, 期望直接加载", got, err)
+	}
+	if stats := c.Stats(); stats.Bypassed != 1 || stats.Misses != 0 {
+		t.Errorf("Stats = %+v, 期望 Bypassed 为 1 且没有未命中", stats)
+	}
+}
+
+// newTestCache 创建连接到 miniredis 的缓存
+func newTestCache(t *testing.T, opts Options) *Cache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return New(rdb, opts)
+}
+
+// waitFor 等待 cond 成立，超时后测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestFetchStaleLoadAfterInvalidate 失效之前开始的加载在失效之后才写回，下一次读取不能返回这份旧数据
+func TestFetchStaleLoadAfterInvalidate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts Options
+	}{
+		{name: "只有 Redis", opts: Options{}},
+		{name: "启用本地缓存", opts: Options{LocalSize: 10, LocalTTL: time.Minute}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newTestCache(t, tt.opts)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			done := make(chan string)
+			go func() {
+				got, err := Fetch(ctx, c, 1, "todos", time.Minute, func(context.Context) (string, error) {
+					close(started)
+					<-release
+					return "旧数据", nil
+				})
+				if err != nil {
+					t.Errorf("Fetch 失败: %v", err)
+				}
+				done <- got
+			}()
+
+			// 读请求已经取得旧代数并开始加载，此时写请求使缓存失效，之后旧的加载才完成并写回
+			<-started
+			c.InvalidateUser(ctx, 1)
+			close(release)
+			if got := <-done; got != "旧数据" {
+				t.Fatalf("并发的读请求得到 %q, 期望 旧数据", got)
+			}
+
+			loads := 0
+			got, err := Fetch(ctx, c, 1, "todos", time.Minute, func(context.Context) (string, error) {
+				loads++
+				return "新数据", nil
+			})
+			if err != nil || got != "新数据" || loads != 1 {
+				t.Errorf("失效后 Fetch = %q, %v (加载 %d 次), 期望重新加载得到 新数据", got, err, loads)
+			}
+		})
+	}
+}
+
+// TestFetchCoalesces 同一个键的并发未命中只加载一次，每个调用方得到独立的副本
+func TestFetchCoalesces(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, Options{})
+
+	const callers = 10
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) ([]string, error) {
+		loads.Add(1)
+		<-release
+		return []string{"a", "b"}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got, err := Fetch(ctx, c, 1, "todos", time.Minute, load)
+			if err != nil {
+				t.Errorf("Fetch 失败: %v", err)
+			}
+			results[i] = got
+		}(i)
+	}
+	// 所有调用方都未命中并等待同一次加载后再让加载完成
+	waitFor(t, "所有调用方未命中", func() bool { return c.Stats().Misses == callers })
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("load 被调用 %d 次, 期望 1 次", n)
+	}
+	if stats := c.Stats(); stats.Coalesced != callers-1 {
+		t.Errorf("Coalesced = %d, 期望 %d", stats.Coalesced, callers-1)
+	}
+	results[0][0] = "changed"
+	for i := 1; i < callers; i++ {
+		if len(results[i]) != 2 || results[i][0] != "a" {
+			t.Fatalf("第 %d 个调用方得到 %v, 期望 [a b] 且不受其他调用方修改的影响", i, results[i])
+		}
+	}
+
+	// 加载结果已写入 Redis，之后的读取直接命中
+	got, err := Fetch(ctx, c, 1, "todos", time.Minute, load)
+	if err != nil || len(got) != 2 || loads.Load() != 1 {
+		t.Errorf("再次 Fetch = %v, %v (加载 %d 次), 期望命中缓存", got, err, loads.Load())
+	}
+}
+
+// TestFetchDoesNotCacheErrors 加载失败时不写入缓存，下一次读取重新加载
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache(t, Options{})
+	failure := errors.New("数据库不可用")
+
+	if _, err := Fetch(ctx, c, 1, "todos", time.Minute, func(context.Context) (string, error) { return "", failure }); !errors.Is(err, failure) {
+		t.Fatalf("Fetch 错误 = %v, 期望 %v", err, failure)
+	}
+	got, err := Fetch(ctx, c, 1, "todos", time.Minute, func(context.Context) (string, error) { return "ok", nil })
+	if err != nil || got != "ok" {
+		t.Errorf("加载失败后 Fetch = %q, %v, 期望重新加载", got, err)
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultProbeInterval    = 5 * time.Second
+	// 恢复时每批删除的键数
+	flushBatchSize = 500
+)
+
+// Available 报告缓存当前是否可用。Redis 熔断期间为 false，此时所有读取直接加载数据
+func (c *Cache) Available() bool {
+	return !c.down.Load()
+}
+
+// observe 记录一次 Redis 操作的结果。连续失败达到阈值后熔断，之后由 monitor 探测恢复
+func (c *Cache) observe(err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		c.failures.Store(0)
+		return
+	}
+	// 调用方取消的请求不代表 Redis 故障
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if c.failures.Add(1) >= int64(c.opts.FailureThreshold) {
+		c.trip(err)
+	}
+}
+
+// trip 熔断：停止访问 Redis，本地缓存可能错过了失效广播，一并清空
+func (c *Cache) trip(err error) {
+	if !c.down.CompareAndSwap(false, true) {
+		return
+	}
+	if c.local != nil {
+		c.local.purge()
+	}
+	log.Printf("警告: Redis 不可用 (%v)，缓存已熔断，每 %s 探测一次", err, c.opts.ProbeInterval)
+	c.notify(false)
+}
+
+// monitor 启动时检查一次 Redis，之后每隔 ProbeInterval 探测熔断中的 Redis 是否恢复，直到 ctx 被取消
+func (c *Cache) monitor(ctx context.Context) {
+	if err := c.rdb.Ping(ctx).Err(); err != nil && ctx.Err() == nil {
+		c.trip(err)
+	}
+	ticker := time.NewTicker(c.opts.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.down.Load() {
+			c.recover(ctx)
+		}
+	}
+}
+
+// recover 探测 Redis，恢复后删除所有缓存键再重新启用缓存。
+// 熔断期间的写操作没有递增代数，Redis 中残留的缓存可能已经过期
+func (c *Cache) recover(ctx context.Context) {
+	if err := c.rdb.Ping(ctx).Err(); err != nil {
+		return
+	}
+	flushed, err := c.flush(ctx)
+	if err != nil {
+		log.Printf("警告: Redis 已恢复，但清除缓存失败: %v，稍后重试", err)
+		return
+	}
+	if c.local != nil {
+		c.local.purge()
+	}
+	c.failures.Store(0)
+	c.down.Store(false)
+	log.Printf("Redis 已恢复，清除了 %d 个可能过期的缓存键，缓存重新启用", flushed)
+	c.notify(true)
+}
+
+// flush 删除所有数据缓存键，代数计数器保留
+func (c *Cache) flush(ctx context.Context) (int, error) {
+	var flushed int
+	iter := c.rdb.Scan(ctx, 0, keyPrefix+"*", flushBatchSize).Iterator()
+	keys := make([]string, 0, flushBatchSize)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == flushBatchSize {
+			if err := c.rdb.Unlink(ctx, keys...).Err(); err != nil {
+				return flushed, err
+			}
+			flushed += len(keys)
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return flushed, err
+	}
+	if len(keys) > 0 {
+		if err := c.rdb.Unlink(ctx, keys...).Err(); err != nil {
+			return flushed, err
+		}
+		flushed += len(keys)
+	}
+	return flushed, nil
+}
+
+func (c *Cache) notify(available bool) {
+	if c.opts.OnAvailabilityChange != nil {
+		c.opts.OnAvailabilityChange(available)
+	}
+}
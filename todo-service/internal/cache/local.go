@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 失效纪元的分片数。按用户 ID 取模，同一分片内任一用户失效都会拒绝正在进行的写入
+const localEpochShards = 256
+
+type localEntry struct {
+	key       string
+	userID    uint
+	data      []byte
+	expiresAt time.Time
+}
+
+// localCache 进程内的 LRU 缓存，保存序列化后的值。条目不带代数，
+// 依靠失效广播按用户清除，TTL 限制广播丢失时数据过期的最长时间
+type localCache struct {
+	size int
+	ttl  time.Duration
+
+	mu     sync.Mutex
+	ll     *list.List
+	items  map[string]*list.Element
+	byUser map[uint]map[*list.Element]struct{}
+	// 每次失效时递增。加载开始前记录纪元，写入时纪元已变化说明加载期间发生过失效，放弃写入
+	epochs [localEpochShards]uint64
+}
+
+func newLocalCache(size int, ttl time.Duration) *localCache {
+	return &localCache{
+		size:   size,
+		ttl:    ttl,
+		ll:     list.New(),
+		items:  make(map[string]*list.Element),
+		byUser: make(map[uint]map[*list.Element]struct{}),
+	}
+}
+
+func localKey(userID uint, name string) string {
+	return strconv.FormatUint(uint64(userID), 10) + ":" + name
+}
+
+func (l *localCache) get(key string, now time.Time) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*localEntry)
+	if now.After(entry.expiresAt) {
+		l.remove(elem)
+		return nil, false
+	}
+	l.ll.MoveToFront(elem)
+	return entry.data, true
+}
+
+func (l *localCache) epoch(userID uint) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.epochs[userID%localEpochShards]
+}
+
+// set 写入条目，epoch 为加载开始前读取的纪元，期间发生过失效时不写入
+func (l *localCache) set(userID uint, key string, data []byte, epoch uint64, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.epochs[userID%localEpochShards] != epoch {
+		return
+	}
+	if elem, ok := l.items[key]; ok {
+		l.remove(elem)
+	}
+	elem := l.ll.PushFront(&localEntry{key: key, userID: userID, data: data, expiresAt: now.Add(l.ttl)})
+	l.items[key] = elem
+	if l.byUser[userID] == nil {
+		l.byUser[userID] = make(map[*list.Element]struct{})
+	}
+	l.byUser[userID][elem] = struct{}{}
+	for l.ll.Len() > l.size {
+		l.remove(l.ll.Back())
+	}
+}
+
+// evictUser 清除用户的所有条目
+func (l *localCache) evictUser(userID uint) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.epochs[userID%localEpochShards]++
+	for elem := range l.byUser[userID] {
+		l.remove(elem)
+	}
+}
+
+// purge 清除所有条目，用于失效广播可能丢失的情况
+func (l *localCache) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.epochs {
+		l.epochs[i]++
+	}
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+	l.byUser = make(map[uint]map[*list.Element]struct{})
+}
+
+func (l *localCache) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
+
+// remove 删除条目，调用方需持有 mu
+func (l *localCache) remove(elem *list.Element) {
+	entry := elem.Value.(*localEntry)
+	l.ll.Remove(elem)
+	delete(l.items, entry.key)
+	if userElems := l.byUser[entry.userID]; userElems != nil {
+		delete(userElems, elem)
+		if len(userElems) == 0 {
+			delete(l.byUser, entry.userID)
+		}
+	}
+}
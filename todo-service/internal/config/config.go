@@ -4,11 +4,21 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
+	// 存储后端: mysql (默认)、postgres、sqlite 或 memory，见 repository 包
+	DBDriver string
+	// DBDriver 为 sqlite 时的数据库文件路径
+	SQLitePath string
+	// DBDriver 为 postgres 时连接使用的 sslmode
+	DBSSLMode string
+	// 启动时是否自动执行未执行的数据库迁移。为 false 时需要先运行 todo-service migrate up
+	MigrateOnStart bool
+
 	DBUser    string
 	DBPass    string
 	DBHost    string
@@ -18,6 +28,19 @@ type Config struct {
 	RedisPass string
 	RedisDB   int
 	GRPCPort  string
+
+	// 自动归档后台任务的执行间隔，小于等于 0 时不启动该任务
+	AutoArchiveInterval time.Duration
+	// 缓存命中率等计数输出到日志的间隔，小于等于 0 时不输出
+	CacheStatsInterval time.Duration
+	// 进程内 LRU 缓存的最大条目数，小于等于 0 时只使用 Redis 缓存
+	CacheLocalSize int
+	// 进程内缓存条目的存活时间，限制失效广播丢失时返回旧数据的最长时间
+	CacheLocalTTL time.Duration
+	// Redis 操作连续失败多少次后缓存熔断
+	CacheFailureThreshold int
+	// 缓存熔断期间探测 Redis 是否恢复的间隔
+	CacheProbeInterval time.Duration
 }
 
 func Load() *Config {
@@ -26,19 +49,42 @@ func Load() *Config {
 		log.Printf("警告: 加载 ../.env 文件失败: %v. 将依赖已设置的环境变量。", err)
 	}
 
+	dbDriver := getEnvOrDefault("DB_DRIVER", "mysql")
 	return &Config{
+		DBDriver:   dbDriver,
+		SQLitePath: getEnvOrDefault("SQLITE_PATH", "todo.db"),
+		DBSSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
+
+		MigrateOnStart: getEnvOrDefaultBool("DB_MIGRATE_ON_START", true),
+
 		DBUser:    getEnvOrDefault("DB_USER", "root"),
 		DBPass:    os.Getenv("DB_PASSWORD"),
 		DBHost:    getEnvOrDefault("DB_HOST", "localhost"),
-		DBPort:    getEnvOrDefault("DB_PORT", "3306"),
+		DBPort:    getEnvOrDefault("DB_PORT", defaultDBPort(dbDriver)),
 		DBName:    getEnvOrDefault("DB_NAME", "todo"),
 		RedisAddr: getEnvOrDefault("REDIS_ADDR", "localhost:6379"),
 		RedisPass: getEnvOrDefault("REDIS_PASSWORD", ""),
 		RedisDB:   getEnvOrDefaultInt("REDIS_DB", 0),
 		GRPCPort:  ":50052",
+
+		AutoArchiveInterval: getEnvOrDefaultDuration("AUTO_ARCHIVE_INTERVAL", time.Hour),
+		CacheStatsInterval:  getEnvOrDefaultDuration("CACHE_STATS_INTERVAL", time.Minute),
+		CacheLocalSize:      getEnvOrDefaultInt("CACHE_LOCAL_SIZE", 0),
+		CacheLocalTTL:       getEnvOrDefaultDuration("CACHE_LOCAL_TTL", 30*time.Second),
+
+		CacheFailureThreshold: getEnvOrDefaultInt("CACHE_FAILURE_THRESHOLD", 3),
+		CacheProbeInterval:    getEnvOrDefaultDuration("CACHE_PROBE_INTERVAL", 5*time.Second),
 	}
 }
 
+// defaultDBPort 返回数据库驱动的默认端口
+func defaultDBPort(driver string) string {
+	if driver == "postgres" {
+		return "5432"
+	}
+	return "3306"
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -58,3 +104,29 @@ func getEnvOrDefaultInt(key string, defaultValue int) int {
 	}
 	return value
 }
+
+func getEnvOrDefaultDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Printf("警告: 环境变量 %s=%q 不是有效的时长，使用默认值 %s", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvOrDefaultBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("警告: 环境变量 %s=%q 不是有效的布尔值，使用默认值 %t", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -2,16 +2,17 @@ package db
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"os"
 	"strconv"
 
 	"todo-project/todo-service/internal/config"
-	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/migrate"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/migrations"
 
+	"github.com/glebarez/sqlite"
 	"github.com/go-redis/redis/v8"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -19,13 +20,88 @@ var (
 	ctx = context.Background()
 )
 
+// 迁移记录中 todo-service 的服务名
+const migrationService = "todo-service"
+
+// InitRepository 按 cfg.DBDriver 创建存储后端。cfg.MigrateOnStart 为 true 时先执行未执行的数据库迁移
+func InitRepository(cfg *config.Config) repository.TodoRepository {
+	if cfg.DBDriver == "memory" {
+		log.Printf("使用内存存储，服务重启后数据将丢失")
+		return repository.NewMemory()
+	}
+	db := Open(cfg)
+	if cfg.MigrateOnStart {
+		if err := Migrate(db); err != nil {
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
+	} else {
+		log.Printf("DB_MIGRATE_ON_START=false，跳过数据库迁移")
+	}
+	return repository.NewGorm(db)
+}
+
+// Open 按 cfg.DBDriver 连接 MySQL、PostgreSQL 或 SQLite，不执行迁移
+func Open(cfg *config.Config) *gorm.DB {
+	switch cfg.DBDriver {
+	case "mysql", "postgres":
+		return InitDB(cfg)
+	case "sqlite":
+		return initSQLite(cfg)
+	default:
+		log.Fatalf("不支持的 DB_DRIVER: %s (可选 mysql、postgres、sqlite、memory)", cfg.DBDriver)
+		return nil
+	}
+}
+
+// NewMigrator 创建 todo-service 的迁移执行器，迁移文件见 migrations 目录
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, db.Dialector.Name(), migrationService, migrations.FS)
+}
+
+// Migrate 执行所有未执行的迁移。多个副本同时启动时只有一个执行，其余等待
+func Migrate(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		log.Printf("已执行数据库迁移 %s", migration)
+	}
+	if err == nil && len(applied) == 0 {
+		log.Printf("数据库结构已是最新")
+	}
+	return err
+}
+
+// initSQLite 打开 SQLite 数据库文件。SQLite 同一时间只允许一个写事务，因此只使用一个连接，
+// 所有数据库操作串行执行
+func initSQLite(cfg *config.Config) *gorm.DB {
+	log.Printf("SQLite 数据库文件: %s", cfg.SQLitePath)
+	db, err := gorm.Open(sqlite.Open(cfg.SQLitePath+"?_pragma=busy_timeout(5000)"), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("无法打开 SQLite 数据库: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("无法打开 SQLite 数据库: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+// InitDB 连接 MySQL 或 PostgreSQL (由 cfg.DBDriver 决定)
 func InitDB(cfg *config.Config) *gorm.DB {
 	// 检查当前环境
 	appEnv := os.Getenv("APP_ENV")
 	if appEnv == "" {
 		appEnv = "development"
 	}
-	log.Printf("当前运行环境: %s", appEnv)
+	log.Printf("当前运行环境: %s, 数据库驱动: %s", appEnv, cfg.DBDriver)
 
 	// 根据环境选择不同的配置
 	if appEnv == "production" || appEnv == "container" {
@@ -44,7 +120,7 @@ func InitDB(cfg *config.Config) *gorm.DB {
 			cfg.DBHost = dbHost
 		} else {
 			// 容器环境默认使用服务名作为主机名
-			cfg.DBHost = "mysql"
+			cfg.DBHost = cfg.DBDriver
 		}
 		if dbPort := os.Getenv("DB_PORT"); dbPort != "" {
 			cfg.DBPort = dbPort
@@ -63,28 +139,26 @@ func InitDB(cfg *config.Config) *gorm.DB {
 		cfg.DBHost = "127.0.0.1" // 本地开发使用 localhost
 		cfg.DBUser = "root"      // 本地开发使用 root
 		cfg.DBPass = ""          // 本地测试密码
+		if cfg.DBDriver == "postgres" {
+			cfg.DBUser = "postgres" // PostgreSQL 的默认超级用户
+		}
 
 		log.Printf("开发/测试环境 - 修改后配置: DBUser=%s, DBHost=%s, DBPort=%s, DBName=%s",
 			cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName)
-
+	dsn, redacted, err := DSN(cfg)
+	if err != nil {
+		log.Fatalf("无法初始化数据库: %v", err)
+	}
 	// 打印DSN（注意不要打印密码）
-	log.Printf("数据库连接DSN: %s:***@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	log.Printf("数据库连接DSN: %s", redacted)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector(cfg.DBDriver, dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatalf("无法初始化数据库: %v", err)
 	}
 	log.Println("成功连接到数据库")
-
-	if err := db.AutoMigrate(&model.Todo{}, &model.BatchOperationLog{}); err != nil {
-		log.Fatalf("数据库迁移失败: %v", err)
-	}
-
 	return db
 }
 
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"testing"
+
+	"todo-project/todo-service/internal/config"
+)
+
+func TestDSN(t *testing.T) {
+	cases := []struct {
+		name          string
+		cfg           config.Config
+		dsn, redacted string
+	}{
+		{
+			name:     "mysql",
+			cfg:      config.Config{DBDriver: "mysql", DBUser: "root", DBPass: "secret", DBHost: "db", DBPort: "3306", DBName: "todo"},
+			dsn:      "root:secret@tcp(db:3306)/todo?charset=utf8mb4&parseTime=True&loc=Local",
+			redacted: "root:***@tcp(db:3306)/todo?charset=utf8mb4&parseTime=True&loc=Local",
+		},
+		{
+			name:     "postgres",
+			cfg:      config.Config{DBDriver: "postgres", DBUser: "todo", DBPass: "p@ss/word", DBHost: "db", DBPort: "5432", DBName: "todo", DBSSLMode: "require"},
+			dsn:      "postgres://todo:p%40ss%2Fword@db:5432/todo?sslmode=require",
+			redacted: "postgres://todo:xxxxx@db:5432/todo?sslmode=require",
+		},
+		{
+			name:     "ipv6",
+			cfg:      config.Config{DBDriver: "postgres", DBUser: "todo", DBHost: "::1", DBPort: "5432", DBName: "todo", DBSSLMode: "disable"},
+			dsn:      "postgres://todo:@[::1]:5432/todo?sslmode=disable",
+			redacted: "postgres://todo:xxxxx@[::1]:5432/todo?sslmode=disable",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dsn, redacted, err := DSN(&c.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dsn != c.dsn {
+				t.Errorf("dsn = %q, want %q", dsn, c.dsn)
+			}
+			if redacted != c.redacted {
+				t.Errorf("redacted = %q, want %q", redacted, c.redacted)
+			}
+		})
+	}
+
+	if _, _, err := DSN(&config.Config{DBDriver: "oracle"}); err == nil {
+		t.Error("不支持的驱动应返回错误")
+	}
+}
@@ -0,0 +1,305 @@
+// Package events 实现 Todo 变更事件的发布与订阅 (变更流)。
+//
+// 每个用户的事件追加到 Redis Stream todo_events:<user_id>，Stream 条目 ID 即事件的恢复令牌
+// (resume token)。追加与 PUBLISH 在同一个 Lua 脚本中执行，因此实时消息的顺序与 Stream 中的顺序一致。
+// 每个副本通过一个 PSUBSCRIBE 连接接收所有用户的实时事件并分发给本地订阅者；
+// 订阅者消费过慢或订阅连接中断时会收到缺口 (gap) 通知，此时应从 Stream 中补读。
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "todo-project/todo-service/proto/todo"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	streamKeyPrefix = "todo_events:"
+	channelPrefix   = "todo_events_live:"
+	// StreamMaxLen 每个用户保留的事件数 (近似值)，更早的恢复令牌会失效
+	StreamMaxLen = 1000
+	// 每个订阅者缓冲的实时事件数，缓冲满时改为通知缺口
+	subscriberBuffer = 64
+	// 订阅连接中断后的重连间隔
+	resubscribeDelay = time.Second
+)
+
+var (
+	// ErrInvalidToken 恢复令牌格式错误
+	ErrInvalidToken = errors.New("invalid resume token")
+	// ErrTokenExpired 恢复令牌之后的部分事件已被清理
+	ErrTokenExpired = errors.New("resume token expired")
+)
+
+// 追加事件并发布实时消息，返回新条目的 ID
+var publishScript = redis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'e', ARGV[2])
+redis.call('PUBLISH', ARGV[3], id .. '\n' .. ARGV[2])
+return id
+`)
+
+// Feed 变更流，同时负责发布事件和向本地订阅者分发实时事件
+type Feed struct {
+	rdb *redis.Client
+
+	mu   sync.Mutex
+	subs map[uint]map[*Subscription]struct{}
+}
+
+// NewFeed 创建变更流，需要调用 Run 才能接收实时事件
+func NewFeed(rdb *redis.Client) *Feed {
+	return &Feed{rdb: rdb, subs: make(map[uint]map[*Subscription]struct{})}
+}
+
+func streamKey(userID uint) string {
+	return streamKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func channelName(userID uint) string {
+	return channelPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Publish 按顺序发布用户的事件，并将分配的恢复令牌写回每个事件
+func (f *Feed) Publish(ctx context.Context, userID uint, events ...*pb.TodoEvent) error {
+	for _, event := range events {
+		event.ResumeToken = ""
+		payload, err := proto.Marshal(event)
+		if err != nil {
+			return err
+		}
+		id, err := publishScript.Run(ctx, f.rdb, []string{streamKey(userID)}, StreamMaxLen, payload, channelName(userID)).Text()
+		if err != nil {
+			return err
+		}
+		event.ResumeToken = id
+	}
+	return nil
+}
+
+// Latest 返回用户最新事件的恢复令牌，没有事件时返回 "0-0"
+func (f *Feed) Latest(ctx context.Context, userID uint) (string, error) {
+	entries, err := f.rdb.XRevRangeN(ctx, streamKey(userID), "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "0-0", nil
+	}
+	return entries[0].ID, nil
+}
+
+// Since 返回 token 之后的所有事件。token 之后的事件已被清理时返回 ErrTokenExpired
+func (f *Feed) Since(ctx context.Context, userID uint, token string) ([]*pb.TodoEvent, error) {
+	if err := ValidateToken(token); err != nil {
+		return nil, err
+	}
+	key := streamKey(userID)
+	pipe := f.rdb.Pipeline()
+	lenCmd := pipe.XLen(ctx, key)
+	firstCmd := pipe.XRangeN(ctx, key, "-", "+", 1)
+	rangeCmd := pipe.XRange(ctx, key, token, "+")
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	// 使用近似裁剪，长度达到上限说明发生过裁剪
+	if first := firstCmd.Val(); len(first) > 0 && lenCmd.Val() >= StreamMaxLen && CompareTokens(token, first[0].ID) < 0 {
+		return nil, ErrTokenExpired
+	}
+
+	var events []*pb.TodoEvent
+	for _, entry := range rangeCmd.Val() {
+		if entry.ID == token {
+			continue
+		}
+		payload, _ := entry.Values["e"].(string)
+		event, err := decodeEvent(entry.ID, payload)
+		if err != nil {
+			log.Printf("警告: 解析用户 %d 的事件 %s 失败: %v", userID, entry.ID, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func decodeEvent(id, payload string) (*pb.TodoEvent, error) {
+	var event pb.TodoEvent
+	if err := proto.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, err
+	}
+	event.ResumeToken = id
+	return &event, nil
+}
+
+// ValidateToken 检查恢复令牌是否为合法的 Stream 条目 ID (<毫秒>-<序号>)
+func ValidateToken(token string) error {
+	if _, _, ok := parseToken(token); !ok {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func parseToken(token string) (uint64, uint64, bool) {
+	ms, seq, found := strings.Cut(token, "-")
+	if !found {
+		return 0, 0, false
+	}
+	msValue, err1 := strconv.ParseUint(ms, 10, 64)
+	seqValue, err2 := strconv.ParseUint(seq, 10, 64)
+	return msValue, seqValue, err1 == nil && err2 == nil
+}
+
+// CompareTokens 比较两个恢复令牌的先后，a 早于 b 时返回负数。调用方需保证令牌合法
+func CompareTokens(a, b string) int {
+	aMs, aSeq, _ := parseToken(a)
+	bMs, bSeq, _ := parseToken(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	}
+	return 0
+}
+
+// Subscription 本地订阅，Events 为实时事件，Gaps 收到通知时说明可能遗漏了实时事件
+type Subscription struct {
+	feed   *Feed
+	userID uint
+	events chan *pb.TodoEvent
+	gaps   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe 订阅用户的实时事件，使用完毕后必须调用 Close
+func (f *Feed) Subscribe(userID uint) *Subscription {
+	sub := &Subscription{
+		feed:   f,
+		userID: userID,
+		events: make(chan *pb.TodoEvent, subscriberBuffer),
+		gaps:   make(chan struct{}, 1),
+	}
+	f.mu.Lock()
+	if f.subs[userID] == nil {
+		f.subs[userID] = make(map[*Subscription]struct{})
+	}
+	f.subs[userID][sub] = struct{}{}
+	f.mu.Unlock()
+	return sub
+}
+
+// Events 返回实时事件通道
+func (s *Subscription) Events() <-chan *pb.TodoEvent { return s.events }
+
+// Gaps 返回缺口通知通道
+func (s *Subscription) Gaps() <-chan struct{} { return s.gaps }
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.feed.mu.Lock()
+		delete(s.feed.subs[s.userID], s)
+		if len(s.feed.subs[s.userID]) == 0 {
+			delete(s.feed.subs, s.userID)
+		}
+		s.feed.mu.Unlock()
+	})
+}
+
+func (s *Subscription) signalGap() {
+	select {
+	case s.gaps <- struct{}{}:
+	default: // 已有未处理的缺口通知
+	}
+}
+
+// Run 维持 PSUBSCRIBE 连接并分发实时事件，直到 ctx 被取消。连接中断后自动重连
+func (f *Feed) Run(ctx context.Context) {
+	log.Printf("变更流订阅已启动")
+	for ctx.Err() == nil {
+		if err := f.receive(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("警告: 变更流订阅中断: %v，%s 后重连", err, resubscribeDelay)
+		}
+		// 连接中断期间可能遗漏了事件，通知所有订阅者从 Stream 补读
+		f.signalGapAll()
+		select {
+		case <-ctx.Done():
+		case <-time.After(resubscribeDelay):
+		}
+	}
+	log.Printf("变更流订阅已停止")
+}
+
+func (f *Feed) receive(ctx context.Context) error {
+	pubsub := f.rdb.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+	// ReceiveMessage 不会因 ctx 取消而返回，关闭连接使其退出
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	// 重新订阅成功前的事件需要补读
+	f.signalGapAll()
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		f.dispatch(msg)
+	}
+}
+
+func (f *Feed) dispatch(msg *redis.Message) {
+	userID, err := strconv.ParseUint(strings.TrimPrefix(msg.Channel, channelPrefix), 10, 64)
+	if err != nil {
+		return
+	}
+	id, payload, found := strings.Cut(msg.Payload, "\n")
+	if !found {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subs := f.subs[uint(userID)]
+	if len(subs) == 0 {
+		return
+	}
+	event, err := decodeEvent(id, payload)
+	if err != nil {
+		log.Printf("警告: 解析用户 %d 的实时事件 %s 失败: %v", userID, id, err)
+		return
+	}
+	for sub := range subs {
+		select {
+		case sub.events <- event:
+		default:
+			sub.signalGap()
+		}
+	}
+}
+
+func (f *Feed) signalGapAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, subs := range f.subs {
+		for sub := range subs {
+			sub.signalGap()
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+// Expr 解析后的过滤表达式
+type Expr interface {
+	// Match 判断 todo 是否满足表达式，now 决定 today 等相对日期以及日期边界所在的时区
+	Match(todo *model.Todo, now time.Time) bool
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Match(todo *model.Todo, now time.Time) bool {
+	return e.left.Match(todo, now) && e.right.Match(todo, now)
+}
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Match(todo *model.Todo, now time.Time) bool {
+	return e.left.Match(todo, now) || e.right.Match(todo, now)
+}
+
+type notExpr struct{ inner Expr }
+
+func (e notExpr) Match(todo *model.Todo, now time.Time) bool {
+	return !e.inner.Match(todo, now)
+}
+
+// predicateExpr 不依赖取值的条件，例如 is:overdue、has:due
+type predicateExpr func(todo *model.Todo, now time.Time) bool
+
+func (e predicateExpr) Match(todo *model.Todo, now time.Time) bool { return e(todo, now) }
+
+type textField int
+
+const (
+	textFieldTitle textField = 1 << iota
+	textFieldDescription
+	textFieldsAll = textFieldTitle | textFieldDescription
+)
+
+// textExpr 不区分大小写的子串匹配，value 已转为小写
+type textExpr struct {
+	fields textField
+	value  string
+}
+
+func (e textExpr) Match(todo *model.Todo, now time.Time) bool {
+	if e.fields&textFieldTitle != 0 && strings.Contains(strings.ToLower(todo.Title), e.value) {
+		return true
+	}
+	return e.fields&textFieldDescription != 0 && strings.Contains(strings.ToLower(todo.Description), e.value)
+}
+
+type tagExpr struct{ tag string }
+
+func (e tagExpr) Match(todo *model.Todo, now time.Time) bool {
+	for _, tag := range todo.Tags {
+		if strings.EqualFold(tag, e.tag) {
+			return true
+		}
+	}
+	return false
+}
+
+type priorityExpr struct {
+	op    string
+	value int
+}
+
+func (e priorityExpr) Match(todo *model.Todo, now time.Time) bool {
+	return compareInts(todo.Priority, e.op, e.value)
+}
+
+// dateExpr 日期比较，字段为空 (例如没有截止时间) 时不满足任何比较
+type dateExpr struct {
+	field func(todo *model.Todo) *time.Time
+	op    string
+	value dateValue
+}
+
+func (e dateExpr) Match(todo *model.Todo, now time.Time) bool {
+	t := e.field(todo)
+	if t == nil {
+		return false
+	}
+	start, end := e.value.resolve(now)
+	switch e.op {
+	case "<":
+		return t.Before(start)
+	case "<=":
+		return t.Before(end)
+	case ">":
+		return !t.Before(end)
+	case ">=":
+		return !t.Before(start)
+	case "!=":
+		return t.Before(start) || !t.Before(end)
+	default: // ":" 和 "="
+		return !t.Before(start) && t.Before(end)
+	}
+}
+
+func compareInts(a int, op string, b int) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
+
+// dateValue 日期取值，在求值时才根据当前时间计算出具体的时间范围
+type dateValue struct {
+	base   string // today / tomorrow / yesterday / now / 2006-01-02
+	amount int
+	unit   byte // h / d / w / m / y，amount 为 0 时忽略
+}
+
+var dateValuePattern = regexp.MustCompile(`^(?i)(today|tomorrow|yesterday|now|\d{4}-\d{2}-\d{2})?(?:([+-]\d+)([hdwmy]))?$`)
+
+func parseDateValue(text string) (dateValue, bool) {
+	m := dateValuePattern.FindStringSubmatch(text)
+	if m == nil || (m[1] == "" && m[2] == "") {
+		return dateValue{}, false
+	}
+	v := dateValue{base: strings.ToLower(m[1])}
+	if v.base == "" {
+		v.base = "today"
+	}
+	if len(v.base) == len("2006-01-02") {
+		if _, err := time.Parse("2006-01-02", v.base); err != nil {
+			return dateValue{}, false
+		}
+	}
+	if m[2] != "" {
+		amount, err := strconv.Atoi(m[2])
+		if err != nil || amount > 100000 || amount < -100000 {
+			return dateValue{}, false
+		}
+		v.amount, v.unit = amount, strings.ToLower(m[3])[0]
+	}
+	return v, true
+}
+
+// resolve 返回取值表示的时间范围 [start, end)。日期表示一整天，now 表示一个时刻
+func (v dateValue) resolve(now time.Time) (start, end time.Time) {
+	loc := now.Location()
+	y, mo, d := now.Date()
+	instant := false
+	switch v.base {
+	case "now":
+		start, instant = now, true
+	case "today":
+		start = time.Date(y, mo, d, 0, 0, 0, 0, loc)
+	case "tomorrow":
+		start = time.Date(y, mo, d+1, 0, 0, 0, 0, loc)
+	case "yesterday":
+		start = time.Date(y, mo, d-1, 0, 0, 0, 0, loc)
+	default:
+		parsed, _ := time.ParseInLocation("2006-01-02", v.base, loc)
+		start = parsed
+	}
+	switch v.unit {
+	case 'h':
+		start = start.Add(time.Duration(v.amount) * time.Hour)
+	case 'd':
+		start = start.AddDate(0, 0, v.amount)
+	case 'w':
+		start = start.AddDate(0, 0, 7*v.amount)
+	case 'm':
+		start = start.AddDate(0, v.amount, 0)
+	case 'y':
+		start = start.AddDate(v.amount, 0, 0)
+	}
+	if instant {
+		return start, start.Add(time.Nanosecond)
+	}
+	return start, start.AddDate(0, 0, 1)
+}
+
+var priorityValues = map[string]int{
+	"none": 0, "low": 1, "medium": 2, "med": 2, "high": 3, "urgent": 4,
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4,
+}
+
+var boolValues = map[string]bool{
+	"true": true, "yes": true, "1": true,
+	"false": false, "no": false, "0": false,
+}
+
+var isPredicates = map[string]predicateExpr{
+	"completed": func(todo *model.Todo, now time.Time) bool { return todo.Completed },
+	"done":      func(todo *model.Todo, now time.Time) bool { return todo.Completed },
+	"open":      func(todo *model.Todo, now time.Time) bool { return !todo.Completed },
+	"overdue": func(todo *model.Todo, now time.Time) bool {
+		return !todo.Completed && todo.DueAt != nil && todo.DueAt.Before(now)
+	},
+	"recurring": func(todo *model.Todo, now time.Time) bool { return todo.RecurrenceFrequency != 0 },
+	"archived":  func(todo *model.Todo, now time.Time) bool { return todo.ArchivedAt != nil },
+}
+
+var hasPredicates = map[string]predicateExpr{
+	"due":         func(todo *model.Todo, now time.Time) bool { return todo.DueAt != nil },
+	"tags":        func(todo *model.Todo, now time.Time) bool { return len(todo.Tags) > 0 },
+	"tag":         func(todo *model.Todo, now time.Time) bool { return len(todo.Tags) > 0 },
+	"parent":      func(todo *model.Todo, now time.Time) bool { return todo.ParentID != nil },
+	"checklist":   func(todo *model.Todo, now time.Time) bool { return len(todo.Checklist) > 0 },
+	"recurrence":  func(todo *model.Todo, now time.Time) bool { return todo.RecurrenceFrequency != 0 },
+	"description": func(todo *model.Todo, now time.Time) bool { return strings.TrimSpace(todo.Description) != "" },
+}
+
+var dateFields = map[string]func(todo *model.Todo) *time.Time{
+	"due":          func(todo *model.Todo) *time.Time { return todo.DueAt },
+	"created":      func(todo *model.Todo) *time.Time { return &todo.CreatedAt },
+	"updated":      func(todo *model.Todo) *time.Time { return &todo.UpdatedAt },
+	"completed_at": func(todo *model.Todo) *time.Time { return todo.CompletedAt },
+	"archived_at":  func(todo *model.Todo) *time.Time { return todo.ArchivedAt },
+}
+
+var textFields = map[string]textField{
+	"title":       textFieldTitle,
+	"description": textFieldDescription,
+	"text":        textFieldsAll,
+}
+
+// parseCondition 解析 "字段 运算符 取值" 形式的条件
+func parseCondition(field, op, value token) (Expr, error) {
+	name := strings.ToLower(field.text)
+	raw := strings.ToLower(value.text)
+	equality := op.text == ":" || op.text == "=" || op.text == "!="
+	negate := func(e Expr) Expr {
+		if op.text == "!=" {
+			return notExpr{e}
+		}
+		return e
+	}
+	unsupportedOp := func() error {
+		return &SyntaxError{op.pos, fmt.Sprintf("字段 '%s' 不支持运算符 '%s'", field.text, op.text)}
+	}
+	invalidValue := func(expected string) error {
+		return &SyntaxError{value.pos, fmt.Sprintf("字段 '%s' 的取值 %s 无效，%s", field.text, value.describe(), expected)}
+	}
+
+	switch {
+	case name == "completed" || name == "done":
+		if !equality {
+			return nil, unsupportedOp()
+		}
+		want, ok := boolValues[raw]
+		if !ok {
+			return nil, invalidValue("应为 true 或 false")
+		}
+		return negate(predicateExpr(func(todo *model.Todo, now time.Time) bool { return todo.Completed == want })), nil
+
+	case name == "is" || name == "has":
+		if !equality {
+			return nil, unsupportedOp()
+		}
+		predicates, expected := isPredicates, "应为 completed、open、overdue、recurring 或 archived"
+		if name == "has" {
+			predicates, expected = hasPredicates, "应为 due、tags、parent、checklist、recurrence 或 description"
+		}
+		predicate, ok := predicates[raw]
+		if !ok {
+			return nil, invalidValue(expected)
+		}
+		return negate(predicate), nil
+
+	case dateFields[name] != nil:
+		if raw == "none" && value.kind == tokWord {
+			if name == "created" || name == "updated" {
+				return nil, invalidValue("该字段总是有值")
+			}
+			if !equality {
+				return nil, unsupportedOp()
+			}
+			// due:none 匹配没有截止时间的 Todo，due!=none 匹配有截止时间的 Todo
+			field := dateFields[name]
+			isSet := predicateExpr(func(todo *model.Todo, now time.Time) bool { return field(todo) != nil })
+			if op.text == "!=" {
+				return isSet, nil
+			}
+			return notExpr{isSet}, nil
+		}
+		v, ok := parseDateValue(value.text)
+		if !ok {
+			return nil, invalidValue("应为 today、tomorrow、yesterday、now、2006-01-02 或 +3d 之类的偏移")
+		}
+		return dateExpr{field: dateFields[name], op: op.text, value: v}, nil
+
+	case name == "tag" || name == "tags":
+		if !equality {
+			return nil, unsupportedOp()
+		}
+		tag := strings.TrimLeft(value.text, "#＃")
+		if tag == "" {
+			return nil, invalidValue("标签不能为空")
+		}
+		return negate(tagExpr{tag}), nil
+
+	case name == "priority":
+		v, ok := priorityValues[raw]
+		if !ok {
+			return nil, invalidValue("应为 none、low、medium、high、urgent 或 0-4")
+		}
+		cmp := op.text
+		if cmp == ":" {
+			cmp = "="
+		}
+		return priorityExpr{op: cmp, value: v}, nil
+
+	case textFields[name] != 0:
+		if !equality {
+			return nil, unsupportedOp()
+		}
+		return negate(textExpr{fields: textFields[name], value: raw}), nil
+	}
+
+	return nil, &SyntaxError{field.pos, fmt.Sprintf("未知字段 '%s'", field.text)}
+}
@@ -0,0 +1,129 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+var now = time.Date(2026, time.March, 11, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))
+
+func due(days int, hour int) *time.Time {
+	t := time.Date(2026, time.March, 11+days, hour, 0, 0, 0, now.Location())
+	return &t
+}
+
+func TestMatch(t *testing.T) {
+	parent := uint(1)
+	todos := map[string]*model.Todo{
+		"report":    {Title: "Quarterly report", Description: "for finance", Tags: []string{"work"}, Priority: 3, DueAt: due(2, 18)},
+		"groceries": {Title: "Buy groceries", Tags: []string{"home", "errand"}, Priority: 1, DueAt: due(0, 20)},
+		"overdue":   {Title: "Renew passport", Priority: 4, DueAt: due(-3, 9)},
+		"done":      {Title: "Call mom", Completed: true, DueAt: due(-1, 12), Tags: []string{"Home"}},
+		"someday":   {Title: "Learn piano", Tags: []string{"someday"}, ParentID: &parent, RecurrenceFrequency: 2},
+	}
+	for _, todo := range todos {
+		todo.CreatedAt = now.AddDate(0, 0, -10)
+		todo.UpdatedAt = now.AddDate(0, 0, -1)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"completed:false", []string{"groceries", "overdue", "report", "someday"}},
+		{"done=true", []string{"done"}},
+		{"is:overdue", []string{"overdue"}},
+		{"due<=today", []string{"done", "groceries", "overdue"}},
+		{"due:today", []string{"groceries"}},
+		{"due>today due<=+7d", []string{"report"}},
+		{"due<yesterday", []string{"overdue"}},
+		{"due:none", []string{"someday"}},
+		{"due!=none and not is:completed", []string{"groceries", "overdue", "report"}},
+		{"due>=2026-03-13", []string{"report"}},
+		{"due<now", []string{"done", "overdue"}},
+		{"created<today-1w", []string{"done", "groceries", "overdue", "report", "someday"}},
+		{"updated:yesterday", []string{"done", "groceries", "overdue", "report", "someday"}},
+		{"tag:home", []string{"done", "groceries"}},
+		{"tag:#work or tag:errand", []string{"groceries", "report"}},
+		{"-tag:someday completed:false", []string{"groceries", "overdue", "report"}},
+		{"tag!=home and tag!=work", []string{"overdue", "someday"}},
+		{"priority>=high", []string{"overdue", "report"}},
+		{"priority:none", []string{"done", "someday"}},
+		{"priority<2 not priority=0", []string{"groceries"}},
+		{"report", []string{"report"}},
+		{"finance", []string{"report"}},
+		{`"buy GROCERIES"`, []string{"groceries"}},
+		{"title:finance", nil},
+		{"description:finance", []string{"report"}},
+		{"has:parent", []string{"someday"}},
+		{"is:recurring or (tag:home and is:open)", []string{"groceries", "someday"}},
+		{"NOT (tag:home OR tag:work) AND is:open", []string{"overdue", "someday"}},
+		{"report or groceries and zzz", []string{"report"}}, // and 的优先级高于 or
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.query, err)
+			}
+			var got []string
+			for _, name := range []string{"done", "groceries", "overdue", "report", "someday"} {
+				if expr.Match(todos[name], now) {
+					got = append(got, name)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("matched %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"   ", 3},
+		{"(tag:work", 9},
+		{"tag:work)", 8},
+		{`title:"unterminated`, 6},
+		{"color:red", 0},
+		{"tag:work and", 12},
+		{"or tag:work", 0},
+		{"due<=someday", 5},
+		{"due:2026-02-30", 4},
+		{"priority>=highest", 10},
+		{"tag<work", 3},
+		{"completed:maybe", 10},
+		{"is:", 3},
+		{"has:wings", 4},
+		{"created:none", 8},
+		{"tag:a !tag:b", 6},
+		{"(a or (b and c)", 15},
+		{"中文 标签:工作", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("Parse(%q) error position = %d, want %d (%v)", tt.query, syntaxErr.Pos, tt.pos, syntaxErr)
+			}
+		})
+	}
+}
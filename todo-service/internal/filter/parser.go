@@ -0,0 +1,271 @@
+// Package filter 实现保存搜索 (智能列表) 使用的过滤表达式语言。
+//
+// 表达式由若干条件组成，条件之间可以用 and / or / not 组合，并列的条件默认为 and，
+// 括号用于改变优先级，"-" 前缀等价于 not。例如：
+//
+//	completed:false due<=today+7d (tag:work or tag:home) priority>=high
+//	is:overdue -tag:someday "季度报告"
+//
+// 支持的条件：
+//
+//	completed:true|false          是否已完成 (也可写作 done)
+//	is:completed|open|overdue|recurring|archived
+//	has:due|tags|parent|checklist|recurrence|description
+//	due / created / updated       日期比较，运算符为 : = != < <= > >=
+//	completed_at / archived_at    完成时间和归档时间，同样为日期比较
+//	tag:work                      包含标签 (不区分大小写)，tag!=work 表示不包含
+//	priority>=high                优先级比较，取值为 none/low/medium/high/urgent 或 0-4
+//	title: / description: / text: 包含文本 (不区分大小写)
+//	单独的单词或带引号的字符串     在标题和描述中搜索
+//
+// 日期取值为 today、tomorrow、yesterday、now 或 2006-01-02，可以附加 +3d、-1w 之类的偏移
+// (单位 h/d/w/m/y)，也可以单独写偏移，例如 due<+3d 等价于 due<today+3d。
+// 日期表示一整天：due<=today 表示截止时间不晚于今天结束。due:none 匹配没有截止时间的 Todo (completed_at、archived_at 同理)。
+//
+// 表达式在解析时完成全部语法检查，日期在求值时根据传入的当前时间 (及其时区) 计算。
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxQueryLength 表达式的最大长度 (按字符计)
+const MaxQueryLength = 1000
+
+// SyntaxError 表达式语法错误，Pos 为出错位置 (从 0 开始的 Unicode 码点偏移)
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("第 %d 个字符处: %s", e.Pos+1, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokOp
+	tokMinus
+)
+
+type token struct {
+	kind tokenKind
+	text string // 原文，tokString 为去掉引号和转义后的内容
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "表达式结尾"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+// isWordRune 判断字符能否出现在单词中
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()":=!<>`, r)
+}
+
+func tokenize(query string) ([]token, error) {
+	runes := []rune(query)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case r == '"':
+			start := i
+			var b strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					b.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, &SyntaxError{start, "字符串缺少结束引号"}
+			}
+			tokens = append(tokens, token{tokString, b.String(), start})
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{tokOp, string(r), i})
+			i++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &SyntaxError{i, "无效的运算符 '!'，取反请使用 not 或 '-'"}
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		case r == '-' && i+1 < len(runes) && !unicode.IsDigit(runes[i+1]) && !unicode.IsSpace(runes[i+1]):
+			// "-tag:x" 表示取反，"-3d" 则是日期偏移
+			tokens = append(tokens, token{tokMinus, "-", i})
+			i++
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokWord, string(runes[start:i]), start})
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+// Parse 解析过滤表达式，语法错误时返回 *SyntaxError
+func Parse(query string) (Expr, error) {
+	if n := len([]rune(query)); n > MaxQueryLength {
+		return nil, &SyntaxError{MaxQueryLength, fmt.Sprintf("表达式长度不能超过 %d 个字符", MaxQueryLength)}
+	}
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{p.peek().pos, "表达式不能为空"}
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, &SyntaxError{t.pos, "多余的 ')'"}
+		}
+		return nil, &SyntaxError{t.pos, "意外的 " + t.describe()}
+	}
+	return expr, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if isKeyword(t, "and") {
+			p.next()
+		} else if t.kind == tokEOF || t.kind == tokRParen || isKeyword(t, "or") {
+			return left, nil
+		}
+		// 并列的条件默认为 and
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if t := p.peek(); isKeyword(t, "not") || t.kind == tokMinus {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &SyntaxError{closing.pos, fmt.Sprintf("期望 ')' 与第 %d 个字符处的 '(' 匹配，实际为%s", t.pos+1, closing.describe())}
+		}
+		return inner, nil
+	case tokString:
+		return textExpr{fields: textFieldsAll, value: strings.ToLower(t.text)}, nil
+	case tokWord:
+		if isKeyword(t, "and") || isKeyword(t, "or") || isKeyword(t, "not") {
+			return nil, &SyntaxError{t.pos, fmt.Sprintf("'%s' 前缺少条件", t.text)}
+		}
+		if p.peek().kind != tokOp {
+			return textExpr{fields: textFieldsAll, value: strings.ToLower(t.text)}, nil
+		}
+		op := p.next()
+		value := p.next()
+		if value.kind != tokWord && value.kind != tokString {
+			return nil, &SyntaxError{value.pos, fmt.Sprintf("'%s%s' 后缺少取值", t.text, op.text)}
+		}
+		return parseCondition(t, op, value)
+	case tokEOF:
+		return nil, &SyntaxError{t.pos, "表达式意外结束，缺少条件"}
+	default:
+		return nil, &SyntaxError{t.pos, "意外的 " + t.describe()}
+	}
+}
@@ -0,0 +1,211 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/glebarez/go-sqlite"
+)
+
+var testFS = fstest.MapFS{
+	"sqlite/0001_create_items.up.sql":   {Data: []byte("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT);\n")},
+	"sqlite/0001_create_items.down.sql": {Data: []byte("DROP TABLE items;\n")},
+	"sqlite/0002_add_note.up.sql": {Data: []byte(`-- 两条语句
+ALTER TABLE items ADD COLUMN note TEXT;
+CREATE INDEX idx_items_note
+  ON items (note);
+`)},
+	"sqlite/0002_add_note.down.sql": {Data: []byte("DROP INDEX idx_items_note;\nALTER TABLE items DROP COLUMN note;\n")},
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory", strings.ReplaceAll(t.Name(), "/", "_")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func versions(migrations []Migration) []int64 {
+	result := []int64{}
+	for _, m := range migrations {
+		result = append(result, m.Version)
+	}
+	return result
+}
+
+func TestUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db, "sqlite", "test", testFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(applied); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("Up 执行了 %v", got)
+	}
+	if _, err := db.Exec("INSERT INTO items (name, note) VALUES ('a', 'b')"); err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("重复 Up: %v, %v", applied, err)
+	}
+
+	rolledBack, err := m.Down(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(rolledBack); !reflect.DeepEqual(got, []int64{2}) {
+		t.Fatalf("Down 回滚了 %v", got)
+	}
+	if _, err := db.Exec("SELECT note FROM items"); err == nil {
+		t.Error("回滚 0002 后 note 列仍然存在")
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].AppliedAt == nil || statuses[1].AppliedAt != nil {
+		t.Fatalf("Status = %+v", statuses)
+	}
+
+	// 不同服务的迁移记录互不影响
+	other, err := New(db, "sqlite", "other", fstest.MapFS{
+		"sqlite/0001_other.up.sql":   {Data: []byte("CREATE TABLE others (id INTEGER);")},
+		"sqlite/0001_other.down.sql": {Data: []byte("DROP TABLE others;")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := other.Up(ctx); err != nil || len(applied) != 1 {
+		t.Fatalf("other Up: %v, %v", applied, err)
+	}
+
+	if _, err := m.Down(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("SELECT 1 FROM items"); err == nil {
+		t.Error("回滚全部迁移后 items 表仍然存在")
+	}
+	if _, err := db.Exec("SELECT 1 FROM others"); err != nil {
+		t.Errorf("other 服务的表被回滚: %v", err)
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	fsys := fstest.MapFS{
+		"sqlite/0001_ok.up.sql":     {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"sqlite/0001_ok.down.sql":   {Data: []byte("DROP TABLE a;")},
+		"sqlite/0002_bad.up.sql":    {Data: []byte("CREATE TABLE b (id INTEGER);\nINSERT INTO missing VALUES (1);")},
+		"sqlite/0002_bad.down.sql":  {Data: []byte("DROP TABLE b;")},
+		"sqlite/0003_next.up.sql":   {Data: []byte("CREATE TABLE c (id INTEGER);")},
+		"sqlite/0003_next.down.sql": {Data: []byte("DROP TABLE c;")},
+	}
+	m, err := New(db, "sqlite", "test", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := m.Up(ctx)
+	if err == nil || !strings.Contains(err.Error(), "0002_bad") {
+		t.Fatalf("期望 0002_bad 失败，实际 %v", err)
+	}
+	if got := versions(applied); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("失败前执行了 %v", got)
+	}
+	if _, err := db.Exec("SELECT 1 FROM b"); err == nil {
+		t.Error("失败的迁移没有回滚")
+	}
+	if _, err := db.Exec("SELECT 1 FROM c"); err == nil {
+		t.Error("失败之后的迁移不应执行")
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses[1].AppliedAt != nil {
+		t.Error("失败的迁移被记录为已执行")
+	}
+}
+
+func TestDownUnknownMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	newer, err := New(db, "sqlite", "test", testFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newer.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 旧版本程序只知道 0001
+	older, err := New(db, "sqlite", "test", fstest.MapFS{
+		"sqlite/0001_create_items.up.sql":   testFS["sqlite/0001_create_items.up.sql"],
+		"sqlite/0001_create_items.down.sql": testFS["sqlite/0001_create_items.down.sql"],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := older.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || !statuses[1].Unknown || statuses[1].Name != "add_note" {
+		t.Fatalf("Status = %+v", statuses)
+	}
+	if _, err := older.Down(ctx, 1); err == nil {
+		t.Error("回滚程序中不存在的迁移应失败")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"缺少 down": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"文件名无效":   {"init.sql": {Data: []byte("SELECT 1;")}},
+		"名称不一致": {
+			"0001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+	if _, err := New(nil, "oracle", "test", testFS); err == nil {
+		t.Error("不支持的方言应返回错误")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释
+CREATE TABLE a (
+  id INTEGER -- 行尾注释
+);
+
+INSERT INTO a VALUES (1);
+SELECT 'x;y' FROM a`
+	want := []string{
+		"CREATE TABLE a (\n  id INTEGER -- 行尾注释\n)",
+		"INSERT INTO a VALUES (1)",
+		"SELECT 'x;y' FROM a",
+	}
+	if got := SplitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitStatements = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ArchivePolicy 用户的自动归档策略：已完成超过 ArchiveAfterDays 天的 Todo 由后台任务自动归档
+type ArchivePolicy struct {
+	UserID           uint       `gorm:"primaryKey;autoIncrement:false"`
+	Enabled          bool       `gorm:"not null;default:false;index"`
+	ArchiveAfterDays int        `gorm:"not null"`
+	LastRunAt        *time.Time // 后台任务最近一次为该用户执行归档的时间
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ChangeCounter 用户的 Todo 变更序号计数器，Seq 为最近分配的序号
+type ChangeCounter struct {
+	UserID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Seq    uint64 `gorm:"not null;default:0"`
+}
+
+// TodoTombstone 已删除 Todo 的墓碑，离线同步据此通知客户端删除本地副本
+type TodoTombstone struct {
+	TodoID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	UserID    uint      `gorm:"not null;index:idx_todo_tombstones_user_change_seq"`
+	ChangeSeq uint64    `gorm:"not null;index:idx_todo_tombstones_user_change_seq"`
+	ClientID  *string   `gorm:"size:64"`
+	CreatedAt time.Time // 删除时间
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// JSON 保存原始 JSON 文本的列。列类型随数据库而定：MySQL 为 JSON，PostgreSQL 为 JSONB，
+// 其他数据库 (SQLite) 为 TEXT
+type JSON string
+
+// GormDBDataType 实现 schema.GormDataTypeInterface，按方言选择列类型
+func (JSON) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "JSON"
+	case "postgres":
+		return "JSONB"
+	default:
+		return "TEXT"
+	}
+}
+
+// Value 以文本形式写入，各驱动都能将其转换为 JSON/JSONB
+func (j JSON) Value() (driver.Value, error) {
+	return string(j), nil
+}
+
+// Scan 读取 JSON 文本。MySQL 返回 []byte，PostgreSQL 和 SQLite 返回 []byte 或 string
+func (j *JSON) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		*j = JSON(v)
+	case string:
+		*j = JSON(v)
+	case nil:
+		*j = ""
+	default:
+		return fmt.Errorf("无法将 %T 读取为 JSON", src)
+	}
+	return nil
+}
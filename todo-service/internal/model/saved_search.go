@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SavedSearch 用户保存的搜索 (智能列表)，Query 为 filter 包定义的过滤表达式
+type SavedSearch struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_saved_searches_user_name"`
+	Name      string `gorm:"size:255;not null;uniqueIndex:idx_saved_searches_user_name"`
+	Query     string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// TodoTemplate 用户拥有的 Todo 模板
+type TodoTemplate struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;index"`
+	Name        string `gorm:"size:255;not null"`
+	Description string
+	Items       []TemplateItem `gorm:"foreignKey:TemplateID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TemplateItem 模板条目，通过 ParentItemID 组成一棵树
+type TemplateItem struct {
+	ID           uint   `gorm:"primaryKey"`
+	TemplateID   uint   `gorm:"not null;index"`
+	ParentItemID *uint  // nil 表示根条目
+	Position     int    `gorm:"not null"` // 先序遍历中的位置，父条目总是排在子条目之前
+	Title        string `gorm:"not null"`
+	Description  string
+	DueOffset    *int64          // 相对基准时间的截止偏移 (秒)，nil 表示无截止时间
+	Tags         []string        `gorm:"type:text;serializer:json"`
+	Checklist    []ChecklistItem `gorm:"type:text;serializer:json"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// TimeEntry Todo 的时间记录。EndedAt 为 nil 表示计时器正在运行，
+// 此时 RunningUserID 等于 UserID，借助唯一索引保证每个用户最多只有一个正在运行的计时器
+type TimeEntry struct {
+	ID              uint      `gorm:"primaryKey"`
+	UserID          uint      `gorm:"not null;index:idx_time_entries_user_started"`
+	TodoID          uint      `gorm:"not null;index"`
+	StartedAt       time.Time `gorm:"not null;index:idx_time_entries_user_started"`
+	EndedAt         *time.Time
+	DurationSeconds int64  `gorm:"not null;default:0"` // 已结束记录的时长，运行中为 0
+	Note            string `gorm:"size:500"`
+	RunningUserID   *uint  `gorm:"uniqueIndex"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
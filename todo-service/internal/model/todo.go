@@ -4,19 +4,41 @@ import "time"
 
 type Todo struct {
 	ID          uint   `gorm:"primaryKey"`
-	UserID      uint   `gorm:"not null;index"`
+	UserID      uint   `gorm:"not null;index;index:idx_todos_user_change_seq;uniqueIndex:idx_todos_user_client"`
+	ParentID    *uint  `gorm:"index"` // 父任务 ID，nil 表示顶层任务
 	Title       string `gorm:"not null"`
 	Description string
-	Completed   bool `gorm:"default:false"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Completed   bool       `gorm:"default:false"`
+	CompletedAt *time.Time // 最近一次标记完成的时间，未完成时为 nil
+	ArchivedAt  *time.Time `gorm:"index"` // 归档时间，nil 表示未归档
+	DueAt       *time.Time
+	Tags        []string        `gorm:"type:text;serializer:json"`
+	Checklist   []ChecklistItem `gorm:"type:text;serializer:json"`
+	Priority    int             `gorm:"not null;default:0"` // 取值与 pb.Priority 一致
+	// 重复规则，RecurrenceFrequency 取值与 pb.Recurrence_Frequency 一致，0 表示不重复
+	RecurrenceFrequency int `gorm:"not null;default:0"`
+	RecurrenceInterval  int `gorm:"not null;default:0"`
+	// 已结束的时间记录的总时长 (秒)，随时间记录的增删改一起更新
+	TrackedSeconds int64 `gorm:"not null;default:0"`
+	// 最近一次修改时分配的用户级变更序号，见 TodoRepository.NextChangeSeq
+	ChangeSeq uint64 `gorm:"not null;default:0;index:idx_todos_user_change_seq"`
+	// 通过离线同步创建时客户端生成的 ID，用于去重
+	ClientID  *string `gorm:"size:64;uniqueIndex:idx_todos_user_client"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ChecklistItem 检查清单条目，以 JSON 形式存储在 Todo 中
+type ChecklistItem struct {
+	Content string `json:"content"`
+	Done    bool   `json:"done"`
 }
 
 type BatchOperationLog struct {
 	ID              uint   `gorm:"primaryKey"`
 	UserID          uint   `gorm:"not null;index"`
 	OperationType   string `gorm:"size:50;not null"`
-	AffectedTodoIDs string `gorm:"type:json;not null"`
+	AffectedTodoIDs JSON   `gorm:"not null"`
 	Status          string `gorm:"size:20;not null"`
 	Details         string `gorm:"type:text"`
 	CreatedAt       time.Time
@@ -0,0 +1,383 @@
+// Package quickadd 将快速添加文本 (例如 "Pay rent tomorrow 9am !high #home every month"
+// 或 "明天上午9点交房租 !高 #家 每月") 解析为标题、截止时间、优先级、标签和重复规则。
+//
+// 解析是确定性的：结果只取决于输入文本和调用方传入的当前时间 (包括其时区)，
+// 不读取系统时钟，也不依赖任何外部状态。
+package quickadd
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Priority 优先级，取值与 proto 中的 todo.Priority 一致
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// Frequency 重复频率，取值与 proto 中的 todo.Recurrence.Frequency 一致
+type Frequency int
+
+const (
+	FrequencyNone Frequency = iota
+	FrequencyDaily
+	FrequencyWeekly
+	FrequencyMonthly
+	FrequencyYearly
+)
+
+// Recurrence 重复规则，Interval 表示每隔几个周期重复一次
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+}
+
+// TokenKind 被识别出的片段类型
+type TokenKind string
+
+const (
+	KindDate       TokenKind = "date"
+	KindTime       TokenKind = "time"
+	KindPriority   TokenKind = "priority"
+	KindTag        TokenKind = "tag"
+	KindRecurrence TokenKind = "recurrence"
+)
+
+// Token 输入中被识别并从标题中移除的片段，Start/End 为 Unicode 码点偏移 (左闭右开)
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start int
+	End   int
+}
+
+// Result 解析结果
+type Result struct {
+	Title      string
+	DueAt      *time.Time
+	AllDay     bool // 只给出了日期没有给出时间，DueAt 为当天 23:59
+	Priority   Priority
+	Tags       []string
+	Recurrence *Recurrence
+	Tokens     []Token // 按 Start 排序
+}
+
+// 只给出日期时使用的截止时刻
+const allDayHour, allDayMinute = 23, 59
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type span struct{ start, end int }
+
+// parser 保存一次解析过程中的中间状态
+type parser struct {
+	text     string
+	now      time.Time
+	consumed []span
+
+	date    *date
+	exact   *time.Time // 由 "in 2 hours" 之类的相对时间得到的精确时刻
+	hasTime bool
+	hour    int
+	minute  int
+
+	// 由 "tonight"、"明早" 之类的词给出的默认时刻，只在没有显式时间时使用
+	hasSoftTime bool
+	softHour    int
+
+	// 由 "every monday"、"每月1号" 给出的锚点，只在没有显式日期时用于计算首次截止日期
+	anchorWeekday  *time.Weekday
+	anchorMonthDay int
+
+	result Result
+}
+
+// Parse 解析快速添加文本，now 决定相对日期的基准以及结果所在的时区
+func Parse(text string, now time.Time) Result {
+	p := &parser{text: text, now: now}
+	for _, r := range rules {
+		for _, loc := range r.re.FindAllStringSubmatchIndex(text, -1) {
+			m := match{text: text, loc: loc}
+			if p.overlaps(m.start(), m.end()) || m.embedded() {
+				continue
+			}
+			if r.apply(p, m) {
+				p.consume(r.kind, m.start(), m.end())
+			}
+		}
+	}
+	p.resolveDue()
+	p.result.Title = p.title()
+	sort.Slice(p.result.Tokens, func(i, j int) bool { return p.result.Tokens[i].Start < p.result.Tokens[j].Start })
+	return p.result
+}
+
+func (p *parser) overlaps(start, end int) bool {
+	for _, s := range p.consumed {
+		if start < s.end && s.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) consume(kind TokenKind, start, end int) {
+	p.consumed = append(p.consumed, span{start, end})
+	p.result.Tokens = append(p.result.Tokens, Token{
+		Kind:  kind,
+		Text:  p.text[start:end],
+		Start: utf8.RuneCountInString(p.text[:start]),
+		End:   utf8.RuneCountInString(p.text[:end]),
+	})
+}
+
+func (p *parser) today() date {
+	y, m, d := p.now.Date()
+	return date{y, m, d}
+}
+
+// setDate 记录日期，只接受第一个出现的日期表达式
+func (p *parser) setDate(d date) bool {
+	if p.date != nil || p.exact != nil {
+		return false
+	}
+	// 拒绝 2 月 30 日之类不存在的日期
+	t := time.Date(d.year, d.month, d.day, 0, 0, 0, 0, p.now.Location())
+	if t.Year() != d.year || t.Month() != d.month || t.Day() != d.day {
+		return false
+	}
+	p.date = &d
+	return true
+}
+
+func (p *parser) setDateFromTime(t time.Time) bool {
+	y, m, d := t.Date()
+	return p.setDate(date{y, m, d})
+}
+
+// setTime 记录时刻，只接受第一个出现的时间表达式
+func (p *parser) setTime(hour, minute int) bool {
+	if p.hasTime || p.exact != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return false
+	}
+	p.hasTime, p.hour, p.minute = true, hour, minute
+	return true
+}
+
+func (p *parser) setRecurrence(freq Frequency, interval int) bool {
+	if p.result.Recurrence != nil || interval <= 0 {
+		return false
+	}
+	p.result.Recurrence = &Recurrence{Frequency: freq, Interval: interval}
+	return true
+}
+
+func (p *parser) setPriority(priority Priority) bool {
+	if p.result.Priority != PriorityNone {
+		return false
+	}
+	p.result.Priority = priority
+	return true
+}
+
+// resolveDue 根据已识别的日期、时间和锚点计算最终的截止时刻
+func (p *parser) resolveDue() {
+	loc := p.now.Location()
+	if p.exact != nil {
+		due := *p.exact
+		p.result.DueAt = &due
+		return
+	}
+
+	d := p.date
+	anchored := false
+	if d == nil && p.anchorWeekday != nil {
+		next := nextWeekday(p.today(), *p.anchorWeekday, loc)
+		d, anchored = &next, true
+	}
+	if d == nil && p.anchorMonthDay > 0 {
+		next := nextMonthDay(p.today(), p.anchorMonthDay, loc)
+		d, anchored = &next, true
+	}
+	if d == nil && p.hasTime {
+		// 只给出时间：今天该时刻已过则顺延到明天
+		today := p.today()
+		d = &today
+		if !time.Date(d.year, d.month, d.day, p.hour, p.minute, 0, 0, loc).After(p.now) {
+			tomorrow := addDays(today, 1, loc)
+			d = &tomorrow
+		}
+	}
+	if d == nil {
+		return
+	}
+
+	hour, minute := allDayHour, allDayMinute
+	switch {
+	case p.hasTime:
+		hour, minute = p.hour, p.minute
+	case p.hasSoftTime:
+		hour, minute = p.softHour, 0
+	default:
+		p.result.AllDay = true
+	}
+	due := time.Date(d.year, d.month, d.day, hour, minute, 0, 0, loc)
+	// 重复规则的首次截止时间落在今天且已经过去时，顺延一个周期
+	if anchored && !due.After(p.now) {
+		if p.anchorWeekday != nil {
+			due = due.AddDate(0, 0, 7)
+		} else {
+			next := nextMonthDay(addDays(*d, 1, loc), p.anchorMonthDay, loc)
+			due = time.Date(next.year, next.month, next.day, hour, minute, 0, 0, loc)
+		}
+	}
+	p.result.DueAt = &due
+}
+
+// 移除已识别片段后，标题首尾残留的连接词和标点
+var (
+	danglingWords = map[string]bool{"at": true, "on": true, "by": true, "due": true}
+	trimPunct     = ",，.。;；:：、-–—"
+)
+
+func (p *parser) title() string {
+	spans := append([]span(nil), p.consumed...)
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		b.WriteString(p.text[last:s.start])
+		// 中文词语之间没有空格，移除片段后直接拼接，其他情况用空格分隔
+		before, _ := utf8.DecodeLastRuneInString(p.text[:s.start])
+		after, _ := utf8.DecodeRuneInString(p.text[s.end:])
+		if !unicode.Is(unicode.Han, before) || !unicode.Is(unicode.Han, after) {
+			b.WriteString(" ")
+		}
+		last = s.end
+	}
+	b.WriteString(p.text[last:])
+
+	words := strings.Fields(b.String())
+	for {
+		trimmed := false
+		for len(words) > 0 && danglingWords[strings.ToLower(words[len(words)-1])] {
+			words, trimmed = words[:len(words)-1], true
+		}
+		for len(words) > 0 && strings.Trim(words[len(words)-1], trimPunct) == "" {
+			words, trimmed = words[:len(words)-1], true
+		}
+		if !trimmed {
+			break
+		}
+	}
+	return strings.Trim(strings.Join(words, " "), trimPunct+" ")
+}
+
+// match 是正则匹配结果的包装，便于读取子匹配和边界字符
+type match struct {
+	text string
+	loc  []int
+}
+
+func (m match) start() int { return m.loc[0] }
+func (m match) end() int   { return m.loc[1] }
+
+func (m match) group(i int) string {
+	if 2*i >= len(m.loc) || m.loc[2*i] < 0 {
+		return ""
+	}
+	return m.text[m.loc[2*i]:m.loc[2*i+1]]
+}
+
+// isolated 判断匹配前后是否紧邻 ASCII 字母或数字 (中文文本中词与词之间通常没有空格，因此只检查 ASCII)
+func (m match) isolated() bool {
+	if r, _ := utf8.DecodeLastRuneInString(m.text[:m.start()]); r != utf8.RuneError && isASCIIAlnum(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(m.text[m.end():]); r != utf8.RuneError && isASCIIAlnum(r) {
+		return false
+	}
+	return true
+}
+
+// embedded 判断匹配是否属于域名、邮箱或路径的一部分，例如 "monday.com" 中的 "monday"
+func (m match) embedded() bool {
+	before, after := m.text[:m.start()], m.text[m.end():]
+	for _, sep := range []string{".", "@", "/"} {
+		if strings.HasPrefix(after, sep) {
+			if r, _ := utf8.DecodeRuneInString(after[len(sep):]); isASCIIAlnum(r) {
+				return true
+			}
+		}
+		if strings.HasSuffix(before, sep) {
+			if r, _ := utf8.DecodeLastRuneInString(before[:len(before)-len(sep)]); isASCIIAlnum(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func addDays(d date, n int, loc *time.Location) date {
+	y, m, day := time.Date(d.year, d.month, d.day+n, 0, 0, 0, 0, loc).Date()
+	return date{y, m, day}
+}
+
+func weekdayOf(d date, loc *time.Location) time.Weekday {
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, loc).Weekday()
+}
+
+// nextWeekday 返回 from 当天或之后最近的 wd
+func nextWeekday(from date, wd time.Weekday, loc *time.Location) date {
+	delta := (int(wd) - int(weekdayOf(from, loc)) + 7) % 7
+	return addDays(from, delta, loc)
+}
+
+// weekdayOfNextWeek 返回下一个自然周 (周一为一周的第一天) 中的 wd
+func weekdayOfNextWeek(from date, wd time.Weekday, loc *time.Location) date {
+	mondayOffset := (int(weekdayOf(from, loc)) + 6) % 7 // from 距本周一的天数
+	nextMonday := addDays(from, 7-mondayOffset, loc)
+	return addDays(nextMonday, (int(wd)+6)%7, loc)
+}
+
+// nextMonthDay 返回 from 当天或之后最近的某月 day 日，跳过没有该日的月份
+func nextMonthDay(from date, day int, loc *time.Location) date {
+	for i := 0; i < 24; i++ {
+		t := time.Date(from.year, from.month+time.Month(i), day, 0, 0, 0, 0, loc)
+		if t.Day() != day {
+			continue
+		}
+		if i == 0 && day < from.day {
+			continue
+		}
+		return date{t.Year(), t.Month(), t.Day()}
+	}
+	return from
+}
+
+// upcomingMonthDay 补全省略的年份：今年的该日期已过则取明年
+func upcomingMonthDay(today date, month time.Month, day int) date {
+	d := date{today.year, month, day}
+	if month < today.month || (month == today.month && day < today.day) {
+		d.year++
+	}
+	return d
+}
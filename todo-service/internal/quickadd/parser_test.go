@@ -0,0 +1,329 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 固定的当前时间：2026-03-11 (周三) 10:00，UTC+8
+var (
+	cst = time.FixedZone("CST", 8*3600)
+	now = time.Date(2026, time.March, 11, 10, 0, 0, 0, cst)
+)
+
+func at(year int, month time.Month, day, hour, minute int) *time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, cst)
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		title      string
+		due        *time.Time
+		allDay     bool
+		priority   Priority
+		tags       []string
+		recurrence *Recurrence
+	}{
+		// ---- 英文 ----
+		{
+			name: "plain title", text: "Buy milk",
+			title: "Buy milk",
+		},
+		{
+			name: "full example", text: "Pay rent tomorrow 9am !high #home every month",
+			title: "Pay rent", due: at(2026, 3, 12, 9, 0), priority: PriorityHigh,
+			tags: []string{"home"}, recurrence: &Recurrence{FrequencyMonthly, 1},
+		},
+		{
+			name: "today all day", text: "Call mom today",
+			title: "Call mom", due: at(2026, 3, 11, 23, 59), allDay: true,
+		},
+		{
+			name: "tonight", text: "Watch movie tonight",
+			title: "Watch movie", due: at(2026, 3, 11, 20, 0),
+		},
+		{
+			name: "tomorrow at 3pm", text: "Meeting tomorrow at 3pm",
+			title: "Meeting", due: at(2026, 3, 12, 15, 0),
+		},
+		{
+			name: "tmr abbreviation", text: "gym tmr 7:30",
+			title: "gym", due: at(2026, 3, 12, 7, 30),
+		},
+		{
+			name: "day after tomorrow", text: "Dentist day after tomorrow",
+			title: "Dentist", due: at(2026, 3, 13, 23, 59), allDay: true,
+		},
+		{
+			name: "weekday with on", text: "Submit report on friday 17:00",
+			title: "Submit report", due: at(2026, 3, 13, 17, 0),
+		},
+		{
+			name: "weekday same day rolls to today", text: "Standup wednesday",
+			title: "Standup", due: at(2026, 3, 11, 23, 59), allDay: true,
+		},
+		{
+			name: "abbreviated weekday needs prefix", text: "Sat in the sun",
+			title: "Sat in the sun",
+		},
+		{
+			name: "abbreviated weekday with by", text: "Finish draft by mon",
+			title: "Finish draft", due: at(2026, 3, 16, 23, 59), allDay: true,
+		},
+		{
+			name: "next monday", text: "Plan sprint next monday 10am",
+			title: "Plan sprint", due: at(2026, 3, 16, 10, 0),
+		},
+		{
+			name: "iso date", text: "Renew passport 2026-05-01",
+			title: "Renew passport", due: at(2026, 5, 1, 23, 59), allDay: true,
+		},
+		{
+			name: "month name", text: "Birthday party March 20 at 6pm",
+			title: "Birthday party", due: at(2026, 3, 20, 18, 0),
+		},
+		{
+			name: "past month name rolls to next year", text: "Tax filing jan 15",
+			title: "Tax filing", due: at(2027, 1, 15, 23, 59), allDay: true,
+		},
+		{
+			name: "invalid date is kept in title", text: "Party feb 30",
+			title: "Party feb 30",
+		},
+		{
+			name: "time only already passed", text: "Take pills 8am",
+			title: "Take pills", due: at(2026, 3, 12, 8, 0),
+		},
+		{
+			name: "time only later today", text: "Lunch at noon",
+			title: "Lunch", due: at(2026, 3, 11, 12, 0),
+		},
+		{
+			name: "in hours", text: "Check oven in 2 hours",
+			title: "Check oven", due: at(2026, 3, 11, 12, 0),
+		},
+		{
+			name: "in days", text: "Follow up in three days",
+			title: "Follow up", due: at(2026, 3, 14, 23, 59), allDay: true,
+		},
+		{
+			name: "in years at limit", text: "Renew in 100 years",
+			title: "Renew", due: at(2126, 3, 11, 23, 59), allDay: true,
+		},
+		{
+			// 换算成 time.Duration 会溢出，不作为日期
+			name: "in minutes overflow", text: "Ping in 9999999999999 minutes",
+			title: "Ping in 9999999999999 minutes",
+		},
+		{
+			name: "in hours too far", text: "Wait in 1000000 hours",
+			title: "Wait in 1000000 hours",
+		},
+		{
+			name: "in years too far", text: "Renew in 101 years",
+			title: "Renew in 101 years",
+		},
+		{
+			name: "every weekday anchors due date", text: "Team sync every monday 9:30",
+			title: "Team sync", due: at(2026, 3, 16, 9, 30), recurrence: &Recurrence{FrequencyWeekly, 1},
+		},
+		{
+			name: "every other week", text: "Water plants every other week",
+			title: "Water plants", recurrence: &Recurrence{FrequencyWeekly, 2},
+		},
+		{
+			name: "every n days", text: "Backup every 3 days",
+			title: "Backup", recurrence: &Recurrence{FrequencyDaily, 3},
+		},
+		{
+			name: "daily adverb", text: "Meditate daily 7am",
+			title: "Meditate", due: at(2026, 3, 12, 7, 0), recurrence: &Recurrence{FrequencyDaily, 1},
+		},
+		{
+			name: "priority digit", text: "Fix prod bug !1",
+			title: "Fix prod bug", priority: PriorityUrgent,
+		},
+		{
+			name: "priority bangs", text: "Reply to email !!",
+			title: "Reply to email", priority: PriorityMedium,
+		},
+		{
+			name: "priority low word", text: "Clean garage !low",
+			title: "Clean garage", priority: PriorityLow,
+		},
+		{
+			name: "multiple tags deduplicated", text: "Read paper #work #ml #work",
+			title: "Read paper", tags: []string{"work", "ml"},
+		},
+		{
+			name: "hash inside word is not a tag", text: "Learn C# basics",
+			title: "Learn C# basics",
+		},
+		{
+			name: "dangling preposition trimmed", text: "Send invoice by friday",
+			title: "Send invoice", due: at(2026, 3, 13, 23, 59), allDay: true,
+		},
+		{
+			name: "date words inside other words are ignored", text: "Update monday.com board",
+			title: "Update monday.com board",
+		},
+
+		// ---- 中文 ----
+		{
+			name: "cn full example", text: "明天上午9点交房租 !高 #家 每月",
+			title: "交房租", due: at(2026, 3, 12, 9, 0), priority: PriorityHigh,
+			tags: []string{"家"}, recurrence: &Recurrence{FrequencyMonthly, 1},
+		},
+		{
+			name: "cn today", text: "今天买菜",
+			title: "买菜", due: at(2026, 3, 11, 23, 59), allDay: true,
+		},
+		{
+			name: "cn afternoon half hour", text: "下午三点半开会",
+			title: "开会", due: at(2026, 3, 11, 15, 30),
+		},
+		{
+			name: "cn bare hour assumes afternoon", text: "明天3点接孩子",
+			title: "接孩子", due: at(2026, 3, 12, 15, 0),
+		},
+		{
+			name: "cn tomorrow morning", text: "明早跑步",
+			title: "跑步", due: at(2026, 3, 12, 9, 0),
+		},
+		{
+			name: "cn day after tomorrow evening", text: "后天晚上8点看电影",
+			title: "看电影", due: at(2026, 3, 13, 20, 0),
+		},
+		{
+			name: "cn next week weekday", text: "下周三提交报告",
+			title: "提交报告", due: at(2026, 3, 18, 23, 59), allDay: true,
+		},
+		{
+			name: "cn this week weekday before", text: "周五前完成设计稿",
+			title: "完成设计稿", due: at(2026, 3, 13, 23, 59), allDay: true,
+		},
+		{
+			name: "cn weekend", text: "周末大扫除",
+			title: "大扫除", due: at(2026, 3, 14, 23, 59), allDay: true,
+		},
+		{
+			name: "cn month day", text: "3月20日体检",
+			title: "体检", due: at(2026, 3, 20, 23, 59), allDay: true,
+		},
+		{
+			name: "cn full date", text: "2026年5月1日出发旅行",
+			title: "出发旅行", due: at(2026, 5, 1, 23, 59), allDay: true,
+		},
+		{
+			name: "cn relative days", text: "3天后复查",
+			title: "复查", due: at(2026, 3, 14, 23, 59), allDay: true,
+		},
+		{
+			name: "cn relative too far", text: "99999999999天后复查",
+			title: "99999999999天后复查",
+		},
+		{
+			name: "cn half hour later", text: "半小时后关火",
+			title: "关火", due: at(2026, 3, 11, 10, 30),
+		},
+		{
+			name: "cn every weekday", text: "每周一例会 上午10点",
+			title: "例会", due: at(2026, 3, 16, 10, 0), recurrence: &Recurrence{FrequencyWeekly, 1},
+		},
+		{
+			name: "cn every month day", text: "每月15号还信用卡",
+			title: "还信用卡", due: at(2026, 3, 15, 23, 59), allDay: true, recurrence: &Recurrence{FrequencyMonthly, 1},
+		},
+		{
+			name: "cn every month day already passed", text: "每月1号交物业费",
+			title: "交物业费", due: at(2026, 4, 1, 23, 59), allDay: true, recurrence: &Recurrence{FrequencyMonthly, 1},
+		},
+		{
+			name: "cn every other day", text: "每隔一天浇花",
+			title: "浇花", recurrence: &Recurrence{FrequencyDaily, 2},
+		},
+		{
+			name: "cn daily", text: "每天背单词",
+			title: "背单词", recurrence: &Recurrence{FrequencyDaily, 1},
+		},
+		{
+			name: "cn urgent full-width bang", text: "修复线上故障！紧急",
+			title: "修复线上故障", priority: PriorityUrgent,
+		},
+		{
+			name: "cn one o'clock without period is not a time", text: "多一点耐心",
+			title: "多一点耐心",
+		},
+		{
+			name: "cn full-width hash tag", text: "写周报 ＃工作",
+			title: "写周报", tags: []string{"工作"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.text, now)
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+			switch {
+			case tt.due == nil && got.DueAt != nil:
+				t.Errorf("DueAt = %v, want nil", got.DueAt)
+			case tt.due != nil && got.DueAt == nil:
+				t.Errorf("DueAt = nil, want %v", tt.due)
+			case tt.due != nil && !got.DueAt.Equal(*tt.due):
+				t.Errorf("DueAt = %v, want %v", got.DueAt, tt.due)
+			}
+			if got.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", got.AllDay, tt.allDay)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("Priority = %v, want %v", got.Priority, tt.priority)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.tags) {
+				t.Errorf("Tags = %q, want %q", got.Tags, tt.tags)
+			}
+			if !reflect.DeepEqual(got.Recurrence, tt.recurrence) {
+				t.Errorf("Recurrence = %+v, want %+v", got.Recurrence, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	got := Parse("明天 交房租 #家", now)
+	want := []Token{
+		{Kind: KindDate, Text: "明天", Start: 0, End: 2},
+		{Kind: KindTag, Text: "#家", Start: 7, End: 9},
+	}
+	if !reflect.DeepEqual(got.Tokens, want) {
+		t.Errorf("Tokens = %+v, want %+v", got.Tokens, want)
+	}
+}
+
+func TestParseIsDeterministic(t *testing.T) {
+	text := "Pay rent tomorrow 9am !high #home #bills every month"
+	first := Parse(text, now)
+	for i := 0; i < 20; i++ {
+		if got := Parse(text, now); !reflect.DeepEqual(got, first) {
+			t.Fatalf("Parse is not deterministic: %+v != %+v", got, first)
+		}
+	}
+}
+
+func TestParseUsesTimeZoneOfNow(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// 上海时间 3 月 11 日 10:00 在纽约仍是 3 月 10 日
+	got := Parse("Call bank tomorrow 9am", now.In(ny))
+	want := time.Date(2026, time.March, 11, 9, 0, 0, 0, ny)
+	if got.DueAt == nil || !got.DueAt.Equal(want) {
+		t.Errorf("DueAt = %v, want %v", got.DueAt, want)
+	}
+}
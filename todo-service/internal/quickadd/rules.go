@@ -0,0 +1,494 @@
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// rule 描述一种可识别的表达式。规则按顺序依次应用，
+// 与已识别片段重叠的匹配会被跳过，因此更具体的规则必须排在前面。
+type rule struct {
+	kind  TokenKind
+	re    *regexp.Regexp
+	apply func(p *parser, m match) bool
+}
+
+const (
+	enWeekdayFull  = `monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+	enWeekdayShort = `mon|tues|tue|wed|thurs|thur|thu|fri|sat|sun`
+	enMonths       = `january|february|march|april|may|june|july|august|september|october|november|december|jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec`
+	enNumber       = `\d+|a|an|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve`
+	cnNumber       = `\d+|[零一二两三四五六七八九十]+`
+	cnWeekday      = `[一二三四五六日天1-7]`
+	datePrefix     = `(?:(?:on|by|due)\s+)?`
+	cnBefore       = `(?:之前|以前|前)?` // "周五前"、"明天下午三点之前"
+)
+
+var rules = []rule{
+	// ---- 重复规则 ----
+	{KindRecurrence, regexp.MustCompile(`(?i)\bevery\s+(` + enWeekdayFull + `|` + enWeekdayShort + `)\b`), func(p *parser, m match) bool {
+		wd := enWeekdays[strings.ToLower(m.group(1))]
+		return p.setWeeklyAnchor(wd)
+	}},
+	{KindRecurrence, regexp.MustCompile(`(?i)\bevery\s+(?:(other)\s+|(` + enNumber + `)\s+)?(day|week|month|year)s?\b`), func(p *parser, m match) bool {
+		interval := 1
+		if m.group(1) != "" {
+			interval = 2
+		} else if m.group(2) != "" {
+			n, ok := parseNumber(m.group(2))
+			if !ok {
+				return false
+			}
+			interval = n
+		}
+		return p.setRecurrence(enUnits[strings.ToLower(m.group(3))], interval)
+	}},
+	{KindRecurrence, regexp.MustCompile(`(?i)\b(daily|weekly|monthly|yearly|annually)\b`), func(p *parser, m match) bool {
+		return p.setRecurrence(enAdverbs[strings.ToLower(m.group(1))], 1)
+	}},
+	{KindRecurrence, regexp.MustCompile(`每(?:周|星期|礼拜)(` + cnWeekday + `)`), func(p *parser, m match) bool {
+		return p.setWeeklyAnchor(cnWeekdays[m.group(1)])
+	}},
+	{KindRecurrence, regexp.MustCompile(`每个?月(` + cnNumber + `)[日号]`), func(p *parser, m match) bool {
+		day, ok := parseNumber(m.group(1))
+		if !ok || day < 1 || day > 31 || p.result.Recurrence != nil {
+			return false
+		}
+		p.anchorMonthDay = day
+		return p.setRecurrence(FrequencyMonthly, 1)
+	}},
+	{KindRecurrence, regexp.MustCompile(`每(隔)?(` + cnNumber + `)?个?(天|日|周|星期|礼拜|月|年)`), func(p *parser, m match) bool {
+		interval := 1
+		if m.group(2) != "" {
+			n, ok := parseNumber(m.group(2))
+			if !ok {
+				return false
+			}
+			interval = n
+		}
+		// "每隔一天" 表示隔一天重复一次，即每两天
+		if m.group(1) != "" {
+			interval++
+		}
+		return p.setRecurrence(cnUnits[m.group(3)], interval)
+	}},
+
+	// ---- 相对日期 ----
+	{KindDate, regexp.MustCompile(`(?i)\bin\s+(` + enNumber + `|half\s+an)\s+(minute|min|hour|hr|day|week|month|year)s?\b`), func(p *parser, m match) bool {
+		unit := strings.ToLower(m.group(2))
+		if strings.HasPrefix(strings.ToLower(m.group(1)), "half") {
+			if unit != "hour" && unit != "hr" {
+				return false
+			}
+			return p.addRelative(30, "minute")
+		}
+		n, ok := parseNumber(m.group(1))
+		return ok && p.addRelative(n, unit)
+	}},
+	{KindDate, regexp.MustCompile(`(` + cnNumber + `|半)\s*个?(分钟|小时|钟头|天|日|周|星期|礼拜|月|年)(?:以后|之后|后)`), func(p *parser, m match) bool {
+		unit := cnRelativeUnits[m.group(2)]
+		if m.group(1) == "半" {
+			if unit != "hour" {
+				return false
+			}
+			return p.addRelative(30, "minute")
+		}
+		n, ok := parseNumber(m.group(1))
+		return ok && p.addRelative(n, unit)
+	}},
+
+	// ---- 绝对日期 ----
+	{KindDate, regexp.MustCompile(`(?i)\b` + datePrefix + `(\d{4})-(\d{1,2})-(\d{1,2})\b`), func(p *parser, m match) bool {
+		y, _ := strconv.Atoi(m.group(1))
+		mo, _ := strconv.Atoi(m.group(2))
+		d, _ := strconv.Atoi(m.group(3))
+		return p.setDate(date{y, time.Month(mo), d})
+	}},
+	{KindDate, regexp.MustCompile(`(?:(\d{4})年)?(` + cnNumber + `)月(` + cnNumber + `)[日号]` + cnBefore), func(p *parser, m match) bool {
+		mo, ok1 := parseNumber(m.group(2))
+		d, ok2 := parseNumber(m.group(3))
+		if !ok1 || !ok2 || mo < 1 || mo > 12 {
+			return false
+		}
+		return p.setMonthDay(m.group(1), time.Month(mo), d)
+	}},
+	{KindDate, regexp.MustCompile(`(?i)\b` + datePrefix + `(` + enMonths + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?\b`), func(p *parser, m match) bool {
+		d, _ := strconv.Atoi(m.group(2))
+		return p.setMonthDay(m.group(3), enMonthNames[strings.ToLower(m.group(1))[:3]], d)
+	}},
+	{KindDate, regexp.MustCompile(`(?i)\b` + datePrefix + `(\d{1,2})(?:st|nd|rd|th)?\s+(` + enMonths + `)\.?(?:,?\s+(\d{4}))?\b`), func(p *parser, m match) bool {
+		d, _ := strconv.Atoi(m.group(1))
+		return p.setMonthDay(m.group(3), enMonthNames[strings.ToLower(m.group(2))[:3]], d)
+	}},
+	{KindDate, regexp.MustCompile(`(?i)\b` + datePrefix + `(\d{1,2})/(\d{1,2})(?:/(\d{4}))?\b`), func(p *parser, m match) bool {
+		mo, _ := strconv.Atoi(m.group(1))
+		d, _ := strconv.Atoi(m.group(2))
+		if mo < 1 || mo > 12 {
+			return false
+		}
+		return p.setMonthDay(m.group(3), time.Month(mo), d)
+	}},
+
+	// ---- 日期关键词 ----
+	{KindDate, regexp.MustCompile(`(?i)\b` + datePrefix + `(day\s+after\s+tomorrow|today|tonight|tomorrow|tmrw|tmr)\b`), func(p *parser, m match) bool {
+		word := strings.Join(strings.Fields(strings.ToLower(m.group(1))), " ")
+		switch word {
+		case "today":
+			return p.setDate(p.today())
+		case "tonight":
+			return p.setDate(p.today()) && p.setSoftTime(20)
+		case "day after tomorrow":
+			return p.setDate(addDays(p.today(), 2, p.now.Location()))
+		default:
+			return p.setDate(addDays(p.today(), 1, p.now.Location()))
+		}
+	}},
+	{KindDate, regexp.MustCompile(`(?i)\b(?:(?:by|due)\s+)?next\s+(week|month|year)\b`), func(p *parser, m match) bool {
+		return p.setNextPeriod(strings.ToLower(m.group(1)))
+	}},
+	{KindDate, regexp.MustCompile(`(?i)\b(?:(on|by|due|this|next)\s+)?(` + enWeekdayFull + `|` + enWeekdayShort + `)\b`), func(p *parser, m match) bool {
+		prefix := strings.ToLower(m.group(1))
+		name := strings.ToLower(m.group(2))
+		// "sun"、"wed" 之类的缩写本身也是常见单词，只在带前缀时识别
+		if prefix == "" && !strings.HasSuffix(name, "day") {
+			return false
+		}
+		return p.setWeekday(enWeekdays[name], prefix == "next")
+	}},
+	{KindDate, regexp.MustCompile(`(大后天|后天|今天|今日|今早|今晚|明天|明日|明早|明晚)` + cnBefore), func(p *parser, m match) bool {
+		loc := p.now.Location()
+		switch m.group(1) {
+		case "今天", "今日":
+			return p.setDate(p.today())
+		case "今早":
+			return p.setDate(p.today()) && p.setSoftTime(9)
+		case "今晚":
+			return p.setDate(p.today()) && p.setSoftTime(20)
+		case "明早":
+			return p.setDate(addDays(p.today(), 1, loc)) && p.setSoftTime(9)
+		case "明晚":
+			return p.setDate(addDays(p.today(), 1, loc)) && p.setSoftTime(20)
+		case "后天":
+			return p.setDate(addDays(p.today(), 2, loc))
+		case "大后天":
+			return p.setDate(addDays(p.today(), 3, loc))
+		default:
+			return p.setDate(addDays(p.today(), 1, loc))
+		}
+	}},
+	{KindDate, regexp.MustCompile(`(下个?|这个?|本)?(?:周|星期|礼拜)(` + cnWeekday + `)` + cnBefore), func(p *parser, m match) bool {
+		return p.setWeekday(cnWeekdays[m.group(2)], strings.HasPrefix(m.group(1), "下"))
+	}},
+	{KindDate, regexp.MustCompile(`(下个?|这个?|本)?周末` + cnBefore), func(p *parser, m match) bool {
+		return p.setWeekday(time.Saturday, strings.HasPrefix(m.group(1), "下"))
+	}},
+	{KindDate, regexp.MustCompile(`下个?(?:周|星期|礼拜)|下个?月|明年`), func(p *parser, m match) bool {
+		switch {
+		case strings.HasSuffix(m.group(0), "月"):
+			return p.setNextPeriod("month")
+		case m.group(0) == "明年":
+			return p.setNextPeriod("year")
+		default:
+			return p.setNextPeriod("week")
+		}
+	}},
+
+	// ---- 时间 ----
+	{KindTime, regexp.MustCompile(`(?i)\b(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s?(am|pm)\b`), func(p *parser, m match) bool {
+		h, _ := strconv.Atoi(m.group(1))
+		min, _ := strconv.Atoi(m.group(2))
+		if h < 1 || h > 12 {
+			return false
+		}
+		h %= 12
+		if strings.EqualFold(m.group(3), "pm") {
+			h += 12
+		}
+		return p.setTime(h, min)
+	}},
+	{KindTime, regexp.MustCompile(`(?i)\b(?:at\s+)?(\d{1,2}):(\d{2})\b`), func(p *parser, m match) bool {
+		h, _ := strconv.Atoi(m.group(1))
+		min, _ := strconv.Atoi(m.group(2))
+		return p.setTime(h, min)
+	}},
+	{KindTime, regexp.MustCompile(`(凌晨|早上|早晨|上午|中午|下午|傍晚|晚上)?(` + cnNumber + `)[点點](?:(半)|(一刻)|(三刻)|(` + cnNumber + `)分?)?` + cnBefore), func(p *parser, m match) bool {
+		period := m.group(1)
+		h, ok := parseNumber(m.group(2))
+		if !ok {
+			return false
+		}
+		min := 0
+		switch {
+		case m.group(3) != "":
+			min = 30
+		case m.group(4) != "":
+			min = 15
+		case m.group(5) != "":
+			min = 45
+		case m.group(6) != "":
+			if min, ok = parseNumber(m.group(6)); !ok {
+				return false
+			}
+		}
+		// "快一点"、"早一点" 中的 "一点" 不是时间
+		if period == "" && m.group(2) == "一" && m.group(3)+m.group(4)+m.group(5)+m.group(6) == "" {
+			return false
+		}
+		switch period {
+		case "下午", "傍晚", "晚上":
+			if h < 12 {
+				h += 12
+			}
+		case "中午":
+			if h < 11 {
+				h += 12
+			}
+		case "":
+			h = assumeAfternoon(h)
+		}
+		return p.setTime(h, min)
+	}},
+	{KindTime, regexp.MustCompile(`(?i)\bat\s+(\d{1,2})\b`), func(p *parser, m match) bool {
+		h, _ := strconv.Atoi(m.group(1))
+		if h > 23 {
+			return false
+		}
+		return p.setTime(assumeAfternoon(h), 0)
+	}},
+	{KindTime, regexp.MustCompile(`(?i)\b(?:at\s+|in\s+the\s+)?(noon|midday|midnight|morning|afternoon|evening)\b`), func(p *parser, m match) bool {
+		h := enTimeWords[strings.ToLower(m.group(1))]
+		if h == 24 {
+			return p.setTime(23, 59)
+		}
+		return p.setTime(h, 0)
+	}},
+	{KindTime, regexp.MustCompile(`早上|早晨|上午|中午|下午|傍晚|晚上`), func(p *parser, m match) bool {
+		return p.setTime(cnTimeWords[m.group(0)], 0)
+	}},
+
+	// ---- 优先级 ----
+	{KindPriority, regexp.MustCompile(`(?i)[!！](urgent|high|medium|med|low|u|h|m|l|[1-4]|紧急|高|中|低)`), func(p *parser, m match) bool {
+		return m.isolated() && p.setPriority(priorityWords[strings.ToLower(m.group(1))])
+	}},
+	{KindPriority, regexp.MustCompile(`[!！]+`), func(p *parser, m match) bool {
+		if !m.isolated() {
+			return false
+		}
+		switch utf8.RuneCountInString(m.group(0)) {
+		case 2:
+			return p.setPriority(PriorityMedium)
+		case 3:
+			return p.setPriority(PriorityHigh)
+		default:
+			return false
+		}
+	}},
+
+	// ---- 标签 ----
+	{KindTag, regexp.MustCompile(`[#＃]([\p{L}\p{N}_][\p{L}\p{N}_\-/]*)`), func(p *parser, m match) bool {
+		if !m.isolated() {
+			return false
+		}
+		tag := m.group(1)
+		for _, existing := range p.result.Tags {
+			if existing == tag {
+				return true
+			}
+		}
+		p.result.Tags = append(p.result.Tags, tag)
+		return true
+	}},
+}
+
+var (
+	enWeekdays = map[string]time.Weekday{
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tues": time.Tuesday, "tue": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thurs": time.Thursday, "thur": time.Thursday, "thu": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+		"sunday": time.Sunday, "sun": time.Sunday,
+	}
+	cnWeekdays = map[string]time.Weekday{
+		"一": time.Monday, "1": time.Monday,
+		"二": time.Tuesday, "2": time.Tuesday,
+		"三": time.Wednesday, "3": time.Wednesday,
+		"四": time.Thursday, "4": time.Thursday,
+		"五": time.Friday, "5": time.Friday,
+		"六": time.Saturday, "6": time.Saturday,
+		"日": time.Sunday, "天": time.Sunday, "7": time.Sunday,
+	}
+	enMonthNames = map[string]time.Month{
+		"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+		"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+		"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+	}
+	enUnits   = map[string]Frequency{"day": FrequencyDaily, "week": FrequencyWeekly, "month": FrequencyMonthly, "year": FrequencyYearly}
+	enAdverbs = map[string]Frequency{"daily": FrequencyDaily, "weekly": FrequencyWeekly, "monthly": FrequencyMonthly, "yearly": FrequencyYearly, "annually": FrequencyYearly}
+	cnUnits   = map[string]Frequency{
+		"天": FrequencyDaily, "日": FrequencyDaily,
+		"周": FrequencyWeekly, "星期": FrequencyWeekly, "礼拜": FrequencyWeekly,
+		"月": FrequencyMonthly, "年": FrequencyYearly,
+	}
+	cnRelativeUnits = map[string]string{
+		"分钟": "minute", "小时": "hour", "钟头": "hour",
+		"天": "day", "日": "day", "周": "week", "星期": "week", "礼拜": "week",
+		"月": "month", "年": "year",
+	}
+	// 24 表示 midnight，按当天 23:59 处理
+	enTimeWords = map[string]int{"noon": 12, "midday": 12, "midnight": 24, "morning": 9, "afternoon": 15, "evening": 19}
+	cnTimeWords = map[string]int{"早上": 9, "早晨": 9, "上午": 9, "中午": 12, "下午": 15, "傍晚": 18, "晚上": 20}
+
+	// 数字优先级与 Todoist 一致：!1 最高，!4 最低
+	priorityWords = map[string]Priority{
+		"urgent": PriorityUrgent, "u": PriorityUrgent, "1": PriorityUrgent, "紧急": PriorityUrgent,
+		"high": PriorityHigh, "h": PriorityHigh, "2": PriorityHigh, "高": PriorityHigh,
+		"medium": PriorityMedium, "med": PriorityMedium, "m": PriorityMedium, "3": PriorityMedium, "中": PriorityMedium,
+		"low": PriorityLow, "l": PriorityLow, "4": PriorityLow, "低": PriorityLow,
+	}
+	enNumberWords = map[string]int{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+		"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	}
+	cnDigits = map[rune]int{'零': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+)
+
+// assumeAfternoon 没有上下午信息的 1-7 点按下午处理 ("at 5"、"三点开会")
+func assumeAfternoon(hour int) int {
+	if hour >= 1 && hour <= 7 {
+		return hour + 12
+	}
+	return hour
+}
+
+func (p *parser) setSoftTime(hour int) bool {
+	p.hasSoftTime, p.softHour = true, hour
+	return true
+}
+
+func (p *parser) setWeeklyAnchor(wd time.Weekday) bool {
+	if p.result.Recurrence != nil {
+		return false
+	}
+	p.anchorWeekday = &wd
+	return p.setRecurrence(FrequencyWeekly, 1)
+}
+
+// setWeekday "friday"/"周五" 取今天或之后最近的一天；"next friday"/"下周五" 取下一个自然周中的那一天
+func (p *parser) setWeekday(wd time.Weekday, nextWeek bool) bool {
+	loc := p.now.Location()
+	if nextWeek {
+		return p.setDate(weekdayOfNextWeek(p.today(), wd, loc))
+	}
+	return p.setDate(nextWeekday(p.today(), wd, loc))
+}
+
+// setNextPeriod "next week" 为下周一，"next month" 为下月 1 日，"next year" 为明年 1 月 1 日
+func (p *parser) setNextPeriod(period string) bool {
+	today := p.today()
+	switch period {
+	case "week":
+		return p.setDate(weekdayOfNextWeek(today, time.Monday, p.now.Location()))
+	case "month":
+		return p.setDateFromTime(time.Date(today.year, today.month+1, 1, 0, 0, 0, 0, p.now.Location()))
+	default:
+		return p.setDate(date{today.year + 1, time.January, 1})
+	}
+}
+
+// setMonthDay 设置月日，year 为空时补全为即将到来的那一天
+func (p *parser) setMonthDay(year string, month time.Month, day int) bool {
+	if year == "" {
+		return p.setDate(upcomingMonthDay(p.today(), month, day))
+	}
+	y, _ := strconv.Atoi(year)
+	return p.setDate(date{y, month, day})
+}
+
+// maxRelativeYears 相对时间最远约 100 年。更大的数量不是有意义的截止时间，换算成 time.Duration 还会溢出，
+// 这样的匹配不作为日期，保留在标题中
+const maxRelativeYears = 100
+
+// addRelative 处理 "in 3 days"、"2小时后" 等相对时间；以分钟、小时计的相对时间会得到精确时刻
+func (p *parser) addRelative(n int, unit string) bool {
+	if n <= 0 || p.date != nil || p.exact != nil {
+		return false
+	}
+	today := p.today()
+	loc := p.now.Location()
+	switch strings.TrimSuffix(unit, "s") {
+	case "minute", "min":
+		if p.hasTime || n > maxRelativeYears*366*24*60 {
+			return false
+		}
+		t := p.now.Add(time.Duration(n) * time.Minute)
+		p.exact = &t
+		return true
+	case "hour", "hr":
+		if p.hasTime || n > maxRelativeYears*366*24 {
+			return false
+		}
+		t := p.now.Add(time.Duration(n) * time.Hour)
+		p.exact = &t
+		return true
+	case "day":
+		return n <= maxRelativeYears*366 && p.setDate(addDays(today, n, loc))
+	case "week":
+		return n <= maxRelativeYears*53 && p.setDate(addDays(today, 7*n, loc))
+	case "month":
+		return n <= maxRelativeYears*12 && p.setDateFromTime(time.Date(today.year, today.month+time.Month(n), today.day, 0, 0, 0, 0, loc))
+	default:
+		return n <= maxRelativeYears && p.setDate(date{today.year + n, today.month, today.day})
+	}
+}
+
+// parseNumber 解析阿拉伯数字、英文数词 (one..twelve) 和 0-99 的中文数字
+func parseNumber(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	if n, ok := enNumberWords[s]; ok {
+		return n, true
+	}
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+	// 中文数字："十"、"十二"、"二十"、"二十三"、"五"
+	tens, ones := 0, 0
+	switch idx := strings.IndexRune(s, '十'); {
+	case idx < 0:
+		if len(runes) != 1 {
+			return 0, false
+		}
+		d, ok := cnDigits[runes[0]]
+		return d, ok
+	default:
+		before, after := []rune(s[:idx]), []rune(s[idx+len("十"):])
+		tens = 1
+		if len(before) == 1 {
+			d, ok := cnDigits[before[0]]
+			if !ok {
+				return 0, false
+			}
+			tens = d
+		} else if len(before) > 1 {
+			return 0, false
+		}
+		if len(after) == 1 {
+			d, ok := cnDigits[after[0]]
+			if !ok {
+				return 0, false
+			}
+			ones = d
+		} else if len(after) > 1 {
+			return 0, false
+		}
+	}
+	return tens*10 + ones, true
+}
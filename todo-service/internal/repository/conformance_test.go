@@ -0,0 +1,633 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+// runConformance 是所有 TodoRepository 实现都必须通过的一致性测试，newRepo 每次返回一个空的仓库
+func runConformance(t *testing.T, newRepo func(t *testing.T) TodoRepository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo TodoRepository)
+	}{
+		{"Todos", testTodos},
+		{"ListTodos", testListTodos},
+		{"ChangeSeq", testChangeSeq},
+		{"Tombstones", testTombstones},
+		{"Transaction", testTransaction},
+		{"Templates", testTemplates},
+		{"SavedSearches", testSavedSearches},
+		{"ArchivePolicies", testArchivePolicies},
+		{"TimeEntries", testTimeEntries},
+		{"BatchOperationLog", testBatchOperationLog},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+var (
+	ctx  = context.Background()
+	base = time.Date(2026, time.March, 11, 10, 0, 0, 0, time.UTC)
+)
+
+func at(hours int) *time.Time {
+	t := base.Add(time.Duration(hours) * time.Hour)
+	return &t
+}
+
+func strPtr(s string) *string { return &s }
+
+func uintPtr(u uint) *uint { return &u }
+
+func mustCreateTodo(t *testing.T, repo TodoRepository, todo *model.Todo) *model.Todo {
+	t.Helper()
+	if err := repo.CreateTodo(ctx, todo); err != nil {
+		t.Fatalf("CreateTodo(%q) error = %v", todo.Title, err)
+	}
+	return todo
+}
+
+func todoIDs(todos []*model.Todo) []uint {
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return ids
+}
+
+func equalIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testTodos(t *testing.T, repo TodoRepository) {
+	todo := mustCreateTodo(t, repo, &model.Todo{
+		UserID:    1,
+		Title:     "Write report",
+		Tags:      []string{"work"},
+		Checklist: []model.ChecklistItem{{Content: "outline"}},
+		DueAt:     at(24),
+		Priority:  2,
+		ClientID:  strPtr("c-1"),
+	})
+	if todo.ID == 0 || todo.CreatedAt.IsZero() || todo.UpdatedAt.IsZero() {
+		t.Fatalf("CreateTodo did not fill ID and timestamps: %+v", todo)
+	}
+
+	got, err := repo.GetTodo(ctx, 1, todo.ID)
+	if err != nil {
+		t.Fatalf("GetTodo error = %v", err)
+	}
+	if got.Title != "Write report" || got.Priority != 2 || len(got.Tags) != 1 || got.Tags[0] != "work" ||
+		len(got.Checklist) != 1 || got.Checklist[0].Content != "outline" || got.DueAt == nil || !got.DueAt.Equal(*at(24)) {
+		t.Errorf("GetTodo = %+v, fields do not round-trip", got)
+	}
+	if _, err := repo.GetTodo(ctx, 2, todo.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo for other user error = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.GetTodo(ctx, 1, todo.ID+100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo for missing todo error = %v, want ErrNotFound", err)
+	}
+
+	// 修改返回的对象不影响已保存的数据
+	got.Title = "changed"
+	got.Tags[0] = "changed"
+	if again, _ := repo.GetTodo(ctx, 1, todo.ID); again.Title != "Write report" || again.Tags[0] != "work" {
+		t.Errorf("GetTodo returned an alias of stored data: %+v", again)
+	}
+
+	byClient, err := repo.GetTodoByClientID(ctx, 1, "c-1")
+	if err != nil || byClient.ID != todo.ID {
+		t.Errorf("GetTodoByClientID = %v, %v, want todo %d", byClient, err, todo.ID)
+	}
+	if _, err := repo.GetTodoByClientID(ctx, 2, "c-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodoByClientID for other user error = %v, want ErrNotFound", err)
+	}
+	if err := repo.CreateTodo(ctx, &model.Todo{UserID: 1, Title: "dup", ClientID: strPtr("c-1")}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateTodo with duplicate client_id error = %v, want ErrDuplicate", err)
+	}
+	mustCreateTodo(t, repo, &model.Todo{UserID: 2, Title: "same client id, other user", ClientID: strPtr("c-1")})
+	// 没有 client_id 的 Todo 不受唯一约束限制
+	mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "no client id"})
+	mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "no client id either"})
+
+	loaded, _ := repo.GetTodo(ctx, 1, todo.ID)
+	before := loaded.UpdatedAt
+	time.Sleep(5 * time.Millisecond)
+	loaded.Title = "Write final report"
+	loaded.Completed = true
+	loaded.CompletedAt = at(1)
+	loaded.DueAt = nil
+	loaded.Tags = []string{"work", "urgent"}
+	loaded.ChangeSeq = 7
+	if err := repo.SaveTodo(ctx, loaded); err != nil {
+		t.Fatalf("SaveTodo error = %v", err)
+	}
+	saved, _ := repo.GetTodo(ctx, 1, todo.ID)
+	if saved.Title != "Write final report" || !saved.Completed || saved.CompletedAt == nil || saved.DueAt != nil ||
+		len(saved.Tags) != 2 || saved.ChangeSeq != 7 {
+		t.Errorf("SaveTodo did not persist all fields: %+v", saved)
+	}
+	if !saved.UpdatedAt.After(before) {
+		t.Errorf("SaveTodo UpdatedAt = %v, want after %v", saved.UpdatedAt, before)
+	}
+	if !saved.CreatedAt.Equal(loaded.CreatedAt) {
+		t.Errorf("SaveTodo changed CreatedAt from %v to %v", loaded.CreatedAt, saved.CreatedAt)
+	}
+
+	if err := repo.DeleteTodo(ctx, saved); err != nil {
+		t.Fatalf("DeleteTodo error = %v", err)
+	}
+	if _, err := repo.GetTodo(ctx, 1, todo.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo after delete error = %v, want ErrNotFound", err)
+	}
+	// 删除后 client_id 可以被重新使用 (重复提交由墓碑判断)
+	mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "reused client id", ClientID: strPtr("c-1")})
+}
+
+func testListTodos(t *testing.T, repo TodoRepository) {
+	root := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "root"})
+	child := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "child", ParentID: uintPtr(root.ID)})
+	archived := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "archived", ParentID: uintPtr(root.ID), ArchivedAt: at(0)})
+	grandchild := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "grandchild", ParentID: uintPtr(child.ID)})
+	other := mustCreateTodo(t, repo, &model.Todo{UserID: 2, Title: "other user", ParentID: uintPtr(root.ID)})
+
+	tests := []struct {
+		name   string
+		userID uint
+		filter TodoFilter
+		want   []uint
+	}{
+		{"all", 1, TodoFilter{}, []uint{root.ID, child.ID, archived.ID, grandchild.ID}},
+		{"exclude archived", 1, TodoFilter{ExcludeArchived: true}, []uint{root.ID, child.ID, grandchild.ID}},
+		{"ids", 1, TodoFilter{IDs: []uint{grandchild.ID, root.ID, other.ID}}, []uint{root.ID, grandchild.ID}},
+		{"empty ids", 1, TodoFilter{IDs: []uint{}}, nil},
+		{"parents", 1, TodoFilter{ParentIDs: []uint{root.ID}}, []uint{child.ID, archived.ID}},
+		{"parents exclude archived", 1, TodoFilter{ParentIDs: []uint{root.ID, child.ID}, ExcludeArchived: true}, []uint{child.ID, grandchild.ID}},
+		{"other user", 2, TodoFilter{}, []uint{other.ID}},
+		{"no todos", 3, TodoFilter{}, nil},
+	}
+	for _, tt := range tests {
+		todos, err := repo.ListTodos(ctx, tt.userID, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: ListTodos error = %v", tt.name, err)
+		}
+		if got := todoIDs(todos); !equalIDs(got, tt.want) {
+			t.Errorf("%s: ListTodos = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testChangeSeq(t *testing.T, repo TodoRepository) {
+	if seq, err := repo.CurrentChangeSeq(ctx, 1); err != nil || seq != 0 {
+		t.Fatalf("CurrentChangeSeq before any change = %d, %v, want 0", seq, err)
+	}
+	next := func(userID uint) uint64 {
+		t.Helper()
+		var seq uint64
+		err := repo.Transaction(ctx, func(tx TodoRepository) error {
+			var err error
+			seq, err = tx.NextChangeSeq(ctx, userID)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("NextChangeSeq error = %v", err)
+		}
+		return seq
+	}
+	if a, b, c := next(1), next(1), next(2); a != 1 || b != 2 || c != 1 {
+		t.Errorf("NextChangeSeq = %d, %d (user 1), %d (user 2), want 1, 2, 1", a, b, c)
+	}
+	if seq, _ := repo.CurrentChangeSeq(ctx, 1); seq != 2 {
+		t.Errorf("CurrentChangeSeq = %d, want 2", seq)
+	}
+
+	a := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "a", ChangeSeq: 3})
+	b := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "b", ChangeSeq: 2})
+	c := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "c", ChangeSeq: 2})
+	mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "d", ChangeSeq: 5})
+	mustCreateTodo(t, repo, &model.Todo{UserID: 2, Title: "other", ChangeSeq: 3})
+
+	todos, err := repo.ListTodoChanges(ctx, 1, 1, 4)
+	if err != nil {
+		t.Fatalf("ListTodoChanges error = %v", err)
+	}
+	if got, want := todoIDs(todos), []uint{b.ID, c.ID, a.ID}; !equalIDs(got, want) {
+		t.Errorf("ListTodoChanges(1, 4] = %v, want %v", got, want)
+	}
+	if todos, _ := repo.ListTodoChanges(ctx, 1, 3, 4); len(todos) != 0 {
+		t.Errorf("ListTodoChanges(3, 4] = %v, want none", todoIDs(todos))
+	}
+}
+
+func testTombstones(t *testing.T, repo TodoRepository) {
+	for _, tombstone := range []*model.TodoTombstone{
+		{TodoID: 10, UserID: 1, ChangeSeq: 4, ClientID: strPtr("c-10")},
+		{TodoID: 12, UserID: 1, ChangeSeq: 3},
+		{TodoID: 11, UserID: 1, ChangeSeq: 3},
+		{TodoID: 13, UserID: 1, ChangeSeq: 6},
+		{TodoID: 14, UserID: 2, ChangeSeq: 4},
+	} {
+		if err := repo.CreateTombstone(ctx, tombstone); err != nil {
+			t.Fatalf("CreateTombstone(%d) error = %v", tombstone.TodoID, err)
+		}
+	}
+	if err := repo.CreateTombstone(ctx, &model.TodoTombstone{TodoID: 10, UserID: 1, ChangeSeq: 9}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateTombstone for the same todo error = %v, want ErrDuplicate", err)
+	}
+
+	tombstone, err := repo.GetTombstone(ctx, 1, 10)
+	if err != nil || tombstone.ChangeSeq != 4 || tombstone.ClientID == nil || *tombstone.ClientID != "c-10" || tombstone.CreatedAt.IsZero() {
+		t.Errorf("GetTombstone = %+v, %v", tombstone, err)
+	}
+	if _, err := repo.GetTombstone(ctx, 2, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTombstone for other user error = %v, want ErrNotFound", err)
+	}
+	if tombstone, err := repo.GetTombstoneByClientID(ctx, 1, "c-10"); err != nil || tombstone.TodoID != 10 {
+		t.Errorf("GetTombstoneByClientID = %+v, %v, want todo 10", tombstone, err)
+	}
+	if _, err := repo.GetTombstoneByClientID(ctx, 1, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTombstoneByClientID for missing client id error = %v, want ErrNotFound", err)
+	}
+
+	tombstones, err := repo.ListTombstones(ctx, 1, 2, 5)
+	if err != nil {
+		t.Fatalf("ListTombstones error = %v", err)
+	}
+	var got []uint
+	for _, tombstone := range tombstones {
+		got = append(got, tombstone.TodoID)
+	}
+	if want := []uint{11, 12, 10}; !equalIDs(got, want) {
+		t.Errorf("ListTombstones(2, 5] = %v, want %v", got, want)
+	}
+}
+
+func testTransaction(t *testing.T, repo TodoRepository) {
+	errRollback := errors.New("rollback")
+	var created *model.Todo
+	err := repo.Transaction(ctx, func(tx TodoRepository) error {
+		if _, err := tx.NextChangeSeq(ctx, 1); err != nil {
+			return err
+		}
+		created = mustCreateTodo(t, tx, &model.Todo{UserID: 1, Title: "rolled back"})
+		// 事务内可以读到自己的写入
+		if _, err := tx.GetTodo(ctx, 1, created.ID); err != nil {
+			t.Errorf("GetTodo inside transaction error = %v", err)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Transaction error = %v, want the error returned by fn", err)
+	}
+	if _, err := repo.GetTodo(ctx, 1, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo after rollback error = %v, want ErrNotFound", err)
+	}
+	if seq, _ := repo.CurrentChangeSeq(ctx, 1); seq != 0 {
+		t.Errorf("CurrentChangeSeq after rollback = %d, want 0", seq)
+	}
+
+	err = repo.Transaction(ctx, func(tx TodoRepository) error {
+		created = mustCreateTodo(t, tx, &model.Todo{UserID: 1, Title: "committed"})
+		// 嵌套事务回滚不影响外层事务
+		_ = tx.Transaction(ctx, func(inner TodoRepository) error {
+			mustCreateTodo(t, inner, &model.Todo{UserID: 1, Title: "inner rolled back"})
+			return errRollback
+		})
+		return tx.Transaction(ctx, func(inner TodoRepository) error {
+			_, err := inner.NextChangeSeq(ctx, 1)
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("Transaction error = %v", err)
+	}
+	todos, _ := repo.ListTodos(ctx, 1, TodoFilter{})
+	if len(todos) != 1 || todos[0].Title != "committed" {
+		t.Errorf("ListTodos after commit = %v, want only the committed todo", todoIDs(todos))
+	}
+	if seq, _ := repo.CurrentChangeSeq(ctx, 1); seq != 1 {
+		t.Errorf("CurrentChangeSeq after commit = %d, want 1", seq)
+	}
+}
+
+func testTemplates(t *testing.T, repo TodoRepository) {
+	template := &model.TodoTemplate{UserID: 1, Name: "Release", Description: "release checklist"}
+	if err := repo.CreateTemplate(ctx, template); err != nil {
+		t.Fatalf("CreateTemplate error = %v", err)
+	}
+	if template.ID == 0 {
+		t.Fatal("CreateTemplate did not fill ID")
+	}
+	offset := int64(3600)
+	root := &model.TemplateItem{TemplateID: template.ID, Position: 0, Title: "Release", DueOffset: &offset}
+	if err := repo.CreateTemplateItem(ctx, root); err != nil {
+		t.Fatalf("CreateTemplateItem error = %v", err)
+	}
+	// 条目按 Position 而不是创建顺序返回
+	for _, item := range []*model.TemplateItem{
+		{TemplateID: template.ID, ParentItemID: &root.ID, Position: 2, Title: "Announce"},
+		{TemplateID: template.ID, ParentItemID: &root.ID, Position: 1, Title: "Tag", Tags: []string{"git"},
+			Checklist: []model.ChecklistItem{{Content: "sign"}}},
+	} {
+		if err := repo.CreateTemplateItem(ctx, item); err != nil {
+			t.Fatalf("CreateTemplateItem error = %v", err)
+		}
+	}
+	other := &model.TodoTemplate{UserID: 2, Name: "Other"}
+	if err := repo.CreateTemplate(ctx, other); err != nil {
+		t.Fatalf("CreateTemplate error = %v", err)
+	}
+
+	got, err := repo.GetTemplate(ctx, 1, template.ID)
+	if err != nil {
+		t.Fatalf("GetTemplate error = %v", err)
+	}
+	var titles []string
+	for _, item := range got.Items {
+		titles = append(titles, item.Title)
+	}
+	if got.Name != "Release" || len(titles) != 3 || titles[0] != "Release" || titles[1] != "Tag" || titles[2] != "Announce" {
+		t.Fatalf("GetTemplate = %q with items %v, want items in position order", got.Name, titles)
+	}
+	if got.Items[0].DueOffset == nil || *got.Items[0].DueOffset != 3600 || got.Items[1].ParentItemID == nil ||
+		*got.Items[1].ParentItemID != root.ID || len(got.Items[1].Checklist) != 1 || len(got.Items[1].Tags) != 1 {
+		t.Errorf("GetTemplate items do not round-trip: %+v", got.Items)
+	}
+	if _, err := repo.GetTemplate(ctx, 2, template.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTemplate for other user error = %v, want ErrNotFound", err)
+	}
+
+	templates, err := repo.ListTemplates(ctx, 1)
+	if err != nil || len(templates) != 1 || templates[0].ID != template.ID || len(templates[0].Items) != 3 {
+		t.Errorf("ListTemplates = %v, %v, want the user's template with 3 items", templates, err)
+	}
+
+	if err := repo.DeleteTemplate(ctx, 2, template.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteTemplate for other user error = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteTemplate(ctx, 1, template.ID); err != nil {
+		t.Fatalf("DeleteTemplate error = %v", err)
+	}
+	if _, err := repo.GetTemplate(ctx, 1, template.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTemplate after delete error = %v, want ErrNotFound", err)
+	}
+	if templates, _ := repo.ListTemplates(ctx, 2); len(templates) != 1 || len(templates[0].Items) != 0 {
+		t.Errorf("ListTemplates(2) = %v, other user's template should be untouched", templates)
+	}
+}
+
+func testSavedSearches(t *testing.T, repo TodoRepository) {
+	search := &model.SavedSearch{UserID: 1, Name: "Urgent", Query: "priority>=3"}
+	if err := repo.CreateSavedSearch(ctx, search); err != nil {
+		t.Fatalf("CreateSavedSearch error = %v", err)
+	}
+	second := &model.SavedSearch{UserID: 1, Name: "Today", Query: "due:today"}
+	if err := repo.CreateSavedSearch(ctx, second); err != nil {
+		t.Fatalf("CreateSavedSearch error = %v", err)
+	}
+	if err := repo.CreateSavedSearch(ctx, &model.SavedSearch{UserID: 1, Name: "Urgent", Query: "x"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateSavedSearch with duplicate name error = %v, want ErrDuplicate", err)
+	}
+	if err := repo.CreateSavedSearch(ctx, &model.SavedSearch{UserID: 2, Name: "Urgent", Query: "x"}); err != nil {
+		t.Errorf("CreateSavedSearch with the same name for other user error = %v", err)
+	}
+
+	exists := func(userID uint, name string, excludeID uint) bool {
+		t.Helper()
+		ok, err := repo.SavedSearchNameExists(ctx, userID, name, excludeID)
+		if err != nil {
+			t.Fatalf("SavedSearchNameExists error = %v", err)
+		}
+		return ok
+	}
+	if !exists(1, "Urgent", 0) || exists(1, "Urgent", search.ID) || exists(3, "Urgent", 0) {
+		t.Error("SavedSearchNameExists returned wrong results")
+	}
+
+	got, err := repo.GetSavedSearch(ctx, 1, search.ID)
+	if err != nil || got.Query != "priority>=3" {
+		t.Fatalf("GetSavedSearch = %+v, %v", got, err)
+	}
+	got.Query = "priority>=4"
+	if err := repo.SaveSavedSearch(ctx, got); err != nil {
+		t.Fatalf("SaveSavedSearch error = %v", err)
+	}
+	if saved, _ := repo.GetSavedSearch(ctx, 1, search.ID); saved.Query != "priority>=4" {
+		t.Errorf("SaveSavedSearch did not persist query: %+v", saved)
+	}
+	got.Name = "Today"
+	if err := repo.SaveSavedSearch(ctx, got); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("SaveSavedSearch with duplicate name error = %v, want ErrDuplicate", err)
+	}
+
+	searches, err := repo.ListSavedSearches(ctx, 1)
+	if err != nil || len(searches) != 2 || searches[0].ID != search.ID || searches[1].ID != second.ID {
+		t.Errorf("ListSavedSearches = %v, %v", searches, err)
+	}
+
+	if err := repo.DeleteSavedSearch(ctx, 2, search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteSavedSearch for other user error = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteSavedSearch(ctx, 1, search.ID); err != nil {
+		t.Fatalf("DeleteSavedSearch error = %v", err)
+	}
+	if _, err := repo.GetSavedSearch(ctx, 1, search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetSavedSearch after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func testArchivePolicies(t *testing.T, repo TodoRepository) {
+	if _, err := repo.GetArchivePolicy(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetArchivePolicy before create error = %v, want ErrNotFound", err)
+	}
+	for _, policy := range []*model.ArchivePolicy{
+		{UserID: 3, Enabled: true, ArchiveAfterDays: 7},
+		{UserID: 1, Enabled: true, ArchiveAfterDays: 30},
+		{UserID: 2, Enabled: false, ArchiveAfterDays: 30},
+	} {
+		if err := repo.CreateArchivePolicy(ctx, policy); err != nil {
+			t.Fatalf("CreateArchivePolicy(%d) error = %v", policy.UserID, err)
+		}
+	}
+	if err := repo.CreateArchivePolicy(ctx, &model.ArchivePolicy{UserID: 1, ArchiveAfterDays: 1}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateArchivePolicy for the same user error = %v, want ErrDuplicate", err)
+	}
+
+	policy, err := repo.GetArchivePolicy(ctx, 3)
+	if err != nil || !policy.Enabled || policy.ArchiveAfterDays != 7 || policy.LastRunAt != nil {
+		t.Fatalf("GetArchivePolicy = %+v, %v", policy, err)
+	}
+	policy.Enabled = false
+	if err := repo.SaveArchivePolicy(ctx, policy); err != nil {
+		t.Fatalf("SaveArchivePolicy error = %v", err)
+	}
+	if saved, _ := repo.GetArchivePolicy(ctx, 3); saved.Enabled {
+		t.Errorf("SaveArchivePolicy did not persist enabled: %+v", saved)
+	}
+
+	if err := repo.CreateArchivePolicy(ctx, &model.ArchivePolicy{UserID: 4, Enabled: true, ArchiveAfterDays: 1}); err != nil {
+		t.Fatalf("CreateArchivePolicy error = %v", err)
+	}
+	policies, err := repo.ListEnabledArchivePolicies(ctx)
+	if err != nil || len(policies) != 2 || policies[0].UserID != 1 || policies[1].UserID != 4 {
+		t.Errorf("ListEnabledArchivePolicies = %v, %v, want users 1 and 4", policies, err)
+	}
+
+	before, _ := repo.GetArchivePolicy(ctx, 1)
+	if err := repo.UpdateArchivePolicyLastRun(ctx, 1, *at(2)); err != nil {
+		t.Fatalf("UpdateArchivePolicyLastRun error = %v", err)
+	}
+	after, _ := repo.GetArchivePolicy(ctx, 1)
+	if after.LastRunAt == nil || !after.LastRunAt.Equal(*at(2)) {
+		t.Errorf("LastRunAt = %v, want %v", after.LastRunAt, at(2))
+	}
+	if !after.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("UpdateArchivePolicyLastRun changed UpdatedAt from %v to %v", before.UpdatedAt, after.UpdatedAt)
+	}
+}
+
+func testTimeEntries(t *testing.T, repo TodoRepository) {
+	todo := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "tracked"})
+	other := mustCreateTodo(t, repo, &model.Todo{UserID: 1, Title: "other"})
+
+	if _, err := repo.FindRunningTimer(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("FindRunningTimer without timer error = %v, want ErrNotFound", err)
+	}
+	create := func(entry *model.TimeEntry) *model.TimeEntry {
+		t.Helper()
+		if err := repo.CreateTimeEntry(ctx, entry); err != nil {
+			t.Fatalf("CreateTimeEntry error = %v", err)
+		}
+		return entry
+	}
+	finished := func(todoID uint, start, end int) *model.TimeEntry {
+		return create(&model.TimeEntry{UserID: 1, TodoID: todoID, StartedAt: *at(start), EndedAt: at(end),
+			DurationSeconds: int64(end-start) * 3600})
+	}
+	early := finished(todo.ID, 0, 1)
+	late := finished(todo.ID, 4, 6)
+	otherEntry := finished(other.ID, 2, 3)
+	uid := uint(1)
+	running := create(&model.TimeEntry{UserID: 1, TodoID: todo.ID, StartedAt: *at(8), RunningUserID: &uid})
+	create(&model.TimeEntry{UserID: 2, TodoID: 99, StartedAt: *at(8), RunningUserID: uintPtr(2)})
+
+	if err := repo.CreateTimeEntry(ctx, &model.TimeEntry{UserID: 1, TodoID: other.ID, StartedAt: *at(9), RunningUserID: &uid}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateTimeEntry with a second running timer error = %v, want ErrDuplicate", err)
+	}
+	found, err := repo.FindRunningTimer(ctx, 1)
+	if err != nil || found.ID != running.ID || found.EndedAt != nil {
+		t.Errorf("FindRunningTimer = %+v, %v, want entry %d", found, err, running.ID)
+	}
+
+	list := func(filter TimeEntryFilter) []uint {
+		t.Helper()
+		entries, err := repo.ListTimeEntries(ctx, 1, filter)
+		if err != nil {
+			t.Fatalf("ListTimeEntries error = %v", err)
+		}
+		ids := make([]uint, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.ID
+		}
+		return ids
+	}
+	tests := []struct {
+		name   string
+		filter TimeEntryFilter
+		want   []uint
+	}{
+		{"all", TimeEntryFilter{}, []uint{early.ID, otherEntry.ID, late.ID, running.ID}},
+		{"todo", TimeEntryFilter{TodoID: todo.ID}, []uint{early.ID, late.ID, running.ID}},
+		// 结束于 From 的记录不计入，正在运行的记录总是计入
+		{"from", TimeEntryFilter{From: at(3)}, []uint{late.ID, running.ID}},
+		// 开始于 To 的记录不计入
+		{"to", TimeEntryFilter{To: at(4)}, []uint{early.ID, otherEntry.ID}},
+		{"range", TimeEntryFilter{TodoID: todo.ID, From: at(1), To: at(9)}, []uint{late.ID, running.ID}},
+	}
+	for _, tt := range tests {
+		if got := list(tt.filter); !equalIDs(got, tt.want) {
+			t.Errorf("%s: ListTimeEntries = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	refresh := func(seq uint64) *model.Todo {
+		t.Helper()
+		if err := repo.RefreshTrackedSeconds(ctx, todo.ID, seq); err != nil {
+			t.Fatalf("RefreshTrackedSeconds error = %v", err)
+		}
+		refreshed, err := repo.GetTodo(ctx, 1, todo.ID)
+		if err != nil {
+			t.Fatalf("GetTodo error = %v", err)
+		}
+		return refreshed
+	}
+	before, _ := repo.GetTodo(ctx, 1, todo.ID)
+	refreshed := refresh(5)
+	if refreshed.TrackedSeconds != 3*3600 || refreshed.ChangeSeq != 5 {
+		t.Errorf("after refresh TrackedSeconds = %d, ChangeSeq = %d, want %d, 5", refreshed.TrackedSeconds, refreshed.ChangeSeq, 3*3600)
+	}
+	if !refreshed.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("RefreshTrackedSeconds changed UpdatedAt from %v to %v", before.UpdatedAt, refreshed.UpdatedAt)
+	}
+
+	// 停止计时器后可以启动新的计时器
+	running.EndedAt = at(9)
+	running.DurationSeconds = 3600
+	running.RunningUserID = nil
+	if err := repo.SaveTimeEntry(ctx, running); err != nil {
+		t.Fatalf("SaveTimeEntry error = %v", err)
+	}
+	if _, err := repo.FindRunningTimer(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindRunningTimer after stop error = %v, want ErrNotFound", err)
+	}
+	create(&model.TimeEntry{UserID: 1, TodoID: other.ID, StartedAt: *at(10), RunningUserID: &uid})
+	if refreshed := refresh(6); refreshed.TrackedSeconds != 4*3600 {
+		t.Errorf("TrackedSeconds after stop = %d, want %d", refreshed.TrackedSeconds, 4*3600)
+	}
+
+	got, err := repo.GetTimeEntry(ctx, 1, late.ID)
+	if err != nil || !got.StartedAt.Equal(*at(4)) || got.EndedAt == nil || !got.EndedAt.Equal(*at(6)) {
+		t.Errorf("GetTimeEntry = %+v, %v", got, err)
+	}
+	if _, err := repo.GetTimeEntry(ctx, 2, late.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTimeEntry for other user error = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteTimeEntry(ctx, got); err != nil {
+		t.Fatalf("DeleteTimeEntry error = %v", err)
+	}
+	if _, err := repo.GetTimeEntry(ctx, 1, late.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTimeEntry after delete error = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteTimeEntriesOfTodo(ctx, todo.ID); err != nil {
+		t.Fatalf("DeleteTimeEntriesOfTodo error = %v", err)
+	}
+	if got := list(TimeEntryFilter{}); len(got) != 2 {
+		t.Errorf("ListTimeEntries after DeleteTimeEntriesOfTodo = %v, want only the other todo's entries", got)
+	}
+}
+
+func testBatchOperationLog(t *testing.T, repo TodoRepository) {
+	entry := &model.BatchOperationLog{UserID: 1, OperationType: "MARK_AS_COMPLETED", AffectedTodoIDs: "[1,2]", Status: "SUCCESS"}
+	if err := repo.CreateBatchOperationLog(ctx, entry); err != nil {
+		t.Fatalf("CreateBatchOperationLog error = %v", err)
+	}
+	if entry.ID == 0 || entry.CreatedAt.IsZero() {
+		t.Errorf("CreateBatchOperationLog did not fill ID and CreatedAt: %+v", entry)
+	}
+}
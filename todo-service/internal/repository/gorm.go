@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormRepository 基于 GORM 的实现，MySQL、PostgreSQL 和 SQLite 共用
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGorm 创建基于 GORM 的 TodoRepository。
+// 为了把唯一约束冲突识别为 ErrDuplicate，会开启 db 的 TranslateError
+func NewGorm(db *gorm.DB) TodoRepository {
+	db.Config.TranslateError = true
+	return &gormRepository{db: db}
+}
+
+// translate 将 GORM 的错误转换为本包定义的错误
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *gormRepository) Transaction(ctx context.Context, fn func(repo TodoRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormRepository{db: tx})
+	})
+}
+
+// first 查找第一条满足条件的记录
+func (r *gormRepository) first(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return translate(r.db.WithContext(ctx).Where(query, args...).First(dest).Error)
+}
+
+func (r *gormRepository) create(ctx context.Context, value interface{}) error {
+	return translate(r.db.WithContext(ctx).Create(value).Error)
+}
+
+// save 写回记录的全部字段。不使用 db.Save，避免记录不存在时被重新插入
+func (r *gormRepository) save(ctx context.Context, value interface{}) error {
+	return translate(r.db.WithContext(ctx).Model(value).Select("*").Updates(value).Error)
+}
+
+func (r *gormRepository) CreateTodo(ctx context.Context, todo *model.Todo) error {
+	return r.create(ctx, todo)
+}
+
+func (r *gormRepository) GetTodo(ctx context.Context, userID, todoID uint) (*model.Todo, error) {
+	var todo model.Todo
+	if err := r.first(ctx, &todo, "id = ? AND user_id = ?", todoID, userID); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *gormRepository) GetTodoByClientID(ctx context.Context, userID uint, clientID string) (*model.Todo, error) {
+	var todo model.Todo
+	if err := r.first(ctx, &todo, "user_id = ? AND client_id = ?", userID, clientID); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *gormRepository) ListTodos(ctx context.Context, userID uint, filter TodoFilter) ([]*model.Todo, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.IDs != nil {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.ParentIDs != nil {
+		query = query.Where("parent_id IN ?", filter.ParentIDs)
+	}
+	if filter.ExcludeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	var todos []*model.Todo
+	if err := query.Order("id").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *gormRepository) SaveTodo(ctx context.Context, todo *model.Todo) error {
+	return r.save(ctx, todo)
+}
+
+func (r *gormRepository) DeleteTodo(ctx context.Context, todo *model.Todo) error {
+	return r.db.WithContext(ctx).Delete(&model.Todo{}, todo.ID).Error
+}
+
+// NextChangeSeq 通过 UPDATE 锁住用户的计数器行直到事务结束，因此同一用户的序号按提交顺序递增
+func (r *gormRepository) NextChangeSeq(ctx context.Context, userID uint) (uint64, error) {
+	tx := r.db.WithContext(ctx)
+	for {
+		result := tx.Model(&model.ChangeCounter{}).Where("user_id = ?", userID).
+			UpdateColumn("seq", gorm.Expr("seq + 1"))
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected > 0 {
+			var seq uint64
+			err := tx.Model(&model.ChangeCounter{}).Where("user_id = ?", userID).Pluck("seq", &seq).Error
+			return seq, err
+		}
+		// 用户的第一个变更。并发插入时只有一个成功，其余的回到 UPDATE 等待其提交
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ChangeCounter{UserID: userID, Seq: 1})
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return 1, nil
+		}
+	}
+}
+
+func (r *gormRepository) CurrentChangeSeq(ctx context.Context, userID uint) (uint64, error) {
+	var seqs []uint64
+	if err := r.db.WithContext(ctx).Model(&model.ChangeCounter{}).Where("user_id = ?", userID).Pluck("seq", &seqs).Error; err != nil {
+		return 0, err
+	}
+	if len(seqs) == 0 {
+		return 0, nil
+	}
+	return seqs[0], nil
+}
+
+func (r *gormRepository) ListTodoChanges(ctx context.Context, userID uint, after, upTo uint64) ([]*model.Todo, error) {
+	var todos []*model.Todo
+	err := r.db.WithContext(ctx).Where("user_id = ? AND change_seq > ? AND change_seq <= ?", userID, after, upTo).
+		Order("change_seq, id").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *gormRepository) CreateTombstone(ctx context.Context, tombstone *model.TodoTombstone) error {
+	return r.create(ctx, tombstone)
+}
+
+func (r *gormRepository) GetTombstone(ctx context.Context, userID, todoID uint) (*model.TodoTombstone, error) {
+	var tombstone model.TodoTombstone
+	if err := r.first(ctx, &tombstone, "todo_id = ? AND user_id = ?", todoID, userID); err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
+
+func (r *gormRepository) GetTombstoneByClientID(ctx context.Context, userID uint, clientID string) (*model.TodoTombstone, error) {
+	var tombstone model.TodoTombstone
+	if err := r.first(ctx, &tombstone, "user_id = ? AND client_id = ?", userID, clientID); err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
+
+func (r *gormRepository) ListTombstones(ctx context.Context, userID uint, after, upTo uint64) ([]*model.TodoTombstone, error) {
+	var tombstones []*model.TodoTombstone
+	err := r.db.WithContext(ctx).Where("user_id = ? AND change_seq > ? AND change_seq <= ?", userID, after, upTo).
+		Order("change_seq, todo_id").Find(&tombstones).Error
+	if err != nil {
+		return nil, err
+	}
+	return tombstones, nil
+}
+
+func (r *gormRepository) CreateBatchOperationLog(ctx context.Context, entry *model.BatchOperationLog) error {
+	return r.create(ctx, entry)
+}
+
+func (r *gormRepository) CreateTemplate(ctx context.Context, template *model.TodoTemplate) error {
+	return translate(r.db.WithContext(ctx).Omit("Items").Create(template).Error)
+}
+
+func (r *gormRepository) CreateTemplateItem(ctx context.Context, item *model.TemplateItem) error {
+	return r.create(ctx, item)
+}
+
+// orderTemplateItems 保证预加载的模板条目按先序遍历顺序排列
+func orderTemplateItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func (r *gormRepository) ListTemplates(ctx context.Context, userID uint) ([]*model.TodoTemplate, error) {
+	var templates []*model.TodoTemplate
+	err := r.db.WithContext(ctx).Preload("Items", orderTemplateItems).Where("user_id = ?", userID).Order("id").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *gormRepository) GetTemplate(ctx context.Context, userID, templateID uint) (*model.TodoTemplate, error) {
+	var template model.TodoTemplate
+	err := r.db.WithContext(ctx).Preload("Items", orderTemplateItems).
+		Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &template, nil
+}
+
+func (r *gormRepository) DeleteTemplate(ctx context.Context, userID, templateID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var template model.TodoTemplate
+		if err := tx.Select("id").Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+			return translate(err)
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&model.TemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&template).Error
+	})
+}
+
+func (r *gormRepository) CreateSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	return r.create(ctx, search)
+}
+
+func (r *gormRepository) ListSavedSearches(ctx context.Context, userID uint) ([]*model.SavedSearch, error) {
+	var searches []*model.SavedSearch
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (r *gormRepository) GetSavedSearch(ctx context.Context, userID, searchID uint) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	if err := r.first(ctx, &search, "id = ? AND user_id = ?", searchID, userID); err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (r *gormRepository) SaveSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	return r.save(ctx, search)
+}
+
+func (r *gormRepository) DeleteSavedSearch(ctx context.Context, userID, searchID uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", searchID, userID).Delete(&model.SavedSearch{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormRepository) SavedSearchNameExists(ctx context.Context, userID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SavedSearch{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormRepository) GetArchivePolicy(ctx context.Context, userID uint) (*model.ArchivePolicy, error) {
+	var policy model.ArchivePolicy
+	if err := r.first(ctx, &policy, "user_id = ?", userID); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *gormRepository) CreateArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error {
+	return r.create(ctx, policy)
+}
+
+func (r *gormRepository) SaveArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error {
+	return r.save(ctx, policy)
+}
+
+func (r *gormRepository) ListEnabledArchivePolicies(ctx context.Context) ([]*model.ArchivePolicy, error) {
+	var policies []*model.ArchivePolicy
+	if err := r.db.WithContext(ctx).Where("enabled = ?", true).Order("user_id").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *gormRepository) UpdateArchivePolicyLastRun(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.ArchivePolicy{}).Where("user_id = ?", userID).
+		UpdateColumn("last_run_at", at).Error
+}
+
+func (r *gormRepository) CreateTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	return r.create(ctx, entry)
+}
+
+func (r *gormRepository) GetTimeEntry(ctx context.Context, userID, entryID uint) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	if err := r.first(ctx, &entry, "id = ? AND user_id = ?", entryID, userID); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *gormRepository) FindRunningTimer(ctx context.Context, userID uint) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	if err := r.first(ctx, &entry, "running_user_id = ?", userID); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *gormRepository) SaveTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	return r.save(ctx, entry)
+}
+
+func (r *gormRepository) DeleteTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	return r.db.WithContext(ctx).Delete(&model.TimeEntry{}, entry.ID).Error
+}
+
+func (r *gormRepository) DeleteTimeEntriesOfTodo(ctx context.Context, todoID uint) error {
+	return r.db.WithContext(ctx).Where("todo_id = ?", todoID).Delete(&model.TimeEntry{}).Error
+}
+
+func (r *gormRepository) ListTimeEntries(ctx context.Context, userID uint, filter TimeEntryFilter) ([]*model.TimeEntry, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.TodoID != 0 {
+		query = query.Where("todo_id = ?", filter.TodoID)
+	}
+	if filter.From != nil {
+		query = query.Where("ended_at IS NULL OR ended_at > ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_at < ?", *filter.To)
+	}
+	var entries []*model.TimeEntry
+	if err := query.Order("started_at, id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *gormRepository) RefreshTrackedSeconds(ctx context.Context, todoID uint, changeSeq uint64) error {
+	tx := r.db.WithContext(ctx)
+	sum := tx.Model(&model.TimeEntry{}).Select("COALESCE(SUM(duration_seconds), 0)").
+		Where("todo_id = ? AND ended_at IS NOT NULL", todoID)
+	return tx.Model(&model.Todo{}).Where("id = ?", todoID).
+		UpdateColumns(map[string]interface{}{"tracked_seconds": sum, "change_seq": changeSeq}).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"todo-project/todo-service/internal/migrate"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/migrations"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	n := 0
+	runConformance(t, func(t *testing.T) TodoRepository {
+		// 每个用例使用独立的内存数据库，单连接保证所有操作落在同一个库上
+		n++
+		db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:conformance%d?mode=memory", n)), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+		migrateUp(t, db)
+		return NewGorm(db)
+	})
+}
+
+// TestMySQLRepository 需要一个可以随意清空的 MySQL 数据库，通过 TODO_TEST_MYSQL_DSN 指定，例如
+// root:@tcp(127.0.0.1:3306)/todo_test?charset=utf8mb4&parseTime=True&loc=Local
+func TestMySQLRepository(t *testing.T) {
+	dsn := os.Getenv("TODO_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TODO_TEST_MYSQL_DSN，跳过 MySQL 一致性测试")
+	}
+	runServerConformance(t, mysql.Open(dsn))
+}
+
+// TestPostgresRepository 与 TestMySQLRepository 相同，数据库通过 TODO_TEST_POSTGRES_DSN 指定，例如
+// postgres://postgres:@127.0.0.1:5432/todo_test?sslmode=disable
+func TestPostgresRepository(t *testing.T) {
+	dsn := os.Getenv("TODO_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TODO_TEST_POSTGRES_DSN，跳过 PostgreSQL 一致性测试")
+	}
+	runServerConformance(t, postgres.Open(dsn))
+}
+
+// runServerConformance 针对数据库服务器运行一致性测试，每个用例开始前删除并重建全部表
+func runServerConformance(t *testing.T, dialector gorm.Dialector) {
+	runConformance(t, func(t *testing.T) TodoRepository {
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { sqlDB.Close() })
+		if err := db.Migrator().DropTable(&model.Todo{}, &model.BatchOperationLog{}, &model.TemplateItem{}, &model.TodoTemplate{},
+			&model.SavedSearch{}, &model.ArchivePolicy{}, &model.TimeEntry{}, &model.ChangeCounter{}, &model.TodoTombstone{},
+			"schema_migrations"); err != nil {
+			t.Fatal(err)
+		}
+		migrateUp(t, db)
+		return NewGorm(db)
+	})
+}
+
+// migrateUp 使用服务实际执行的迁移建表
+func migrateUp(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrate.New(sqlDB, db.Dialector.Name(), "todo-service", migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
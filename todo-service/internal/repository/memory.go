@@ -0,0 +1,664 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+// memoryState 内存实现的全部数据。map 中保存的对象写入后不再修改 (修改时整体替换)，
+// 因此事务开始时浅拷贝各个 map 即可得到一份快照
+type memoryState struct {
+	todos         map[uint]*model.Todo
+	counters      map[uint]uint64
+	tombstones    map[uint]*model.TodoTombstone
+	logs          map[uint]*model.BatchOperationLog
+	templates     map[uint]*model.TodoTemplate // 不含条目
+	templateItems map[uint]*model.TemplateItem
+	savedSearches map[uint]*model.SavedSearch
+	policies      map[uint]*model.ArchivePolicy
+	timeEntries   map[uint]*model.TimeEntry
+	lastIDs       map[string]uint // 各表最近分配的自增 ID
+}
+
+func (s *memoryState) clone() *memoryState {
+	return &memoryState{
+		todos:         cloneMap(s.todos),
+		counters:      cloneMap(s.counters),
+		tombstones:    cloneMap(s.tombstones),
+		logs:          cloneMap(s.logs),
+		templates:     cloneMap(s.templates),
+		templateItems: cloneMap(s.templateItems),
+		savedSearches: cloneMap(s.savedSearches),
+		policies:      cloneMap(s.policies),
+		timeEntries:   cloneMap(s.timeEntries),
+		lastIDs:       cloneMap(s.lastIDs),
+	}
+}
+
+// nextID 为表分配自增 ID，id 非 0 时沿用调用方指定的 ID
+func (s *memoryState) nextID(table string, id uint) uint {
+	if id == 0 {
+		id = s.lastIDs[table] + 1
+	}
+	if id > s.lastIDs[table] {
+		s.lastIDs[table] = id
+	}
+	return id
+}
+
+// memoryRepository 内存实现，数据不持久化，用于本地开发和测试。
+// 所有操作由一把互斥锁串行执行，事务在持有锁的期间修改快照，成功后再替换原数据
+type memoryRepository struct {
+	mu    *sync.Mutex // 事务内为 nil，锁已由外层持有
+	state *memoryState
+}
+
+// NewMemory 创建内存实现的 TodoRepository
+func NewMemory() TodoRepository {
+	return &memoryRepository{
+		mu: &sync.Mutex{},
+		state: &memoryState{
+			todos:         make(map[uint]*model.Todo),
+			counters:      make(map[uint]uint64),
+			tombstones:    make(map[uint]*model.TodoTombstone),
+			logs:          make(map[uint]*model.BatchOperationLog),
+			templates:     make(map[uint]*model.TodoTemplate),
+			templateItems: make(map[uint]*model.TemplateItem),
+			savedSearches: make(map[uint]*model.SavedSearch),
+			policies:      make(map[uint]*model.ArchivePolicy),
+			timeEntries:   make(map[uint]*model.TimeEntry),
+			lastIDs:       make(map[string]uint),
+		},
+	}
+}
+
+// lock 加锁并返回解锁函数，事务内不重复加锁
+func (r *memoryRepository) lock() func() {
+	if r.mu == nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+func (r *memoryRepository) Transaction(ctx context.Context, fn func(repo TodoRepository) error) error {
+	defer r.lock()()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	snapshot := r.state.clone()
+	if err := fn(&memoryRepository{state: snapshot}); err != nil {
+		return err
+	}
+	*r.state = *snapshot
+	return nil
+}
+
+func (r *memoryRepository) CreateTodo(ctx context.Context, todo *model.Todo) error {
+	defer r.lock()()
+	if todo.ClientID != nil && r.findTodo(todo.UserID, func(t *model.Todo) bool { return t.ClientID != nil && *t.ClientID == *todo.ClientID }) != nil {
+		return ErrDuplicate
+	}
+	if _, ok := r.state.todos[todo.ID]; ok && todo.ID != 0 {
+		return ErrDuplicate
+	}
+	todo.ID = r.state.nextID("todos", todo.ID)
+	setTimestamps(&todo.CreatedAt, &todo.UpdatedAt)
+	r.state.todos[todo.ID] = cloneTodo(todo)
+	return nil
+}
+
+// findTodo 返回用户第一个满足条件的 Todo (按 ID)，没有时返回 nil
+func (r *memoryRepository) findTodo(userID uint, match func(*model.Todo) bool) *model.Todo {
+	var found *model.Todo
+	for _, todo := range r.state.todos {
+		if todo.UserID == userID && match(todo) && (found == nil || todo.ID < found.ID) {
+			found = todo
+		}
+	}
+	return found
+}
+
+func (r *memoryRepository) GetTodo(ctx context.Context, userID, todoID uint) (*model.Todo, error) {
+	defer r.lock()()
+	todo, ok := r.state.todos[todoID]
+	if !ok || todo.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return cloneTodo(todo), nil
+}
+
+func (r *memoryRepository) GetTodoByClientID(ctx context.Context, userID uint, clientID string) (*model.Todo, error) {
+	defer r.lock()()
+	todo := r.findTodo(userID, func(t *model.Todo) bool { return t.ClientID != nil && *t.ClientID == clientID })
+	if todo == nil {
+		return nil, ErrNotFound
+	}
+	return cloneTodo(todo), nil
+}
+
+func (r *memoryRepository) ListTodos(ctx context.Context, userID uint, filter TodoFilter) ([]*model.Todo, error) {
+	defer r.lock()()
+	ids := toSet(filter.IDs)
+	parentIDs := toSet(filter.ParentIDs)
+	todos := make([]*model.Todo, 0)
+	for _, todo := range r.state.todos {
+		if todo.UserID != userID ||
+			(ids != nil && !ids[todo.ID]) ||
+			(parentIDs != nil && (todo.ParentID == nil || !parentIDs[*todo.ParentID])) ||
+			(filter.ExcludeArchived && todo.ArchivedAt != nil) {
+			continue
+		}
+		todos = append(todos, cloneTodo(todo))
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	return todos, nil
+}
+
+func (r *memoryRepository) SaveTodo(ctx context.Context, todo *model.Todo) error {
+	defer r.lock()()
+	if _, ok := r.state.todos[todo.ID]; !ok {
+		return ErrNotFound
+	}
+	if todo.ClientID != nil && r.findTodo(todo.UserID, func(t *model.Todo) bool {
+		return t.ID != todo.ID && t.ClientID != nil && *t.ClientID == *todo.ClientID
+	}) != nil {
+		return ErrDuplicate
+	}
+	todo.UpdatedAt = time.Now()
+	r.state.todos[todo.ID] = cloneTodo(todo)
+	return nil
+}
+
+func (r *memoryRepository) DeleteTodo(ctx context.Context, todo *model.Todo) error {
+	defer r.lock()()
+	delete(r.state.todos, todo.ID)
+	return nil
+}
+
+// NextChangeSeq 内存实现的所有事务都持有同一把锁，天然满足串行要求
+func (r *memoryRepository) NextChangeSeq(ctx context.Context, userID uint) (uint64, error) {
+	defer r.lock()()
+	r.state.counters[userID]++
+	return r.state.counters[userID], nil
+}
+
+func (r *memoryRepository) CurrentChangeSeq(ctx context.Context, userID uint) (uint64, error) {
+	defer r.lock()()
+	return r.state.counters[userID], nil
+}
+
+func (r *memoryRepository) ListTodoChanges(ctx context.Context, userID uint, after, upTo uint64) ([]*model.Todo, error) {
+	defer r.lock()()
+	todos := make([]*model.Todo, 0)
+	for _, todo := range r.state.todos {
+		if todo.UserID == userID && todo.ChangeSeq > after && todo.ChangeSeq <= upTo {
+			todos = append(todos, cloneTodo(todo))
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		if todos[i].ChangeSeq != todos[j].ChangeSeq {
+			return todos[i].ChangeSeq < todos[j].ChangeSeq
+		}
+		return todos[i].ID < todos[j].ID
+	})
+	return todos, nil
+}
+
+func (r *memoryRepository) CreateTombstone(ctx context.Context, tombstone *model.TodoTombstone) error {
+	defer r.lock()()
+	if _, ok := r.state.tombstones[tombstone.TodoID]; ok {
+		return ErrDuplicate
+	}
+	if tombstone.CreatedAt.IsZero() {
+		tombstone.CreatedAt = time.Now()
+	}
+	stored := *tombstone
+	stored.ClientID = clonePtr(tombstone.ClientID)
+	r.state.tombstones[tombstone.TodoID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetTombstone(ctx context.Context, userID, todoID uint) (*model.TodoTombstone, error) {
+	defer r.lock()()
+	tombstone, ok := r.state.tombstones[todoID]
+	if !ok || tombstone.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return cloneTombstone(tombstone), nil
+}
+
+func (r *memoryRepository) GetTombstoneByClientID(ctx context.Context, userID uint, clientID string) (*model.TodoTombstone, error) {
+	defer r.lock()()
+	var found *model.TodoTombstone
+	for _, tombstone := range r.state.tombstones {
+		if tombstone.UserID == userID && tombstone.ClientID != nil && *tombstone.ClientID == clientID &&
+			(found == nil || tombstone.TodoID < found.TodoID) {
+			found = tombstone
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return cloneTombstone(found), nil
+}
+
+func (r *memoryRepository) ListTombstones(ctx context.Context, userID uint, after, upTo uint64) ([]*model.TodoTombstone, error) {
+	defer r.lock()()
+	tombstones := make([]*model.TodoTombstone, 0)
+	for _, tombstone := range r.state.tombstones {
+		if tombstone.UserID == userID && tombstone.ChangeSeq > after && tombstone.ChangeSeq <= upTo {
+			tombstones = append(tombstones, cloneTombstone(tombstone))
+		}
+	}
+	sort.Slice(tombstones, func(i, j int) bool {
+		if tombstones[i].ChangeSeq != tombstones[j].ChangeSeq {
+			return tombstones[i].ChangeSeq < tombstones[j].ChangeSeq
+		}
+		return tombstones[i].TodoID < tombstones[j].TodoID
+	})
+	return tombstones, nil
+}
+
+func (r *memoryRepository) CreateBatchOperationLog(ctx context.Context, entry *model.BatchOperationLog) error {
+	defer r.lock()()
+	entry.ID = r.state.nextID("batch_operation_logs", entry.ID)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	stored := *entry
+	r.state.logs[entry.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) CreateTemplate(ctx context.Context, template *model.TodoTemplate) error {
+	defer r.lock()()
+	template.ID = r.state.nextID("todo_templates", template.ID)
+	setTimestamps(&template.CreatedAt, &template.UpdatedAt)
+	stored := *template
+	stored.Items = nil
+	r.state.templates[template.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) CreateTemplateItem(ctx context.Context, item *model.TemplateItem) error {
+	defer r.lock()()
+	item.ID = r.state.nextID("template_items", item.ID)
+	r.state.templateItems[item.ID] = cloneTemplateItem(item)
+	return nil
+}
+
+// loadTemplate 复制模板并附上按 Position 排列的条目
+func (r *memoryRepository) loadTemplate(template *model.TodoTemplate) *model.TodoTemplate {
+	loaded := *template
+	loaded.Items = make([]model.TemplateItem, 0)
+	for _, item := range r.state.templateItems {
+		if item.TemplateID == template.ID {
+			loaded.Items = append(loaded.Items, *cloneTemplateItem(item))
+		}
+	}
+	sort.Slice(loaded.Items, func(i, j int) bool {
+		if loaded.Items[i].Position != loaded.Items[j].Position {
+			return loaded.Items[i].Position < loaded.Items[j].Position
+		}
+		return loaded.Items[i].ID < loaded.Items[j].ID
+	})
+	return &loaded
+}
+
+func (r *memoryRepository) ListTemplates(ctx context.Context, userID uint) ([]*model.TodoTemplate, error) {
+	defer r.lock()()
+	templates := make([]*model.TodoTemplate, 0)
+	for _, template := range r.state.templates {
+		if template.UserID == userID {
+			templates = append(templates, r.loadTemplate(template))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates, nil
+}
+
+func (r *memoryRepository) GetTemplate(ctx context.Context, userID, templateID uint) (*model.TodoTemplate, error) {
+	defer r.lock()()
+	template, ok := r.state.templates[templateID]
+	if !ok || template.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return r.loadTemplate(template), nil
+}
+
+func (r *memoryRepository) DeleteTemplate(ctx context.Context, userID, templateID uint) error {
+	defer r.lock()()
+	template, ok := r.state.templates[templateID]
+	if !ok || template.UserID != userID {
+		return ErrNotFound
+	}
+	for id, item := range r.state.templateItems {
+		if item.TemplateID == templateID {
+			delete(r.state.templateItems, id)
+		}
+	}
+	delete(r.state.templates, templateID)
+	return nil
+}
+
+// savedSearchNameTaken 检查唯一索引 (user_id, name)
+func (r *memoryRepository) savedSearchNameTaken(userID uint, name string, excludeID uint) bool {
+	for _, search := range r.state.savedSearches {
+		if search.UserID == userID && search.Name == name && search.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepository) CreateSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	defer r.lock()()
+	if r.savedSearchNameTaken(search.UserID, search.Name, 0) {
+		return ErrDuplicate
+	}
+	search.ID = r.state.nextID("saved_searches", search.ID)
+	setTimestamps(&search.CreatedAt, &search.UpdatedAt)
+	stored := *search
+	r.state.savedSearches[search.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) ListSavedSearches(ctx context.Context, userID uint) ([]*model.SavedSearch, error) {
+	defer r.lock()()
+	searches := make([]*model.SavedSearch, 0)
+	for _, search := range r.state.savedSearches {
+		if search.UserID == userID {
+			copied := *search
+			searches = append(searches, &copied)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].ID < searches[j].ID })
+	return searches, nil
+}
+
+func (r *memoryRepository) GetSavedSearch(ctx context.Context, userID, searchID uint) (*model.SavedSearch, error) {
+	defer r.lock()()
+	search, ok := r.state.savedSearches[searchID]
+	if !ok || search.UserID != userID {
+		return nil, ErrNotFound
+	}
+	copied := *search
+	return &copied, nil
+}
+
+func (r *memoryRepository) SaveSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	defer r.lock()()
+	if _, ok := r.state.savedSearches[search.ID]; !ok {
+		return ErrNotFound
+	}
+	if r.savedSearchNameTaken(search.UserID, search.Name, search.ID) {
+		return ErrDuplicate
+	}
+	search.UpdatedAt = time.Now()
+	stored := *search
+	r.state.savedSearches[search.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) DeleteSavedSearch(ctx context.Context, userID, searchID uint) error {
+	defer r.lock()()
+	search, ok := r.state.savedSearches[searchID]
+	if !ok || search.UserID != userID {
+		return ErrNotFound
+	}
+	delete(r.state.savedSearches, searchID)
+	return nil
+}
+
+func (r *memoryRepository) SavedSearchNameExists(ctx context.Context, userID uint, name string, excludeID uint) (bool, error) {
+	defer r.lock()()
+	return r.savedSearchNameTaken(userID, name, excludeID), nil
+}
+
+func (r *memoryRepository) GetArchivePolicy(ctx context.Context, userID uint) (*model.ArchivePolicy, error) {
+	defer r.lock()()
+	policy, ok := r.state.policies[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneArchivePolicy(policy), nil
+}
+
+func (r *memoryRepository) CreateArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error {
+	defer r.lock()()
+	if _, ok := r.state.policies[policy.UserID]; ok {
+		return ErrDuplicate
+	}
+	setTimestamps(&policy.CreatedAt, &policy.UpdatedAt)
+	r.state.policies[policy.UserID] = cloneArchivePolicy(policy)
+	return nil
+}
+
+func (r *memoryRepository) SaveArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error {
+	defer r.lock()()
+	if _, ok := r.state.policies[policy.UserID]; !ok {
+		return ErrNotFound
+	}
+	policy.UpdatedAt = time.Now()
+	r.state.policies[policy.UserID] = cloneArchivePolicy(policy)
+	return nil
+}
+
+func (r *memoryRepository) ListEnabledArchivePolicies(ctx context.Context) ([]*model.ArchivePolicy, error) {
+	defer r.lock()()
+	policies := make([]*model.ArchivePolicy, 0)
+	for _, policy := range r.state.policies {
+		if policy.Enabled {
+			policies = append(policies, cloneArchivePolicy(policy))
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].UserID < policies[j].UserID })
+	return policies, nil
+}
+
+func (r *memoryRepository) UpdateArchivePolicyLastRun(ctx context.Context, userID uint, at time.Time) error {
+	defer r.lock()()
+	policy, ok := r.state.policies[userID]
+	if !ok {
+		return nil
+	}
+	updated := cloneArchivePolicy(policy)
+	updated.LastRunAt = &at
+	r.state.policies[userID] = updated
+	return nil
+}
+
+// runningTimerTaken 检查唯一索引 running_user_id
+func (r *memoryRepository) runningTimerTaken(entry *model.TimeEntry) bool {
+	if entry.RunningUserID == nil {
+		return false
+	}
+	for _, existing := range r.state.timeEntries {
+		if existing.ID != entry.ID && existing.RunningUserID != nil && *existing.RunningUserID == *entry.RunningUserID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepository) CreateTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	defer r.lock()()
+	if r.runningTimerTaken(entry) {
+		return ErrDuplicate
+	}
+	entry.ID = r.state.nextID("time_entries", entry.ID)
+	setTimestamps(&entry.CreatedAt, &entry.UpdatedAt)
+	r.state.timeEntries[entry.ID] = cloneTimeEntry(entry)
+	return nil
+}
+
+func (r *memoryRepository) GetTimeEntry(ctx context.Context, userID, entryID uint) (*model.TimeEntry, error) {
+	defer r.lock()()
+	entry, ok := r.state.timeEntries[entryID]
+	if !ok || entry.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return cloneTimeEntry(entry), nil
+}
+
+func (r *memoryRepository) FindRunningTimer(ctx context.Context, userID uint) (*model.TimeEntry, error) {
+	defer r.lock()()
+	for _, entry := range r.state.timeEntries {
+		if entry.RunningUserID != nil && *entry.RunningUserID == userID {
+			return cloneTimeEntry(entry), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRepository) SaveTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	defer r.lock()()
+	if _, ok := r.state.timeEntries[entry.ID]; !ok {
+		return ErrNotFound
+	}
+	if r.runningTimerTaken(entry) {
+		return ErrDuplicate
+	}
+	entry.UpdatedAt = time.Now()
+	r.state.timeEntries[entry.ID] = cloneTimeEntry(entry)
+	return nil
+}
+
+func (r *memoryRepository) DeleteTimeEntry(ctx context.Context, entry *model.TimeEntry) error {
+	defer r.lock()()
+	delete(r.state.timeEntries, entry.ID)
+	return nil
+}
+
+func (r *memoryRepository) DeleteTimeEntriesOfTodo(ctx context.Context, todoID uint) error {
+	defer r.lock()()
+	for id, entry := range r.state.timeEntries {
+		if entry.TodoID == todoID {
+			delete(r.state.timeEntries, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListTimeEntries(ctx context.Context, userID uint, filter TimeEntryFilter) ([]*model.TimeEntry, error) {
+	defer r.lock()()
+	entries := make([]*model.TimeEntry, 0)
+	for _, entry := range r.state.timeEntries {
+		if entry.UserID != userID ||
+			(filter.TodoID != 0 && entry.TodoID != filter.TodoID) ||
+			(filter.From != nil && entry.EndedAt != nil && !entry.EndedAt.After(*filter.From)) ||
+			(filter.To != nil && !entry.StartedAt.Before(*filter.To)) {
+			continue
+		}
+		entries = append(entries, cloneTimeEntry(entry))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].StartedAt.Equal(entries[j].StartedAt) {
+			return entries[i].StartedAt.Before(entries[j].StartedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+func (r *memoryRepository) RefreshTrackedSeconds(ctx context.Context, todoID uint, changeSeq uint64) error {
+	defer r.lock()()
+	todo, ok := r.state.todos[todoID]
+	if !ok {
+		return nil
+	}
+	var total int64
+	for _, entry := range r.state.timeEntries {
+		if entry.TodoID == todoID && entry.EndedAt != nil {
+			total += entry.DurationSeconds
+		}
+	}
+	updated := cloneTodo(todo)
+	updated.TrackedSeconds = total
+	updated.ChangeSeq = changeSeq
+	r.state.todos[todoID] = updated
+	return nil
+}
+
+// setTimestamps 与 GORM 一致：创建时未设置的 CreatedAt 和 UpdatedAt 取当前时间
+func setTimestamps(createdAt, updatedAt *time.Time) {
+	now := time.Now()
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = now
+	}
+}
+
+func toSet(ids []uint) map[uint]bool {
+	if ids == nil {
+		return nil
+	}
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneTodo(todo *model.Todo) *model.Todo {
+	copied := *todo
+	copied.ParentID = clonePtr(todo.ParentID)
+	copied.CompletedAt = clonePtr(todo.CompletedAt)
+	copied.ArchivedAt = clonePtr(todo.ArchivedAt)
+	copied.DueAt = clonePtr(todo.DueAt)
+	copied.ClientID = clonePtr(todo.ClientID)
+	copied.Tags = append([]string(nil), todo.Tags...)
+	copied.Checklist = append([]model.ChecklistItem(nil), todo.Checklist...)
+	return &copied
+}
+
+func cloneTombstone(tombstone *model.TodoTombstone) *model.TodoTombstone {
+	copied := *tombstone
+	copied.ClientID = clonePtr(tombstone.ClientID)
+	return &copied
+}
+
+func cloneTemplateItem(item *model.TemplateItem) *model.TemplateItem {
+	copied := *item
+	copied.ParentItemID = clonePtr(item.ParentItemID)
+	copied.DueOffset = clonePtr(item.DueOffset)
+	copied.Tags = append([]string(nil), item.Tags...)
+	copied.Checklist = append([]model.ChecklistItem(nil), item.Checklist...)
+	return &copied
+}
+
+func cloneArchivePolicy(policy *model.ArchivePolicy) *model.ArchivePolicy {
+	copied := *policy
+	copied.LastRunAt = clonePtr(policy.LastRunAt)
+	return &copied
+}
+
+func cloneTimeEntry(entry *model.TimeEntry) *model.TimeEntry {
+	copied := *entry
+	copied.EndedAt = clonePtr(entry.EndedAt)
+	copied.RunningUserID = clonePtr(entry.RunningUserID)
+	return &copied
+}
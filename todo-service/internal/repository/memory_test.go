@@ -0,0 +1,9 @@
+package repository
+
+import "testing"
+
+func TestMemoryRepository(t *testing.T) {
+	runConformance(t, func(t *testing.T) TodoRepository {
+		return NewMemory()
+	})
+}
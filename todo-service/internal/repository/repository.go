@@ -0,0 +1,115 @@
+// Package repository 定义 todo-service 的存储接口 TodoRepository 及其实现。
+//
+// 所有 RPC 和后台任务都通过 TodoRepository 访问数据，不直接依赖具体的数据库。
+// 目前提供两种实现：基于 GORM 的实现 (MySQL、PostgreSQL、SQLite 共用) 和内存实现 (开发、测试使用)，
+// 通过配置中的 DB_DRIVER 选择，二者都必须通过 conformance_test.go 中的一致性测试。
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+var (
+	// ErrNotFound 要查找、修改或删除的记录不存在 (或不属于该用户)
+	ErrNotFound = errors.New("repository: record not found")
+	// ErrDuplicate 写入违反唯一约束，例如重复的 client_id 或同一用户的第二个运行中的计时器
+	ErrDuplicate = errors.New("repository: duplicate key")
+)
+
+// TodoFilter ListTodos 的过滤条件，零值表示返回用户的全部 Todo (含已归档)
+type TodoFilter struct {
+	// IDs 非 nil 时只返回这些 ID 的 Todo，空切片不匹配任何 Todo
+	IDs []uint
+	// ParentIDs 非 nil 时只返回父任务为这些 ID 的 Todo
+	ParentIDs []uint
+	// ExcludeArchived 为 true 时不返回已归档的 Todo
+	ExcludeArchived bool
+}
+
+// TimeEntryFilter ListTimeEntries 的过滤条件
+type TimeEntryFilter struct {
+	// TodoID 非 0 时只返回该 Todo 的时间记录
+	TodoID uint
+	// From 非 nil 时只返回在 From 之后仍在计时的记录 (正在运行或结束于 From 之后)
+	From *time.Time
+	// To 非 nil 时只返回开始于 To 之前的记录
+	To *time.Time
+}
+
+// TodoRepository todo-service 的存储接口。
+//
+// 列表按 ID 升序返回 (另有说明的除外)；单条查询找不到记录时返回 ErrNotFound。
+// 返回的对象归调用方所有，修改后需要调用对应的 Save 方法才会写回。
+type TodoRepository interface {
+	// Transaction 在事务中执行 fn，fn 返回错误时回滚。fn 内必须使用传入的 repo，
+	// 而不是外层的 TodoRepository，否则内存和 SQLite 实现会因等待同一把锁而死锁
+	Transaction(ctx context.Context, fn func(repo TodoRepository) error) error
+
+	// CreateTodo 创建 Todo 并回填 ID 和创建时间
+	CreateTodo(ctx context.Context, todo *model.Todo) error
+	GetTodo(ctx context.Context, userID, todoID uint) (*model.Todo, error)
+	GetTodoByClientID(ctx context.Context, userID uint, clientID string) (*model.Todo, error)
+	ListTodos(ctx context.Context, userID uint, filter TodoFilter) ([]*model.Todo, error)
+	// SaveTodo 写回已存在的 Todo 的全部字段，并把 UpdatedAt 更新为当前时间
+	SaveTodo(ctx context.Context, todo *model.Todo) error
+	DeleteTodo(ctx context.Context, todo *model.Todo) error
+
+	// NextChangeSeq 为用户分配下一个变更序号，必须在事务中、且在写入 Todo 之前调用。
+	// 实现需保证同一用户分配序号的事务串行执行直到提交，见 ListTodoChanges
+	NextChangeSeq(ctx context.Context, userID uint) (uint64, error)
+	// CurrentChangeSeq 返回用户最近分配的变更序号，没有变更时返回 0
+	CurrentChangeSeq(ctx context.Context, userID uint) (uint64, error)
+	// ListTodoChanges 返回 change_seq 在 (after, upTo] 之间的 Todo，按 change_seq、ID 升序。
+	// upTo 取自 CurrentChangeSeq 时，不大于 upTo 的变更都已提交，不会遗漏
+	ListTodoChanges(ctx context.Context, userID uint, after, upTo uint64) ([]*model.Todo, error)
+	CreateTombstone(ctx context.Context, tombstone *model.TodoTombstone) error
+	GetTombstone(ctx context.Context, userID, todoID uint) (*model.TodoTombstone, error)
+	GetTombstoneByClientID(ctx context.Context, userID uint, clientID string) (*model.TodoTombstone, error)
+	// ListTombstones 返回 change_seq 在 (after, upTo] 之间的墓碑，按 change_seq、Todo ID 升序
+	ListTombstones(ctx context.Context, userID uint, after, upTo uint64) ([]*model.TodoTombstone, error)
+
+	CreateBatchOperationLog(ctx context.Context, entry *model.BatchOperationLog) error
+
+	// CreateTemplate 只创建模板本身，条目通过 CreateTemplateItem 逐条创建
+	CreateTemplate(ctx context.Context, template *model.TodoTemplate) error
+	CreateTemplateItem(ctx context.Context, item *model.TemplateItem) error
+	// ListTemplates 和 GetTemplate 返回的模板包含按 Position 排列的全部条目
+	ListTemplates(ctx context.Context, userID uint) ([]*model.TodoTemplate, error)
+	GetTemplate(ctx context.Context, userID, templateID uint) (*model.TodoTemplate, error)
+	// DeleteTemplate 删除模板及其全部条目
+	DeleteTemplate(ctx context.Context, userID, templateID uint) error
+
+	CreateSavedSearch(ctx context.Context, search *model.SavedSearch) error
+	ListSavedSearches(ctx context.Context, userID uint) ([]*model.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, userID, searchID uint) (*model.SavedSearch, error)
+	SaveSavedSearch(ctx context.Context, search *model.SavedSearch) error
+	DeleteSavedSearch(ctx context.Context, userID, searchID uint) error
+	// SavedSearchNameExists 检查用户是否已有同名的保存搜索，excludeID 为正在更新的保存搜索
+	SavedSearchNameExists(ctx context.Context, userID uint, name string, excludeID uint) (bool, error)
+
+	GetArchivePolicy(ctx context.Context, userID uint) (*model.ArchivePolicy, error)
+	CreateArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error
+	SaveArchivePolicy(ctx context.Context, policy *model.ArchivePolicy) error
+	// ListEnabledArchivePolicies 返回所有启用的自动归档策略，按用户 ID 升序
+	ListEnabledArchivePolicies(ctx context.Context) ([]*model.ArchivePolicy, error)
+	// UpdateArchivePolicyLastRun 记录后台任务最近一次执行归档的时间，不修改 UpdatedAt
+	UpdateArchivePolicyLastRun(ctx context.Context, userID uint, at time.Time) error
+
+	// CreateTimeEntry 创建时间记录。RunningUserID 与已有的运行中计时器冲突时返回 ErrDuplicate
+	CreateTimeEntry(ctx context.Context, entry *model.TimeEntry) error
+	GetTimeEntry(ctx context.Context, userID, entryID uint) (*model.TimeEntry, error)
+	// FindRunningTimer 返回用户正在运行的计时器，没有时返回 ErrNotFound
+	FindRunningTimer(ctx context.Context, userID uint) (*model.TimeEntry, error)
+	SaveTimeEntry(ctx context.Context, entry *model.TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, entry *model.TimeEntry) error
+	DeleteTimeEntriesOfTodo(ctx context.Context, todoID uint) error
+	// ListTimeEntries 返回用户的时间记录，按开始时间、ID 升序
+	ListTimeEntries(ctx context.Context, userID uint, filter TimeEntryFilter) ([]*model.TimeEntry, error)
+	// RefreshTrackedSeconds 把 Todo 的 TrackedSeconds 重新计算为其已结束时间记录的总时长，
+	// 同时写入 changeSeq。该字段不是用户直接编辑的，因此不修改 UpdatedAt
+	RefreshTrackedSeconds(ctx context.Context, todoID uint, changeSeq uint64) error
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 自动归档策略的默认天数和允许范围
+const (
+	defaultArchiveAfterDays = 30
+	maxArchiveAfterDays     = 3650
+)
+
+func (s *server) ArchiveTodo(ctx context.Context, req *pb.ArchiveTodoRequest) (*pb.ArchiveTodoResponse, error) {
+	log.Printf("Received ArchiveTodo request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	return s.setArchived(ctx, req.GetUserId(), req.GetTodoId(), true)
+}
+
+func (s *server) UnarchiveTodo(ctx context.Context, req *pb.ArchiveTodoRequest) (*pb.ArchiveTodoResponse, error) {
+	log.Printf("Received UnarchiveTodo request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	return s.setArchived(ctx, req.GetUserId(), req.GetTodoId(), false)
+}
+
+// setArchived 归档或恢复 Todo 及其全部子任务。恢复时同时恢复已归档的上级任务，
+// 避免恢复出的 Todo 挂在一个仍处于归档状态的父任务下
+func (s *server) setArchived(ctx context.Context, userID, todoID uint32, archived bool) (*pb.ArchiveTodoResponse, error) {
+	if userID == 0 || todoID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
+	}
+
+	var changed []*model.Todo
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		// 先分配序号：同一用户分配序号的事务串行执行，之后读到的 Todo 在提交前不会被其他请求修改，
+		// SaveTodo 写回全部列时也就不会覆盖并发的更新
+		seq, err := tx.NextChangeSeq(ctx, uint(userID))
+		if err != nil {
+			return err
+		}
+		root, err := tx.GetTodo(ctx, uint(userID), uint(todoID))
+		if err != nil {
+			return err
+		}
+		ids, err := descendantIDs(ctx, tx, uint(userID), root.ID)
+		if err != nil {
+			return err
+		}
+		if !archived {
+			ancestors, err := ancestorIDs(ctx, tx, uint(userID), root.ParentID)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, ancestors...)
+		}
+
+		todos, err := tx.ListTodos(ctx, uint(userID), repository.TodoFilter{IDs: ids})
+		if err != nil {
+			return err
+		}
+		for _, todo := range todos {
+			if (todo.ArchivedAt == nil) == archived {
+				changed = append(changed, todo)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		var archivedAt *time.Time
+		if archived {
+			now := time.Now()
+			archivedAt = &now
+		}
+		for _, todo := range changed {
+			todo.ArchivedAt = archivedAt
+			todo.ChangeSeq = seq
+			if err := tx.SaveTodo(ctx, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	action := "归档"
+	if !archived {
+		action = "恢复"
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权%s", action)
+		}
+		log.Printf("%s Todo %d 失败: %v", action, todoID, err)
+		return nil, status.Errorf(codes.Internal, "%s待办事项失败", action)
+	}
+
+	log.Printf("%s Todo %d 成功，共 %d 个 Todo 状态发生变化", action, todoID, len(changed))
+	changedIDs := make([]uint, len(changed))
+	for i, todo := range changed {
+		changedIDs[i] = todo.ID
+	}
+	s.cache.InvalidateUser(ctx, uint(userID))
+	s.publishTodoEvents(ctx, uint(userID), pb.TodoEvent_UPDATED, changed)
+
+	return &pb.ArchiveTodoResponse{Todos: util.ConvertToProtoTodos(changed)}, nil
+}
+
+func (s *server) GetArchivePolicy(ctx context.Context, req *pb.GetArchivePolicyRequest) (*pb.ArchivePolicy, error) {
+	log.Printf("Received GetArchivePolicy request for user_id: %d", req.GetUserId())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	policy, err := s.repo.GetArchivePolicy(ctx, uint(userID))
+	if errors.Is(err, repository.ErrNotFound) {
+		// 未设置过策略的用户返回默认策略 (未启用)
+		policy = &model.ArchivePolicy{UserID: uint(userID), ArchiveAfterDays: defaultArchiveAfterDays}
+	} else if err != nil {
+		log.Printf("获取用户 %d 的自动归档策略失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取自动归档策略失败")
+	}
+	return util.ConvertToProtoArchivePolicy(policy), nil
+}
+
+func (s *server) UpdateArchivePolicy(ctx context.Context, req *pb.UpdateArchivePolicyRequest) (*pb.ArchivePolicy, error) {
+	log.Printf("Received UpdateArchivePolicy request for user_id: %d, enabled: %t, archive_after_days: %d", req.GetUserId(), req.GetEnabled(), req.GetArchiveAfterDays())
+	userID := req.GetUserId()
+	days := int(req.GetArchiveAfterDays())
+
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	if days > maxArchiveAfterDays || (req.GetEnabled() && days == 0) {
+		return nil, status.Errorf(codes.InvalidArgument, "自动归档天数必须在 1 到 %d 之间", maxArchiveAfterDays)
+	}
+
+	var policy *model.ArchivePolicy
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		var err error
+		policy, err = tx.GetArchivePolicy(ctx, uint(userID))
+		if errors.Is(err, repository.ErrNotFound) {
+			policy = &model.ArchivePolicy{UserID: uint(userID), ArchiveAfterDays: defaultArchiveAfterDays}
+			if days > 0 {
+				policy.ArchiveAfterDays = days
+			}
+			policy.Enabled = req.GetEnabled()
+			return tx.CreateArchivePolicy(ctx, policy)
+		}
+		if err != nil {
+			return err
+		}
+		// 关闭策略时可以不传天数，保留原来的设置
+		if days > 0 {
+			policy.ArchiveAfterDays = days
+		}
+		policy.Enabled = req.GetEnabled()
+		return tx.SaveArchivePolicy(ctx, policy)
+	})
+	if err != nil {
+		log.Printf("更新用户 %d 的自动归档策略失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "更新自动归档策略失败")
+	}
+
+	log.Printf("用户 %d 的自动归档策略已更新: enabled=%t, archive_after_days=%d", userID, policy.Enabled, policy.ArchiveAfterDays)
+	return util.ConvertToProtoArchivePolicy(policy), nil
+}
+
+// descendantIDs 返回 rootID 及其全部子孙任务的 ID
+func descendantIDs(ctx context.Context, tx repository.TodoRepository, userID, rootID uint) ([]uint, error) {
+	ids := []uint{rootID}
+	visited := map[uint]bool{rootID: true}
+	frontier := []uint{rootID}
+	for len(frontier) > 0 {
+		children, err := tx.ListTodos(ctx, userID, repository.TodoFilter{ParentIDs: frontier})
+		if err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, child := range children {
+			if !visited[child.ID] {
+				visited[child.ID] = true
+				ids = append(ids, child.ID)
+				frontier = append(frontier, child.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ancestorIDs 沿 parent_id 向上返回全部上级任务的 ID
+func ancestorIDs(ctx context.Context, tx repository.TodoRepository, userID uint, parentID *uint) ([]uint, error) {
+	var ids []uint
+	visited := make(map[uint]bool)
+	for parentID != nil && !visited[*parentID] {
+		visited[*parentID] = true
+		parent, err := tx.GetTodo(ctx, userID, *parentID)
+		if errors.Is(err, repository.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, parent.ID)
+		parentID = parent.ParentID
+	}
+	return ids, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"todo-project/todo-service/internal/quickadd"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 快速添加文本的最大长度 (按字节计)
+const maxQuickAddTextLength = 1000
+
+func (s *server) QuickAddTodo(ctx context.Context, req *pb.QuickAddTodoRequest) (*pb.QuickAddTodoResponse, error) {
+	log.Printf("Received QuickAddTodo request for user_id: %d, text: %s", req.GetUserId(), req.GetText())
+	text := strings.TrimSpace(req.GetText())
+
+	if req.GetUserId() == 0 || text == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "用户 ID 和文本不能为空")
+	}
+	if len(text) > maxQuickAddTextLength {
+		return nil, status.Errorf(codes.InvalidArgument, "文本长度不能超过 %d 字节", maxQuickAddTextLength)
+	}
+
+	loc, err := loadTimeZone(req.GetTimeZone())
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := quickadd.Parse(text, time.Now().In(loc))
+	if parsed.Title == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "无法从文本中解析出标题")
+	}
+
+	interpretation := convertToProtoInterpretation(&parsed)
+	todo, err := s.CreateTodo(ctx, &pb.CreateTodoRequest{
+		UserId:     req.GetUserId(),
+		Title:      interpretation.Title,
+		DueAt:      interpretation.DueAt,
+		Tags:       interpretation.Tags,
+		Priority:   interpretation.Priority,
+		Recurrence: interpretation.Recurrence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.QuickAddTodoResponse{Todo: todo, Interpretation: interpretation}, nil
+}
+
+// loadTimeZone 加载请求中的 IANA 时区，未指定时使用服务端时区 (可通过 TZ 环境变量设置)
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的时区: %s", name)
+	}
+	return loc, nil
+}
+
+func convertToProtoInterpretation(parsed *quickadd.Result) *pb.QuickAddInterpretation {
+	interpretation := &pb.QuickAddInterpretation{
+		Title:    parsed.Title,
+		AllDay:   parsed.AllDay,
+		Priority: pb.Priority(parsed.Priority),
+		Tags:     parsed.Tags,
+	}
+	if parsed.DueAt != nil {
+		interpretation.DueAt = timestamppb.New(*parsed.DueAt)
+	}
+	if parsed.Recurrence != nil {
+		interpretation.Recurrence = &pb.Recurrence{
+			Frequency: pb.Recurrence_Frequency(parsed.Recurrence.Frequency),
+			Interval:  uint32(parsed.Recurrence.Interval),
+		}
+	}
+	for _, token := range parsed.Tokens {
+		interpretation.Tokens = append(interpretation.Tokens, &pb.QuickAddToken{
+			Kind:  string(token.Kind),
+			Text:  token.Text,
+			Start: uint32(token.Start),
+			End:   uint32(token.End),
+		})
+	}
+	return interpretation
+}
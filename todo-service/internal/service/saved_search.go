@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-project/todo-service/internal/filter"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// 保存搜索名称的最大长度 (按字符计)，与数据库列宽一致
+const maxSavedSearchNameLength = 255
+
+func (s *server) CreateSavedSearch(ctx context.Context, req *pb.CreateSavedSearchRequest) (*pb.SavedSearch, error) {
+	log.Printf("Received CreateSavedSearch request for user_id: %d, name: %s", req.GetUserId(), req.GetName())
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	name, query, err := validateSavedSearch(req.GetName(), req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSavedSearchName(ctx, req.GetUserId(), 0, name); err != nil {
+		return nil, err
+	}
+
+	search := model.SavedSearch{UserID: uint(req.GetUserId()), Name: name, Query: query}
+	if err := s.repo.CreateSavedSearch(ctx, &search); err != nil {
+		log.Printf("创建保存搜索失败 for user %d: %v", req.GetUserId(), err)
+		return nil, status.Errorf(codes.Internal, "创建保存搜索失败")
+	}
+
+	log.Printf("保存搜索创建成功: ID=%d", search.ID)
+	return util.ConvertToProtoSavedSearch(&search), nil
+}
+
+func (s *server) ListSavedSearches(ctx context.Context, req *pb.ListSavedSearchesRequest) (*pb.ListSavedSearchesResponse, error) {
+	log.Printf("Received ListSavedSearches request for user_id: %d", req.GetUserId())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	searches, err := s.repo.ListSavedSearches(ctx, uint(userID))
+	if err != nil {
+		log.Printf("获取保存搜索失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取保存搜索失败")
+	}
+
+	protoSearches := make([]*pb.SavedSearch, len(searches))
+	for i, search := range searches {
+		protoSearches[i] = util.ConvertToProtoSavedSearch(search)
+	}
+	return &pb.ListSavedSearchesResponse{SavedSearches: protoSearches}, nil
+}
+
+func (s *server) GetSavedSearch(ctx context.Context, req *pb.GetSavedSearchRequest) (*pb.SavedSearch, error) {
+	log.Printf("Received GetSavedSearch request for user_id: %d, saved_search_id: %d", req.GetUserId(), req.GetSavedSearchId())
+	search, err := s.findSavedSearch(ctx, req.GetUserId(), req.GetSavedSearchId())
+	if err != nil {
+		return nil, err
+	}
+	return util.ConvertToProtoSavedSearch(search), nil
+}
+
+func (s *server) UpdateSavedSearch(ctx context.Context, req *pb.UpdateSavedSearchRequest) (*pb.SavedSearch, error) {
+	log.Printf("Received UpdateSavedSearch request for user_id: %d, saved_search_id: %d", req.GetUserId(), req.GetSavedSearchId())
+	name, query, err := validateSavedSearch(req.GetName(), req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	search, err := s.findSavedSearch(ctx, req.GetUserId(), req.GetSavedSearchId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSavedSearchName(ctx, req.GetUserId(), search.ID, name); err != nil {
+		return nil, err
+	}
+
+	search.Name = name
+	search.Query = query
+	if err := s.repo.SaveSavedSearch(ctx, search); err != nil {
+		log.Printf("更新保存搜索 %d 失败: %v", search.ID, err)
+		return nil, status.Errorf(codes.Internal, "更新保存搜索失败")
+	}
+
+	log.Printf("保存搜索 %d 更新成功", search.ID)
+	return util.ConvertToProtoSavedSearch(search), nil
+}
+
+func (s *server) DeleteSavedSearch(ctx context.Context, req *pb.DeleteSavedSearchRequest) (*emptypb.Empty, error) {
+	log.Printf("Received DeleteSavedSearch request for user_id: %d, saved_search_id: %d", req.GetUserId(), req.GetSavedSearchId())
+	userID := req.GetUserId()
+	searchID := req.GetSavedSearchId()
+
+	if userID == 0 || searchID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或保存搜索 ID")
+	}
+
+	if err := s.repo.DeleteSavedSearch(ctx, uint(userID), uint(searchID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "保存搜索未找到或无权删除")
+		}
+		log.Printf("删除保存搜索 %d 失败: %v", searchID, err)
+		return nil, status.Errorf(codes.Internal, "删除保存搜索失败")
+	}
+
+	log.Printf("保存搜索 %d 删除成功", searchID)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) RunSavedSearch(ctx context.Context, req *pb.RunSavedSearchRequest) (*pb.RunSavedSearchResponse, error) {
+	log.Printf("Received RunSavedSearch request for user_id: %d, saved_search_id: %d", req.GetUserId(), req.GetSavedSearchId())
+	loc, err := loadTimeZone(req.GetTimeZone())
+	if err != nil {
+		return nil, err
+	}
+	search, err := s.findSavedSearch(ctx, req.GetUserId(), req.GetSavedSearchId())
+	if err != nil {
+		return nil, err
+	}
+	expr, err := filter.Parse(search.Query)
+	if err != nil {
+		return nil, invalidQueryError(err)
+	}
+
+	todos, err := s.repo.ListTodos(ctx, search.UserID, repository.TodoFilter{ExcludeArchived: !req.GetIncludeArchived()})
+	if err != nil {
+		log.Printf("获取 Todos 失败 for user %d: %v", search.UserID, err)
+		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
+	}
+
+	now := time.Now().In(loc)
+	matched := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if expr.Match(todo, now) {
+			matched = append(matched, todo)
+		}
+	}
+
+	log.Printf("保存搜索 %d 匹配了 %d/%d 个 Todo", search.ID, len(matched), len(todos))
+	return &pb.RunSavedSearchResponse{
+		SavedSearch: util.ConvertToProtoSavedSearch(search),
+		Todos:       util.ConvertToProtoTodos(matched),
+	}, nil
+}
+
+// validateSavedSearch 校验名称和查询表达式，返回去除首尾空白后的值
+func validateSavedSearch(name, query string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "保存搜索名称不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxSavedSearchNameLength {
+		return "", "", status.Errorf(codes.InvalidArgument, "保存搜索名称不能超过 %d 个字符", maxSavedSearchNameLength)
+	}
+	// 保留表达式原文，使错误位置与用户输入一致
+	if _, err := filter.Parse(query); err != nil {
+		return "", "", invalidQueryError(err)
+	}
+	return name, query, nil
+}
+
+// invalidQueryError 将表达式语法错误转换为 InvalidArgument，并在 ErrorInfo 中附带出错位置
+func invalidQueryError(err error) error {
+	var syntaxErr *filter.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return status.Errorf(codes.InvalidArgument, "查询表达式无效: %v", err)
+	}
+	st := status.New(codes.InvalidArgument, "查询表达式无效: "+syntaxErr.Error())
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "INVALID_QUERY",
+		Domain: "todo-service",
+		Metadata: map[string]string{
+			"position": strconv.Itoa(syntaxErr.Pos),
+			"message":  syntaxErr.Msg,
+		},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// checkSavedSearchName 检查同一用户下是否已有同名的保存搜索，excludeID 为正在更新的保存搜索
+func (s *server) checkSavedSearchName(ctx context.Context, userID uint32, excludeID uint, name string) error {
+	exists, err := s.repo.SavedSearchNameExists(ctx, uint(userID), name, excludeID)
+	if err != nil {
+		log.Printf("检查保存搜索名称失败 for user %d: %v", userID, err)
+		return status.Errorf(codes.Internal, "保存搜索失败")
+	}
+	if exists {
+		return status.Errorf(codes.AlreadyExists, "已存在名为 %s 的保存搜索", name)
+	}
+	return nil
+}
+
+// findSavedSearch 加载属于指定用户的保存搜索
+func (s *server) findSavedSearch(ctx context.Context, userID, searchID uint32) (*model.SavedSearch, error) {
+	if userID == 0 || searchID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或保存搜索 ID")
+	}
+
+	search, err := s.repo.GetSavedSearch(ctx, uint(userID), uint(searchID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "保存搜索未找到或无权访问")
+		}
+		log.Printf("获取保存搜索 %d 失败 for user %d: %v", searchID, userID, err)
+		return nil, status.Errorf(codes.Internal, "获取保存搜索失败")
+	}
+	return search, nil
+}
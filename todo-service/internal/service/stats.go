@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"todo-project/todo-service/internal/cache"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultStatsDays  = 30
+	maxStatsDays      = 366
+	defaultStatsWeeks = 12
+	maxStatsWeeks     = 104
+	// 逾期数依赖当前时间，因此统计缓存的时间比列表缓存短
+	statsCacheDuration = 5 * time.Minute
+)
+
+func (s *server) GetTodoStats(ctx context.Context, req *pb.GetTodoStatsRequest) (*pb.TodoStats, error) {
+	log.Printf("Received GetTodoStats request for user_id: %d, time_zone: %s", req.GetUserId(), req.GetTimeZone())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	days, weeks := req.GetDays(), req.GetWeeks()
+	if days == 0 {
+		days = defaultStatsDays
+	}
+	if weeks == 0 {
+		weeks = defaultStatsWeeks
+	}
+	if days > maxStatsDays || weeks > maxStatsWeeks {
+		return nil, status.Errorf(codes.InvalidArgument, "统计天数不能超过 %d，周数不能超过 %d", maxStatsDays, maxStatsWeeks)
+	}
+	loc, err := loadTimeZone(req.GetTimeZone())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(loc)
+	// 日期是缓存名的一部分，跨天后自然不会命中前一天的统计
+	name := fmt.Sprintf("stats:%s|%d|%d|%s", loc.String(), days, weeks, now.Format("2006-01-02"))
+	stats, err := cache.Fetch(ctx, s.cache, uint(userID), name, statsCacheDuration, func(ctx context.Context) (*pb.TodoStats, error) {
+		todos, err := s.repo.ListTodos(ctx, uint(userID), repository.TodoFilter{})
+		if err != nil {
+			return nil, err
+		}
+		stats := computeTodoStats(todos, now, int(days), int(weeks))
+		stats.TimeZone = loc.String()
+		return stats, nil
+	})
+	if err != nil {
+		log.Printf("获取用户 %d 的统计失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取统计失败")
+	}
+	return stats, nil
+}
+
+// computeTodoStats 根据用户的全部 Todo 计算统计，now 的时区决定日期边界
+func computeTodoStats(todos []*model.Todo, now time.Time, days, weeks int) *pb.TodoStats {
+	loc := now.Location()
+	today := startOfDay(now)
+	stats := &pb.TodoStats{GeneratedAt: timestamppb.New(now)}
+
+	perDay := make(map[string]uint32)
+	var totalToComplete time.Duration
+	var timedCount int64
+	for _, todo := range todos {
+		if todo.ArchivedAt != nil {
+			stats.Archived++
+		} else {
+			stats.Total++
+			switch {
+			case todo.Completed:
+				stats.Completed++
+			case todo.DueAt != nil && todo.DueAt.Before(now):
+				stats.Open++
+				stats.Overdue++
+			default:
+				stats.Open++
+			}
+		}
+		// 没有记录完成时间的旧数据不参与与时间相关的统计
+		if !todo.Completed || todo.CompletedAt == nil {
+			continue
+		}
+		perDay[todo.CompletedAt.In(loc).Format("2006-01-02")]++
+		if d := todo.CompletedAt.Sub(todo.CreatedAt); d > 0 {
+			totalToComplete += d
+		}
+		timedCount++
+	}
+	if timedCount > 0 {
+		stats.AverageTimeToComplete = durationpb.New((totalToComplete / time.Duration(timedCount)).Truncate(time.Second))
+	}
+
+	for i := days - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		stats.CompletionsPerDay = append(stats.CompletionsPerDay, &pb.CompletionCount{Date: date, Count: perDay[date]})
+	}
+
+	thisWeek := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	for i := weeks - 1; i >= 0; i-- {
+		weekStart := thisWeek.AddDate(0, 0, -7*i)
+		var count uint32
+		for d := 0; d < 7; d++ {
+			count += perDay[weekStart.AddDate(0, 0, d).Format("2006-01-02")]
+		}
+		stats.CompletionsPerWeek = append(stats.CompletionsPerWeek, &pb.CompletionCount{Date: weekStart.Format("2006-01-02"), Count: count})
+	}
+
+	// 当前连续天数：今天还没有完成时不中断，从昨天开始计算
+	day := today
+	if perDay[day.Format("2006-01-02")] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for perDay[day.Format("2006-01-02")] > 0 {
+		stats.CurrentStreak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	dates := make([]string, 0, len(perDay))
+	for date := range perDay {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	var streak uint32
+	var prev time.Time
+	for _, date := range dates {
+		t, _ := time.ParseInLocation("2006-01-02", date, loc)
+		if !prev.IsZero() && prev.AddDate(0, 0, 1).Equal(t) {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+		prev = t
+	}
+	return stats
+}
+
+// startOfDay 返回 t 所在时区当天的零点
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
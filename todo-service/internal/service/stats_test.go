@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+)
+
+func TestComputeTodoStats(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2026-03-11 是周三
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, loc)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2026, 3, day, hour, 0, 0, 0, loc)
+		return &t
+	}
+	done := func(createdDay, completedDay, hour int) *model.Todo {
+		return &model.Todo{Completed: true, CreatedAt: *at(createdDay, hour), CompletedAt: at(completedDay, hour)}
+	}
+
+	todos := []*model.Todo{
+		// 连续完成: 3/1-3/3 (最长 3 天)，3/9-3/10 (今天尚未完成，当前 2 天)
+		done(1, 1, 12), done(1, 2, 12), done(1, 3, 12),
+		done(8, 9, 23), done(9, 10, 0),
+		// 已完成但没有完成时间的旧数据只计入数量
+		{Completed: true, CreatedAt: *at(1, 0)},
+		// 逾期和未逾期
+		{DueAt: at(10, 9), CreatedAt: *at(1, 0)},
+		{DueAt: at(12, 9), CreatedAt: *at(1, 0)},
+		{CreatedAt: *at(1, 0)},
+		// 已归档的已完成 Todo 只计入 archived，不计入 total 和完成数，完成记录仍计入平均完成时间和每日完成数
+		{Completed: true, CreatedAt: *at(2, 12), CompletedAt: at(2, 13), ArchivedAt: at(5, 0)},
+	}
+
+	stats := computeTodoStats(todos, now, 7, 3)
+
+	if stats.Total != 9 || stats.Open != 3 || stats.Completed != 6 || stats.Overdue != 1 || stats.Archived != 1 {
+		t.Errorf("counts = total %d open %d completed %d overdue %d archived %d, want 9/3/6/1/1",
+			stats.Total, stats.Open, stats.Completed, stats.Overdue, stats.Archived)
+	}
+	if stats.CurrentStreak != 2 || stats.LongestStreak != 3 {
+		t.Errorf("streaks = current %d longest %d, want 2/3", stats.CurrentStreak, stats.LongestStreak)
+	}
+
+	// 6 条完成记录: 0 + 1 + 2 + 1 + 1 天, 外加 1 小时
+	wantAverage := (5*24*time.Hour + time.Hour) / 6
+	if got := stats.AverageTimeToComplete.AsDuration(); got != wantAverage.Truncate(time.Second) {
+		t.Errorf("average = %v, want %v", got, wantAverage)
+	}
+
+	if len(stats.CompletionsPerDay) != 7 {
+		t.Fatalf("len(per day) = %d, want 7", len(stats.CompletionsPerDay))
+	}
+	first, last := stats.CompletionsPerDay[0], stats.CompletionsPerDay[6]
+	if first.Date != "2026-03-05" || last.Date != "2026-03-11" || last.Count != 0 {
+		t.Errorf("per day range = %s..%s (last %d), want 2026-03-05..2026-03-11 (0)", first.Date, last.Date, last.Count)
+	}
+	// 3/9 23:00 和 3/10 00:00 分属两天
+	if got := stats.CompletionsPerDay[4]; got.Date != "2026-03-09" || got.Count != 1 {
+		t.Errorf("per day[4] = %s %d, want 2026-03-09 1", got.Date, got.Count)
+	}
+
+	wantWeeks := []struct {
+		date  string
+		count uint32
+	}{{"2026-02-23", 1}, {"2026-03-02", 3}, {"2026-03-09", 2}}
+	if len(stats.CompletionsPerWeek) != len(wantWeeks) {
+		t.Fatalf("len(per week) = %d, want %d", len(stats.CompletionsPerWeek), len(wantWeeks))
+	}
+	for i, want := range wantWeeks {
+		got := stats.CompletionsPerWeek[i]
+		if got.Date != want.date || got.Count != want.count {
+			t.Errorf("per week[%d] = %s %d, want %s %d", i, got.Date, got.Count, want.date, want.count)
+		}
+	}
+}
+
+func TestComputeTodoStatsEmpty(t *testing.T) {
+	stats := computeTodoStats(nil, time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC), 30, 12)
+	if stats.AverageTimeToComplete != nil || stats.CurrentStreak != 0 || stats.LongestStreak != 0 {
+		t.Errorf("empty stats = %v", stats)
+	}
+	if len(stats.CompletionsPerDay) != 30 || len(stats.CompletionsPerWeek) != 12 {
+		t.Errorf("series lengths = %d/%d, want 30/12", len(stats.CompletionsPerDay), len(stats.CompletionsPerWeek))
+	}
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// 单次同步最多上传的修改数
+	maxSyncMutations = 500
+	// 客户端 ID 的最大长度，与 todos.client_id 列一致
+	maxClientIDLength = 64
+)
+
+// syncFields 离线同步中 CREATE 使用以及 UPDATE 默认修改的字段
+var syncFields = []string{"title", "description", "completed", "due_at", "tags", "checklist", "priority", "recurrence"}
+
+// errNotApplied 表示修改未写入数据库 (冲突、被拒绝或重复提交)，用于回滚为其分配的变更序号
+var errNotApplied = errors.New("sync mutation not applied")
+
+func (s *server) SyncTodos(ctx context.Context, req *pb.SyncTodosRequest) (*pb.SyncTodosResponse, error) {
+	log.Printf("Received SyncTodos request for user_id: %d, sync_token: %s, mutations: %d", req.GetUserId(), req.GetSyncToken(), len(req.GetMutations()))
+	userID := uint(req.GetUserId())
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	var since uint64
+	full := req.GetSyncToken() == ""
+	if !full {
+		var err error
+		if since, err = strconv.ParseUint(req.GetSyncToken(), 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "无效的 sync_token: %s", req.GetSyncToken())
+		}
+	}
+	if len(req.GetMutations()) > maxSyncMutations {
+		return nil, status.Errorf(codes.InvalidArgument, "单次同步最多上传 %d 条修改", maxSyncMutations)
+	}
+
+	applier := &syncApplier{repo: s.repo, userID: userID, applied: make(map[uint]uint64)}
+	results := make([]*pb.SyncMutationResult, 0, len(req.GetMutations()))
+	for _, mutation := range req.GetMutations() {
+		result, err := applier.apply(ctx, mutation)
+		if err != nil {
+			// 已应用的修改仍然需要清除缓存和发布事件，客户端重试时这些修改会返回 DUPLICATE 或 CONFLICT
+			s.finishSync(ctx, applier)
+			log.Printf("用户 %d 应用离线修改 %q 失败: %v", userID, mutation.GetMutationId(), err)
+			return nil, status.Errorf(codes.Internal, "同步失败")
+		}
+		results = append(results, result)
+	}
+	s.finishSync(ctx, applier)
+
+	// 先读取计数器再读取变更：不大于 current 的变更都已提交，之后提交的变更留到下次同步
+	current, err := s.repo.CurrentChangeSeq(ctx, userID)
+	if err != nil {
+		log.Printf("获取用户 %d 的变更序号失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "同步失败")
+	}
+	fullResync := false
+	if since > current {
+		// 令牌来自未来 (例如数据库从备份恢复)，要求客户端以完整列表替换本地副本
+		log.Printf("用户 %d 的 sync_token %d 大于当前变更序号 %d，返回完整列表", userID, since, current)
+		full, fullResync = true, true
+	}
+
+	todos, err := s.syncChanges(ctx, userID, since, current, full)
+	if err != nil {
+		log.Printf("获取用户 %d 的变更失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "同步失败")
+	}
+	var deletedIDs []uint32
+	if !full {
+		tombstones, err := s.repo.ListTombstones(ctx, userID, since, current)
+		if err != nil {
+			log.Printf("获取用户 %d 的删除记录失败: %v", userID, err)
+			return nil, status.Errorf(codes.Internal, "同步失败")
+		}
+		for _, tombstone := range tombstones {
+			deletedIDs = append(deletedIDs, uint32(tombstone.TodoID))
+		}
+	}
+
+	log.Printf("用户 %d 同步完成: 应用 %d 条修改，返回 %d 个变更和 %d 个删除，sync_token %d", userID, len(results), len(todos), len(deletedIDs), current)
+	return &pb.SyncTodosResponse{
+		SyncToken:      strconv.FormatUint(current, 10),
+		Todos:          util.ConvertToProtoTodos(todos),
+		DeletedTodoIds: deletedIDs,
+		Results:        results,
+		FullResync:     fullResync,
+	}, nil
+}
+
+// syncChanges 返回 change_seq 在 (since, current] 之间的 Todo。full 为 true 时返回不大于 current 的全部 Todo，
+// 包括引入变更序号之前创建、change_seq 仍为 0 的 Todo
+func (s *server) syncChanges(ctx context.Context, userID uint, since, current uint64, full bool) ([]*model.Todo, error) {
+	if !full {
+		return s.repo.ListTodoChanges(ctx, userID, since, current)
+	}
+	all, err := s.repo.ListTodos(ctx, userID, repository.TodoFilter{})
+	if err != nil {
+		return nil, err
+	}
+	todos := make([]*model.Todo, 0, len(all))
+	for _, todo := range all {
+		if todo.ChangeSeq <= current {
+			todos = append(todos, todo)
+		}
+	}
+	sort.SliceStable(todos, func(i, j int) bool { return todos[i].ChangeSeq < todos[j].ChangeSeq })
+	return todos, nil
+}
+
+// finishSync 清除已应用修改涉及的缓存并发布变更事件
+func (s *server) finishSync(ctx context.Context, a *syncApplier) {
+	ids := append(append(append([]uint{}, a.createdIDs...), a.updatedIDs...), a.deletedIDs...)
+	if len(ids) == 0 {
+		return
+	}
+	s.cache.InvalidateUser(ctx, a.userID)
+	if len(a.createdIDs) > 0 {
+		todos, err := s.repo.ListTodos(ctx, a.userID, repository.TodoFilter{IDs: a.createdIDs})
+		if err != nil {
+			log.Printf("警告: 加载用户 %d 的 Todo 以发布事件失败: %v", a.userID, err)
+		}
+		s.publishTodoEvents(ctx, a.userID, pb.TodoEvent_CREATED, todos)
+	}
+	created := make(map[uint]bool, len(a.createdIDs))
+	for _, id := range a.createdIDs {
+		created[id] = true
+	}
+	var updatedIDs []uint
+	for _, id := range a.updatedIDs {
+		if !created[id] {
+			updatedIDs = append(updatedIDs, id)
+		}
+	}
+	s.publishTodoUpdates(ctx, a.userID, updatedIDs)
+	if len(a.deletedIDs) > 0 {
+		s.publishTodoDeletes(ctx, a.userID, a.deletedIDs)
+	}
+}
+
+// syncApplier 按顺序应用一次同步请求中的离线修改，每条修改在单独的事务中执行
+type syncApplier struct {
+	repo   repository.TodoRepository
+	userID uint
+	// 本次请求中已创建或修改的 Todo 及其 change_seq。客户端不知道自己的修改分配的序号，
+	// 因此目标的最近一次修改来自本次请求时不检查 base_change_seq
+	applied map[uint]uint64
+
+	createdIDs, updatedIDs, deletedIDs []uint
+}
+
+func (a *syncApplier) apply(ctx context.Context, m *pb.SyncMutation) (*pb.SyncMutationResult, error) {
+	result := &pb.SyncMutationResult{MutationId: m.GetMutationId()}
+	var seq uint64
+	var createdIDs, updatedIDs, deletedIDs []uint
+	err := a.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		// 先分配序号：同一用户分配序号的事务串行执行，之后读到的 change_seq 不会在提交前被其他请求改变
+		var err error
+		if seq, err = tx.NextChangeSeq(ctx, a.userID); err != nil {
+			return err
+		}
+		switch m.GetOp() {
+		case pb.SyncMutation_CREATE:
+			var todo *model.Todo
+			if todo, err = a.create(ctx, tx, seq, m, result); err == nil {
+				createdIDs = []uint{todo.ID}
+			}
+		case pb.SyncMutation_UPDATE:
+			var todo *model.Todo
+			if todo, err = a.update(ctx, tx, seq, m, result); err == nil {
+				updatedIDs = []uint{todo.ID}
+			}
+		case pb.SyncMutation_DELETE:
+			var todo *model.Todo
+			if todo, updatedIDs, err = a.delete(ctx, tx, seq, m, result); err == nil {
+				deletedIDs = []uint{todo.ID}
+			}
+		default:
+			err = reject(result, "无效的操作类型: %s", m.GetOp())
+		}
+		return err
+	})
+	if errors.Is(err, errNotApplied) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Status = pb.SyncMutationResult_APPLIED
+	for _, ids := range [][]uint{createdIDs, updatedIDs} {
+		for _, id := range ids {
+			a.applied[id] = seq
+		}
+	}
+	a.createdIDs = append(a.createdIDs, createdIDs...)
+	a.updatedIDs = append(a.updatedIDs, updatedIDs...)
+	a.deletedIDs = append(a.deletedIDs, deletedIDs...)
+	return result, nil
+}
+
+// reject 将修改标记为无效并返回 errNotApplied
+func reject(result *pb.SyncMutationResult, format string, args ...interface{}) error {
+	return notApplied(result, pb.SyncMutationResult_REJECTED, format, args...)
+}
+
+func notApplied(result *pb.SyncMutationResult, st pb.SyncMutationResult_Status, format string, args ...interface{}) error {
+	result.Status = st
+	result.Message = fmt.Sprintf(format, args...)
+	return errNotApplied
+}
+
+func (a *syncApplier) create(ctx context.Context, tx repository.TodoRepository, seq uint64, m *pb.SyncMutation, result *pb.SyncMutationResult) (*model.Todo, error) {
+	clientID := m.GetClientId()
+	if clientID == "" || utf8.RuneCountInString(clientID) > maxClientIDLength {
+		return nil, reject(result, "CREATE 必须指定不超过 %d 个字符的 client_id", maxClientIDLength)
+	}
+	// 相同 client_id 的 Todo 已创建 (之后可能已被删除)，说明是客户端在未收到响应时的重试
+	existing, tombstone, err := a.findByClientID(ctx, tx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		result.TodoId = uint32(existing.ID)
+		return nil, notApplied(result, pb.SyncMutationResult_DUPLICATE, "")
+	}
+	if tombstone != nil {
+		result.TodoId = uint32(tombstone.TodoID)
+		return nil, notApplied(result, pb.SyncMutationResult_DUPLICATE, "该 Todo 已创建并已被删除")
+	}
+
+	todo := &model.Todo{UserID: a.userID, ClientID: &clientID, ChangeSeq: seq}
+	if err := applySyncFields(todo, m.GetTodo(), syncFields, time.Now()); err != nil {
+		return nil, reject(result, "%s", status.Convert(err).Message())
+	}
+
+	// 父任务可以是离线创建的 Todo
+	if parentClientID, parentID := m.GetParentClientId(), m.GetTodo().GetParentId(); parentClientID != "" || parentID != 0 {
+		var parent *model.Todo
+		if parentClientID != "" {
+			parent, _, err = a.findByClientID(ctx, tx, parentClientID)
+		} else {
+			parent, _, err = a.findByID(ctx, tx, uint(parentID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, reject(result, "父任务不存在或无权访问")
+		}
+		todo.ParentID = &parent.ID
+	}
+
+	if err := tx.CreateTodo(ctx, todo); err != nil {
+		return nil, err
+	}
+	result.TodoId = uint32(todo.ID)
+	return todo, nil
+}
+
+func (a *syncApplier) update(ctx context.Context, tx repository.TodoRepository, seq uint64, m *pb.SyncMutation, result *pb.SyncMutationResult) (*model.Todo, error) {
+	todo, tombstone, err := a.target(ctx, tx, m, result)
+	if err != nil {
+		return nil, err
+	}
+	if tombstone != nil {
+		// 删除优先
+		return nil, notApplied(result, pb.SyncMutationResult_CONFLICT, "待办事项已被删除")
+	}
+	if a.stale(todo, m) {
+		return nil, notApplied(result, pb.SyncMutationResult_CONFLICT, "待办事项已在服务端被修改")
+	}
+
+	fields := m.GetUpdateFields()
+	if len(fields) == 0 {
+		fields = syncFields
+	}
+	if err := applySyncFields(todo, m.GetTodo(), fields, time.Now()); err != nil {
+		return nil, reject(result, "%s", status.Convert(err).Message())
+	}
+	todo.ChangeSeq = seq
+	if err := tx.SaveTodo(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// delete 删除目标 Todo，返回被删除的 Todo 和被提升的子任务 ID
+func (a *syncApplier) delete(ctx context.Context, tx repository.TodoRepository, seq uint64, m *pb.SyncMutation, result *pb.SyncMutationResult) (*model.Todo, []uint, error) {
+	todo, tombstone, err := a.target(ctx, tx, m, result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tombstone != nil {
+		// 重复删除视为成功
+		return nil, nil, notApplied(result, pb.SyncMutationResult_APPLIED, "")
+	}
+	if a.stale(todo, m) {
+		// 服务端的修改优先，Todo 保留
+		return nil, nil, notApplied(result, pb.SyncMutationResult_CONFLICT, "待办事项已在服务端被修改")
+	}
+	childIDs, err := deleteTodo(ctx, tx, todo, seq)
+	if err != nil {
+		return nil, nil, err
+	}
+	return todo, childIDs, nil
+}
+
+// stale 判断客户端修改时看到的版本是否早于服务端的当前版本
+func (a *syncApplier) stale(todo *model.Todo, m *pb.SyncMutation) bool {
+	if seq, ok := a.applied[todo.ID]; ok && seq == todo.ChangeSeq {
+		return false
+	}
+	return m.GetBaseChangeSeq() < todo.ChangeSeq
+}
+
+// target 查找 UPDATE/DELETE 的目标。Todo 已被删除时返回其墓碑
+func (a *syncApplier) target(ctx context.Context, tx repository.TodoRepository, m *pb.SyncMutation, result *pb.SyncMutationResult) (*model.Todo, *model.TodoTombstone, error) {
+	var todo *model.Todo
+	var tombstone *model.TodoTombstone
+	var err error
+	switch {
+	case m.GetClientId() != "":
+		todo, tombstone, err = a.findByClientID(ctx, tx, m.GetClientId())
+	case m.GetTodoId() != 0:
+		todo, tombstone, err = a.findByID(ctx, tx, uint(m.GetTodoId()))
+	default:
+		return nil, nil, reject(result, "必须指定 todo_id 或 client_id")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case todo != nil:
+		result.TodoId = uint32(todo.ID)
+	case tombstone != nil:
+		result.TodoId = uint32(tombstone.TodoID)
+	default:
+		return nil, nil, reject(result, "待办事项未找到或无权访问")
+	}
+	return todo, tombstone, nil
+}
+
+// findByID 查找用户的 Todo，找不到时查找墓碑，两者都不存在时返回两个 nil
+func (a *syncApplier) findByID(ctx context.Context, tx repository.TodoRepository, todoID uint) (*model.Todo, *model.TodoTombstone, error) {
+	todo, err := tx.GetTodo(ctx, a.userID, todoID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return todo, nil, err
+	}
+	tombstone, err := tx.GetTombstone(ctx, a.userID, todoID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, tombstone, err
+	}
+	return nil, nil, nil
+}
+
+// findByClientID 与 findByID 相同，按客户端 ID 查找
+func (a *syncApplier) findByClientID(ctx context.Context, tx repository.TodoRepository, clientID string) (*model.Todo, *model.TodoTombstone, error) {
+	todo, err := tx.GetTodoByClientID(ctx, a.userID, clientID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return todo, nil, err
+	}
+	tombstone, err := tx.GetTombstoneByClientID(ctx, a.userID, clientID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, tombstone, err
+	}
+	return nil, nil, nil
+}
+
+// applySyncFields 将 src 中 fields 指定的字段写入 todo。字段无效时返回 InvalidArgument
+func applySyncFields(todo *model.Todo, src *pb.Todo, fields []string, now time.Time) error {
+	for _, field := range fields {
+		switch field {
+		case "title":
+			if src.GetTitle() == "" {
+				return status.Errorf(codes.InvalidArgument, "标题不能为空")
+			}
+			todo.Title = src.GetTitle()
+		case "description":
+			todo.Description = src.GetDescription()
+		case "completed":
+			// 与 UpdateTodo 相同：从未完成变为完成时记录完成时间，重新打开时清除
+			if src.GetCompleted() && !todo.Completed {
+				todo.CompletedAt = &now
+			} else if !src.GetCompleted() {
+				todo.CompletedAt = nil
+			}
+			todo.Completed = src.GetCompleted()
+		case "due_at":
+			todo.DueAt = nil
+			if src.GetDueAt() != nil {
+				dueAt := src.GetDueAt().AsTime()
+				todo.DueAt = &dueAt
+			}
+		case "tags":
+			todo.Tags = util.NormalizeTags(src.GetTags())
+		case "checklist":
+			todo.Checklist = util.ConvertFromProtoChecklist(src.GetChecklist())
+		case "priority":
+			if _, ok := pb.Priority_name[int32(src.GetPriority())]; !ok {
+				return status.Errorf(codes.InvalidArgument, "无效的优先级: %d", src.GetPriority())
+			}
+			todo.Priority = int(src.GetPriority())
+		case "recurrence":
+			todo.RecurrenceFrequency, todo.RecurrenceInterval = 0, 0
+			if recurrence := src.GetRecurrence(); recurrence != nil && recurrence.GetFrequency() != pb.Recurrence_FREQUENCY_UNSPECIFIED {
+				if _, ok := pb.Recurrence_Frequency_name[int32(recurrence.GetFrequency())]; !ok {
+					return status.Errorf(codes.InvalidArgument, "无效的重复频率: %d", recurrence.GetFrequency())
+				}
+				todo.RecurrenceFrequency = int(recurrence.GetFrequency())
+				todo.RecurrenceInterval = max(int(recurrence.GetInterval()), 1)
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, "不支持同步修改的字段: %s", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// 单个模板允许包含的最大条目数，防止误把超大的 Todo 树保存为模板
+const maxTemplateItems = 200
+
+func (s *server) CreateTemplateFromTodo(ctx context.Context, req *pb.CreateTemplateFromTodoRequest) (*pb.TodoTemplate, error) {
+	log.Printf("Received CreateTemplateFromTodo request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	userID := req.GetUserId()
+	todoID := req.GetTodoId()
+
+	if userID == 0 || todoID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
+	}
+
+	root, err := s.repo.GetTodo(ctx, uint(userID), uint(todoID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权访问")
+		}
+		log.Printf("查找模板源 Todo %d 失败: %v", todoID, err)
+		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
+	}
+
+	// 逐层加载子任务，并按先序遍历排列整棵树
+	children := make(map[uint][]*model.Todo)
+	visited := map[uint]bool{root.ID: true}
+	total := 1
+	frontier := []uint{root.ID}
+	for len(frontier) > 0 {
+		level, err := s.repo.ListTodos(ctx, uint(userID), repository.TodoFilter{ParentIDs: frontier})
+		if err != nil {
+			log.Printf("加载 Todo %d 的子任务失败: %v", todoID, err)
+			return nil, status.Errorf(codes.Internal, "获取待办事项失败")
+		}
+		frontier = frontier[:0]
+		for _, child := range level {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			total++
+			if total > maxTemplateItems {
+				return nil, status.Errorf(codes.InvalidArgument, "模板最多包含 %d 个条目", maxTemplateItems)
+			}
+			children[*child.ParentID] = append(children[*child.ParentID], child)
+			frontier = append(frontier, child.ID)
+		}
+	}
+	ordered := make([]*model.Todo, 0, total)
+	var walk func(todo *model.Todo)
+	walk = func(todo *model.Todo) {
+		ordered = append(ordered, todo)
+		for _, child := range children[todo.ID] {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	// 截止偏移以根任务的截止时间为基准，根任务没有截止时间时以其创建时间为基准
+	reference := root.CreatedAt
+	if root.DueAt != nil {
+		reference = *root.DueAt
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		name = root.Title
+	}
+	template := model.TodoTemplate{
+		UserID:      uint(userID),
+		Name:        name,
+		Description: req.GetDescription(),
+	}
+
+	err = s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		if err := tx.CreateTemplate(ctx, &template); err != nil {
+			return err
+		}
+		itemIDs := make(map[uint]uint, len(ordered)) // Todo ID -> 模板条目 ID
+		for i, todo := range ordered {
+			item := model.TemplateItem{
+				TemplateID:  template.ID,
+				Position:    i,
+				Title:       todo.Title,
+				Description: todo.Description,
+				Tags:        todo.Tags,
+				Checklist:   todo.Checklist,
+			}
+			if i > 0 {
+				parentItemID := itemIDs[*todo.ParentID]
+				item.ParentItemID = &parentItemID
+			}
+			if todo.DueAt != nil {
+				offset := int64(todo.DueAt.Sub(reference) / time.Second)
+				item.DueOffset = &offset
+			}
+			// 模板中的检查清单总是从未完成状态开始
+			for j := range item.Checklist {
+				item.Checklist[j].Done = false
+			}
+			if err := tx.CreateTemplateItem(ctx, &item); err != nil {
+				return err
+			}
+			itemIDs[todo.ID] = item.ID
+			template.Items = append(template.Items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("创建模板失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "创建模板失败")
+	}
+
+	log.Printf("模板创建成功: ID=%d, 条目数=%d", template.ID, len(template.Items))
+	return util.ConvertToProtoTemplate(&template), nil
+}
+
+func (s *server) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	log.Printf("Received ListTemplates request for user_id: %d", req.GetUserId())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	templates, err := s.repo.ListTemplates(ctx, uint(userID))
+	if err != nil {
+		log.Printf("获取模板失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取模板失败")
+	}
+
+	protoTemplates := make([]*pb.TodoTemplate, len(templates))
+	for i, template := range templates {
+		protoTemplates[i] = util.ConvertToProtoTemplate(template)
+	}
+	return &pb.ListTemplatesResponse{Templates: protoTemplates}, nil
+}
+
+func (s *server) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.TodoTemplate, error) {
+	log.Printf("Received GetTemplate request for user_id: %d, template_id: %d", req.GetUserId(), req.GetTemplateId())
+	template, err := s.findTemplate(ctx, req.GetUserId(), req.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+	return util.ConvertToProtoTemplate(template), nil
+}
+
+func (s *server) DeleteTemplate(ctx context.Context, req *pb.DeleteTemplateRequest) (*emptypb.Empty, error) {
+	log.Printf("Received DeleteTemplate request for user_id: %d, template_id: %d", req.GetUserId(), req.GetTemplateId())
+	userID := req.GetUserId()
+	templateID := req.GetTemplateId()
+
+	if userID == 0 || templateID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或模板 ID")
+	}
+
+	if err := s.repo.DeleteTemplate(ctx, uint(userID), uint(templateID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "模板未找到或无权删除")
+		}
+		log.Printf("删除模板 %d 失败: %v", templateID, err)
+		return nil, status.Errorf(codes.Internal, "删除模板失败")
+	}
+
+	log.Printf("模板 %d 删除成功", templateID)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) InstantiateTemplate(ctx context.Context, req *pb.InstantiateTemplateRequest) (*pb.InstantiateTemplateResponse, error) {
+	log.Printf("Received InstantiateTemplate request for user_id: %d, template_id: %d", req.GetUserId(), req.GetTemplateId())
+	template, err := s.findTemplate(ctx, req.GetUserId(), req.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+
+	baseTime := time.Now()
+	if req.GetBaseTime() != nil {
+		baseTime = req.GetBaseTime().AsTime()
+	}
+
+	todos := make([]*model.Todo, 0, len(template.Items))
+	err = s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		seq, err := tx.NextChangeSeq(ctx, template.UserID)
+		if err != nil {
+			return err
+		}
+		todoIDs := make(map[uint]uint, len(template.Items)) // 模板条目 ID -> 新 Todo ID
+		for _, item := range template.Items {
+			todo := &model.Todo{
+				UserID:      template.UserID,
+				Title:       item.Title,
+				Description: item.Description,
+				Tags:        item.Tags,
+				Checklist:   item.Checklist,
+				ChangeSeq:   seq,
+			}
+			if item.ParentItemID != nil {
+				parentID, ok := todoIDs[*item.ParentItemID]
+				if !ok {
+					return fmt.Errorf("模板条目 %d 的父条目 %d 不存在", item.ID, *item.ParentItemID)
+				}
+				todo.ParentID = &parentID
+			}
+			if item.DueOffset != nil {
+				dueAt := baseTime.Add(time.Duration(*item.DueOffset) * time.Second)
+				todo.DueAt = &dueAt
+			}
+			if err := tx.CreateTodo(ctx, todo); err != nil {
+				return err
+			}
+			todoIDs[item.ID] = todo.ID
+			todos = append(todos, todo)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("实例化模板 %d 失败: %v", template.ID, err)
+		return nil, status.Errorf(codes.Internal, "实例化模板失败")
+	}
+
+	log.Printf("模板 %d 实例化成功，创建了 %d 个 Todo", template.ID, len(todos))
+	s.cache.InvalidateUser(ctx, template.UserID)
+
+	s.publishTodoEvents(ctx, template.UserID, pb.TodoEvent_CREATED, todos)
+
+	return &pb.InstantiateTemplateResponse{Todos: util.ConvertToProtoTodos(todos)}, nil
+}
+
+// findTemplate 加载属于指定用户的模板及其全部条目
+func (s *server) findTemplate(ctx context.Context, userID, templateID uint32) (*model.TodoTemplate, error) {
+	if userID == 0 || templateID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或模板 ID")
+	}
+
+	template, err := s.repo.GetTemplate(ctx, uint(userID), uint(templateID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "模板未找到或无权访问")
+		}
+		log.Printf("获取模板 %d 失败 for user %d: %v", templateID, userID, err)
+		return nil, status.Errorf(codes.Internal, "获取模板失败")
+	}
+	return template, nil
+}
@@ -0,0 +1,520 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxTimeEntryNoteLength = 500
+	maxTimeReportRange     = 366 * 24 * time.Hour
+	// 允许客户端与服务端之间存在的时钟偏差
+	clockSkewTolerance = time.Minute
+)
+
+var errTimerRunning = errors.New("timer already running")
+
+func (s *server) StartTimer(ctx context.Context, req *pb.StartTimerRequest) (*pb.TimeEntry, error) {
+	log.Printf("Received StartTimer request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	userID := req.GetUserId()
+	todoID := req.GetTodoId()
+
+	if userID == 0 || todoID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
+	}
+	note, err := validateTimeEntryNote(req.GetNote())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTodoOwner(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	uid := uint(userID)
+	entry := model.TimeEntry{
+		UserID:        uid,
+		TodoID:        uint(todoID),
+		StartedAt:     now,
+		Note:          note,
+		RunningUserID: &uid,
+	}
+	var stopped *model.TimeEntry
+	err = s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		running, err := findRunningTimer(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			if !req.GetStopRunning() {
+				return errTimerRunning
+			}
+			if err := stopTimeEntry(ctx, tx, running, now); err != nil {
+				return err
+			}
+			stopped = running
+		}
+		return tx.CreateTimeEntry(ctx, &entry)
+	})
+	if err != nil {
+		// 并发启动时由 running_user_id 的唯一约束兜底
+		if errors.Is(err, errTimerRunning) || errors.Is(err, repository.ErrDuplicate) {
+			return nil, status.Errorf(codes.FailedPrecondition, "已有正在运行的计时器，请先停止")
+		}
+		log.Printf("启动计时器失败 for user %d, todo %d: %v", userID, todoID, err)
+		return nil, status.Errorf(codes.Internal, "启动计时器失败")
+	}
+
+	log.Printf("计时器已启动: ID=%d, user=%d, todo=%d", entry.ID, userID, todoID)
+	if stopped != nil {
+		log.Printf("用户 %d 之前运行的计时器 %d 已停止", userID, stopped.ID)
+		s.cache.InvalidateUser(ctx, uid)
+		s.publishTodoUpdates(ctx, uid, []uint{stopped.TodoID})
+	}
+	return util.ConvertToProtoTimeEntry(&entry, now), nil
+}
+
+func (s *server) StopTimer(ctx context.Context, req *pb.StopTimerRequest) (*pb.TimeEntry, error) {
+	log.Printf("Received StopTimer request for user_id: %d", req.GetUserId())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	now := time.Now()
+	var entry *model.TimeEntry
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		var err error
+		if entry, err = findRunningTimer(ctx, tx, userID); err != nil || entry == nil {
+			return err
+		}
+		return stopTimeEntry(ctx, tx, entry, now)
+	})
+	if err != nil {
+		log.Printf("停止计时器失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "停止计时器失败")
+	}
+	if entry == nil {
+		return nil, status.Errorf(codes.NotFound, "没有正在运行的计时器")
+	}
+
+	log.Printf("计时器已停止: ID=%d, 时长=%ds", entry.ID, entry.DurationSeconds)
+	s.cache.InvalidateUser(ctx, entry.UserID)
+	s.publishTodoUpdates(ctx, entry.UserID, []uint{entry.TodoID})
+	return util.ConvertToProtoTimeEntry(entry, now), nil
+}
+
+func (s *server) GetRunningTimer(ctx context.Context, req *pb.GetRunningTimerRequest) (*pb.TimeEntry, error) {
+	log.Printf("Received GetRunningTimer request for user_id: %d", req.GetUserId())
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	entry, err := findRunningTimer(ctx, s.repo, req.GetUserId())
+	if err != nil {
+		log.Printf("获取用户 %d 正在运行的计时器失败: %v", req.GetUserId(), err)
+		return nil, status.Errorf(codes.Internal, "获取计时器失败")
+	}
+	if entry == nil {
+		return nil, status.Errorf(codes.NotFound, "没有正在运行的计时器")
+	}
+	return util.ConvertToProtoTimeEntry(entry, time.Now()), nil
+}
+
+func (s *server) CreateTimeEntry(ctx context.Context, req *pb.CreateTimeEntryRequest) (*pb.TimeEntry, error) {
+	log.Printf("Received CreateTimeEntry request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	userID := req.GetUserId()
+	todoID := req.GetTodoId()
+
+	if userID == 0 || todoID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
+	}
+	startedAt, endedAt, err := validateTimeRange(req.GetStartedAt(), req.GetEndedAt(), false)
+	if err != nil {
+		return nil, err
+	}
+	note, err := validateTimeEntryNote(req.GetNote())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTodoOwner(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	entry := model.TimeEntry{
+		UserID:          uint(userID),
+		TodoID:          uint(todoID),
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(endedAt.Sub(startedAt) / time.Second),
+		Note:            note,
+	}
+	err = s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		if err := tx.CreateTimeEntry(ctx, &entry); err != nil {
+			return err
+		}
+		return refreshTrackedTime(ctx, tx, entry.UserID, entry.TodoID)
+	})
+	if err != nil {
+		log.Printf("创建时间记录失败 for user %d, todo %d: %v", userID, todoID, err)
+		return nil, status.Errorf(codes.Internal, "创建时间记录失败")
+	}
+
+	log.Printf("时间记录创建成功: ID=%d, 时长=%ds", entry.ID, entry.DurationSeconds)
+	s.cache.InvalidateUser(ctx, entry.UserID)
+	s.publishTodoUpdates(ctx, entry.UserID, []uint{entry.TodoID})
+	return util.ConvertToProtoTimeEntry(&entry, time.Now()), nil
+}
+
+func (s *server) ListTimeEntries(ctx context.Context, req *pb.ListTimeEntriesRequest) (*pb.ListTimeEntriesResponse, error) {
+	log.Printf("Received ListTimeEntries request for user_id: %d, todo_id: %d", req.GetUserId(), req.GetTodoId())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+
+	filter := repository.TimeEntryFilter{TodoID: uint(req.GetTodoId())}
+	if req.GetFrom() != nil {
+		from := req.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if req.GetTo() != nil {
+		to := req.GetTo().AsTime()
+		filter.To = &to
+	}
+	entries, err := s.repo.ListTimeEntries(ctx, uint(userID), filter)
+	if err != nil {
+		log.Printf("获取时间记录失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取时间记录失败")
+	}
+
+	now := time.Now()
+	protoEntries := make([]*pb.TimeEntry, len(entries))
+	for i, entry := range entries {
+		protoEntries[i] = util.ConvertToProtoTimeEntry(entry, now)
+	}
+	return &pb.ListTimeEntriesResponse{TimeEntries: protoEntries}, nil
+}
+
+func (s *server) UpdateTimeEntry(ctx context.Context, req *pb.UpdateTimeEntryRequest) (*pb.TimeEntry, error) {
+	log.Printf("Received UpdateTimeEntry request for user_id: %d, time_entry_id: %d", req.GetUserId(), req.GetTimeEntryId())
+	userID := req.GetUserId()
+	entryID := req.GetTimeEntryId()
+
+	if userID == 0 || entryID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或时间记录 ID")
+	}
+	note, err := validateTimeEntryNote(req.GetNote())
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *model.TimeEntry
+	err = s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		var err error
+		if entry, err = tx.GetTimeEntry(ctx, uint(userID), uint(entryID)); err != nil {
+			return err
+		}
+		running := entry.EndedAt == nil
+		startedAt, endedAt, err := validateTimeRange(req.GetStartedAt(), req.GetEndedAt(), running)
+		if err != nil {
+			return err
+		}
+		entry.StartedAt = startedAt
+		entry.Note = note
+		if !running {
+			entry.EndedAt = endedAt
+			entry.DurationSeconds = int64(endedAt.Sub(startedAt) / time.Second)
+		}
+		if err := tx.SaveTimeEntry(ctx, entry); err != nil {
+			return err
+		}
+		return refreshTrackedTime(ctx, tx, entry.UserID, entry.TodoID)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "时间记录未找到或无权修改")
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		log.Printf("修改时间记录 %d 失败: %v", entryID, err)
+		return nil, status.Errorf(codes.Internal, "修改时间记录失败")
+	}
+
+	log.Printf("时间记录 %d 修改成功", entryID)
+	s.cache.InvalidateUser(ctx, entry.UserID)
+	s.publishTodoUpdates(ctx, entry.UserID, []uint{entry.TodoID})
+	return util.ConvertToProtoTimeEntry(entry, time.Now()), nil
+}
+
+func (s *server) DeleteTimeEntry(ctx context.Context, req *pb.DeleteTimeEntryRequest) (*emptypb.Empty, error) {
+	log.Printf("Received DeleteTimeEntry request for user_id: %d, time_entry_id: %d", req.GetUserId(), req.GetTimeEntryId())
+	userID := req.GetUserId()
+	entryID := req.GetTimeEntryId()
+
+	if userID == 0 || entryID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或时间记录 ID")
+	}
+
+	var entry *model.TimeEntry
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		var err error
+		if entry, err = tx.GetTimeEntry(ctx, uint(userID), uint(entryID)); err != nil {
+			return err
+		}
+		if err := tx.DeleteTimeEntry(ctx, entry); err != nil {
+			return err
+		}
+		return refreshTrackedTime(ctx, tx, entry.UserID, entry.TodoID)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "时间记录未找到或无权删除")
+		}
+		log.Printf("删除时间记录 %d 失败: %v", entryID, err)
+		return nil, status.Errorf(codes.Internal, "删除时间记录失败")
+	}
+
+	log.Printf("时间记录 %d 删除成功", entryID)
+	s.cache.InvalidateUser(ctx, entry.UserID)
+	s.publishTodoUpdates(ctx, entry.UserID, []uint{entry.TodoID})
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) GetTimeReport(ctx context.Context, req *pb.GetTimeReportRequest) (*pb.TimeReport, error) {
+	log.Printf("Received GetTimeReport request for user_id: %d, group_by: %s", req.GetUserId(), req.GetGroupBy())
+	userID := req.GetUserId()
+	if userID == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	if req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "必须指定报表的起止时间")
+	}
+	from, to := req.GetFrom().AsTime(), req.GetTo().AsTime()
+	if !to.After(from) || to.Sub(from) > maxTimeReportRange {
+		return nil, status.Errorf(codes.InvalidArgument, "报表结束时间必须晚于开始时间，且范围不能超过 366 天")
+	}
+	groupBy := req.GetGroupBy()
+	if groupBy == pb.GetTimeReportRequest_GROUP_BY_UNSPECIFIED {
+		groupBy = pb.GetTimeReportRequest_DAY
+	}
+	if _, ok := pb.GetTimeReportRequest_GroupBy_name[int32(groupBy)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的分组方式: %d", groupBy)
+	}
+	loc, err := loadTimeZone(req.GetTimeZone())
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListTimeEntries(ctx, uint(userID), repository.TimeEntryFilter{From: &from, To: &to})
+	if err != nil {
+		log.Printf("获取用户 %d 的时间记录失败: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "生成时间报表失败")
+	}
+	// 按标签或项目分组需要 Todo 的标签以及整棵任务树 (已归档的 Todo 同样计入)
+	todos := make(map[uint]*model.Todo)
+	if groupBy != pb.GetTimeReportRequest_DAY && len(entries) > 0 {
+		list, err := s.repo.ListTodos(ctx, uint(userID), repository.TodoFilter{})
+		if err != nil {
+			log.Printf("获取用户 %d 的 Todos 失败: %v", userID, err)
+			return nil, status.Errorf(codes.Internal, "生成时间报表失败")
+		}
+		for _, todo := range list {
+			todos[todo.ID] = todo
+		}
+	}
+
+	now := time.Now()
+	rows := make(map[string]*pb.TimeReportRow)
+	addTo := func(key, label string, d time.Duration) {
+		row, ok := rows[key]
+		if !ok {
+			row = &pb.TimeReportRow{Key: key, Label: label, Total: durationpb.New(0)}
+			rows[key] = row
+		}
+		row.Total = durationpb.New(row.Total.AsDuration() + d)
+		row.EntryCount++
+	}
+	var total time.Duration
+	for _, entry := range entries {
+		start, end := entry.StartedAt, now
+		if entry.EndedAt != nil {
+			end = *entry.EndedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+		total += end.Sub(start)
+
+		switch groupBy {
+		case pb.GetTimeReportRequest_DAY:
+			// 跨天的记录按日期边界拆分
+			for dayStart := start; dayStart.Before(end); {
+				y, m, d := dayStart.In(loc).Date()
+				next := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+				if next.After(end) {
+					next = end
+				}
+				key := dayStart.In(loc).Format("2006-01-02")
+				addTo(key, key, next.Sub(dayStart))
+				dayStart = next
+			}
+		case pb.GetTimeReportRequest_TAG:
+			todo := todos[entry.TodoID]
+			if todo == nil || len(todo.Tags) == 0 {
+				addTo("", "(无标签)", end.Sub(start))
+				continue
+			}
+			for _, tag := range todo.Tags {
+				addTo(tag, tag, end.Sub(start))
+			}
+		case pb.GetTimeReportRequest_PROJECT:
+			root := projectOf(todos, entry.TodoID)
+			if root == nil {
+				addTo("", "(已删除)", end.Sub(start))
+				continue
+			}
+			addTo(strconv.FormatUint(uint64(root.ID), 10), root.Title, end.Sub(start))
+		}
+	}
+
+	report := &pb.TimeReport{
+		GroupBy: groupBy,
+		From:    timestamppb.New(from),
+		To:      timestamppb.New(to),
+		Total:   durationpb.New(total),
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if groupBy != pb.GetTimeReportRequest_DAY && a.Total.AsDuration() != b.Total.AsDuration() {
+			return a.Total.AsDuration() > b.Total.AsDuration()
+		}
+		return a.Key < b.Key
+	})
+	return report, nil
+}
+
+// projectOf 返回 Todo 所在任务树的根任务
+func projectOf(todos map[uint]*model.Todo, todoID uint) *model.Todo {
+	todo := todos[todoID]
+	for depth := 0; todo != nil && todo.ParentID != nil && depth < len(todos); depth++ {
+		parent := todos[*todo.ParentID]
+		if parent == nil {
+			break
+		}
+		todo = parent
+	}
+	return todo
+}
+
+// checkTodoOwner 检查 Todo 是否存在且属于该用户
+func (s *server) checkTodoOwner(ctx context.Context, userID, todoID uint32) error {
+	if _, err := s.repo.GetTodo(ctx, uint(userID), uint(todoID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return status.Errorf(codes.NotFound, "待办事项未找到或无权访问")
+		}
+		log.Printf("查找 Todo %d 失败: %v", todoID, err)
+		return status.Errorf(codes.Internal, "获取待办事项失败")
+	}
+	return nil
+}
+
+// findRunningTimer 返回用户正在运行的计时器，没有时返回 nil
+func findRunningTimer(ctx context.Context, repo repository.TodoRepository, userID uint32) (*model.TimeEntry, error) {
+	entry, err := repo.FindRunningTimer(ctx, uint(userID))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// stopTimeEntry 在 now 停止计时器并更新所属 Todo 的累计时长
+func stopTimeEntry(ctx context.Context, tx repository.TodoRepository, entry *model.TimeEntry, now time.Time) error {
+	if now.Before(entry.StartedAt) {
+		now = entry.StartedAt
+	}
+	entry.EndedAt = &now
+	entry.DurationSeconds = int64(now.Sub(entry.StartedAt) / time.Second)
+	entry.RunningUserID = nil
+	if err := tx.SaveTimeEntry(ctx, entry); err != nil {
+		return err
+	}
+	return refreshTrackedTime(ctx, tx, entry.UserID, entry.TodoID)
+}
+
+// refreshTrackedTime 重新计算 Todo 已结束时间记录的总时长。
+// 时长不是用户直接编辑的字段，因此不更新 updated_at，但仍分配新的变更序号以便离线客户端同步
+func refreshTrackedTime(ctx context.Context, tx repository.TodoRepository, userID, todoID uint) error {
+	seq, err := tx.NextChangeSeq(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return tx.RefreshTrackedSeconds(ctx, todoID, seq)
+}
+
+// validateTimeRange 校验时间记录的起止时间，running 为 true 时不允许设置结束时间
+func validateTimeRange(startedAt, endedAt *timestamppb.Timestamp, running bool) (time.Time, *time.Time, error) {
+	if startedAt == nil || !startedAt.IsValid() {
+		return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "必须指定开始时间")
+	}
+	start := startedAt.AsTime()
+	latest := time.Now().Add(clockSkewTolerance)
+	if start.After(latest) {
+		return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "开始时间不能晚于当前时间")
+	}
+	if running {
+		if endedAt != nil {
+			return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "正在运行的计时器不能设置结束时间，请先停止计时器")
+		}
+		return start, nil, nil
+	}
+	if endedAt == nil || !endedAt.IsValid() {
+		return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "必须指定结束时间")
+	}
+	end := endedAt.AsTime()
+	if !end.After(start) {
+		return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "结束时间必须晚于开始时间")
+	}
+	if end.After(latest) {
+		return time.Time{}, nil, status.Errorf(codes.InvalidArgument, "结束时间不能晚于当前时间")
+	}
+	return start, &end, nil
+}
+
+func validateTimeEntryNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxTimeEntryNoteLength {
+		return "", status.Errorf(codes.InvalidArgument, "备注不能超过 %d 个字符", maxTimeEntryNoteLength)
+	}
+	return note, nil
+}
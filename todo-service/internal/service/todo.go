@@ -3,19 +3,21 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"todo-project/todo-service/internal/cache"
+	"todo-project/todo-service/internal/events"
 	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
 	"todo-project/todo-service/internal/util"
 	pb "todo-project/todo-service/proto/todo"
 
-	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"gorm.io/gorm"
 )
 
 // 定义缓存持续时间
@@ -23,14 +25,15 @@ const cacheDuration = 10 * time.Minute
 
 // server 实现了 pb.TodoServiceServer 接口
 type server struct {
-	db  *gorm.DB
-	rdb *redis.Client
+	repo  repository.TodoRepository
+	cache *cache.Cache
+	feed  *events.Feed
 	pb.UnimplementedTodoServiceServer
 }
 
-// NewTodoService 创建一个新的 TodoService
-func NewTodoService(db *gorm.DB, rdb *redis.Client) pb.TodoServiceServer {
-	return &server{db: db, rdb: rdb}
+// NewTodoService 创建一个新的 TodoService，读请求经过 todoCache 缓存，Todo 的变更会发布到 feed
+func NewTodoService(repo repository.TodoRepository, todoCache *cache.Cache, feed *events.Feed) pb.TodoServiceServer {
+	return &server{repo: repo, cache: todoCache, feed: feed}
 }
 
 // 实现 gRPC 方法
@@ -40,29 +43,65 @@ func (s *server) CreateTodo(ctx context.Context, req *pb.CreateTodoRequest) (*pb
 	if req.GetUserId() == 0 || req.GetTitle() == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "用户 ID 和标题不能为空")
 	}
+	if _, ok := pb.Priority_name[int32(req.GetPriority())]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的优先级: %d", req.GetPriority())
+	}
 
 	newTodo := model.Todo{
 		UserID:      uint(req.GetUserId()),
 		Title:       req.GetTitle(),
 		Description: req.GetDescription(),
 		Completed:   false,
+		Tags:        util.NormalizeTags(req.GetTags()),
+		Checklist:   util.ConvertFromProtoChecklist(req.GetChecklist()),
+		Priority:    int(req.GetPriority()),
+	}
+	if req.GetDueAt() != nil {
+		dueAt := req.GetDueAt().AsTime()
+		newTodo.DueAt = &dueAt
+	}
+	if recurrence := req.GetRecurrence(); recurrence != nil && recurrence.GetFrequency() != pb.Recurrence_FREQUENCY_UNSPECIFIED {
+		if _, ok := pb.Recurrence_Frequency_name[int32(recurrence.GetFrequency())]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "无效的重复频率: %d", recurrence.GetFrequency())
+		}
+		newTodo.RecurrenceFrequency = int(recurrence.GetFrequency())
+		newTodo.RecurrenceInterval = int(recurrence.GetInterval())
+		if newTodo.RecurrenceInterval == 0 {
+			newTodo.RecurrenceInterval = 1
+		}
 	}
 
-	result := s.db.Create(&newTodo)
-	if result.Error != nil {
-		log.Printf("创建 Todo 失败: %v", result.Error)
-		return nil, status.Errorf(codes.Internal, "创建 Todo 失败")
+	// 父任务必须存在且属于同一用户
+	if parentID := req.GetParentId(); parentID != 0 {
+		parent, err := s.repo.GetTodo(ctx, uint(req.GetUserId()), uint(parentID))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, status.Errorf(codes.InvalidArgument, "父任务不存在或无权访问")
+			}
+			log.Printf("查找父任务 %d 失败: %v", parentID, err)
+			return nil, status.Errorf(codes.Internal, "创建 Todo 失败")
+		}
+		newTodo.ParentID = &parent.ID
 	}
 
-	log.Printf("Todo 创建成功: ID=%d", newTodo.ID)
-	userCacheKey := fmt.Sprintf("user_todos:%d", newTodo.UserID)
-	err := s.rdb.Del(ctx, userCacheKey).Err()
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		seq, err := tx.NextChangeSeq(ctx, newTodo.UserID)
+		if err != nil {
+			return err
+		}
+		newTodo.ChangeSeq = seq
+		return tx.CreateTodo(ctx, &newTodo)
+	})
 	if err != nil {
-		log.Printf("警告: 清除用户 %d 的 Todos 列表缓存 (%s) 失败: %v", newTodo.UserID, userCacheKey, err)
-	} else {
-		log.Printf("Redis 用户 Todos 列表缓存已清除: %s", userCacheKey)
+		log.Printf("创建 Todo 失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "创建 Todo 失败")
 	}
 
+	log.Printf("Todo 创建成功: ID=%d", newTodo.ID)
+	s.cache.InvalidateUser(ctx, newTodo.UserID)
+
+	s.publishTodoEvents(ctx, newTodo.UserID, pb.TodoEvent_CREATED, []*model.Todo{&newTodo})
+
 	return util.ConvertToProtoTodo(&newTodo), nil
 }
 
@@ -73,49 +112,29 @@ func (s *server) GetTodos(ctx context.Context, req *pb.GetTodosRequest) (*pb.Get
 		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID")
 	}
 
-	cacheKey := fmt.Sprintf("user_todos:%d", userID)
-
-	// 尝试从 Redis 读取缓存
-	cachedTodosJSON, err := s.rdb.Get(ctx, cacheKey).Result()
-	if err == nil { // 缓存命中
-		var cachedTodos []*pb.Todo
-		if unmarshalErr := json.Unmarshal([]byte(cachedTodosJSON), &cachedTodos); unmarshalErr == nil {
-			log.Printf("从 Redis 缓存获取用户 %d 的 Todos 成功 (%d 条)", userID, len(cachedTodos))
-			return &pb.GetTodosResponse{Todos: cachedTodos}, nil
-		} else { // 将日志记录移到此 else 块中
-			// 反序列化失败，记录日志并继续从数据库读取
-			log.Printf("警告: 反序列化用户 %d 的 Todos 缓存失败: %v。将从数据库获取。", userID, unmarshalErr)
+	// 缓存中只保存未归档的列表，包含已归档 Todo 的请求直接查询数据库
+	if req.GetIncludeArchived() {
+		todos, err := s.repo.ListTodos(ctx, uint(userID), repository.TodoFilter{})
+		if err != nil {
+			log.Printf("获取 Todos (含已归档) 失败 for user %d: %v", userID, err)
+			return nil, status.Errorf(codes.Internal, "获取待办事项失败")
 		}
-	} else if err != redis.Nil { // Redis 出错 (非 key 不存在)
-		log.Printf("警告: 从 Redis 获取用户 %d 的 Todos 缓存失败: %v。将从数据库获取。", userID, err)
-	} else { // 缓存未命中 (err == redis.Nil)
-		log.Printf("用户 %d 的 Todos 缓存未命中: %s。将从数据库获取。", userID, cacheKey)
+		log.Printf("找到 %d 个 Todos (含已归档) for user %d (从数据库)", len(todos), userID)
+		return &pb.GetTodosResponse{Todos: util.ConvertToProtoTodos(todos)}, nil
 	}
 
-	var todos []*model.Todo
-	result := s.db.Where("user_id = ?", userID).Find(&todos)
-	if result.Error != nil {
-		log.Printf("获取 Todos 失败 for user %d: %v", userID, result.Error)
-		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
-	}
-
-	// 将从数据库获取的数据转换为 Protobuf 格式
-	protoTodos := util.ConvertToProtoTodos(todos)
-
-	// 尝试将结果写入 Redis 缓存
-	todosJSON, errMarshal := json.Marshal(protoTodos)
-	if errMarshal == nil {
-		errSet := s.rdb.Set(ctx, cacheKey, todosJSON, cacheDuration).Err()
-		if errSet != nil {
-			log.Printf("警告: 写入用户 %d 的 Todos (%d 条) 到 Redis 缓存 (%s) 失败: %v", userID, len(protoTodos), cacheKey, errSet)
-		} else {
-			log.Printf("用户 %d 的 Todos (%d 条) 已写入 Redis 缓存: %s", userID, len(protoTodos), cacheKey)
+	protoTodos, err := cache.Fetch(ctx, s.cache, uint(userID), "todos", cacheDuration, func(ctx context.Context) ([]*pb.Todo, error) {
+		todos, err := s.repo.ListTodos(ctx, uint(userID), repository.TodoFilter{ExcludeArchived: true})
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		log.Printf("警告: 序列化用户 %d 的 Todos 以进行缓存失败: %v", userID, errMarshal)
+		log.Printf("找到 %d 个 Todos for user %d (从数据库)", len(todos), userID)
+		return util.ConvertToProtoTodos(todos), nil
+	})
+	if err != nil {
+		log.Printf("获取 Todos 失败 for user %d: %v", userID, err)
+		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
 	}
-
-	log.Printf("找到 %d 个 Todos for user %d (从数据库)", len(todos), userID)
 	return &pb.GetTodosResponse{Todos: protoTodos}, nil
 }
 
@@ -128,57 +147,23 @@ func (s *server) GetTodoByID(ctx context.Context, req *pb.GetTodoByIDRequest) (*
 		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
 	}
 
-	cacheKey := fmt.Sprintf("todo:%d", todoID)
-
-	// 尝试从 Redis 读取缓存
-	cachedTodoJSON, err := s.rdb.Get(ctx, cacheKey).Result()
-	if err == nil { // 缓存命中
-		var cachedTodo pb.Todo
-		if unmarshalErr := json.Unmarshal([]byte(cachedTodoJSON), &cachedTodo); unmarshalErr == nil {
-			// 关键: 验证缓存的 Todo 是否属于请求的用户
-			if cachedTodo.GetUserId() == userID {
-				log.Printf("从 Redis 缓存获取 Todo %d (用户 %d) 成功", todoID, userID)
-				return &cachedTodo, nil
-			}
-			log.Printf("警告: 缓存的 Todo %d (属于用户 %d) 与请求用户 %d 不匹配。将从数据库获取。", todoID, cachedTodo.GetUserId(), userID)
-			// 用户不匹配，视为缓存未命中，将从数据库重新验证和获取
-		} else {
-			log.Printf("警告: 反序列化 Todo %d 缓存失败: %v。将从数据库获取。", todoID, unmarshalErr)
+	// 缓存键属于请求的用户，其他用户的 Todo 不会从缓存中返回
+	protoTodo, err := cache.Fetch(ctx, s.cache, uint(userID), fmt.Sprintf("todo:%d", todoID), cacheDuration, func(ctx context.Context) (*pb.Todo, error) {
+		todo, err := s.repo.GetTodo(ctx, uint(userID), uint(todoID))
+		if err != nil {
+			return nil, err
 		}
-	} else if err != redis.Nil { // Redis 出错 (非 key 不存在)
-		log.Printf("警告: 从 Redis 获取 Todo %d 缓存失败: %v。将从数据库获取。", todoID, err)
-	} else { // 缓存未命中 (err == redis.Nil)
-		log.Printf("Todo %d 缓存未命中: %s。将从数据库获取。", todoID, cacheKey)
-	}
-
-	var todo model.Todo
-	dbErr := s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error
-	if dbErr != nil {
-		if dbErr == gorm.ErrRecordNotFound {
+		log.Printf("找到 Todo: ID=%d (从数据库)", todo.ID)
+		return util.ConvertToProtoTodo(todo), nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			log.Printf("Todo 未找到: user_id=%d, todo_id=%d", userID, todoID)
 			return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权访问")
 		}
-		log.Printf("获取 Todo %d 失败 for user %d: %v", todoID, userID, dbErr)
+		log.Printf("获取 Todo %d 失败 for user %d: %v", todoID, userID, err)
 		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
 	}
-
-	// 将从数据库获取的数据转换为 Protobuf 格式
-	protoTodo := util.ConvertToProtoTodo(&todo)
-
-	// 尝试将结果写入 Redis 缓存
-	todoJSON, errMarshal := json.Marshal(protoTodo)
-	if errMarshal == nil {
-		errSet := s.rdb.Set(ctx, cacheKey, todoJSON, cacheDuration).Err()
-		if errSet != nil {
-			log.Printf("警告: 写入 Todo %d 到 Redis 缓存 (%s) 失败: %v", todoID, cacheKey, errSet)
-		} else {
-			log.Printf("Todo %d 已写入 Redis 缓存: %s", todoID, cacheKey)
-		}
-	} else {
-		log.Printf("警告: 序列化 Todo %d 以进行缓存失败: %v", todoID, errMarshal)
-	}
-
-	log.Printf("找到 Todo: ID=%d (从数据库)", todo.ID)
 	return protoTodo, nil
 }
 
@@ -191,63 +176,46 @@ func (s *server) UpdateTodo(ctx context.Context, req *pb.UpdateTodoRequest) (*pb
 		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
 	}
 
-	// 查找原始 Todo 以进行权限检查
-	var originalTodo model.Todo
-	err := s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&originalTodo).Error
+	// 在事务中重新读取 Todo：分配序号时锁住了用户的计数器，读到的版本在提交前不会被其他请求修改
+	var updatedTodo *model.Todo
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		seq, err := tx.NextChangeSeq(ctx, uint(userID))
+		if err != nil {
+			return err
+		}
+		todo, err := tx.GetTodo(ctx, uint(userID), uint(todoID))
+		if err != nil {
+			return err
+		}
+		// 记录完成时间：从未完成变为完成时设置，重新打开时清除
+		if req.GetCompleted() && !todo.Completed {
+			now := time.Now()
+			todo.CompletedAt = &now
+		} else if !req.GetCompleted() {
+			todo.CompletedAt = nil
+		}
+		todo.Title = req.GetTitle()
+		todo.Description = req.GetDescription()
+		todo.Completed = req.GetCompleted()
+		todo.ChangeSeq = seq
+		updatedTodo = todo
+		return tx.SaveTodo(ctx, todo)
+	})
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, repository.ErrNotFound) {
 			log.Printf("更新时 Todo 未找到: user_id=%d, todo_id=%d", userID, todoID)
 			return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权更新")
 		}
-		log.Printf("查找待更新 Todo 失败: %v", err)
-		return nil, status.Errorf(codes.Internal, "获取待办事项失败")
-	}
-
-	// 构建更新映射，只更新请求中提供的字段
-	updates := map[string]interface{}{
-		"title":       req.GetTitle(),
-		"description": req.GetDescription(),
-		"completed":   req.GetCompleted(),
-		// 更新时间会自动处理
-	}
-
-	// 执行更新
-	result := s.db.Model(&originalTodo).Updates(updates)
-	if result.Error != nil {
-		log.Printf("更新 Todo %d 失败: %v", todoID, result.Error)
+		log.Printf("更新 Todo %d 失败: %v", todoID, err)
 		return nil, status.Errorf(codes.Internal, "更新待办事项失败")
 	}
-	if result.RowsAffected == 0 {
-		// 理论上不应该发生，因为我们已经先查到了记录
-		log.Printf("更新 Todo %d 时影响行数为 0", todoID)
-		// 可以选择返回 NotFound 或 Internal 错误
-		return nil, status.Errorf(codes.Internal, "更新失败，记录可能已不存在")
-	}
 
 	log.Printf("Todo %d 更新成功", todoID)
-	// 清除相关 Redis 缓存 (用户列表和单个 Todo 缓存)
-	userCacheKey := fmt.Sprintf("user_todos:%d", req.GetUserId())
-	todoCacheKey := fmt.Sprintf("todo:%d", todoID)
-
-	errDelTodo := s.rdb.Del(ctx, todoCacheKey).Err()
-	if errDelTodo != nil {
-		log.Printf("警告: 清除单个 Todo %d 的缓存 (%s) 失败: %v", todoID, todoCacheKey, errDelTodo)
-	} else {
-		log.Printf("Redis 单个 Todo 缓存已清除: %s", todoCacheKey)
-	}
+	s.cache.InvalidateUser(ctx, uint(userID))
 
-	errDelUserTodos := s.rdb.Del(ctx, userCacheKey).Err()
-	if errDelUserTodos != nil {
-		log.Printf("警告: 清除用户 %d 的 Todos 列表缓存 (%s) 失败: %v", req.GetUserId(), userCacheKey, errDelUserTodos)
-	} else {
-		log.Printf("Redis 用户 Todos 列表缓存已清除: %s", userCacheKey)
-	}
+	s.publishTodoEvents(ctx, updatedTodo.UserID, pb.TodoEvent_UPDATED, []*model.Todo{updatedTodo})
 
-	// 返回更新后的 Todo (从数据库重新获取以确保数据最新)
-	var updatedTodo model.Todo
-	s.db.First(&updatedTodo, todoID)
-
-	return util.ConvertToProtoTodo(&updatedTodo), nil
+	return util.ConvertToProtoTodo(updatedTodo), nil
 }
 
 func (s *server) DeleteTodo(ctx context.Context, req *pb.DeleteTodoRequest) (*emptypb.Empty, error) {
@@ -259,37 +227,36 @@ func (s *server) DeleteTodo(ctx context.Context, req *pb.DeleteTodoRequest) (*em
 		return nil, status.Errorf(codes.InvalidArgument, "无效的用户 ID 或 Todo ID")
 	}
 
-	// 直接尝试删除，GORM 的 Delete 会返回影响的行数
-	result := s.db.Where("id = ? AND user_id = ?", todoID, userID).Delete(&model.Todo{})
+	// 删除 Todo，并将其子任务提升到被删除 Todo 的父任务下，避免产生悬空的 parent_id
+	var childIDs []uint
+	err := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+		todo, err := tx.GetTodo(ctx, uint(userID), uint(todoID))
+		if err != nil {
+			return err
+		}
+		seq, err := tx.NextChangeSeq(ctx, todo.UserID)
+		if err != nil {
+			return err
+		}
+		childIDs, err = deleteTodo(ctx, tx, todo, seq)
+		return err
+	})
 
-	if result.Error != nil {
-		log.Printf("删除 Todo %d 失败: %v", todoID, result.Error)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Printf("删除时 Todo 未找到: user_id=%d, todo_id=%d", userID, todoID)
+			return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权删除")
+		}
+		log.Printf("删除 Todo %d 失败: %v", todoID, err)
 		return nil, status.Errorf(codes.Internal, "删除待办事项失败")
 	}
 
-	if result.RowsAffected == 0 {
-		log.Printf("删除时 Todo 未找到: user_id=%d, todo_id=%d", userID, todoID)
-		return nil, status.Errorf(codes.NotFound, "待办事项未找到或无权删除")
-	}
-
 	log.Printf("Todo %d 删除成功", todoID)
-	// 清除相关 Redis 缓存
-	userCacheKey := fmt.Sprintf("user_todos:%d", userID)
-	todoCacheKey := fmt.Sprintf("todo:%d", todoID)
-
-	errDelTodo := s.rdb.Del(ctx, todoCacheKey).Err()
-	if errDelTodo != nil {
-		log.Printf("警告: 清除单个 Todo %d 的缓存 (%s) 失败: %v", todoID, todoCacheKey, errDelTodo)
-	} else {
-		log.Printf("Redis 单个 Todo 缓存已清除: %s", todoCacheKey)
-	}
+	// 被提升的子任务 parent_id 已变化，使整个用户的缓存失效也会清除它们
+	s.cache.InvalidateUser(ctx, uint(userID))
 
-	errDelUserTodos := s.rdb.Del(ctx, userCacheKey).Err()
-	if errDelUserTodos != nil {
-		log.Printf("警告: 清除用户 %d 的 Todos 列表缓存 (%s) 失败: %v", userID, userCacheKey, errDelUserTodos)
-	} else {
-		log.Printf("Redis 用户 Todos 列表缓存已清除: %s", userCacheKey)
-	}
+	s.publishTodoDeletes(ctx, uint(userID), []uint{uint(todoID)})
+	s.publishTodoUpdates(ctx, uint(userID), childIDs)
 
 	return &emptypb.Empty{}, nil
 }
@@ -319,36 +286,60 @@ func (s *server) BatchUpdateTodos(ctx context.Context, req *pb.BatchUpdateTodosR
 		log.Printf("序列化 affected_todo_ids 失败: %v", err)
 		// 即使序列化失败，也尝试记录日志，但 affected_todo_ids 可能为空
 		operationLog.Details = fmt.Sprintf("序列化 affected_todo_ids 失败: %v", err)
-		s.db.Create(&operationLog) // 尽力记录
+		s.repo.CreateBatchOperationLog(ctx, &operationLog) // 尽力记录
 		return nil, status.Errorf(codes.Internal, "处理请求失败: 序列化ID失败")
 	}
-	operationLog.AffectedTodoIDs = string(affectedTodoIDsBytes)
+	operationLog.AffectedTodoIDs = model.JSON(affectedTodoIDsBytes)
 
-	dbErr := s.db.Transaction(func(tx *gorm.DB) error {
-		var updates map[string]interface{}
+	dbErr := s.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
 		var operationDetail string
 		var actualAffectedRows int64
 
 		switch action {
 		case pb.BatchUpdateTodosRequest_MARK_AS_COMPLETED:
-			updates = map[string]interface{}{"completed": true}
 			operationDetail = "批量标记完成"
 		case pb.BatchUpdateTodosRequest_MARK_AS_INCOMPLETE:
-			updates = map[string]interface{}{"completed": false}
 			operationDetail = "批量标记未完成"
 		default:
 			return status.Errorf(codes.InvalidArgument, "无效的批量操作类型: %s", action.String())
 		}
 
-		// 执行批量更新
-		// GORM 的 Where 子句接受 []uint32，不需要手动转换
-		result := tx.Model(&model.Todo{}).Where("id IN ? AND user_id = ?", todoIDs, userID).Updates(updates)
-		if result.Error != nil {
-			log.Printf("事务内：批量更新 Todos 失败 for user %d: %v", userID, result.Error)
-			operationLog.Details = fmt.Sprintf("数据库更新失败: %v", result.Error)
-			return result.Error // 这会回滚事务
+		seq, err := tx.NextChangeSeq(ctx, uint(userID))
+		if err != nil {
+			return err
+		}
+
+		// 只会加载属于该用户的 Todo
+		ids := make([]uint, len(todoIDs))
+		for i, id := range todoIDs {
+			ids[i] = uint(id)
+		}
+		todos, err := tx.ListTodos(ctx, uint(userID), repository.TodoFilter{IDs: ids})
+		if err != nil {
+			log.Printf("事务内：加载待批量更新的 Todos 失败 for user %d: %v", userID, err)
+			operationLog.Details = fmt.Sprintf("数据库查询失败: %v", err)
+			return err
 		}
-		actualAffectedRows = result.RowsAffected
+		now := time.Now()
+		for _, todo := range todos {
+			if action == pb.BatchUpdateTodosRequest_MARK_AS_COMPLETED {
+				todo.Completed = true
+				// 已完成的 Todo 保留原来的完成时间
+				if todo.CompletedAt == nil {
+					todo.CompletedAt = &now
+				}
+			} else {
+				todo.Completed = false
+				todo.CompletedAt = nil
+			}
+			todo.ChangeSeq = seq
+			if err := tx.SaveTodo(ctx, todo); err != nil {
+				log.Printf("事务内：批量更新 Todos 失败 for user %d: %v", userID, err)
+				operationLog.Details = fmt.Sprintf("数据库更新失败: %v", err)
+				return err // 这会回滚事务
+			}
+		}
+		actualAffectedRows = int64(len(todos))
 
 		log.Printf("事务内：用户 %d 的批量操作 '%s' 影响了 %d 行 (请求 %d 个 IDs)", userID, operationDetail, actualAffectedRows, len(todoIDs))
 
@@ -367,7 +358,7 @@ func (s *server) BatchUpdateTodos(ctx context.Context, req *pb.BatchUpdateTodosR
 		}
 
 		// 创建批量操作日志条目
-		if err := tx.Create(&operationLog).Error; err != nil {
+		if err := tx.CreateBatchOperationLog(ctx, &operationLog); err != nil {
 			log.Printf("事务内：创建批量操作日志失败: %v", err)
 			// 这个错误也应该回滚整个事务
 			return err
@@ -388,23 +379,39 @@ func (s *server) BatchUpdateTodos(ctx context.Context, req *pb.BatchUpdateTodosR
 	}
 
 	// 事务成功，现在清理 Redis 缓存
-	log.Printf("批量操作事务成功 for user %d. 清理相关缓存...", userID)
-	userCacheKey := fmt.Sprintf("user_todos:%d", userID)
-	if rdbErr := s.rdb.Del(ctx, userCacheKey).Err(); rdbErr != nil {
-		log.Printf("警告: 批量操作后清除用户 %d 列表缓存 (%s) 失败: %v", userID, userCacheKey, rdbErr)
-	} else {
-		log.Printf("批量操作后 Redis 用户 %d 列表缓存已清除: %s", userID, userCacheKey)
+	s.cache.InvalidateUser(ctx, uint(userID))
+
+	log.Printf("用户 %d 的批量操作 (%s) 成功完成并已清理缓存。日志状态: %s", userID, action.String(), operationLog.Status)
+	changedIDs := make([]uint, len(todoIDs))
+	for i, id := range todoIDs {
+		changedIDs[i] = uint(id)
 	}
+	s.publishTodoUpdates(ctx, uint(userID), changedIDs)
+	return &emptypb.Empty{}, nil
+}
 
-	for _, singleTodoID := range todoIDs {
-		todoCacheKey := fmt.Sprintf("todo:%d", singleTodoID)
-		if rdbErr := s.rdb.Del(ctx, todoCacheKey).Err(); rdbErr != nil {
-			log.Printf("警告: 批量操作后清除 Todo %d 缓存 (%s) 失败: %v", singleTodoID, todoCacheKey, rdbErr)
-		} else {
-			log.Printf("批量操作后 Redis Todo %d 缓存已清除: %s", singleTodoID, todoCacheKey)
+// deleteTodo 在事务中删除 Todo 及其时间记录并写入墓碑，子任务提升到被删除 Todo 的父任务下，
+// 避免产生悬空的 parent_id。seq 为事务分配的变更序号，返回被提升的子任务 ID
+func deleteTodo(ctx context.Context, tx repository.TodoRepository, todo *model.Todo, seq uint64) ([]uint, error) {
+	children, err := tx.ListTodos(ctx, todo.UserID, repository.TodoFilter{ParentIDs: []uint{todo.ID}})
+	if err != nil {
+		return nil, err
+	}
+	var childIDs []uint
+	for _, child := range children {
+		child.ParentID = todo.ParentID
+		child.ChangeSeq = seq
+		if err := tx.SaveTodo(ctx, child); err != nil {
+			return nil, err
 		}
+		childIDs = append(childIDs, child.ID)
 	}
-
-	log.Printf("用户 %d 的批量操作 (%s) 成功完成并已清理缓存。日志状态: %s", userID, action.String(), operationLog.Status)
-	return &emptypb.Empty{}, nil
+	if err := tx.DeleteTimeEntriesOfTodo(ctx, todo.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.DeleteTodo(ctx, todo); err != nil {
+		return nil, err
+	}
+	tombstone := &model.TodoTombstone{TodoID: todo.ID, UserID: todo.UserID, ChangeSeq: seq, ClientID: todo.ClientID}
+	return childIDs, tx.CreateTombstone(ctx, tombstone)
 }
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"todo-project/todo-service/internal/events"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *server) WatchTodos(req *pb.WatchTodosRequest, stream grpc.ServerStreamingServer[pb.TodoEvent]) error {
+	log.Printf("Received WatchTodos request for user_id: %d, resume_token: %s", req.GetUserId(), req.GetResumeToken())
+	userID := uint(req.GetUserId())
+	if userID == 0 {
+		return status.Errorf(codes.InvalidArgument, "无效的用户 ID")
+	}
+	token := req.GetResumeToken()
+	if token != "" && events.ValidateToken(token) != nil {
+		return status.Errorf(codes.InvalidArgument, "无效的 resume_token: %s", token)
+	}
+	ctx := stream.Context()
+
+	// 先订阅再读取 Stream，补读期间到达的实时事件会留在缓冲中，按令牌去重
+	sub := s.feed.Subscribe(userID)
+	defer sub.Close()
+
+	last := token
+	catchUp := func() error {
+		replay, err := s.feed.Since(ctx, userID, last)
+		if errors.Is(err, events.ErrTokenExpired) {
+			latest, err := s.feed.Latest(ctx, userID)
+			if err != nil {
+				return err
+			}
+			log.Printf("用户 %d 的 resume_token %s 已过期，要求客户端重新加载", userID, last)
+			last = latest
+			return stream.Send(&pb.TodoEvent{Type: pb.TodoEvent_RESET, ResumeToken: latest, OccurredAt: timestamppb.Now()})
+		}
+		if err != nil {
+			return err
+		}
+		for _, event := range replay {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+			last = event.ResumeToken
+		}
+		return nil
+	}
+
+	var err error
+	if last == "" {
+		last, err = s.feed.Latest(ctx, userID)
+	} else {
+		err = catchUp()
+	}
+	if err == nil {
+		err = stream.Send(&pb.TodoEvent{Type: pb.TodoEvent_READY, ResumeToken: last, OccurredAt: timestamppb.Now()})
+	}
+
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			log.Printf("用户 %d 的 WatchTodos 已断开", userID)
+			return nil
+		case event := <-sub.Events():
+			if events.CompareTokens(event.ResumeToken, last) <= 0 {
+				continue
+			}
+			if err = stream.Send(event); err == nil {
+				last = event.ResumeToken
+			}
+		case <-sub.Gaps():
+			err = catchUp()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	log.Printf("用户 %d 的 WatchTodos 失败: %v", userID, err)
+	return status.Errorf(codes.Unavailable, "读取变更事件失败，请稍后使用 resume_token 重新订阅")
+}
+
+// publishTodoEvents 发布 Todo 的 CREATED 或 UPDATED 事件。
+// 与缓存一样，发布失败只记录日志，不影响已完成的写操作
+func (s *server) publishTodoEvents(ctx context.Context, userID uint, eventType pb.TodoEvent_Type, todos []*model.Todo) {
+	if len(todos) == 0 || !s.feedAvailable(userID, len(todos)) {
+		return
+	}
+	now := timestamppb.New(time.Now())
+	todoEvents := make([]*pb.TodoEvent, len(todos))
+	for i, todo := range todos {
+		todoEvents[i] = &pb.TodoEvent{Type: eventType, TodoId: uint32(todo.ID), Todo: util.ConvertToProtoTodo(todo), OccurredAt: now}
+	}
+	if err := s.feed.Publish(ctx, userID, todoEvents...); err != nil {
+		log.Printf("警告: 发布用户 %d 的 %s 事件 (%d 个) 失败: %v", userID, eventType, len(todoEvents), err)
+	}
+}
+
+// publishTodoUpdates 重新加载指定的 Todo 并发布 UPDATED 事件
+func (s *server) publishTodoUpdates(ctx context.Context, userID uint, todoIDs []uint) {
+	if len(todoIDs) == 0 || !s.feedAvailable(userID, len(todoIDs)) {
+		return
+	}
+	todos, err := s.repo.ListTodos(ctx, userID, repository.TodoFilter{IDs: todoIDs})
+	if err != nil {
+		log.Printf("警告: 加载用户 %d 的 Todo 以发布事件失败: %v", userID, err)
+		return
+	}
+	s.publishTodoEvents(ctx, userID, pb.TodoEvent_UPDATED, todos)
+}
+
+// publishTodoDeletes 发布 DELETED 事件
+func (s *server) publishTodoDeletes(ctx context.Context, userID uint, todoIDs []uint) {
+	if len(todoIDs) == 0 || !s.feedAvailable(userID, len(todoIDs)) {
+		return
+	}
+	now := timestamppb.New(time.Now())
+	todoEvents := make([]*pb.TodoEvent, len(todoIDs))
+	for i, id := range todoIDs {
+		todoEvents[i] = &pb.TodoEvent{Type: pb.TodoEvent_DELETED, TodoId: uint32(id), OccurredAt: now}
+	}
+	if err := s.feed.Publish(ctx, userID, todoEvents...); err != nil {
+		log.Printf("警告: 发布用户 %d 的删除事件失败: %v", userID, err)
+	}
+}
+
+// feedAvailable 报告是否应发布事件。变更流与缓存使用同一个 Redis，缓存熔断期间跳过发布，
+// 避免每次写操作都同步等待 Redis 超时。跳过的事件不会写入 Stream，客户端通过增量同步获取这些变更
+func (s *server) feedAvailable(userID uint, count int) bool {
+	if s.cache.Available() {
+		return true
+	}
+	log.Printf("警告: Redis 已熔断，跳过发布用户 %d 的 %d 个事件", userID, count)
+	return false
+}
@@ -1,14 +1,18 @@
 package util
 
 import (
+	"strings"
+	"time"
+
 	"todo-project/todo-service/internal/model"
 	pb "todo-project/todo-service/proto/todo"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func ConvertToProtoTodo(todoModel *model.Todo) *pb.Todo {
-	return &pb.Todo{
+	protoTodo := &pb.Todo{
 		Id:          uint32(todoModel.ID),
 		UserId:      uint32(todoModel.UserID),
 		Title:       todoModel.Title,
@@ -16,7 +20,29 @@ func ConvertToProtoTodo(todoModel *model.Todo) *pb.Todo {
 		Completed:   todoModel.Completed,
 		CreatedAt:   timestamppb.New(todoModel.CreatedAt),
 		UpdatedAt:   timestamppb.New(todoModel.UpdatedAt),
+		Tags:        todoModel.Tags,
+		Checklist:   ConvertToProtoChecklist(todoModel.Checklist),
+		Priority:    pb.Priority(todoModel.Priority),
+		Recurrence:  ConvertToProtoRecurrence(todoModel.RecurrenceFrequency, todoModel.RecurrenceInterval),
+		TrackedTime: durationpb.New(time.Duration(todoModel.TrackedSeconds) * time.Second),
+		ChangeSeq:   todoModel.ChangeSeq,
+	}
+	if todoModel.ClientID != nil {
+		protoTodo.ClientId = *todoModel.ClientID
+	}
+	if todoModel.ParentID != nil {
+		protoTodo.ParentId = uint32(*todoModel.ParentID)
+	}
+	if todoModel.DueAt != nil {
+		protoTodo.DueAt = timestamppb.New(*todoModel.DueAt)
+	}
+	if todoModel.CompletedAt != nil {
+		protoTodo.CompletedAt = timestamppb.New(*todoModel.CompletedAt)
 	}
+	if todoModel.ArchivedAt != nil {
+		protoTodo.ArchivedAt = timestamppb.New(*todoModel.ArchivedAt)
+	}
+	return protoTodo
 }
 
 func ConvertToProtoTodos(todoModels []*model.Todo) []*pb.Todo {
@@ -26,3 +52,130 @@ func ConvertToProtoTodos(todoModels []*model.Todo) []*pb.Todo {
 	}
 	return protoTodos
 }
+
+func ConvertToProtoChecklist(items []model.ChecklistItem) []*pb.ChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+	protoItems := make([]*pb.ChecklistItem, len(items))
+	for i, item := range items {
+		protoItems[i] = &pb.ChecklistItem{Content: item.Content, Done: item.Done}
+	}
+	return protoItems
+}
+
+// ConvertFromProtoChecklist 转换检查清单，忽略内容为空的条目
+func ConvertFromProtoChecklist(protoItems []*pb.ChecklistItem) []model.ChecklistItem {
+	var items []model.ChecklistItem
+	for _, item := range protoItems {
+		content := strings.TrimSpace(item.GetContent())
+		if content == "" {
+			continue
+		}
+		items = append(items, model.ChecklistItem{Content: content, Done: item.GetDone()})
+	}
+	return items
+}
+
+// ConvertToProtoRecurrence 转换重复规则，frequency 为 0 表示不重复，返回 nil
+func ConvertToProtoRecurrence(frequency, interval int) *pb.Recurrence {
+	if frequency == 0 {
+		return nil
+	}
+	return &pb.Recurrence{
+		Frequency: pb.Recurrence_Frequency(frequency),
+		Interval:  uint32(interval),
+	}
+}
+
+func ConvertToProtoTemplate(templateModel *model.TodoTemplate) *pb.TodoTemplate {
+	protoItems := make([]*pb.TemplateItem, len(templateModel.Items))
+	for i := range templateModel.Items {
+		item := &templateModel.Items[i]
+		protoItem := &pb.TemplateItem{
+			Id:          uint32(item.ID),
+			Title:       item.Title,
+			Description: item.Description,
+			Tags:        item.Tags,
+			Checklist:   ConvertToProtoChecklist(item.Checklist),
+		}
+		if item.ParentItemID != nil {
+			protoItem.ParentItemId = uint32(*item.ParentItemID)
+		}
+		if item.DueOffset != nil {
+			protoItem.DueOffset = durationpb.New(time.Duration(*item.DueOffset) * time.Second)
+		}
+		protoItems[i] = protoItem
+	}
+	return &pb.TodoTemplate{
+		Id:          uint32(templateModel.ID),
+		UserId:      uint32(templateModel.UserID),
+		Name:        templateModel.Name,
+		Description: templateModel.Description,
+		Items:       protoItems,
+		CreatedAt:   timestamppb.New(templateModel.CreatedAt),
+		UpdatedAt:   timestamppb.New(templateModel.UpdatedAt),
+	}
+}
+
+func ConvertToProtoSavedSearch(searchModel *model.SavedSearch) *pb.SavedSearch {
+	return &pb.SavedSearch{
+		Id:        uint32(searchModel.ID),
+		UserId:    uint32(searchModel.UserID),
+		Name:      searchModel.Name,
+		Query:     searchModel.Query,
+		CreatedAt: timestamppb.New(searchModel.CreatedAt),
+		UpdatedAt: timestamppb.New(searchModel.UpdatedAt),
+	}
+}
+
+func ConvertToProtoArchivePolicy(policyModel *model.ArchivePolicy) *pb.ArchivePolicy {
+	protoPolicy := &pb.ArchivePolicy{
+		UserId:           uint32(policyModel.UserID),
+		Enabled:          policyModel.Enabled,
+		ArchiveAfterDays: uint32(policyModel.ArchiveAfterDays),
+	}
+	if policyModel.LastRunAt != nil {
+		protoPolicy.LastRunAt = timestamppb.New(*policyModel.LastRunAt)
+	}
+	if !policyModel.UpdatedAt.IsZero() {
+		protoPolicy.UpdatedAt = timestamppb.New(policyModel.UpdatedAt)
+	}
+	return protoPolicy
+}
+
+// ConvertToProtoTimeEntry 转换时间记录，正在运行的计时器的时长计算到 now
+func ConvertToProtoTimeEntry(entryModel *model.TimeEntry, now time.Time) *pb.TimeEntry {
+	protoEntry := &pb.TimeEntry{
+		Id:        uint32(entryModel.ID),
+		UserId:    uint32(entryModel.UserID),
+		TodoId:    uint32(entryModel.TodoID),
+		StartedAt: timestamppb.New(entryModel.StartedAt),
+		Duration:  durationpb.New(time.Duration(entryModel.DurationSeconds) * time.Second),
+		Note:      entryModel.Note,
+		Running:   entryModel.EndedAt == nil,
+		CreatedAt: timestamppb.New(entryModel.CreatedAt),
+		UpdatedAt: timestamppb.New(entryModel.UpdatedAt),
+	}
+	if entryModel.EndedAt != nil {
+		protoEntry.EndedAt = timestamppb.New(*entryModel.EndedAt)
+	} else if elapsed := now.Sub(entryModel.StartedAt); elapsed > 0 {
+		protoEntry.Duration = durationpb.New(elapsed.Truncate(time.Second))
+	}
+	return protoEntry
+}
+
+// NormalizeTags 去除标签首尾空白和前导 '#'，并去掉空标签和重复标签 (保持原有顺序)
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
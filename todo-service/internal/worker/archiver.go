@@ -0,0 +1,223 @@
+// Package worker 包含 todo-service 的后台任务
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"todo-project/todo-service/internal/cache"
+	"todo-project/todo-service/internal/events"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+	"todo-project/todo-service/internal/util"
+	pb "todo-project/todo-service/proto/todo"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 每个事务最多归档的 Todo 数量
+const archiveBatchSize = 500
+
+// 多副本部署时只允许一个副本执行同一轮归档
+const archiveLockKey = "auto_archive:lock"
+
+// AutoArchiver 按用户的自动归档策略定期归档已完成的 Todo
+type AutoArchiver struct {
+	repo     repository.TodoRepository
+	rdb      *redis.Client
+	cache    *cache.Cache
+	feed     *events.Feed
+	interval time.Duration
+}
+
+// NewAutoArchiver 创建自动归档任务，interval 为执行间隔，归档后使 todoCache 中用户的缓存失效，
+// 归档产生的变更发布到 feed
+func NewAutoArchiver(repo repository.TodoRepository, rdb *redis.Client, todoCache *cache.Cache, feed *events.Feed, interval time.Duration) *AutoArchiver {
+	return &AutoArchiver{repo: repo, rdb: rdb, cache: todoCache, feed: feed, interval: interval}
+}
+
+// Run 立即执行一轮归档，之后每隔 interval 执行一次，直到 ctx 被取消
+func (a *AutoArchiver) Run(ctx context.Context) {
+	log.Printf("自动归档任务已启动，执行间隔: %s", a.interval)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if archived, err := a.RunOnce(ctx, time.Now()); err != nil {
+			log.Printf("自动归档执行失败: %v", err)
+		} else if archived > 0 {
+			log.Printf("自动归档完成，本轮共归档 %d 个 Todo", archived)
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("自动归档任务已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 为所有启用了自动归档的用户执行一轮归档，返回归档的 Todo 数量
+func (a *AutoArchiver) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	if !a.acquireLock(ctx) {
+		return 0, nil
+	}
+
+	policies, err := a.repo.ListEnabledArchivePolicies(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("加载自动归档策略失败: %w", err)
+	}
+
+	total := 0
+	for i := range policies {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+		archived, err := a.archiveForUser(ctx, policies[i], now)
+		if err != nil {
+			// 单个用户失败不影响其他用户
+			log.Printf("为用户 %d 自动归档失败: %v", policies[i].UserID, err)
+			continue
+		}
+		total += archived
+	}
+	return total, nil
+}
+
+// acquireLock 尝试获取本轮归档的分布式锁。Redis 不可用时仍然执行 (归档操作是幂等的)
+func (a *AutoArchiver) acquireLock(ctx context.Context) bool {
+	ttl := a.interval / 2
+	if ttl < time.Minute {
+		ttl = time.Minute
+	}
+	owner, _ := os.Hostname()
+	ok, err := a.rdb.SetNX(ctx, archiveLockKey, fmt.Sprintf("%s:%d", owner, os.Getpid()), ttl).Result()
+	if err != nil {
+		log.Printf("警告: 获取自动归档锁失败: %v。将继续执行。", err)
+		return true
+	}
+	return ok
+}
+
+// archiveForUser 归档用户在截止时间之前完成的 Todo。只有整棵子树都满足条件的 Todo 才会被归档，
+// 避免未完成的子任务随已完成的父任务一起从列表中消失
+func (a *AutoArchiver) archiveForUser(ctx context.Context, policy *model.ArchivePolicy, now time.Time) (int, error) {
+	cutoff := now.AddDate(0, 0, -policy.ArchiveAfterDays)
+
+	todos, err := a.repo.ListTodos(ctx, policy.UserID, repository.TodoFilter{ExcludeArchived: true})
+	if err != nil {
+		return 0, err
+	}
+
+	children := make(map[uint][]*model.Todo)
+	for _, todo := range todos {
+		if todo.ParentID != nil {
+			children[*todo.ParentID] = append(children[*todo.ParentID], todo)
+		}
+	}
+	eligible := make(map[uint]bool, len(todos))
+	var check func(todo *model.Todo) bool
+	check = func(todo *model.Todo) bool {
+		if result, ok := eligible[todo.ID]; ok {
+			return result
+		}
+		eligible[todo.ID] = false // 防止 parent_id 成环时无限递归
+		result := completedBefore(todo, cutoff)
+		for _, child := range children[todo.ID] {
+			if !check(child) {
+				result = false
+			}
+		}
+		eligible[todo.ID] = result
+		return result
+	}
+	var ids []uint
+	for _, todo := range todos {
+		if check(todo) {
+			ids = append(ids, todo.ID)
+		}
+	}
+
+	var archived []uint
+	for start := 0; start < len(ids); start += archiveBatchSize {
+		end := start + archiveBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var batchArchived []uint
+		err := a.repo.Transaction(ctx, func(tx repository.TodoRepository) error {
+			batchArchived = batchArchived[:0]
+			seq, err := tx.NextChangeSeq(ctx, policy.UserID)
+			if err != nil {
+				return err
+			}
+			// 在事务中重新加载并再次检查，跳过本轮扫描之后已被其他请求归档、重新打开或重新完成的 Todo
+			batch, err := tx.ListTodos(ctx, policy.UserID, repository.TodoFilter{IDs: ids[start:end], ExcludeArchived: true})
+			if err != nil {
+				return err
+			}
+			for _, todo := range batch {
+				if !completedBefore(todo, cutoff) {
+					continue
+				}
+				archivedAt := now
+				todo.ArchivedAt = &archivedAt
+				todo.ChangeSeq = seq
+				if err := tx.SaveTodo(ctx, todo); err != nil {
+					return err
+				}
+				batchArchived = append(batchArchived, todo.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return len(archived), err
+		}
+		archived = append(archived, batchArchived...)
+	}
+
+	if err := a.repo.UpdateArchivePolicyLastRun(ctx, policy.UserID, now); err != nil {
+		log.Printf("警告: 更新用户 %d 的自动归档执行时间失败: %v", policy.UserID, err)
+	}
+	if len(archived) > 0 {
+		log.Printf("用户 %d 自动归档了 %d 个 Todo (完成于 %s 之前)", policy.UserID, len(archived), cutoff.Format(time.RFC3339))
+		a.cache.InvalidateUser(ctx, policy.UserID)
+		a.publishUpdates(ctx, policy.UserID, archived)
+	}
+	return len(archived), nil
+}
+
+// completedBefore 判断 Todo 是否已完成且完成时间早于 cutoff
+func completedBefore(todo *model.Todo, cutoff time.Time) bool {
+	// 早于完成时间字段的历史数据没有 completed_at，以最后更新时间代替
+	completedAt := todo.UpdatedAt
+	if todo.CompletedAt != nil {
+		completedAt = *todo.CompletedAt
+	}
+	return todo.Completed && completedAt.Before(cutoff)
+}
+
+// publishUpdates 为归档的 Todo 发布 UPDATED 事件，使在线客户端移除这些 Todo
+func (a *AutoArchiver) publishUpdates(ctx context.Context, userID uint, ids []uint) {
+	// 变更流与缓存使用同一个 Redis，熔断期间不发布
+	if !a.cache.Available() {
+		log.Printf("警告: Redis 已熔断，跳过发布用户 %d 的自动归档事件", userID)
+		return
+	}
+	todos, err := a.repo.ListTodos(ctx, userID, repository.TodoFilter{IDs: ids})
+	if err != nil {
+		log.Printf("警告: 加载用户 %d 自动归档的 Todo 以发布事件失败: %v", userID, err)
+		return
+	}
+	now := timestamppb.Now()
+	todoEvents := make([]*pb.TodoEvent, len(todos))
+	for i, todo := range todos {
+		todoEvents[i] = &pb.TodoEvent{Type: pb.TodoEvent_UPDATED, TodoId: uint32(todo.ID), Todo: util.ConvertToProtoTodo(todo), OccurredAt: now}
+	}
+	if err := a.feed.Publish(ctx, userID, todoEvents...); err != nil {
+		log.Printf("警告: 发布用户 %d 的自动归档事件失败: %v", userID, err)
+	}
+}
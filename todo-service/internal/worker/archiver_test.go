@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"todo-project/todo-service/internal/cache"
+	"todo-project/todo-service/internal/events"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/internal/repository"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// reopeningRepo 在归档事务开始前执行 beforeTx，模拟扫描之后、事务之前其他请求对 Todo 的修改
+type reopeningRepo struct {
+	repository.TodoRepository
+	beforeTx func(ctx context.Context)
+}
+
+func (r *reopeningRepo) Transaction(ctx context.Context, fn func(tx repository.TodoRepository) error) error {
+	if r.beforeTx != nil {
+		r.beforeTx(ctx)
+		r.beforeTx = nil
+	}
+	return r.TodoRepository.Transaction(ctx, fn)
+}
+
+func TestArchiveSkipsTodosChangedAfterScan(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	now := time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC)
+	longAgo := now.AddDate(0, 0, -30)
+	repo := &reopeningRepo{TodoRepository: repository.NewMemory()}
+	if err := repo.CreateArchivePolicy(ctx, &model.ArchivePolicy{UserID: 1, Enabled: true, ArchiveAfterDays: 7}); err != nil {
+		t.Fatal(err)
+	}
+	var todos []*model.Todo
+	for _, title := range []string{"保持完成", "被重新打开", "重新完成"} {
+		todo := &model.Todo{UserID: 1, Title: title, Completed: true, CompletedAt: &longAgo}
+		if err := repo.CreateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+		todos = append(todos, todo)
+	}
+	repo.beforeTx = func(ctx context.Context) {
+		reopened, completedAgain := *todos[1], *todos[2]
+		reopened.Completed, reopened.CompletedAt = false, nil
+		recently := now.Add(-time.Hour)
+		completedAgain.CompletedAt = &recently
+		for _, todo := range []*model.Todo{&reopened, &completedAgain} {
+			if err := repo.SaveTodo(ctx, todo); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	a := NewAutoArchiver(repo, rdb, cache.New(rdb, cache.Options{}), events.NewFeed(rdb), time.Hour)
+	archived, err := a.RunOnce(ctx, now)
+	if err != nil {
+		t.Fatalf("RunOnce 失败: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("归档了 %d 个 Todo, 期望 1 个", archived)
+	}
+	for i, wantArchived := range []bool{true, false, false} {
+		todo, err := repo.GetTodo(ctx, 1, todos[i].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := todo.ArchivedAt != nil; got != wantArchived {
+			t.Errorf("%s: 已归档 = %t, 期望 %t", todo.Title, got, wantArchived)
+		}
+	}
+}
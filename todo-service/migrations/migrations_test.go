@@ -0,0 +1,193 @@
+package migrations_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"time"
+
+	"todo-project/todo-service/internal/migrate"
+	"todo-project/todo-service/internal/model"
+	"todo-project/todo-service/migrations"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var models = []interface{}{&model.Todo{}, &model.BatchOperationLog{}, &model.TodoTemplate{}, &model.TemplateItem{},
+	&model.SavedSearch{}, &model.ArchivePolicy{}, &model.TimeEntry{}, &model.ChangeCounter{}, &model.TodoTombstone{}}
+
+// 各方言的迁移版本必须一一对应
+func TestDialectsHaveSameVersions(t *testing.T) {
+	var want []migrate.Migration
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		sub, err := fs.Sub(migrations.FS, dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := migrate.Load(sub)
+		if err != nil {
+			t.Fatalf("%s: %v", dialect, err)
+		}
+		if want == nil {
+			want = loaded
+			continue
+		}
+		if len(loaded) != len(want) {
+			t.Fatalf("%s 有 %d 个迁移，mysql 有 %d 个", dialect, len(loaded), len(want))
+		}
+		for i := range loaded {
+			if loaded[i].String() != want[i].String() {
+				t.Errorf("%s 的第 %d 个迁移为 %s，mysql 为 %s", dialect, i+1, loaded[i], want[i])
+			}
+		}
+	}
+}
+
+// openDB 打开一个独立的内存 SQLite 数据库
+func openDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func newMigrator(t *testing.T, db *gorm.DB) *migrate.Migrator {
+	t.Helper()
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrate.New(sqlDB, "sqlite", "todo-service", migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator
+}
+
+// checkModels 检查数据库中包含模型的全部表、列和索引
+func checkModels(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			t.Fatal(err)
+		}
+		table := stmt.Schema.Table
+		if !db.Migrator().HasTable(m) {
+			t.Errorf("迁移没有创建表 %s", table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(m, field.DBName) {
+				t.Errorf("表 %s 缺少列 %s", table, field.DBName)
+			}
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if !db.Migrator().HasIndex(m, index.Name) {
+				t.Errorf("表 %s 缺少索引 %s", table, index.Name)
+			}
+		}
+	}
+}
+
+// 迁移建出的表必须包含模型的全部列和索引，避免修改模型后忘记添加迁移
+func TestMigrationsMatchModels(t *testing.T) {
+	db := openDB(t, "migrations")
+	migrator := newMigrator(t, db)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkModels(t, db)
+
+	// 回滚全部迁移后不应留下任何表
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(ctx, len(statuses)); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range models {
+		if db.Migrator().HasTable(m) {
+			stmt := &gorm.Statement{DB: db}
+			stmt.Parse(m)
+			t.Errorf("回滚后仍存在表 %s", stmt.Schema.Table)
+		}
+	}
+}
+
+// baselineTodo 和 baselineBatchOperationLog 为引入迁移之前由 AutoMigrate 创建的表结构
+type baselineTodo struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;index"`
+	Title       string `gorm:"not null"`
+	Description string
+	Completed   bool `gorm:"default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselineTodo) TableName() string { return "todos" }
+
+type baselineBatchOperationLog struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"not null;index"`
+	OperationType   string `gorm:"size:50;not null"`
+	AffectedTodoIDs string `gorm:"type:json;not null"`
+	Status          string `gorm:"size:20;not null"`
+	Details         string `gorm:"type:text"`
+	CreatedAt       time.Time
+}
+
+func (baselineBatchOperationLog) TableName() string { return "batch_operation_logs" }
+
+// 之前由 AutoMigrate 创建的数据库执行迁移后同样包含模型的全部列和索引，已有数据保留
+func TestUpgradeFromAutoMigrate(t *testing.T) {
+	db := openDB(t, "baseline")
+	if err := db.AutoMigrate(&baselineTodo{}, &baselineBatchOperationLog{}); err != nil {
+		t.Fatal(err)
+	}
+	existing := baselineTodo{UserID: 7, Title: "升级前创建", Description: "保留", Completed: true}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	migrator := newMigrator(t, db)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("升级 AutoMigrate 创建的数据库失败: %v", err)
+	}
+	checkModels(t, db)
+
+	var todo model.Todo
+	if err := db.First(&todo, existing.ID).Error; err != nil {
+		t.Fatalf("读取升级前的 Todo 失败: %v", err)
+	}
+	if todo.Title != existing.Title || todo.Description != existing.Description || !todo.Completed ||
+		todo.ParentID != nil || todo.ArchivedAt != nil || todo.Priority != 0 || todo.ChangeSeq != 0 || todo.ClientID != nil {
+		t.Errorf("升级后的 Todo = %+v", todo)
+	}
+
+	// 新的列可以正常写入
+	clientID := "client-1"
+	todo.Tags = []string{"work"}
+	todo.ChangeSeq = 3
+	todo.ClientID = &clientID
+	if err := db.Save(&todo).Error; err != nil {
+		t.Fatalf("写入新列失败: %v", err)
+	}
+	if err := db.Create(&model.TodoTombstone{TodoID: 99, UserID: 7, ChangeSeq: 4}).Error; err != nil {
+		t.Fatalf("写入新表失败: %v", err)
+	}
+}
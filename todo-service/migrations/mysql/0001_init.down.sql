@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `batch_operation_logs`;
+DROP TABLE IF EXISTS `todos`;
@@ -0,0 +1,25 @@
+-- 初始表结构，与之前 GORM AutoMigrate 创建的表一致
+
+CREATE TABLE IF NOT EXISTS `todos` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `title` longtext NOT NULL,
+  `description` longtext,
+  `completed` boolean DEFAULT false,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_todos_user_id` (`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `batch_operation_logs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `operation_type` varchar(50) NOT NULL,
+  `affected_todo_ids` JSON NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `details` text,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_batch_operation_logs_user_id` (`user_id`)
+);
@@ -0,0 +1,34 @@
+-- Todo 模板：子任务、截止时间、标签和检查清单，以及模板和模板条目两张表
+
+ALTER TABLE `todos`
+  ADD COLUMN `parent_id` bigint unsigned,
+  ADD COLUMN `due_at` datetime(3) NULL,
+  ADD COLUMN `tags` text,
+  ADD COLUMN `checklist` text,
+  ADD INDEX `idx_todos_parent_id` (`parent_id`);
+
+CREATE TABLE `todo_templates` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` longtext,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_todo_templates_user_id` (`user_id`)
+);
+
+CREATE TABLE `template_items` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `template_id` bigint unsigned NOT NULL,
+  `parent_item_id` bigint unsigned,
+  `position` bigint NOT NULL,
+  `title` longtext NOT NULL,
+  `description` longtext,
+  `due_offset` bigint,
+  `tags` text,
+  `checklist` text,
+  PRIMARY KEY (`id`),
+  INDEX `idx_template_items_template_id` (`template_id`),
+  CONSTRAINT `fk_todo_templates_items` FOREIGN KEY (`template_id`) REFERENCES `todo_templates` (`id`)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE `todos`
+  DROP COLUMN `priority`,
+  DROP COLUMN `recurrence_frequency`,
+  DROP COLUMN `recurrence_interval`;
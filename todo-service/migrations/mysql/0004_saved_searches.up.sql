@@ -0,0 +1,12 @@
+-- 保存的搜索
+
+CREATE TABLE `saved_searches` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `query` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_saved_searches_user_name` (`user_id`, `name`)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `archive_policies`;
+
+ALTER TABLE `todos`
+  DROP INDEX `idx_todos_archived_at`,
+  DROP COLUMN `completed_at`,
+  DROP COLUMN `archived_at`;
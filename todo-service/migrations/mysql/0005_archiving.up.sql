@@ -0,0 +1,17 @@
+-- 归档：完成时间、归档时间和自动归档策略
+
+ALTER TABLE `todos`
+  ADD COLUMN `completed_at` datetime(3) NULL,
+  ADD COLUMN `archived_at` datetime(3) NULL,
+  ADD INDEX `idx_todos_archived_at` (`archived_at`);
+
+CREATE TABLE `archive_policies` (
+  `user_id` bigint unsigned,
+  `enabled` boolean NOT NULL DEFAULT false,
+  `archive_after_days` bigint NOT NULL,
+  `last_run_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`),
+  INDEX `idx_archive_policies_enabled` (`enabled`)
+);
@@ -0,0 +1,21 @@
+-- 时间记录：Todo 的累计时长和时间记录表
+
+ALTER TABLE `todos`
+  ADD COLUMN `tracked_seconds` bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE `time_entries` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `todo_id` bigint unsigned NOT NULL,
+  `started_at` datetime(3) NOT NULL,
+  `ended_at` datetime(3) NULL,
+  `duration_seconds` bigint NOT NULL DEFAULT 0,
+  `note` varchar(500),
+  `running_user_id` bigint unsigned,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_time_entries_user_started` (`user_id`, `started_at`),
+  INDEX `idx_time_entries_todo_id` (`todo_id`),
+  UNIQUE INDEX `idx_time_entries_running_user_id` (`running_user_id`)
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS `todo_tombstones`;
+DROP TABLE IF EXISTS `change_counters`;
+
+ALTER TABLE `todos`
+  DROP INDEX `idx_todos_user_change_seq`,
+  DROP INDEX `idx_todos_user_client`,
+  DROP COLUMN `change_seq`,
+  DROP COLUMN `client_id`;
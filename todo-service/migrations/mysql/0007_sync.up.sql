@@ -0,0 +1,23 @@
+-- 离线同步：用户级变更序号、客户端 ID、变更计数器和删除墓碑
+
+ALTER TABLE `todos`
+  ADD COLUMN `change_seq` bigint unsigned NOT NULL DEFAULT 0,
+  ADD COLUMN `client_id` varchar(64),
+  ADD INDEX `idx_todos_user_change_seq` (`user_id`, `change_seq`),
+  ADD UNIQUE INDEX `idx_todos_user_client` (`user_id`, `client_id`);
+
+CREATE TABLE `change_counters` (
+  `user_id` bigint unsigned,
+  `seq` bigint unsigned NOT NULL DEFAULT 0,
+  PRIMARY KEY (`user_id`)
+);
+
+CREATE TABLE `todo_tombstones` (
+  `todo_id` bigint unsigned,
+  `user_id` bigint unsigned NOT NULL,
+  `change_seq` bigint unsigned NOT NULL,
+  `client_id` varchar(64),
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`todo_id`),
+  INDEX `idx_todo_tombstones_user_change_seq` (`user_id`, `change_seq`)
+);
@@ -0,0 +1,25 @@
+-- 初始表结构，与之前 GORM AutoMigrate 创建的表一致
+
+CREATE TABLE IF NOT EXISTS "todos" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "title" text NOT NULL,
+  "description" text,
+  "completed" boolean DEFAULT false,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_todos_user_id" ON "todos" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "batch_operation_logs" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "operation_type" varchar(50) NOT NULL,
+  "affected_todo_ids" JSONB NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "details" text,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_batch_operation_logs_user_id" ON "batch_operation_logs" ("user_id");
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "template_items";
+DROP TABLE IF EXISTS "todo_templates";
+
+DROP INDEX IF EXISTS "idx_todos_parent_id";
+ALTER TABLE "todos"
+  DROP COLUMN "parent_id",
+  DROP COLUMN "due_at",
+  DROP COLUMN "tags",
+  DROP COLUMN "checklist";
@@ -0,0 +1,34 @@
+-- Todo 模板：子任务、截止时间、标签和检查清单，以及模板和模板条目两张表
+
+ALTER TABLE "todos"
+  ADD COLUMN "parent_id" bigint,
+  ADD COLUMN "due_at" timestamptz,
+  ADD COLUMN "tags" text,
+  ADD COLUMN "checklist" text;
+CREATE INDEX "idx_todos_parent_id" ON "todos" ("parent_id");
+
+CREATE TABLE "todo_templates" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "description" text,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_todo_templates_user_id" ON "todo_templates" ("user_id");
+
+CREATE TABLE "template_items" (
+  "id" bigserial,
+  "template_id" bigint NOT NULL,
+  "parent_item_id" bigint,
+  "position" bigint NOT NULL,
+  "title" text NOT NULL,
+  "description" text,
+  "due_offset" bigint,
+  "tags" text,
+  "checklist" text,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_todo_templates_items" FOREIGN KEY ("template_id") REFERENCES "todo_templates" ("id")
+);
+CREATE INDEX "idx_template_items_template_id" ON "template_items" ("template_id");
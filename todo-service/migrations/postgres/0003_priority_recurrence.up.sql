@@ -0,0 +1,6 @@
+-- Todo 的优先级和重复规则
+
+ALTER TABLE "todos"
+  ADD COLUMN "priority" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "recurrence_frequency" bigint NOT NULL DEFAULT 0,
+  ADD COLUMN "recurrence_interval" bigint NOT NULL DEFAULT 0;
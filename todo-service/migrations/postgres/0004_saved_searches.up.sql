@@ -0,0 +1,12 @@
+-- 保存的搜索
+
+CREATE TABLE "saved_searches" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "query" text NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_saved_searches_user_name" ON "saved_searches" ("user_id", "name");
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "archive_policies";
+
+DROP INDEX IF EXISTS "idx_todos_archived_at";
+ALTER TABLE "todos"
+  DROP COLUMN "completed_at",
+  DROP COLUMN "archived_at";
@@ -0,0 +1,17 @@
+-- 归档：完成时间、归档时间和自动归档策略
+
+ALTER TABLE "todos"
+  ADD COLUMN "completed_at" timestamptz,
+  ADD COLUMN "archived_at" timestamptz;
+CREATE INDEX "idx_todos_archived_at" ON "todos" ("archived_at");
+
+CREATE TABLE "archive_policies" (
+  "user_id" bigint,
+  "enabled" boolean NOT NULL DEFAULT false,
+  "archive_after_days" bigint NOT NULL,
+  "last_run_at" timestamptz,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("user_id")
+);
+CREATE INDEX "idx_archive_policies_enabled" ON "archive_policies" ("enabled");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "time_entries";
+
+ALTER TABLE "todos"
+  DROP COLUMN "tracked_seconds";
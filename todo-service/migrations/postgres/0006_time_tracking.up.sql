@@ -0,0 +1,21 @@
+-- 时间记录：Todo 的累计时长和时间记录表
+
+ALTER TABLE "todos"
+  ADD COLUMN "tracked_seconds" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "time_entries" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "todo_id" bigint NOT NULL,
+  "started_at" timestamptz NOT NULL,
+  "ended_at" timestamptz,
+  "duration_seconds" bigint NOT NULL DEFAULT 0,
+  "note" varchar(500),
+  "running_user_id" bigint,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_time_entries_user_started" ON "time_entries" ("user_id", "started_at");
+CREATE INDEX "idx_time_entries_todo_id" ON "time_entries" ("todo_id");
+CREATE UNIQUE INDEX "idx_time_entries_running_user_id" ON "time_entries" ("running_user_id");
@@ -0,0 +1,23 @@
+-- 初始表结构，与之前 GORM AutoMigrate 创建的表一致
+
+CREATE TABLE IF NOT EXISTS `todos` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `title` text NOT NULL,
+  `description` text,
+  `completed` numeric DEFAULT false,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_todos_user_id` ON `todos` (`user_id`);
+
+CREATE TABLE IF NOT EXISTS `batch_operation_logs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `operation_type` text NOT NULL,
+  `affected_todo_ids` TEXT NOT NULL,
+  `status` text NOT NULL,
+  `details` text,
+  `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_batch_operation_logs_user_id` ON `batch_operation_logs` (`user_id`);
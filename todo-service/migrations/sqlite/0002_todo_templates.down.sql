@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS `template_items`;
+DROP TABLE IF EXISTS `todo_templates`;
+
+DROP INDEX IF EXISTS `idx_todos_parent_id`;
+ALTER TABLE `todos` DROP COLUMN `parent_id`;
+ALTER TABLE `todos` DROP COLUMN `due_at`;
+ALTER TABLE `todos` DROP COLUMN `tags`;
+ALTER TABLE `todos` DROP COLUMN `checklist`;
@@ -0,0 +1,31 @@
+-- Todo 模板：子任务、截止时间、标签和检查清单，以及模板和模板条目两张表
+
+ALTER TABLE `todos` ADD COLUMN `parent_id` integer;
+ALTER TABLE `todos` ADD COLUMN `due_at` datetime;
+ALTER TABLE `todos` ADD COLUMN `tags` text;
+ALTER TABLE `todos` ADD COLUMN `checklist` text;
+CREATE INDEX `idx_todos_parent_id` ON `todos` (`parent_id`);
+
+CREATE TABLE `todo_templates` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `name` text NOT NULL,
+  `description` text,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE INDEX `idx_todo_templates_user_id` ON `todo_templates` (`user_id`);
+
+CREATE TABLE `template_items` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `template_id` integer NOT NULL,
+  `parent_item_id` integer,
+  `position` integer NOT NULL,
+  `title` text NOT NULL,
+  `description` text,
+  `due_offset` integer,
+  `tags` text,
+  `checklist` text,
+  CONSTRAINT `fk_todo_templates_items` FOREIGN KEY (`template_id`) REFERENCES `todo_templates` (`id`)
+);
+CREATE INDEX `idx_template_items_template_id` ON `template_items` (`template_id`);
@@ -0,0 +1,3 @@
+ALTER TABLE `todos` DROP COLUMN `priority`;
+ALTER TABLE `todos` DROP COLUMN `recurrence_frequency`;
+ALTER TABLE `todos` DROP COLUMN `recurrence_interval`;
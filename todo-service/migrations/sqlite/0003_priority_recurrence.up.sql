@@ -0,0 +1,5 @@
+-- Todo 的优先级和重复规则
+
+ALTER TABLE `todos` ADD COLUMN `priority` integer NOT NULL DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `recurrence_frequency` integer NOT NULL DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `recurrence_interval` integer NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- 保存的搜索
+
+CREATE TABLE `saved_searches` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `name` text NOT NULL,
+  `query` text NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_saved_searches_user_name` ON `saved_searches` (`user_id`, `name`);
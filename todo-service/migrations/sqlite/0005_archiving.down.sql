@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `archive_policies`;
+
+DROP INDEX IF EXISTS `idx_todos_archived_at`;
+ALTER TABLE `todos` DROP COLUMN `completed_at`;
+ALTER TABLE `todos` DROP COLUMN `archived_at`;
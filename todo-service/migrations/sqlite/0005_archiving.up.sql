@@ -0,0 +1,16 @@
+-- 归档：完成时间、归档时间和自动归档策略
+
+ALTER TABLE `todos` ADD COLUMN `completed_at` datetime;
+ALTER TABLE `todos` ADD COLUMN `archived_at` datetime;
+CREATE INDEX `idx_todos_archived_at` ON `todos` (`archived_at`);
+
+CREATE TABLE `archive_policies` (
+  `user_id` integer,
+  `enabled` numeric NOT NULL DEFAULT false,
+  `archive_after_days` integer NOT NULL,
+  `last_run_at` datetime,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`user_id`)
+);
+CREATE INDEX `idx_archive_policies_enabled` ON `archive_policies` (`enabled`);
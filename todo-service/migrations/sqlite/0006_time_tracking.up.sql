@@ -0,0 +1,19 @@
+-- 时间记录：Todo 的累计时长和时间记录表
+
+ALTER TABLE `todos` ADD COLUMN `tracked_seconds` integer NOT NULL DEFAULT 0;
+
+CREATE TABLE `time_entries` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `todo_id` integer NOT NULL,
+  `started_at` datetime NOT NULL,
+  `ended_at` datetime,
+  `duration_seconds` integer NOT NULL DEFAULT 0,
+  `note` text,
+  `running_user_id` integer,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE INDEX `idx_time_entries_user_started` ON `time_entries` (`user_id`, `started_at`);
+CREATE INDEX `idx_time_entries_todo_id` ON `time_entries` (`todo_id`);
+CREATE UNIQUE INDEX `idx_time_entries_running_user_id` ON `time_entries` (`running_user_id`);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `todo_tombstones`;
+DROP TABLE IF EXISTS `change_counters`;
+
+DROP INDEX IF EXISTS `idx_todos_user_change_seq`;
+DROP INDEX IF EXISTS `idx_todos_user_client`;
+ALTER TABLE `todos` DROP COLUMN `change_seq`;
+ALTER TABLE `todos` DROP COLUMN `client_id`;
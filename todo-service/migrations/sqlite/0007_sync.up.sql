@@ -0,0 +1,22 @@
+-- 离线同步：用户级变更序号、客户端 ID、变更计数器和删除墓碑
+
+ALTER TABLE `todos` ADD COLUMN `change_seq` integer NOT NULL DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `client_id` text;
+CREATE INDEX `idx_todos_user_change_seq` ON `todos` (`user_id`, `change_seq`);
+CREATE UNIQUE INDEX `idx_todos_user_client` ON `todos` (`user_id`, `client_id`);
+
+CREATE TABLE `change_counters` (
+  `user_id` integer,
+  `seq` integer NOT NULL DEFAULT 0,
+  PRIMARY KEY (`user_id`)
+);
+
+CREATE TABLE `todo_tombstones` (
+  `todo_id` integer,
+  `user_id` integer NOT NULL,
+  `change_seq` integer NOT NULL,
+  `client_id` text,
+  `created_at` datetime,
+  PRIMARY KEY (`todo_id`)
+);
+CREATE INDEX `idx_todo_tombstones_user_change_seq` ON `todo_tombstones` (`user_id`, `change_seq`);
@@ -24,12 +24,39 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TodoService_CreateTodo_FullMethodName       = "/todo.TodoService/CreateTodo"
-	TodoService_GetTodos_FullMethodName         = "/todo.TodoService/GetTodos"
-	TodoService_GetTodoByID_FullMethodName      = "/todo.TodoService/GetTodoByID"
-	TodoService_UpdateTodo_FullMethodName       = "/todo.TodoService/UpdateTodo"
-	TodoService_DeleteTodo_FullMethodName       = "/todo.TodoService/DeleteTodo"
-	TodoService_BatchUpdateTodos_FullMethodName = "/todo.TodoService/BatchUpdateTodos"
+	TodoService_CreateTodo_FullMethodName             = "/todo.TodoService/CreateTodo"
+	TodoService_GetTodos_FullMethodName               = "/todo.TodoService/GetTodos"
+	TodoService_GetTodoByID_FullMethodName            = "/todo.TodoService/GetTodoByID"
+	TodoService_UpdateTodo_FullMethodName             = "/todo.TodoService/UpdateTodo"
+	TodoService_DeleteTodo_FullMethodName             = "/todo.TodoService/DeleteTodo"
+	TodoService_BatchUpdateTodos_FullMethodName       = "/todo.TodoService/BatchUpdateTodos"
+	TodoService_CreateTemplateFromTodo_FullMethodName = "/todo.TodoService/CreateTemplateFromTodo"
+	TodoService_ListTemplates_FullMethodName          = "/todo.TodoService/ListTemplates"
+	TodoService_GetTemplate_FullMethodName            = "/todo.TodoService/GetTemplate"
+	TodoService_DeleteTemplate_FullMethodName         = "/todo.TodoService/DeleteTemplate"
+	TodoService_InstantiateTemplate_FullMethodName    = "/todo.TodoService/InstantiateTemplate"
+	TodoService_QuickAddTodo_FullMethodName           = "/todo.TodoService/QuickAddTodo"
+	TodoService_CreateSavedSearch_FullMethodName      = "/todo.TodoService/CreateSavedSearch"
+	TodoService_ListSavedSearches_FullMethodName      = "/todo.TodoService/ListSavedSearches"
+	TodoService_GetSavedSearch_FullMethodName         = "/todo.TodoService/GetSavedSearch"
+	TodoService_UpdateSavedSearch_FullMethodName      = "/todo.TodoService/UpdateSavedSearch"
+	TodoService_DeleteSavedSearch_FullMethodName      = "/todo.TodoService/DeleteSavedSearch"
+	TodoService_RunSavedSearch_FullMethodName         = "/todo.TodoService/RunSavedSearch"
+	TodoService_ArchiveTodo_FullMethodName            = "/todo.TodoService/ArchiveTodo"
+	TodoService_UnarchiveTodo_FullMethodName          = "/todo.TodoService/UnarchiveTodo"
+	TodoService_GetArchivePolicy_FullMethodName       = "/todo.TodoService/GetArchivePolicy"
+	TodoService_UpdateArchivePolicy_FullMethodName    = "/todo.TodoService/UpdateArchivePolicy"
+	TodoService_StartTimer_FullMethodName             = "/todo.TodoService/StartTimer"
+	TodoService_StopTimer_FullMethodName              = "/todo.TodoService/StopTimer"
+	TodoService_GetRunningTimer_FullMethodName        = "/todo.TodoService/GetRunningTimer"
+	TodoService_CreateTimeEntry_FullMethodName        = "/todo.TodoService/CreateTimeEntry"
+	TodoService_ListTimeEntries_FullMethodName        = "/todo.TodoService/ListTimeEntries"
+	TodoService_UpdateTimeEntry_FullMethodName        = "/todo.TodoService/UpdateTimeEntry"
+	TodoService_DeleteTimeEntry_FullMethodName        = "/todo.TodoService/DeleteTimeEntry"
+	TodoService_GetTimeReport_FullMethodName          = "/todo.TodoService/GetTimeReport"
+	TodoService_GetTodoStats_FullMethodName           = "/todo.TodoService/GetTodoStats"
+	TodoService_WatchTodos_FullMethodName             = "/todo.TodoService/WatchTodos"
+	TodoService_SyncTodos_FullMethodName              = "/todo.TodoService/SyncTodos"
 )
 
 // TodoServiceClient is the client API for TodoService service.
@@ -51,6 +78,68 @@ type TodoServiceClient interface {
 	// --- 新增：批量更新 Todos --- //
 	// 批量更新用户的一系列 Todo (例如：批量标记完成/未完成)
 	BatchUpdateTodos(ctx context.Context, in *BatchUpdateTodosRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// --- 新增：Todo 模板 --- //
+	// 以已有 Todo (及其全部子任务) 为蓝本创建模板
+	CreateTemplateFromTodo(ctx context.Context, in *CreateTemplateFromTodoRequest, opts ...grpc.CallOption) (*TodoTemplate, error)
+	// 获取用户的所有模板
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	// 获取单个模板
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*TodoTemplate, error)
+	// 删除模板
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// 在一个事务中将模板实例化为真实的 Todo 树
+	InstantiateTemplate(ctx context.Context, in *InstantiateTemplateRequest, opts ...grpc.CallOption) (*InstantiateTemplateResponse, error)
+	// --- 新增：快速添加 --- //
+	// 解析自然语言文本 (中英文) 并创建 Todo，同时返回解析结果
+	QuickAddTodo(ctx context.Context, in *QuickAddTodoRequest, opts ...grpc.CallOption) (*QuickAddTodoResponse, error)
+	// --- 新增：保存搜索 (智能列表) --- //
+	// 创建保存搜索，查询表达式有误时返回 InvalidArgument，错误信息中包含出错位置
+	CreateSavedSearch(ctx context.Context, in *CreateSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error)
+	// 获取用户的所有保存搜索
+	ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error)
+	// 获取单个保存搜索
+	GetSavedSearch(ctx context.Context, in *GetSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error)
+	// 更新保存搜索
+	UpdateSavedSearch(ctx context.Context, in *UpdateSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error)
+	// 删除保存搜索
+	DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// 对用户的 Todo 执行保存搜索
+	RunSavedSearch(ctx context.Context, in *RunSavedSearchRequest, opts ...grpc.CallOption) (*RunSavedSearchResponse, error)
+	// --- 新增：归档 --- //
+	// 归档 Todo 及其全部子任务，归档不同于删除，可以随时恢复
+	ArchiveTodo(ctx context.Context, in *ArchiveTodoRequest, opts ...grpc.CallOption) (*ArchiveTodoResponse, error)
+	// 恢复已归档的 Todo 及其子任务，同时恢复其已归档的上级任务
+	UnarchiveTodo(ctx context.Context, in *ArchiveTodoRequest, opts ...grpc.CallOption) (*ArchiveTodoResponse, error)
+	// 获取用户的自动归档策略，未设置时返回默认策略 (未启用)
+	GetArchivePolicy(ctx context.Context, in *GetArchivePolicyRequest, opts ...grpc.CallOption) (*ArchivePolicy, error)
+	// 设置用户的自动归档策略
+	UpdateArchivePolicy(ctx context.Context, in *UpdateArchivePolicyRequest, opts ...grpc.CallOption) (*ArchivePolicy, error)
+	// --- 新增：时间记录 --- //
+	// 为 Todo 启动计时器，每个用户同一时间最多只有一个正在运行的计时器
+	StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error)
+	// 停止用户正在运行的计时器
+	StopTimer(ctx context.Context, in *StopTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error)
+	// 获取用户正在运行的计时器，没有时返回 NOT_FOUND
+	GetRunningTimer(ctx context.Context, in *GetRunningTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error)
+	// 手动添加时间记录
+	CreateTimeEntry(ctx context.Context, in *CreateTimeEntryRequest, opts ...grpc.CallOption) (*TimeEntry, error)
+	// 查询时间记录
+	ListTimeEntries(ctx context.Context, in *ListTimeEntriesRequest, opts ...grpc.CallOption) (*ListTimeEntriesResponse, error)
+	// 修改时间记录
+	UpdateTimeEntry(ctx context.Context, in *UpdateTimeEntryRequest, opts ...grpc.CallOption) (*TimeEntry, error)
+	// 删除时间记录
+	DeleteTimeEntry(ctx context.Context, in *DeleteTimeEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// 按天、标签或项目汇总指定时间范围内的时间记录
+	GetTimeReport(ctx context.Context, in *GetTimeReportRequest, opts ...grpc.CallOption) (*TimeReport, error)
+	// --- 新增：统计 --- //
+	// 获取用户的完成情况统计，结果会短暂缓存
+	GetTodoStats(ctx context.Context, in *GetTodoStatsRequest, opts ...grpc.CallOption) (*TodoStats, error)
+	// --- 新增：实时变更 --- //
+	// 订阅用户的 Todo 变更事件。先补发 resume_token 之后的事件，然后发送 READY，之后持续推送实时事件
+	WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error)
+	// --- 新增：离线同步 --- //
+	// 按顺序应用客户端离线期间的修改，然后返回 sync_token 之后服务端的全部变更 (含删除墓碑)
+	SyncTodos(ctx context.Context, in *SyncTodosRequest, opts ...grpc.CallOption) (*SyncTodosResponse, error)
 }
 
 type todoServiceClient struct {
@@ -121,6 +210,285 @@ func (c *todoServiceClient) BatchUpdateTodos(ctx context.Context, in *BatchUpdat
 	return out, nil
 }
 
+func (c *todoServiceClient) CreateTemplateFromTodo(ctx context.Context, in *CreateTemplateFromTodoRequest, opts ...grpc.CallOption) (*TodoTemplate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoTemplate)
+	err := c.cc.Invoke(ctx, TodoService_CreateTemplateFromTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*TodoTemplate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoTemplate)
+	err := c.cc.Invoke(ctx, TodoService_GetTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoService_DeleteTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) InstantiateTemplate(ctx context.Context, in *InstantiateTemplateRequest, opts ...grpc.CallOption) (*InstantiateTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InstantiateTemplateResponse)
+	err := c.cc.Invoke(ctx, TodoService_InstantiateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) QuickAddTodo(ctx context.Context, in *QuickAddTodoRequest, opts ...grpc.CallOption) (*QuickAddTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuickAddTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_QuickAddTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateSavedSearch(ctx context.Context, in *CreateSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedSearch)
+	err := c.cc.Invoke(ctx, TodoService_CreateSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSavedSearchesResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListSavedSearches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetSavedSearch(ctx context.Context, in *GetSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedSearch)
+	err := c.cc.Invoke(ctx, TodoService_GetSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateSavedSearch(ctx context.Context, in *UpdateSavedSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedSearch)
+	err := c.cc.Invoke(ctx, TodoService_UpdateSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoService_DeleteSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RunSavedSearch(ctx context.Context, in *RunSavedSearchRequest, opts ...grpc.CallOption) (*RunSavedSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunSavedSearchResponse)
+	err := c.cc.Invoke(ctx, TodoService_RunSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ArchiveTodo(ctx context.Context, in *ArchiveTodoRequest, opts ...grpc.CallOption) (*ArchiveTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_ArchiveTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UnarchiveTodo(ctx context.Context, in *ArchiveTodoRequest, opts ...grpc.CallOption) (*ArchiveTodoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveTodoResponse)
+	err := c.cc.Invoke(ctx, TodoService_UnarchiveTodo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetArchivePolicy(ctx context.Context, in *GetArchivePolicyRequest, opts ...grpc.CallOption) (*ArchivePolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchivePolicy)
+	err := c.cc.Invoke(ctx, TodoService_GetArchivePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateArchivePolicy(ctx context.Context, in *UpdateArchivePolicyRequest, opts ...grpc.CallOption) (*ArchivePolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchivePolicy)
+	err := c.cc.Invoke(ctx, TodoService_UpdateArchivePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) StartTimer(ctx context.Context, in *StartTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, TodoService_StartTimer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) StopTimer(ctx context.Context, in *StopTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, TodoService_StopTimer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetRunningTimer(ctx context.Context, in *GetRunningTimerRequest, opts ...grpc.CallOption) (*TimeEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, TodoService_GetRunningTimer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateTimeEntry(ctx context.Context, in *CreateTimeEntryRequest, opts ...grpc.CallOption) (*TimeEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, TodoService_CreateTimeEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTimeEntries(ctx context.Context, in *ListTimeEntriesRequest, opts ...grpc.CallOption) (*ListTimeEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTimeEntriesResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListTimeEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateTimeEntry(ctx context.Context, in *UpdateTimeEntryRequest, opts ...grpc.CallOption) (*TimeEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, TodoService_UpdateTimeEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteTimeEntry(ctx context.Context, in *DeleteTimeEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoService_DeleteTimeEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTimeReport(ctx context.Context, in *GetTimeReportRequest, opts ...grpc.CallOption) (*TimeReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeReport)
+	err := c.cc.Invoke(ctx, TodoService_GetTimeReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTodoStats(ctx context.Context, in *GetTodoStatsRequest, opts ...grpc.CallOption) (*TodoStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoStats)
+	err := c.cc.Invoke(ctx, TodoService_GetTodoStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TodoEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[0], TodoService_WatchTodos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTodosRequest, TodoEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchTodosClient = grpc.ServerStreamingClient[TodoEvent]
+
+func (c *todoServiceClient) SyncTodos(ctx context.Context, in *SyncTodosRequest, opts ...grpc.CallOption) (*SyncTodosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncTodosResponse)
+	err := c.cc.Invoke(ctx, TodoService_SyncTodos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility.
@@ -140,6 +508,68 @@ type TodoServiceServer interface {
 	// --- 新增：批量更新 Todos --- //
 	// 批量更新用户的一系列 Todo (例如：批量标记完成/未完成)
 	BatchUpdateTodos(context.Context, *BatchUpdateTodosRequest) (*emptypb.Empty, error)
+	// --- 新增：Todo 模板 --- //
+	// 以已有 Todo (及其全部子任务) 为蓝本创建模板
+	CreateTemplateFromTodo(context.Context, *CreateTemplateFromTodoRequest) (*TodoTemplate, error)
+	// 获取用户的所有模板
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	// 获取单个模板
+	GetTemplate(context.Context, *GetTemplateRequest) (*TodoTemplate, error)
+	// 删除模板
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*emptypb.Empty, error)
+	// 在一个事务中将模板实例化为真实的 Todo 树
+	InstantiateTemplate(context.Context, *InstantiateTemplateRequest) (*InstantiateTemplateResponse, error)
+	// --- 新增：快速添加 --- //
+	// 解析自然语言文本 (中英文) 并创建 Todo，同时返回解析结果
+	QuickAddTodo(context.Context, *QuickAddTodoRequest) (*QuickAddTodoResponse, error)
+	// --- 新增：保存搜索 (智能列表) --- //
+	// 创建保存搜索，查询表达式有误时返回 InvalidArgument，错误信息中包含出错位置
+	CreateSavedSearch(context.Context, *CreateSavedSearchRequest) (*SavedSearch, error)
+	// 获取用户的所有保存搜索
+	ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error)
+	// 获取单个保存搜索
+	GetSavedSearch(context.Context, *GetSavedSearchRequest) (*SavedSearch, error)
+	// 更新保存搜索
+	UpdateSavedSearch(context.Context, *UpdateSavedSearchRequest) (*SavedSearch, error)
+	// 删除保存搜索
+	DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*emptypb.Empty, error)
+	// 对用户的 Todo 执行保存搜索
+	RunSavedSearch(context.Context, *RunSavedSearchRequest) (*RunSavedSearchResponse, error)
+	// --- 新增：归档 --- //
+	// 归档 Todo 及其全部子任务，归档不同于删除，可以随时恢复
+	ArchiveTodo(context.Context, *ArchiveTodoRequest) (*ArchiveTodoResponse, error)
+	// 恢复已归档的 Todo 及其子任务，同时恢复其已归档的上级任务
+	UnarchiveTodo(context.Context, *ArchiveTodoRequest) (*ArchiveTodoResponse, error)
+	// 获取用户的自动归档策略，未设置时返回默认策略 (未启用)
+	GetArchivePolicy(context.Context, *GetArchivePolicyRequest) (*ArchivePolicy, error)
+	// 设置用户的自动归档策略
+	UpdateArchivePolicy(context.Context, *UpdateArchivePolicyRequest) (*ArchivePolicy, error)
+	// --- 新增：时间记录 --- //
+	// 为 Todo 启动计时器，每个用户同一时间最多只有一个正在运行的计时器
+	StartTimer(context.Context, *StartTimerRequest) (*TimeEntry, error)
+	// 停止用户正在运行的计时器
+	StopTimer(context.Context, *StopTimerRequest) (*TimeEntry, error)
+	// 获取用户正在运行的计时器，没有时返回 NOT_FOUND
+	GetRunningTimer(context.Context, *GetRunningTimerRequest) (*TimeEntry, error)
+	// 手动添加时间记录
+	CreateTimeEntry(context.Context, *CreateTimeEntryRequest) (*TimeEntry, error)
+	// 查询时间记录
+	ListTimeEntries(context.Context, *ListTimeEntriesRequest) (*ListTimeEntriesResponse, error)
+	// 修改时间记录
+	UpdateTimeEntry(context.Context, *UpdateTimeEntryRequest) (*TimeEntry, error)
+	// 删除时间记录
+	DeleteTimeEntry(context.Context, *DeleteTimeEntryRequest) (*emptypb.Empty, error)
+	// 按天、标签或项目汇总指定时间范围内的时间记录
+	GetTimeReport(context.Context, *GetTimeReportRequest) (*TimeReport, error)
+	// --- 新增：统计 --- //
+	// 获取用户的完成情况统计，结果会短暂缓存
+	GetTodoStats(context.Context, *GetTodoStatsRequest) (*TodoStats, error)
+	// --- 新增：实时变更 --- //
+	// 订阅用户的 Todo 变更事件。先补发 resume_token 之后的事件，然后发送 READY，之后持续推送实时事件
+	WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error
+	// --- 新增：离线同步 --- //
+	// 按顺序应用客户端离线期间的修改，然后返回 sync_token 之后服务端的全部变更 (含删除墓碑)
+	SyncTodos(context.Context, *SyncTodosRequest) (*SyncTodosResponse, error)
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -168,6 +598,87 @@ func (UnimplementedTodoServiceServer) DeleteTodo(context.Context, *DeleteTodoReq
 func (UnimplementedTodoServiceServer) BatchUpdateTodos(context.Context, *BatchUpdateTodosRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateTodos not implemented")
 }
+func (UnimplementedTodoServiceServer) CreateTemplateFromTodo(context.Context, *CreateTemplateFromTodoRequest) (*TodoTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplateFromTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedTodoServiceServer) GetTemplate(context.Context, *GetTemplateRequest) (*TodoTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteTemplate(context.Context, *DeleteTemplateRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedTodoServiceServer) InstantiateTemplate(context.Context, *InstantiateTemplateRequest) (*InstantiateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstantiateTemplate not implemented")
+}
+func (UnimplementedTodoServiceServer) QuickAddTodo(context.Context, *QuickAddTodoRequest) (*QuickAddTodoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuickAddTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateSavedSearch(context.Context, *CreateSavedSearchRequest) (*SavedSearch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSavedSearch not implemented")
+}
+func (UnimplementedTodoServiceServer) ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSavedSearches not implemented")
+}
+func (UnimplementedTodoServiceServer) GetSavedSearch(context.Context, *GetSavedSearchRequest) (*SavedSearch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSavedSearch not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateSavedSearch(context.Context, *UpdateSavedSearchRequest) (*SavedSearch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSavedSearch not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSavedSearch not implemented")
+}
+func (UnimplementedTodoServiceServer) RunSavedSearch(context.Context, *RunSavedSearchRequest) (*RunSavedSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunSavedSearch not implemented")
+}
+func (UnimplementedTodoServiceServer) ArchiveTodo(context.Context, *ArchiveTodoRequest) (*ArchiveTodoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) UnarchiveTodo(context.Context, *ArchiveTodoRequest) (*ArchiveTodoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnarchiveTodo not implemented")
+}
+func (UnimplementedTodoServiceServer) GetArchivePolicy(context.Context, *GetArchivePolicyRequest) (*ArchivePolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArchivePolicy not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateArchivePolicy(context.Context, *UpdateArchivePolicyRequest) (*ArchivePolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateArchivePolicy not implemented")
+}
+func (UnimplementedTodoServiceServer) StartTimer(context.Context, *StartTimerRequest) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTimer not implemented")
+}
+func (UnimplementedTodoServiceServer) StopTimer(context.Context, *StopTimerRequest) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopTimer not implemented")
+}
+func (UnimplementedTodoServiceServer) GetRunningTimer(context.Context, *GetRunningTimerRequest) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunningTimer not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateTimeEntry(context.Context, *CreateTimeEntryRequest) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTimeEntry not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTimeEntries(context.Context, *ListTimeEntriesRequest) (*ListTimeEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimeEntries not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateTimeEntry(context.Context, *UpdateTimeEntryRequest) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTimeEntry not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteTimeEntry(context.Context, *DeleteTimeEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimeEntry not implemented")
+}
+func (UnimplementedTodoServiceServer) GetTimeReport(context.Context, *GetTimeReportRequest) (*TimeReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimeReport not implemented")
+}
+func (UnimplementedTodoServiceServer) GetTodoStats(context.Context, *GetTodoStatsRequest) (*TodoStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodoStats not implemented")
+}
+func (UnimplementedTodoServiceServer) WatchTodos(*WatchTodosRequest, grpc.ServerStreamingServer[TodoEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) SyncTodos(context.Context, *SyncTodosRequest) (*SyncTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncTodos not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 func (UnimplementedTodoServiceServer) testEmbeddedByValue()                     {}
 
@@ -297,38 +808,627 @@ func _TodoService_BatchUpdateTodos_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-// TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var TodoService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "todo.TodoService",
-	HandlerType: (*TodoServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateTodo",
-			Handler:    _TodoService_CreateTodo_Handler,
-		},
-		{
-			MethodName: "GetTodos",
-			Handler:    _TodoService_GetTodos_Handler,
-		},
-		{
-			MethodName: "GetTodoByID",
-			Handler:    _TodoService_GetTodoByID_Handler,
-		},
-		{
-			MethodName: "UpdateTodo",
-			Handler:    _TodoService_UpdateTodo_Handler,
-		},
-		{
-			MethodName: "DeleteTodo",
-			Handler:    _TodoService_DeleteTodo_Handler,
-		},
-		{
-			MethodName: "BatchUpdateTodos",
-			Handler:    _TodoService_BatchUpdateTodos_Handler,
+func _TodoService_CreateTemplateFromTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateFromTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTemplateFromTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateTemplateFromTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTemplateFromTodo(ctx, req.(*CreateTemplateFromTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_DeleteTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_InstantiateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstantiateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).InstantiateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_InstantiateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).InstantiateTemplate(ctx, req.(*InstantiateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_QuickAddTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuickAddTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).QuickAddTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_QuickAddTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).QuickAddTodo(ctx, req.(*QuickAddTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateSavedSearch(ctx, req.(*CreateSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListSavedSearches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSavedSearchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListSavedSearches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListSavedSearches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListSavedSearches(ctx, req.(*ListSavedSearchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetSavedSearch(ctx, req.(*GetSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UpdateSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateSavedSearch(ctx, req.(*UpdateSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_DeleteSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteSavedSearch(ctx, req.(*DeleteSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RunSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RunSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_RunSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RunSavedSearch(ctx, req.(*RunSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ArchiveTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ArchiveTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ArchiveTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ArchiveTodo(ctx, req.(*ArchiveTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UnarchiveTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UnarchiveTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UnarchiveTodo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UnarchiveTodo(ctx, req.(*ArchiveTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetArchivePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArchivePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetArchivePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetArchivePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetArchivePolicy(ctx, req.(*GetArchivePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateArchivePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateArchivePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateArchivePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UpdateArchivePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateArchivePolicy(ctx, req.(*UpdateArchivePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_StartTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).StartTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_StartTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).StartTimer(ctx, req.(*StartTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_StopTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).StopTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_StopTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).StopTimer(ctx, req.(*StopTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetRunningTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunningTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetRunningTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetRunningTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetRunningTimer(ctx, req.(*GetRunningTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTimeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateTimeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTimeEntry(ctx, req.(*CreateTimeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTimeEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimeEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTimeEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListTimeEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTimeEntries(ctx, req.(*ListTimeEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTimeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UpdateTimeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateTimeEntry(ctx, req.(*UpdateTimeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTimeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_DeleteTimeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTimeEntry(ctx, req.(*DeleteTimeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTimeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTimeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetTimeReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTimeReport(ctx, req.(*GetTimeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTodoStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTodoStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetTodoStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTodoStats(ctx, req.(*GetTodoStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_WatchTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).WatchTodos(m, &grpc.GenericServerStream[WatchTodosRequest, TodoEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoService_WatchTodosServer = grpc.ServerStreamingServer[TodoEvent]
+
+func _TodoService_SyncTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).SyncTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_SyncTodos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).SyncTodos(ctx, req.(*SyncTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TodoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todo.TodoService",
+	HandlerType: (*TodoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTodo",
+			Handler:    _TodoService_CreateTodo_Handler,
+		},
+		{
+			MethodName: "GetTodos",
+			Handler:    _TodoService_GetTodos_Handler,
+		},
+		{
+			MethodName: "GetTodoByID",
+			Handler:    _TodoService_GetTodoByID_Handler,
+		},
+		{
+			MethodName: "UpdateTodo",
+			Handler:    _TodoService_UpdateTodo_Handler,
+		},
+		{
+			MethodName: "DeleteTodo",
+			Handler:    _TodoService_DeleteTodo_Handler,
+		},
+		{
+			MethodName: "BatchUpdateTodos",
+			Handler:    _TodoService_BatchUpdateTodos_Handler,
+		},
+		{
+			MethodName: "CreateTemplateFromTodo",
+			Handler:    _TodoService_CreateTemplateFromTodo_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _TodoService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _TodoService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _TodoService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "InstantiateTemplate",
+			Handler:    _TodoService_InstantiateTemplate_Handler,
+		},
+		{
+			MethodName: "QuickAddTodo",
+			Handler:    _TodoService_QuickAddTodo_Handler,
+		},
+		{
+			MethodName: "CreateSavedSearch",
+			Handler:    _TodoService_CreateSavedSearch_Handler,
+		},
+		{
+			MethodName: "ListSavedSearches",
+			Handler:    _TodoService_ListSavedSearches_Handler,
+		},
+		{
+			MethodName: "GetSavedSearch",
+			Handler:    _TodoService_GetSavedSearch_Handler,
+		},
+		{
+			MethodName: "UpdateSavedSearch",
+			Handler:    _TodoService_UpdateSavedSearch_Handler,
+		},
+		{
+			MethodName: "DeleteSavedSearch",
+			Handler:    _TodoService_DeleteSavedSearch_Handler,
+		},
+		{
+			MethodName: "RunSavedSearch",
+			Handler:    _TodoService_RunSavedSearch_Handler,
+		},
+		{
+			MethodName: "ArchiveTodo",
+			Handler:    _TodoService_ArchiveTodo_Handler,
+		},
+		{
+			MethodName: "UnarchiveTodo",
+			Handler:    _TodoService_UnarchiveTodo_Handler,
+		},
+		{
+			MethodName: "GetArchivePolicy",
+			Handler:    _TodoService_GetArchivePolicy_Handler,
+		},
+		{
+			MethodName: "UpdateArchivePolicy",
+			Handler:    _TodoService_UpdateArchivePolicy_Handler,
+		},
+		{
+			MethodName: "StartTimer",
+			Handler:    _TodoService_StartTimer_Handler,
+		},
+		{
+			MethodName: "StopTimer",
+			Handler:    _TodoService_StopTimer_Handler,
+		},
+		{
+			MethodName: "GetRunningTimer",
+			Handler:    _TodoService_GetRunningTimer_Handler,
+		},
+		{
+			MethodName: "CreateTimeEntry",
+			Handler:    _TodoService_CreateTimeEntry_Handler,
+		},
+		{
+			MethodName: "ListTimeEntries",
+			Handler:    _TodoService_ListTimeEntries_Handler,
+		},
+		{
+			MethodName: "UpdateTimeEntry",
+			Handler:    _TodoService_UpdateTimeEntry_Handler,
+		},
+		{
+			MethodName: "DeleteTimeEntry",
+			Handler:    _TodoService_DeleteTimeEntry_Handler,
+		},
+		{
+			MethodName: "GetTimeReport",
+			Handler:    _TodoService_GetTimeReport_Handler,
+		},
+		{
+			MethodName: "GetTodoStats",
+			Handler:    _TodoService_GetTodoStats_Handler,
+		},
+		{
+			MethodName: "SyncTodos",
+			Handler:    _TodoService_SyncTodos_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTodos",
+			Handler:       _TodoService_WatchTodos_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo.proto",
 }
@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
+	"time"
+	_ "time/tzdata" // 运行镜像中没有时区数据库，校验用户资料中的时区时使用内置的数据
 
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	"todo-project/user-service/internal/config"
 	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/keys"
 	"todo-project/user-service/internal/mq"
+	"todo-project/user-service/internal/password"
+	"todo-project/user-service/internal/revocation"
 	"todo-project/user-service/internal/service"
+	"todo-project/user-service/internal/throttle"
 	pb "todo-project/user-service/proto/user"
 )
 
@@ -19,7 +29,15 @@ func main() {
 	cfg := config.LoadConfig()
 	log.Printf("当前运行环境: %s", cfg.AppEnv)
 
-	if string(cfg.JWTSecretKey) == "" {
+	// user-service migrate up|down|status: 只执行数据库迁移，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("迁移失败: %v", err)
+		}
+		return
+	}
+
+	if cfg.JWTSigningAlg == keys.AlgHS256 && string(cfg.JWTSecretKey) == "" {
 		log.Fatal("JWT_SECRET_KEY 环境变量未设置!")
 	}
 
@@ -44,8 +62,44 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	signingKeys, err := initSigningKeys(cfg)
+	if err != nil {
+		log.Fatalf("无法初始化签名密钥: %v", err)
+	}
+	go signingKeys.Run(context.Background())
+
 	s := grpc.NewServer()
-	userService := service.NewUserService(cfg.JWTSecretKey)
+	switch cfg.EmailVerificationPolicy {
+	case service.EmailVerificationOff, service.EmailVerificationOptional, service.EmailVerificationRequired:
+	default:
+		log.Fatalf("不支持的 EMAIL_VERIFICATION_POLICY: %s (可选 off、optional、required)", cfg.EmailVerificationPolicy)
+	}
+	emailVerification := service.EmailVerificationOptions{
+		Policy:         cfg.EmailVerificationPolicy,
+		TokenTTL:       cfg.EmailVerificationTTL,
+		GracePeriod:    cfg.EmailVerificationGracePeriod,
+		ResendInterval: cfg.EmailVerificationResendInterval,
+		MaxSendsPerDay: cfg.EmailVerificationMaxPerDay,
+	}
+	log.Printf("邮箱验证策略: %s", cfg.EmailVerificationPolicy)
+
+	rdb := initRedis(cfg)
+	userService := service.NewUserService(signingKeys, initRevocationStore(rdb), initLoginGuard(cfg, rdb), service.Options{
+		AccessTokenTTL:    cfg.AccessTokenTTL,
+		RefreshTokenTTL:   cfg.RefreshTokenTTL,
+		EmailVerification: emailVerification,
+		PasswordReset: service.PasswordResetOptions{
+			TokenTTL:        cfg.PasswordResetTTL,
+			RequestInterval: cfg.PasswordResetRequestInterval,
+		},
+		TwoFactor: service.TwoFactorOptions{
+			Issuer:       cfg.TOTPIssuer,
+			ChallengeTTL: cfg.TwoFactorChallengeTTL,
+			MaxAttempts:  cfg.TwoFactorMaxAttempts,
+		},
+		PasswordPolicy: initPasswordPolicy(cfg),
+		PasswordHasher: initPasswordHasher(cfg),
+	})
 	pb.RegisterUserServiceServer(s, userService)
 
 	// 注册反射服务，便于调试
@@ -58,3 +112,116 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// initSigningKeys 按 JWT_SIGNING_ALG 创建访问令牌的签名密钥
+func initSigningKeys(cfg *config.Config) (*keys.Manager, error) {
+	if cfg.JWTSigningAlg == keys.AlgHS256 {
+		log.Printf("访问令牌使用 HS256 共享密钥签名")
+		return keys.NewHMACManager(cfg.JWTSecretKey), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	signingKeys, err := keys.NewManager(ctx, database.DB, cfg.JWTSigningAlg, cfg.JWTKeyRotationInterval, cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("访问令牌使用 %s 签名，密钥每 %s 轮换一次，公钥通过 GetJWKS 发布", cfg.JWTSigningAlg, cfg.JWTKeyRotationInterval)
+	return signingKeys, nil
+}
+
+// initRedis 连接保存令牌撤销记录和登录失败记录的 Redis，未配置或连接失败时返回 nil
+func initRedis(cfg *config.Config) *redis.Client {
+	if cfg.RedisAddr == "" {
+		log.Printf("警告: 未配置 REDIS_ADDR，令牌撤销记录和登录失败记录只保存在本进程内，API 网关无法据此拒绝已撤销的令牌")
+		return nil
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPass, DB: cfg.RedisDB})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Printf("警告: 连接 Redis (%s) 失败: %v。令牌撤销记录和登录失败记录只保存在本进程内", cfg.RedisAddr, err)
+		rdb.Close()
+		return nil
+	}
+	log.Printf("令牌撤销记录和登录失败记录保存在 Redis (%s, DB=%d)", cfg.RedisAddr, cfg.RedisDB)
+	return rdb
+}
+
+// initRevocationStore 令牌撤销记录保存在 Redis 中，没有 Redis 时退回进程内存储
+func initRevocationStore(rdb *redis.Client) revocation.Store {
+	if rdb == nil {
+		return revocation.NewMemoryStore()
+	}
+	return revocation.NewRedisStore(rdb)
+}
+
+// initLoginGuard 按配置创建登录限制，按用户名和按 IP 的策略共用等待时间和锁定时长
+func initLoginGuard(cfg *config.Config, rdb *redis.Client) *throttle.Guard {
+	var store throttle.Store = throttle.NewMemoryStore()
+	if rdb != nil {
+		store = throttle.NewRedisStore(rdb)
+	}
+	user := throttle.Policy{
+		BackoffAfter: cfg.LoginBackoffAfter,
+		BaseDelay:    cfg.LoginBackoffBaseDelay,
+		MaxDelay:     cfg.LoginBackoffMaxDelay,
+		LockAfter:    cfg.LoginLockoutAfter,
+		LockDuration: cfg.LoginLockoutDuration,
+		Window:       cfg.LoginFailureWindow,
+	}
+	ip := user
+	ip.BackoffAfter = cfg.LoginIPBackoffAfter
+	ip.LockAfter = cfg.LoginIPLockoutAfter
+	return throttle.New(store, user, ip)
+}
+
+// initPasswordPolicy 按配置创建密码策略，配置了泄露密码列表但无法打开时退出
+func initPasswordPolicy(cfg *config.Config) password.Policy {
+	if cfg.PasswordMaxBytes > password.MaxBcryptBytes {
+		log.Printf("警告: PASSWORD_MAX_BYTES=%d 超过 bcrypt 的上限，按 %d 处理", cfg.PasswordMaxBytes, password.MaxBcryptBytes)
+	}
+	policy := password.Policy{
+		MinLength:        cfg.PasswordMinLength,
+		MaxBytes:         cfg.PasswordMaxBytes,
+		MinCharClasses:   cfg.PasswordMinCharClasses,
+		DisallowUserInfo: cfg.PasswordDisallowUserInfo,
+	}
+	if cfg.PasswordBreachedListFile != "" {
+		breached, err := password.OpenBreachedList(cfg.PasswordBreachedListFile)
+		if err != nil {
+			log.Fatalf("无法打开泄露密码列表: %v", err)
+		}
+		policy.Breached = breached
+		log.Printf("新密码将与泄露密码列表 %s 比对", cfg.PasswordBreachedListFile)
+	}
+	return policy
+}
+
+// initPasswordHasher 按 PASSWORD_HASH_ALG 选择生成新哈希的算法，另一种算法生成的旧哈希仍能验证
+func initPasswordHasher(cfg *config.Config) *password.Hasher {
+	if cfg.Argon2Memory <= 0 || cfg.Argon2Iterations <= 0 || cfg.Argon2Parallelism <= 0 || cfg.Argon2Parallelism > 255 {
+		log.Fatalf("无效的 argon2id 参数: ARGON2_MEMORY=%d, ARGON2_ITERATIONS=%d, ARGON2_PARALLELISM=%d (1-255)",
+			cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+	}
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		log.Fatalf("无效的 BCRYPT_COST: %d (%d-%d)", cfg.BcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	argon2id := password.Argon2id{
+		Memory:      uint32(cfg.Argon2Memory),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	}
+	bcryptAlg := password.Bcrypt{Cost: cfg.BcryptCost}
+
+	switch cfg.PasswordHashAlg {
+	case password.AlgArgon2id:
+		log.Printf("新密码使用 argon2id 哈希 (m=%d KiB, t=%d, p=%d)", argon2id.Memory, argon2id.Iterations, argon2id.Parallelism)
+		return password.NewHasher(argon2id, bcryptAlg)
+	case password.AlgBcrypt:
+		log.Printf("新密码使用 bcrypt 哈希 (cost=%d)", cfg.BcryptCost)
+		return password.NewHasher(bcryptAlg, argon2id)
+	default:
+		log.Fatalf("不支持的 PASSWORD_HASH_ALG: %s (可选 argon2id、bcrypt)", cfg.PasswordHashAlg)
+		return nil
+	}
+}
@@ -1,40 +1,76 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"todo-project/user-service/internal/config"
-	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/migrate"
+	"todo-project/user-service/migrations"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// 迁移记录中 user-service 的服务名
+const migrationService = "user-service"
+
 // DB 全局数据库连接
 var DB *gorm.DB
 
-// InitDB 初始化数据库连接
+// InitDB 初始化数据库连接，cfg.DBDriver 决定使用 MySQL 还是 PostgreSQL。
+// cfg.MigrateOnStart 为 true 时执行未执行的数据库迁移
 func InitDB(cfg *config.Config) error {
-	var err error
+	if err := Open(cfg); err != nil {
+		return err
+	}
+	if !cfg.MigrateOnStart {
+		log.Printf("DB_MIGRATE_ON_START=false，跳过数据库迁移")
+		return nil
+	}
+	migrator, err := NewMigrator()
+	if err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		log.Printf("已执行数据库迁移 %s", migration)
+	}
+	if err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	if len(applied) == 0 {
+		log.Printf("数据库结构已是最新")
+	}
+	return nil
+}
 
-	log.Printf("数据库连接配置: User=%s, Host=%s, Port=%s, DB=%s",
-		cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
+// Open 连接数据库并设置 DB，不执行迁移
+func Open(cfg *config.Config) error {
+	var err error
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	log.Printf("数据库连接配置: Driver=%s, User=%s, Host=%s, Port=%s, DB=%s",
+		cfg.DBDriver, cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
 
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	dsn, _, err := DSN(cfg)
 	if err != nil {
-		return fmt.Errorf("数据库连接失败: %w", err)
+		return err
 	}
 
-	// 自动迁移数据库表结构
-	err = DB.AutoMigrate(&models.User{})
+	DB, err = gorm.Open(dialector(cfg.DBDriver, dsn), &gorm.Config{})
 	if err != nil {
-		return fmt.Errorf("数据库迁移失败: %w", err)
+		return fmt.Errorf("数据库连接失败: %w", err)
 	}
 
 	log.Println("成功连接到数据库")
 	return nil
 }
+
+// NewMigrator 创建 user-service 的迁移执行器，迁移文件见 migrations 目录。需要先调用 Open
+func NewMigrator() (*migrate.Migrator, error) {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, DB.Dialector.Name(), migrationService, migrations.FS)
+}
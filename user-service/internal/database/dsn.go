@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"todo-project/user-service/internal/config"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DSN 按 cfg.DBDriver 生成 MySQL 或 PostgreSQL 的连接串。redacted 与 dsn 相同但隐藏了密码，用于日志
+func DSN(cfg *config.Config) (dsn, redacted string, err error) {
+	switch cfg.DBDriver {
+	case "mysql":
+		format := "%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local"
+		addr := net.JoinHostPort(cfg.DBHost, cfg.DBPort)
+		return fmt.Sprintf(format, cfg.DBUser, cfg.DBPass, addr, cfg.DBName),
+			fmt.Sprintf(format, cfg.DBUser, "***", addr, cfg.DBName), nil
+	case "postgres":
+		// 使用 URL 形式，用户名、密码和库名中的特殊字符会被转义
+		u := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.DBUser, cfg.DBPass),
+			Host:     net.JoinHostPort(cfg.DBHost, cfg.DBPort),
+			Path:     "/" + cfg.DBName,
+			RawQuery: url.Values{"sslmode": {cfg.DBSSLMode}}.Encode(),
+		}
+		return u.String(), u.Redacted(), nil
+	default:
+		return "", "", fmt.Errorf("不支持的数据库驱动: %s (可选 mysql、postgres)", cfg.DBDriver)
+	}
+}
+
+// dialector 返回 DSN 对应驱动的 GORM Dialector
+func dialector(driver, dsn string) gorm.Dialector {
+	if driver == "postgres" {
+		return postgres.Open(dsn)
+	}
+	return mysql.Open(dsn)
+}
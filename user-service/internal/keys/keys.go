@@ -0,0 +1,327 @@
+// Package keys 管理签发访问令牌 (JWT) 的密钥。
+//
+// HS256 使用与 API 网关共享的 JWT_SECRET_KEY，能校验令牌的服务同样能签发令牌。
+// RS256 和 EdDSA 使用非对称密钥：私钥保存在数据库的 signing_keys 表中，由所有 user-service 实例共享；
+// 公钥以 JWKS 的形式发布，API 网关只能校验令牌。令牌头部的 kid 标识签名使用的密钥。
+//
+// 当前密钥使用超过轮换周期后生成新密钥，旧密钥停止签名但继续发布，直到它签发的令牌全部过期后删除。
+package keys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/models"
+)
+
+// 支持的签名算法，与 JWT 头部的 alg 一致
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits = 2048
+	// 每隔多久从数据库重新加载密钥并检查是否需要轮换
+	refreshInterval = time.Minute
+	// 遇到未知 kid 时重新加载密钥的最小间隔，其他实例可能刚刚完成轮换
+	reloadOnMissInterval = 10 * time.Second
+)
+
+// ErrUnknownKey 令牌头部的 kid 不对应任何已知密钥
+var ErrUnknownKey = errors.New("未知的签名密钥")
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	retiredAt *time.Time
+}
+
+// Manager 签名密钥集合，可并发使用
+type Manager struct {
+	alg    string
+	secret []byte // 仅 HS256 使用
+
+	db               *gorm.DB
+	rotationInterval time.Duration
+	// 密钥停止签名后继续发布的时间。其他实例最长在 refreshInterval 之后才会发现轮换，
+	// 期间仍用旧密钥签名，因此在访问令牌有效期之外再多保留两个刷新周期
+	retention time.Duration
+
+	mu         sync.RWMutex
+	keys       []signingKey // 按创建时间倒序，第一个未停用且算法匹配的密钥用于签名
+	lastReload time.Time
+}
+
+// NewHMACManager 创建使用共享密钥 secret 签名 (HS256) 的密钥集合，不发布公钥
+func NewHMACManager(secret []byte) *Manager {
+	return &Manager{alg: AlgHS256, secret: secret}
+}
+
+// NewManager 创建使用非对称密钥签名的密钥集合，alg 为 RS256 或 EdDSA。
+// 数据库中没有可用的密钥或当前密钥已超过 rotationInterval 时立即生成新密钥。
+// accessTokenTTL 为访问令牌的有效期，决定旧密钥继续发布的时间
+func NewManager(ctx context.Context, db *gorm.DB, alg string, rotationInterval, accessTokenTTL time.Duration) (*Manager, error) {
+	if alg != AlgRS256 && alg != AlgEdDSA {
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+	m := &Manager{
+		alg:              alg,
+		db:               db,
+		rotationInterval: rotationInterval,
+		retention:        accessTokenTTL + 2*refreshInterval,
+	}
+	if err := m.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Run 定期重新加载密钥并在到期时轮换，直到 ctx 被取消。HS256 时直接返回
+func (m *Manager) Run(ctx context.Context) {
+	if m.db == nil {
+		return
+	}
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := m.refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("警告: 刷新签名密钥失败: %v，继续使用已加载的密钥", err)
+		}
+	}
+}
+
+// Sign 签发令牌，非对称密钥在头部写入 kid
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	if m.alg == AlgHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	}
+	m.mu.RLock()
+	key, ok := m.current()
+	m.mu.RUnlock()
+	if !ok {
+		return "", errors.New("没有可用的签名密钥")
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// Keyfunc 按令牌头部的 kid 返回校验签名的公钥，用于 jwt.Parse。令牌的签名算法必须与密钥一致
+func (m *Manager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if m.alg == AlgHS256 {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("非预期的签名方法: %v", token.Header["alg"])
+		}
+		return m.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.lookup(kid)
+	if !ok && m.reloadOnMiss() {
+		key, ok = m.lookup(kid)
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("非预期的签名方法: %v", token.Header["alg"])
+	}
+	return key.private.Public(), nil
+}
+
+// PublicKeys 返回当前发布的所有公钥，HS256 时为空
+func (m *Manager) PublicKeys() []JWK {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jwks := make([]JWK, 0, len(m.keys))
+	for _, key := range m.keys {
+		jwks = append(jwks, newJWK(key))
+	}
+	return jwks
+}
+
+// current 返回用于签名的密钥，调用方需持有 mu
+func (m *Manager) current() (signingKey, bool) {
+	for _, key := range m.keys {
+		if key.retiredAt == nil && key.method.Alg() == m.alg {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
+
+func (m *Manager) lookup(kid string) (signingKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.keys {
+		if key.id == kid {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
+
+// reloadOnMiss 距离上次加载超过 reloadOnMissInterval 时重新加载密钥，返回是否重新加载成功
+func (m *Manager) reloadOnMiss() bool {
+	m.mu.RLock()
+	recent := time.Since(m.lastReload) < reloadOnMissInterval
+	m.mu.RUnlock()
+	if recent {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.load(ctx); err != nil {
+		log.Printf("警告: 重新加载签名密钥失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// refresh 必要时轮换密钥，删除已过保留期的旧密钥，然后重新加载
+func (m *Manager) refresh(ctx context.Context) error {
+	if err := m.rotate(ctx); err != nil {
+		return fmt.Errorf("轮换签名密钥失败: %w", err)
+	}
+	now := time.Now()
+	if err := m.db.WithContext(ctx).Where("retired_at < ?", now.Add(-m.retention)).Delete(&models.SigningKey{}).Error; err != nil {
+		return fmt.Errorf("删除过期的签名密钥失败: %w", err)
+	}
+	return m.load(ctx)
+}
+
+// rotate 当前算法没有可用密钥或当前密钥已超过轮换周期时生成新密钥，并停用其他所有密钥。
+// 多个实例同时轮换时可能各生成一个密钥，下一次轮换会停用多余的密钥，不影响令牌校验
+func (m *Manager) rotate(ctx context.Context) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var current []models.SigningKey
+		err := tx.Where("algorithm = ? AND retired_at IS NULL", m.alg).Order("created_at DESC").Limit(1).Find(&current).Error
+		if err != nil {
+			return err
+		}
+		if len(current) > 0 && now.Sub(current[0].CreatedAt) < m.rotationInterval {
+			// 切换算法后停用其他算法的密钥
+			return tx.Model(&models.SigningKey{}).
+				Where("algorithm <> ? AND retired_at IS NULL", m.alg).
+				Update("retired_at", now).Error
+		}
+
+		record, err := generateKey(m.alg, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SigningKey{}).
+			Where("id <> ? AND retired_at IS NULL", record.ID).
+			Update("retired_at", now).Error; err != nil {
+			return err
+		}
+		log.Printf("已生成新的 %s 签名密钥 %s", m.alg, record.ID)
+		return nil
+	})
+}
+
+// load 从数据库加载所有仍在发布期内的密钥
+func (m *Manager) load(ctx context.Context) error {
+	var records []models.SigningKey
+	err := m.db.WithContext(ctx).
+		Where("retired_at IS NULL OR retired_at >= ?", time.Now().Add(-m.retention)).
+		Order("created_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return err
+	}
+	keys := make([]signingKey, 0, len(records))
+	for _, record := range records {
+		key, err := parseKey(record)
+		if err != nil {
+			log.Printf("警告: 忽略无法解析的签名密钥 %s: %v", record.ID, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	m.mu.Lock()
+	m.keys = keys
+	m.lastReload = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// generateKey 生成 alg 算法的新密钥对，kid 为随机字符串
+func generateKey(alg string, now time.Time) (*models.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{
+		ID:         base64.RawURLEncoding.EncodeToString(id),
+		Algorithm:  alg,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		CreatedAt:  now,
+	}, nil
+}
+
+func parseKey(record models.SigningKey) (signingKey, error) {
+	block, _ := pem.Decode([]byte(record.PrivateKey))
+	if block == nil {
+		return signingKey{}, errors.New("私钥不是有效的 PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, err
+	}
+	key := signingKey{id: record.ID, retiredAt: record.RetiredAt}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private = jwt.SigningMethodRS256, private
+	case ed25519.PrivateKey:
+		key.method, key.private = jwt.SigningMethodEdDSA, private
+	default:
+		return signingKey{}, fmt.Errorf("不支持的私钥类型 %T", parsed)
+	}
+	if key.method.Alg() != record.Algorithm {
+		return signingKey{}, fmt.Errorf("私钥类型与算法 %s 不符", record.Algorithm)
+	}
+	return key, nil
+}
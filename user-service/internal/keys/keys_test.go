@@ -0,0 +1,280 @@
+package keys
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"todo-project/user-service/internal/models"
+)
+
+const (
+	testRotation = 24 * time.Hour
+	testTokenTTL = 15 * time.Minute
+)
+
+var testDBCount atomic.Int64
+
+// openTestDB 每次打开一个新的只包含 signing_keys 表的内存 SQLite 数据库
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:keys%d?mode=memory", testDBCount.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.SigningKey{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newTestManager(t *testing.T, db *gorm.DB, alg string) *Manager {
+	t.Helper()
+	m, err := NewManager(context.Background(), db, alg, testRotation, testTokenTTL)
+	if err != nil {
+		t.Fatalf("NewManager(%s) 失败: %v", alg, err)
+	}
+	return m
+}
+
+func sign(t *testing.T, m *Manager) string {
+	t.Helper()
+	token, err := m.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(testTokenTTL).Unix()})
+	if err != nil {
+		t.Fatalf("Sign 失败: %v", err)
+	}
+	return token
+}
+
+func kidOf(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+// publicKeyFromJWK 按 RFC 7517 从发布的 JWK 还原公钥，与 API 网关的解析方式相同
+func publicKeyFromJWK(t *testing.T, jwk JWK) interface{} {
+	t.Helper()
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("JWK %s 的字段不是 Base64URL: %v", jwk.Kid, err)
+		}
+		return b
+	}
+	switch jwk.Kty {
+	case "RSA":
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(decode(jwk.N)), E: int(new(big.Int).SetBytes(decode(jwk.E)).Int64())}
+	case "OKP":
+		return ed25519.PublicKey(decode(jwk.X))
+	}
+	t.Fatalf("未知的 JWK 类型 %s", jwk.Kty)
+	return nil
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			m := newTestManager(t, openTestDB(t), alg)
+			token := sign(t, m)
+
+			parsed, err := jwt.Parse(token, m.Keyfunc)
+			if err != nil || !parsed.Valid {
+				t.Fatalf("校验令牌失败: %v", err)
+			}
+			if parsed.Method.Alg() != alg {
+				t.Errorf("签名算法 = %s, 期望 %s", parsed.Method.Alg(), alg)
+			}
+
+			// 只用发布的 JWKS 也能校验令牌
+			jwks := m.PublicKeys()
+			if len(jwks) != 1 || jwks[0].Kid != kidOf(t, token) || jwks[0].Alg != alg || jwks[0].Use != "sig" {
+				t.Fatalf("PublicKeys = %+v, 期望唯一的 %s 公钥 %s", jwks, alg, kidOf(t, token))
+			}
+			public := publicKeyFromJWK(t, jwks[0])
+			if _, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return public, nil }); err != nil {
+				t.Errorf("用 JWKS 中的公钥校验失败: %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyfuncRejects(t *testing.T) {
+	m := newTestManager(t, openTestDB(t), AlgRS256)
+	other := newTestManager(t, openTestDB(t), AlgRS256)
+	kid := m.PublicKeys()[0].Kid
+
+	// 以公开的 RSA 模数作为 HMAC 密钥伪造令牌 (算法混淆)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1})
+	forged.Header["kid"] = kid
+	forgedHS256, err := forged.SignedString([]byte(m.PublicKeys()[0].N))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 其他密钥集合签发的令牌，kid 改为本集合的 kid
+	impostorToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"user_id": 1})
+	impostorToken.Header["kid"] = kid
+	impostor, err := impostorToken.SignedString(other.keys[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		unknown bool // 期望 ErrUnknownKey
+	}{
+		{name: "HS256 使用 RSA 的 kid", token: forgedHS256},
+		{name: "签名不匹配", token: impostor},
+		{name: "未知的 kid", token: sign(t, other), unknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwt.Parse(tt.token, m.Keyfunc)
+			if err == nil {
+				t.Fatal("令牌通过了校验")
+			}
+			if got := errors.Is(err, ErrUnknownKey); got != tt.unknown {
+				t.Errorf("errors.Is(%v, ErrUnknownKey) = %t, 期望 %t", err, got, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestManager(t, db, AlgEdDSA)
+	oldToken := sign(t, m)
+	oldKid := kidOf(t, oldToken)
+
+	// 未到轮换周期时不生成新密钥
+	if err := m.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := kidOf(t, sign(t, m)); got != oldKid {
+		t.Fatalf("未到轮换周期时签名密钥从 %s 变为 %s", oldKid, got)
+	}
+
+	// 当前密钥超过轮换周期后生成新密钥，旧密钥停止签名但继续发布
+	db.Model(&models.SigningKey{}).Where("id = ?", oldKid).Update("created_at", time.Now().Add(-testRotation-time.Minute))
+	if err := m.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	newToken := sign(t, m)
+	if kidOf(t, newToken) == oldKid {
+		t.Fatal("超过轮换周期后仍使用旧密钥签名")
+	}
+	if got := len(m.PublicKeys()); got != 2 {
+		t.Fatalf("轮换后发布了 %d 个公钥, 期望 2 个", got)
+	}
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := jwt.Parse(token, m.Keyfunc); err != nil {
+			t.Errorf("轮换后校验 %s 的令牌失败: %v", kidOf(t, token), err)
+		}
+	}
+
+	// 旧密钥停用超过保留期后被删除，它签发的令牌被拒绝
+	db.Model(&models.SigningKey{}).Where("id = ?", oldKid).Update("retired_at", time.Now().Add(-m.retention-time.Minute))
+	if err := m.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(m.PublicKeys()); got != 1 {
+		t.Errorf("保留期过后发布了 %d 个公钥, 期望 1 个", got)
+	}
+	if _, err := jwt.Parse(oldToken, m.Keyfunc); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("旧密钥删除后 err = %v, 期望 ErrUnknownKey", err)
+	}
+}
+
+func TestAlgorithmSwitchRetiresOtherKeys(t *testing.T) {
+	db := openTestDB(t)
+	rs := newTestManager(t, db, AlgRS256)
+	rsToken := sign(t, rs)
+
+	ed := newTestManager(t, db, AlgEdDSA)
+	edToken := sign(t, ed)
+	if parsed, err := jwt.Parse(edToken, ed.Keyfunc); err != nil || parsed.Method.Alg() != AlgEdDSA {
+		t.Fatalf("切换算法后签发的令牌校验失败: %v", err)
+	}
+	// 切换前签发的 RS256 令牌在过期前仍然有效
+	if _, err := jwt.Parse(rsToken, ed.Keyfunc); err != nil {
+		t.Errorf("切换算法后旧令牌校验失败: %v", err)
+	}
+	var active int64
+	db.Model(&models.SigningKey{}).Where("retired_at IS NULL").Count(&active)
+	if active != 1 {
+		t.Errorf("未停用的密钥有 %d 个, 期望 1 个", active)
+	}
+}
+
+// 其他实例轮换密钥后，遇到未知 kid 时重新加载
+func TestKeyfuncReloadsOnMiss(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestManager(t, db, AlgRS256)
+	other := newTestManager(t, db, AlgRS256)
+
+	db.Model(&models.SigningKey{}).Where("1 = 1").Update("created_at", time.Now().Add(-testRotation-time.Minute))
+	if err := other.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	token := sign(t, other)
+
+	// 刚加载过密钥时不重新加载
+	if _, err := jwt.Parse(token, m.Keyfunc); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("刚加载过密钥时 err = %v, 期望 ErrUnknownKey", err)
+	}
+	m.mu.Lock()
+	m.lastReload = time.Now().Add(-reloadOnMissInterval)
+	m.mu.Unlock()
+	if _, err := jwt.Parse(token, m.Keyfunc); err != nil {
+		t.Errorf("重新加载后校验失败: %v", err)
+	}
+}
+
+func TestHMACManager(t *testing.T) {
+	m := NewHMACManager([]byte("test-secret"))
+	token := sign(t, m)
+	if _, err := jwt.Parse(token, m.Keyfunc); err != nil {
+		t.Fatalf("校验 HS256 令牌失败: %v", err)
+	}
+	if kid := kidOf(t, token); kid != "" {
+		t.Errorf("HS256 令牌包含 kid %s", kid)
+	}
+	if keys := m.PublicKeys(); len(keys) != 0 {
+		t.Errorf("HS256 发布了公钥 %+v", keys)
+	}
+
+	// 非对称密钥签发的令牌被拒绝
+	rs := newTestManager(t, openTestDB(t), AlgRS256)
+	if _, err := jwt.Parse(sign(t, rs), m.Keyfunc); err == nil {
+		t.Error("HS256 密钥集合接受了 RS256 令牌")
+	}
+	if _, err := NewManager(context.Background(), openTestDB(t), AlgHS256, testRotation, testTokenTTL); err == nil {
+		t.Error("NewManager 接受了 HS256")
+	}
+}
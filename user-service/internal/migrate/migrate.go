@@ -0,0 +1,382 @@
+// Package migrate 执行版本化的 SQL 迁移。
+//
+// 迁移文件按方言分目录存放 (mysql、postgres、sqlite)，文件名为 <版本>_<名称>.up.sql 和
+// <版本>_<名称>.down.sql，每个版本必须同时提供两个文件。已执行的版本记录在 schema_migrations 表中，
+// 以服务名区分，多个服务可以共用同一个数据库。
+//
+// 执行迁移前先获取数据库的咨询锁 (MySQL 的 GET_LOCK，PostgreSQL 的 pg_advisory_lock)，
+// 多个副本同时启动时只有一个执行迁移，其余等待其完成后发现没有待执行的迁移。
+// SQLite 只用于单实例部署，不加锁。
+//
+// 每个迁移在一个事务中执行并记录版本。MySQL 的 DDL 会隐式提交，无法回滚，
+// 迁移中途失败时需要手动修复数据库后再重新执行。
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 等待其他副本完成迁移的最长时间 (MySQL)
+const lockTimeout = 10 * time.Minute
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil 表示尚未执行
+	Unknown   bool       // 数据库中记录已执行，但当前程序中没有这个迁移 (通常是由更新的版本执行的)
+}
+
+// Migrator 对一个数据库执行某个服务的迁移
+type Migrator struct {
+	db         *sql.DB
+	dialect    *dialect
+	service    string
+	migrations []Migration
+}
+
+// New 创建 Migrator。dialect 为 mysql、postgres 或 sqlite (与 GORM Dialector.Name() 一致)，
+// 迁移文件从 fsys 中与 dialect 同名的目录读取
+func New(db *sql.DB, dialectName, service string, fsys fs.FS) (*Migrator, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("迁移不支持数据库方言: %s", dialectName)
+	}
+	sub, err := fs.Sub(fsys, dialectName)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, service: service, migrations: migrations}, nil
+}
+
+// Load 读取目录中的迁移文件，按版本升序返回
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("无法识别的迁移文件名: %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("迁移文件 %s 的版本号无效", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件 %s 失败: %w", entry.Name(), err)
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本 %d 对应多个名称: %s 和 %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("迁移 %s 缺少 up 或 down 文件", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Up 按版本顺序执行所有尚未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := m.run(ctx, conn, migration.Up, m.dialect.insert, m.service, migration.Version, migration.Name, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("执行迁移 %s 失败: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本倒序回滚最近执行的 steps 个迁移，返回被回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("回滚的迁移数量必须大于 0")
+	}
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		if len(versions) > steps {
+			versions = versions[:steps]
+		}
+		for _, version := range versions {
+			migration, ok := known[version]
+			if !ok {
+				return fmt.Errorf("无法回滚迁移 %04d_%s: 当前程序中没有这个迁移", version, done[version].name)
+			}
+			if err := m.run(ctx, conn, migration.Down, m.dialect.delete, m.service, migration.Version); err != nil {
+				return fmt.Errorf("回滚迁移 %s 失败: %w", migration, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status 返回所有迁移的执行状态，按版本升序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return nil, fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+	}
+	done, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := done[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+			delete(done, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range done {
+		appliedAt := record.appliedAt
+		statuses = append(statuses, Status{Version: version, Name: record.name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock 在一个独占的连接上获取迁移锁后执行 fn。咨询锁属于会话，加锁、迁移和解锁必须使用同一个连接
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	key := "schema_migrations:" + m.service
+	if err := m.dialect.lock(ctx, conn, key); err != nil {
+		return fmt.Errorf("获取迁移锁失败: %w", err)
+	}
+	defer func() {
+		// ctx 被取消时仍然释放锁。释放失败时丢弃这个连接，会话结束后锁随之释放
+		if err := m.dialect.unlock(context.Background(), conn, key); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	// 在锁内建表，避免多个副本并发执行 CREATE TABLE
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+	}
+	return fn(conn)
+}
+
+type appliedRecord struct {
+	name      string
+	appliedAt time.Time
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	rows, err := conn.QueryContext(ctx, m.dialect.selectApplied, m.service)
+	if err != nil {
+		return nil, fmt.Errorf("读取已执行的迁移失败: %w", err)
+	}
+	defer rows.Close()
+	done := make(map[int64]appliedRecord)
+	for rows.Next() {
+		var version int64
+		var record appliedRecord
+		if err := rows.Scan(&version, &record.name, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("读取已执行的迁移失败: %w", err)
+		}
+		done[version] = record
+	}
+	return done, rows.Err()
+}
+
+// run 在一个事务中执行迁移脚本，然后执行 record 更新 schema_migrations
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range SplitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%w\n语句: %s", err, stmt)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SplitStatements 将迁移脚本拆分为单条语句。语句以行尾的分号结束，只包含 -- 注释的行被忽略。
+// 部分驱动不支持在一次调用中执行多条语句，因此逐条执行
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(strings.TrimRight(line, " \t\r"))
+		current.WriteByte('\n')
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// dialect 迁移记录表和咨询锁在各数据库上的实现
+type dialect struct {
+	createTable   string
+	selectApplied string
+	insert        string
+	delete        string
+	lock          func(ctx context.Context, conn *sql.Conn, key string) error
+	unlock        func(ctx context.Context, conn *sql.Conn, key string) error
+}
+
+var dialects = map[string]*dialect{
+	"mysql": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"service VARCHAR(64) NOT NULL, version BIGINT NOT NULL, name VARCHAR(255) NOT NULL, " +
+			"applied_at DATETIME(3) NOT NULL, PRIMARY KEY (service, version))",
+		selectApplied: "SELECT version, name, applied_at FROM schema_migrations WHERE service = ?",
+		insert:        "INSERT INTO schema_migrations (service, version, name, applied_at) VALUES (?, ?, ?, ?)",
+		delete:        "DELETE FROM schema_migrations WHERE service = ? AND version = ?",
+		lock: func(ctx context.Context, conn *sql.Conn, key string) error {
+			// GET_LOCK 的锁名在整个 MySQL 实例内共享，加上库名区分不同的数据库
+			var acquired sql.NullInt64
+			err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(?, '@', DATABASE()), ?)", key, int(lockTimeout.Seconds())).Scan(&acquired)
+			if err != nil {
+				return err
+			}
+			if !acquired.Valid || acquired.Int64 != 1 {
+				return fmt.Errorf("等待其他实例完成迁移超时 (%s)", lockTimeout)
+			}
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, key string) error {
+			_, err := conn.ExecContext(ctx, "DO RELEASE_LOCK(CONCAT(?, '@', DATABASE()))", key)
+			return err
+		},
+	},
+	"postgres": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"service VARCHAR(64) NOT NULL, version BIGINT NOT NULL, name VARCHAR(255) NOT NULL, " +
+			"applied_at TIMESTAMPTZ NOT NULL, PRIMARY KEY (service, version))",
+		selectApplied: "SELECT version, name, applied_at FROM schema_migrations WHERE service = $1",
+		insert:        "INSERT INTO schema_migrations (service, version, name, applied_at) VALUES ($1, $2, $3, $4)",
+		delete:        "DELETE FROM schema_migrations WHERE service = $1 AND version = $2",
+		lock: func(ctx context.Context, conn *sql.Conn, key string) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryKey(key))
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn, key string) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryKey(key))
+			return err
+		},
+	},
+	"sqlite": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"service TEXT NOT NULL, version INTEGER NOT NULL, name TEXT NOT NULL, " +
+			"applied_at DATETIME NOT NULL, PRIMARY KEY (service, version))",
+		selectApplied: "SELECT version, name, applied_at FROM schema_migrations WHERE service = ?",
+		insert:        "INSERT INTO schema_migrations (service, version, name, applied_at) VALUES (?, ?, ?, ?)",
+		delete:        "DELETE FROM schema_migrations WHERE service = ? AND version = ?",
+		lock:          func(context.Context, *sql.Conn, string) error { return nil },
+		unlock:        func(context.Context, *sql.Conn, string) error { return nil },
+	},
+}
+
+// advisoryKey 将锁名转换为 pg_advisory_lock 使用的 64 位整数
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// EmailVerificationToken 邮箱验证令牌，只保存令牌的 SHA-256 摘要。令牌只能使用一次，重新发送时旧令牌被删除
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
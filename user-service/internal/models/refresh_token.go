@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌，只保存令牌的 SHA-256 摘要。
+// 同一次登录轮换产生的令牌属于同一个家族 (FamilyID)，检测到重用或登出时撤销整个家族
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	FamilyID  string     `gorm:"type:varchar(64);not null;index"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // 轮换时设置，已使用的令牌再次出现说明发生了重用
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
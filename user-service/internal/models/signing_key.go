@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// SigningKey 签发访问令牌的非对称密钥，私钥以 PKCS#8 PEM 格式保存。
+// 轮换后设置 RetiredAt，此后不再用于签名，但在它签发的令牌过期前继续发布公钥
+type SigningKey struct {
+	ID         string     `gorm:"type:varchar(64);primaryKey"` // 即令牌头部的 kid
+	Algorithm  string     `gorm:"type:varchar(16);not null"`
+	PrivateKey string     `gorm:"type:text;not null"`
+	CreatedAt  time.Time  `gorm:"not null"`
+	RetiredAt  *time.Time `gorm:"index"`
+}
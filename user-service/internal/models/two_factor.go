@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// UserTOTP 用户的 TOTP 密钥 (Base32 编码)。ConfirmedAt 为空表示用户尚未用第一个验证码确认绑定，此时两步验证未启用。
+// LastUsedStep 为最近一次验证成功的时间步，同一时间步的验证码不能重复使用
+type UserTOTP struct {
+	UserID       uint   `gorm:"primaryKey;autoIncrement:false"`
+	Secret       string `gorm:"type:varchar(64);not null"`
+	ConfirmedAt  *time.Time
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RecoveryCode 两步验证的恢复码，只保存 SHA-256 摘要。每个恢复码只能使用一次，重新生成时旧恢复码全部删除
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorChallenge 密码验证通过后等待第二步验证的登录，只保存令牌的 SHA-256 摘要。
+// Attempts 记录验证码错误的次数，达到上限后令牌失效
+type TwoFactorChallenge struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	Attempts  int       `gorm:"not null;default:0"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -6,12 +6,17 @@ import (
 
 // User 表示一个用户
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Username  string    `json:"username" gorm:"type:varchar(255);uniqueIndex;not null"`
-	Email     string    `json:"email" gorm:"type:varchar(255);not null"`
-	Password  string    `json:"-" gorm:"type:varchar(255);not null"` // 密码在 JSON 中省略
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Username      string    `json:"username" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Email         string    `json:"email" gorm:"type:varchar(255);not null"`
+	Password      string    `json:"-" gorm:"type:varchar(255);not null"`          // 密码在 JSON 中省略
+	EmailVerified bool      `json:"email_verified" gorm:"not null;default:false"` // 是否已通过验证邮件证明邮箱归属
+	DisplayName   string    `json:"display_name" gorm:"type:varchar(64);not null;default:''"`
+	AvatarURL     string    `json:"avatar_url" gorm:"type:varchar(512);not null;default:''"`
+	TimeZone      string    `json:"time_zone" gorm:"type:varchar(64);not null;default:''"` // IANA 时区名称
+	Locale        string    `json:"locale" gorm:"type:varchar(35);not null;default:''"`    // BCP 47 语言标签
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // (移除了 LoginRequest 和 LoginResponse，因为它们由 proto 定义)
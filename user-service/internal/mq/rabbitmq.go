@@ -22,8 +22,14 @@ var (
 // 队列名称常量
 const (
 	UserRegisteredQueue = "user_registered_queue"
-	MaxRabbitMQRetries  = 5
-	RabbitMQRetryDelay  = 5 * time.Second
+	// EmailVerificationQueue 注册或重新发送验证邮件时发布，email-service 发送带验证链接的邮件
+	EmailVerificationQueue = "email_verification_queue"
+	// PasswordResetQueue 申请重置密码时发布，email-service 发送带重置链接的邮件
+	PasswordResetQueue = "password_reset_queue"
+	// AccountLockedQueue 账号因登录失败次数过多被锁定时发布的安全事件，email-service 通知用户
+	AccountLockedQueue = "account_locked_queue"
+	MaxRabbitMQRetries = 5
+	RabbitMQRetryDelay = 5 * time.Second
 )
 
 // ConnectRabbitMQ 连接到RabbitMQ
@@ -67,49 +73,88 @@ func ConnectRabbitMQ(cfg *config.Config) error {
 	}
 
 	// 声明队列
-	_, err = RabbitChannel.QueueDeclare(
-		UserRegisteredQueue,
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	if err != nil {
-		RabbitChannel.Close()
-		RabbitConn.Close()
-		return fmt.Errorf("failed to declare a queue: %w", err)
+	for _, queue := range []string{UserRegisteredQueue, EmailVerificationQueue, PasswordResetQueue, AccountLockedQueue} {
+		_, err = RabbitChannel.QueueDeclare(
+			queue,
+			true,  // durable
+			false, // delete when unused
+			false, // exclusive
+			false, // no-wait
+			nil,   // arguments
+		)
+		if err != nil {
+			RabbitChannel.Close()
+			RabbitConn.Close()
+			return fmt.Errorf("failed to declare queue %s: %w", queue, err)
+		}
 	}
 
 	log.Println("成功打开RabbitMQ通道并声明队列")
 	return nil
 }
 
-// PublishUserRegistered 发布用户注册事件
+// PublishUserRegistered 发布用户注册事件，email-service 据此发送欢迎邮件
 func PublishUserRegistered(user *models.User) error {
+	return publish(UserRegisteredQueue, "UserRegistered", map[string]interface{}{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"email":    user.Email,
+	})
+}
+
+// PublishEmailVerification 发布邮箱验证事件，token 为验证令牌明文，email-service 据此发送验证链接
+func PublishEmailVerification(user *models.User, token string, expiresAt time.Time) error {
+	return publish(EmailVerificationQueue, "EmailVerification", map[string]interface{}{
+		"user_id":    user.ID,
+		"username":   user.Username,
+		"email":      user.Email,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+// PublishPasswordReset 发布重置密码事件，token 为重置令牌明文，email-service 据此发送重置链接
+func PublishPasswordReset(user *models.User, token string, expiresAt time.Time) error {
+	return publish(PasswordResetQueue, "PasswordReset", map[string]interface{}{
+		"user_id":    user.ID,
+		"username":   user.Username,
+		"email":      user.Email,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+// PublishAccountLocked 发布账号锁定事件，ip 为最后一次失败登录的来源
+func PublishAccountLocked(user *models.User, lockedUntil time.Time, ip string) error {
+	return publish(AccountLockedQueue, "AccountLocked", map[string]interface{}{
+		"user_id":      user.ID,
+		"username":     user.Username,
+		"email":        user.Email,
+		"locked_until": lockedUntil,
+		"ip":           ip,
+	})
+}
+
+// publish 将消息以 JSON 持久化发布到队列 queue，event 为事件名，用于日志
+func publish(queue, event string, messageBody map[string]interface{}) error {
 	if RabbitChannel == nil {
 		log.Println("RabbitMQ channel未初始化或连接失败，跳过事件发布。")
 		return nil // 允许在没有RabbitMQ的情况下继续
 	}
 
-	messageBody := map[string]interface{}{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-	}
 	body, err := json.Marshal(messageBody)
 	if err != nil {
-		return fmt.Errorf("无法序列化注册事件消息: %w", err)
+		return fmt.Errorf("无法序列化%s事件消息: %w", event, err)
 	}
 
 	publishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	err = RabbitChannel.PublishWithContext(publishCtx,
-		"",                  // exchange
-		UserRegisteredQueue, // routing key
-		false,               // mandatory
-		false,               // immediate
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
 		amqp091.Publishing{
 			ContentType:  "application/json",
 			Body:         body,
@@ -117,10 +162,10 @@ func PublishUserRegistered(user *models.User) error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("发布UserRegistered事件失败: %w", err)
+		return fmt.Errorf("发布%s事件失败: %w", event, err)
 	}
 
-	log.Printf("UserRegistered事件已发布到队列%s", UserRegisteredQueue)
+	log.Printf("%s事件已发布到队列%s", event, queue)
 	return nil
 }
 
@@ -0,0 +1,123 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id argon2id 算法 (RFC 9106)，哈希编码为 PHC 格式：
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<盐>$<哈希>
+//
+// 盐和哈希使用不带填充的标准 Base64 编码
+type Argon2id struct {
+	// Memory 使用的内存，单位 KiB
+	Memory uint32
+	// Iterations 迭代次数
+	Iterations uint32
+	// Parallelism 并行度
+	Parallelism uint8
+	// SaltLength 和 KeyLength 为盐和哈希的字节数，为 0 时分别使用 16 和 32
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+const argon2idPrefix = "$" + AlgArgon2id + "$"
+
+// argon2Hash 解析后的 argon2id 哈希
+type argon2Hash struct {
+	version int
+	params  Argon2id
+	salt    []byte
+	key     []byte
+}
+
+// Name 实现 Algorithm
+func (a Argon2id) Name() string {
+	return AlgArgon2id
+}
+
+// Hash 实现 Algorithm
+func (a Argon2id) Hash(password string) (string, error) {
+	saltLength, keyLength := a.SaltLength, a.KeyLength
+	if saltLength == 0 {
+		saltLength = 16
+	}
+	if keyLength == 0 {
+		keyLength = 32
+	}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, keyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Identifies 实现 Algorithm
+func (a Argon2id) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+// Verify 实现 Algorithm，使用哈希中记录的参数计算并比较
+func (a Argon2id) Verify(encoded, password string) (bool, error) {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	if h.version != argon2.Version {
+		return false, fmt.Errorf("不支持的 argon2 版本: %d", h.version)
+	}
+	key := argon2.IDKey([]byte(password), h.salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, uint32(len(h.key)))
+	return subtle.ConstantTimeCompare(key, h.key) == 1, nil
+}
+
+// Weaker 实现 Algorithm，内存、迭代次数、并行度或哈希长度任一低于当前配置时返回 true
+func (a Argon2id) Weaker(encoded string) (bool, error) {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	keyLength := a.KeyLength
+	if keyLength == 0 {
+		keyLength = 32
+	}
+	return h.params.Memory < a.Memory ||
+		h.params.Iterations < a.Iterations ||
+		h.params.Parallelism < a.Parallelism ||
+		uint32(len(h.key)) < keyLength, nil
+}
+
+// parseArgon2id 解析 PHC 格式的 argon2id 哈希
+func parseArgon2id(encoded string) (*argon2Hash, error) {
+	// 以 $ 分隔: "", "argon2id", "v=19", "m=..,t=..,p=..", 盐, 哈希
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgArgon2id {
+		return nil, fmt.Errorf("无效的 argon2id 哈希")
+	}
+	h := &argon2Hash{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &h.version); err != nil {
+		return nil, fmt.Errorf("无效的 argon2id 版本: %w", err)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.params.Memory, &h.params.Iterations, &h.params.Parallelism); err != nil {
+		return nil, fmt.Errorf("无效的 argon2id 参数: %w", err)
+	}
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("无效的 argon2id 盐: %w", err)
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("无效的 argon2id 哈希值: %w", err)
+	}
+	if len(h.key) == 0 || h.params.Memory == 0 || h.params.Iterations == 0 || h.params.Parallelism == 0 {
+		return nil, fmt.Errorf("无效的 argon2id 参数")
+	}
+	return h, nil
+}
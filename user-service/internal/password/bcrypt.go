@@ -0,0 +1,58 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt bcrypt 算法，只使用密码的前 MaxBcryptBytes 个字节，更长的密码 Hash 返回错误
+type Bcrypt struct {
+	Cost int
+}
+
+// Name 实现 Algorithm
+func (b Bcrypt) Name() string {
+	return AlgBcrypt
+}
+
+// Hash 实现 Algorithm
+func (b Bcrypt) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Identifies 实现 Algorithm，bcrypt 哈希以 $2a$、$2b$ 或 $2y$ 开头
+func (b Bcrypt) Identifies(encoded string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encoded, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify 实现 Algorithm
+func (b Bcrypt) Verify(encoded, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Weaker 实现 Algorithm，成本低于当前配置时返回 true
+func (b Bcrypt) Weaker(encoded string) (bool, error) {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, err
+	}
+	return cost < b.Cost, nil
+}
@@ -0,0 +1,164 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// hashPrefixLen 按 k-anonymity 方式查询时使用的 SHA-1 前缀长度，与 Have I Been Pwned 的 range API 相同
+const hashPrefixLen = 5
+
+// BreachedList 本地的泄露密码 SHA-1 列表。
+//
+// 文件每行一个大写或小写的 40 位十六进制 SHA-1，后面可以跟 ":出现次数"
+// (即 Have I Been Pwned 提供下载的 "ordered by hash" 格式)，必须按哈希升序排列。
+// 查询时与 range API 一样先按哈希的前 5 位找到同一范围的所有后缀，再在本地比较，
+// 文件不需要读入内存，几十 GB 的完整列表也只需要少量随机读取
+type BreachedList struct {
+	file *os.File
+	size int64
+}
+
+// OpenBreachedList 打开 path 指定的泄露密码列表
+func OpenBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	l := &BreachedList{file: f, size: info.Size()}
+	// 检查第一行的格式，尽早发现配置了错误的文件
+	if l.size > 0 {
+		_, first, err := l.lineFrom(0)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if !isSHA1Hex(first) {
+			f.Close()
+			return nil, fmt.Errorf("%s 不是 SHA-1 列表，第一行为 %q", path, first)
+		}
+	}
+	return l, nil
+}
+
+// Close 关闭列表文件
+func (l *BreachedList) Close() error {
+	return l.file.Close()
+}
+
+// Contains 返回 password 是否出现在列表中
+func (l *BreachedList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := l.rangeOf(hash[:hashPrefixLen])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[hashPrefixLen:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rangeOf 返回列表中以 prefix 开头的所有哈希去掉前缀后的部分
+func (l *BreachedList) rangeOf(prefix string) ([]string, error) {
+	start, err := l.lowerBound(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if start >= l.size {
+		return nil, nil
+	}
+	r := bufio.NewReader(io.NewSectionReader(l.file, start, l.size-start))
+	var suffixes []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		hash := parseHash(line)
+		if !strings.HasPrefix(hash, prefix) {
+			return suffixes, nil
+		}
+		suffixes = append(suffixes, hash[len(prefix):])
+		if err == io.EOF {
+			return suffixes, nil
+		}
+	}
+}
+
+// lowerBound 二分查找第一个哈希不小于 target 的行，返回该行的起始位置，不存在时返回文件大小
+func (l *BreachedList) lowerBound(target string) (int64, error) {
+	// 起始位置小于 lo 的行都小于 target，起始位置不小于 hi 的行都不小于 target
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, hash, err := l.lineFrom(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start < hi && hash < target {
+			lo = start + 1
+		} else {
+			hi = mid
+		}
+	}
+	start, _, err := l.lineFrom(lo)
+	return start, err
+}
+
+// lineFrom 返回起始位置不小于 off 的第一行的起始位置和哈希，没有这样的行时返回文件大小
+func (l *BreachedList) lineFrom(off int64) (int64, string, error) {
+	if off >= l.size {
+		return l.size, "", nil
+	}
+	start := off
+	r := bufio.NewReaderSize(io.NewSectionReader(l.file, off, l.size-off), 128)
+	if off > 0 {
+		// 从前一个字节开始找换行符，off 恰好是行首时也能正确处理
+		r = bufio.NewReaderSize(io.NewSectionReader(l.file, off-1, l.size-off+1), 128)
+		skipped, err := r.ReadString('\n')
+		if err == io.EOF {
+			return l.size, "", nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		start = off - 1 + int64(len(skipped))
+		if start >= l.size {
+			return l.size, "", nil
+		}
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	return start, parseHash(line), nil
+}
+
+// parseHash 去掉行尾的出现次数和换行符，返回大写的哈希
+func parseHash(line string) string {
+	hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+	return strings.ToUpper(hash)
+}
+
+// isSHA1Hex 返回 s 是否为 40 位十六进制字符串
+func isSHA1Hex(s string) bool {
+	if len(s) != 2*sha1.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,169 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// listFormat 列表文件的一种写法
+type listFormat struct {
+	name     string
+	newline  string
+	trailing bool // 最后一行是否以换行符结尾
+	counts   bool // 是否带 ":出现次数"
+	lower    bool
+}
+
+var listFormats = []listFormat{
+	{name: "LF", newline: "\n", trailing: true, counts: true},
+	{name: "CRLF", newline: "\r\n", trailing: true, counts: true},
+	{name: "无结尾换行", newline: "\n", counts: true},
+	{name: "CRLF 无出现次数", newline: "\r\n", trailing: true},
+	{name: "小写", newline: "\n", trailing: true, counts: true, lower: true},
+}
+
+// writeList 按 format 将已排序的 hashes 写入临时文件并打开
+func writeList(t *testing.T, hashes []string, format listFormat) *BreachedList {
+	t.Helper()
+	var b strings.Builder
+	for i, hash := range hashes {
+		if format.lower {
+			hash = strings.ToLower(hash)
+		}
+		b.WriteString(hash)
+		if format.counts {
+			fmt.Fprintf(&b, ":%d", i+1)
+		}
+		if i < len(hashes)-1 || format.trailing {
+			b.WriteString(format.newline)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("写入列表失败: %v", err)
+	}
+	l, err := OpenBreachedList(path)
+	if err != nil {
+		t.Fatalf("打开列表失败: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestBreachedListContains(t *testing.T) {
+	var leaked []string
+	for i := 0; i < 200; i++ {
+		leaked = append(leaked, fmt.Sprintf("leaked-%d", i))
+	}
+	byHash := make(map[string]string, len(leaked))
+	var hashes []string
+	for _, p := range leaked {
+		byHash[sha1Hex(p)] = p
+		hashes = append(hashes, sha1Hex(p))
+	}
+	sort.Strings(hashes)
+	first, last := byHash[hashes[0]], byHash[hashes[len(hashes)-1]]
+
+	for _, format := range listFormats {
+		t.Run(format.name, func(t *testing.T) {
+			l := writeList(t, hashes, format)
+			for _, p := range append([]string{first, last}, leaked...) {
+				if ok, err := l.Contains(p); err != nil || !ok {
+					t.Fatalf("Contains(%q) = %t, %v, 期望 true", p, ok, err)
+				}
+			}
+			for _, p := range []string{"not-leaked", "leaked-200", "", "Leaked-1"} {
+				if ok, err := l.Contains(p); err != nil || ok {
+					t.Errorf("Contains(%q) = %t, %v, 期望 false", p, ok, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedListRange(t *testing.T) {
+	// 人为构造的哈希：00000 和 ABCDE 范围各有多行，与相邻范围紧挨着，FFFFF 为最后一行
+	hashes := []string{
+		"0000000000000000000000000000000000000000",
+		"000000000000000000000000000000000000FFFF",
+		"00000FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"0000100000000000000000000000000000000000",
+		"ABCDD00000000000000000000000000000000000",
+		"ABCDE00000000000000000000000000000000001",
+		"ABCDE00000000000000000000000000000000002",
+		"ABCDE00000000000000000000000000000000003",
+		"ABCDF00000000000000000000000000000000000",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+	}
+	// 期望的结果以 hashes 中的下标表示
+	tests := []struct {
+		prefix string
+		want   []int
+	}{
+		{"00000", []int{0, 1, 2}},
+		{"00001", []int{3}},
+		{"ABCDD", []int{4}},
+		{"ABCDE", []int{5, 6, 7}},
+		{"ABCDF", []int{8}},
+		{"FFFFF", []int{9}},
+		{"00002", nil},
+		{"ABCDC", nil},
+		{"FFFFE", nil},
+	}
+	for _, format := range listFormats {
+		t.Run(format.name, func(t *testing.T) {
+			l := writeList(t, hashes, format)
+			for _, tt := range tests {
+				got, err := l.rangeOf(tt.prefix)
+				if err != nil {
+					t.Fatalf("rangeOf(%s) 失败: %v", tt.prefix, err)
+				}
+				var want []string
+				for _, i := range tt.want {
+					want = append(want, hashes[i][hashPrefixLen:])
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("rangeOf(%s) = %v, 期望 %v", tt.prefix, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreachedListEdgeCases(t *testing.T) {
+	t.Run("单行", func(t *testing.T) {
+		l := writeList(t, []string{sha1Hex("only")}, listFormats[1])
+		if ok, err := l.Contains("only"); err != nil || !ok {
+			t.Errorf("Contains(only) = %t, %v, 期望 true", ok, err)
+		}
+	})
+
+	t.Run("空文件", func(t *testing.T) {
+		l := writeList(t, nil, listFormats[0])
+		if ok, err := l.Contains("password"); err != nil || ok {
+			t.Errorf("Contains(password) = %t, %v, 期望 false", ok, err)
+		}
+	})
+
+	t.Run("不是 SHA-1 列表", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "words.txt")
+		if err := os.WriteFile(path, []byte("password\n123456\n"), 0o600); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+		if _, err := OpenBreachedList(path); err == nil {
+			t.Errorf("OpenBreachedList 接受了密码明文列表")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package password
+
+import "errors"
+
+// 支持的哈希算法名称
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+)
+
+// ErrUnknownAlgorithm 保存的哈希不是任何已知算法生成的
+var ErrUnknownAlgorithm = errors.New("未知的密码哈希格式")
+
+// Algorithm 一种密码哈希算法。编码后的哈希自带算法标识和参数 (argon2id 为 PHC 格式，bcrypt 为 $2a$ 等前缀)，
+// 因此数据库中不需要单独记录算法
+type Algorithm interface {
+	// Name 算法名称
+	Name() string
+	// Hash 用当前参数和随机盐生成编码后的哈希
+	Hash(password string) (string, error)
+	// Identifies 返回 encoded 是否由该算法生成
+	Identifies(encoded string) bool
+	// Verify 返回 password 是否与 encoded 匹配，encoded 格式错误时返回错误
+	Verify(encoded, password string) (bool, error)
+	// Weaker 返回 encoded 使用的参数是否弱于当前参数
+	Weaker(encoded string) (bool, error)
+}
+
+// Hasher 用 current 生成新的哈希，同时能验证 legacy 中各算法生成的旧哈希
+type Hasher struct {
+	current    Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher 创建 Hasher
+func NewHasher(current Algorithm, legacy ...Algorithm) *Hasher {
+	return &Hasher{
+		current:    current,
+		algorithms: append([]Algorithm{current}, legacy...),
+	}
+}
+
+// Name 返回生成新哈希使用的算法名称
+func (h *Hasher) Name() string {
+	return h.current.Name()
+}
+
+// Hash 用当前算法生成哈希
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify 返回 password 是否与 encoded 匹配。匹配且 encoded 不是当前算法生成的、
+// 或参数弱于当前配置时 rehash 为 true，调用方应使用 Hash 重新生成并保存
+func (h *Hasher) Verify(encoded, password string) (ok, rehash bool, err error) {
+	for _, alg := range h.algorithms {
+		if !alg.Identifies(encoded) {
+			continue
+		}
+		ok, err = alg.Verify(encoded, password)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		if alg.Name() != h.current.Name() {
+			return true, true, nil
+		}
+		weaker, err := alg.Weaker(encoded)
+		if err != nil {
+			return true, false, err
+		}
+		return true, weaker, nil
+	}
+	return false, false, ErrUnknownAlgorithm
+}
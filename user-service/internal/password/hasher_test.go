@@ -0,0 +1,144 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 测试使用较小的参数，避免每次哈希都占用 64 MiB 内存
+var (
+	testArgon2id = Argon2id{Memory: 1024, Iterations: 2, Parallelism: 1}
+	testBcrypt   = Bcrypt{Cost: bcrypt.MinCost}
+)
+
+func TestArgon2idReferenceHash(t *testing.T) {
+	// argon2 参考实现 (phc-winner-argon2) 对 "password"、盐 "somesalt" 输出的哈希
+	const encoded = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	for password, want := range map[string]bool{"password": true, "Password": false} {
+		ok, err := testArgon2id.Verify(encoded, password)
+		if err != nil || ok != want {
+			t.Errorf("Verify(%q) = %t, %v, 期望 %t", password, ok, err, want)
+		}
+	}
+}
+
+func TestHasherRoundTrip(t *testing.T) {
+	for _, alg := range []Algorithm{testArgon2id, testBcrypt} {
+		t.Run(alg.Name(), func(t *testing.T) {
+			h := NewHasher(alg)
+			encoded, err := h.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash 失败: %v", err)
+			}
+			if !alg.Identifies(encoded) {
+				t.Fatalf("%s 不能识别自己生成的哈希 %s", alg.Name(), encoded)
+			}
+			again, _ := h.Hash("correct horse")
+			if again == encoded {
+				t.Errorf("两次哈希相同，没有使用随机盐")
+			}
+
+			ok, rehash, err := h.Verify(encoded, "correct horse")
+			if err != nil || !ok || rehash {
+				t.Errorf("Verify(正确密码) = %t, %t, %v, 期望 true, false, nil", ok, rehash, err)
+			}
+			ok, rehash, err = h.Verify(encoded, "correct horse ")
+			if err != nil || ok || rehash {
+				t.Errorf("Verify(错误密码) = %t, %t, %v, 期望 false, false, nil", ok, rehash, err)
+			}
+		})
+	}
+}
+
+func TestHasherRehash(t *testing.T) {
+	stronger := testArgon2id
+	stronger.Iterations++
+	longerKey := testArgon2id
+	longerKey.KeyLength = 64
+
+	tests := []struct {
+		name       string
+		old        Algorithm
+		hasher     *Hasher
+		wantRehash bool
+	}{
+		{name: "bcrypt 升级为 argon2id", old: testBcrypt, hasher: NewHasher(testArgon2id, testBcrypt), wantRehash: true},
+		{name: "argon2id 降级为 bcrypt", old: testArgon2id, hasher: NewHasher(testBcrypt, testArgon2id), wantRehash: true},
+		{name: "argon2id 迭代次数增加", old: testArgon2id, hasher: NewHasher(stronger), wantRehash: true},
+		{name: "argon2id 哈希长度增加", old: testArgon2id, hasher: NewHasher(longerKey), wantRehash: true},
+		{name: "argon2id 参数不变", old: testArgon2id, hasher: NewHasher(testArgon2id, testBcrypt)},
+		{name: "argon2id 参数比当前更强", old: stronger, hasher: NewHasher(testArgon2id)},
+		{name: "bcrypt 成本增加", old: testBcrypt, hasher: NewHasher(Bcrypt{Cost: bcrypt.MinCost + 1}), wantRehash: true},
+		{name: "bcrypt 成本不变", old: testBcrypt, hasher: NewHasher(testBcrypt)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.old.Hash("s3cret-pass")
+			if err != nil {
+				t.Fatalf("Hash 失败: %v", err)
+			}
+			ok, rehash, err := tt.hasher.Verify(encoded, "s3cret-pass")
+			if err != nil || !ok || rehash != tt.wantRehash {
+				t.Fatalf("Verify = %t, %t, %v, 期望 true, %t, nil", ok, rehash, err, tt.wantRehash)
+			}
+			// 错误的密码不要求重新生成
+			if _, rehash, _ := tt.hasher.Verify(encoded, "wrong"); rehash {
+				t.Errorf("密码错误时 rehash 为 true")
+			}
+			if !tt.wantRehash {
+				return
+			}
+			// 重新生成的哈希不再需要升级
+			upgraded, err := tt.hasher.Hash("s3cret-pass")
+			if err != nil {
+				t.Fatalf("Hash 失败: %v", err)
+			}
+			ok, rehash, err = tt.hasher.Verify(upgraded, "s3cret-pass")
+			if err != nil || !ok || rehash {
+				t.Errorf("升级后 Verify = %t, %t, %v, 期望 true, false, nil", ok, rehash, err)
+			}
+		})
+	}
+}
+
+func TestHasherVerifyInvalid(t *testing.T) {
+	const salt, key = "c29tZXNhbHQ", "CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	phc := func(version, params, salt, key string) string {
+		return strings.Join([]string{"", "argon2id", version, params, salt, key}, "$")
+	}
+	h := NewHasher(testArgon2id, testBcrypt)
+	tests := []struct {
+		name    string
+		hasher  *Hasher
+		encoded string
+		unknown bool // 期望 ErrUnknownAlgorithm
+	}{
+		{name: "空哈希", hasher: h, encoded: "", unknown: true},
+		{name: "明文", hasher: h, encoded: "password", unknown: true},
+		{name: "不支持的算法", hasher: h, encoded: "$argon2i$v=19$m=65536,t=2,p=1$" + salt + "$" + key, unknown: true},
+		{name: "未启用的旧算法", hasher: NewHasher(testArgon2id), encoded: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", unknown: true},
+		{name: "argon2id 字段不足", hasher: h, encoded: "$argon2id$v=19$m=65536,t=2,p=1$" + salt},
+		{name: "argon2id 版本错误", hasher: h, encoded: phc("v=16", "m=65536,t=2,p=1", salt, key)},
+		{name: "argon2id 参数格式错误", hasher: h, encoded: phc("v=19", "m=65536;t=2;p=1", salt, key)},
+		{name: "argon2id 迭代次数为 0", hasher: h, encoded: phc("v=19", "m=65536,t=0,p=1", salt, key)},
+		{name: "argon2id 并行度为 0", hasher: h, encoded: phc("v=19", "m=65536,t=2,p=0", salt, key)},
+		{name: "argon2id 内存为 0", hasher: h, encoded: phc("v=19", "m=0,t=2,p=1", salt, key)},
+		{name: "argon2id 哈希为空", hasher: h, encoded: phc("v=19", "m=65536,t=2,p=1", salt, "")},
+		{name: "argon2id 盐不是 Base64", hasher: h, encoded: phc("v=19", "m=65536,t=2,p=1", "!!!", key)},
+		{name: "bcrypt 被截断", hasher: h, encoded: "$2a$10$N9qo8uLOickgx2ZMRZoMye"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := tt.hasher.Verify(tt.encoded, "password")
+			if ok || rehash || err == nil {
+				t.Fatalf("Verify = %t, %t, %v, 期望返回错误", ok, rehash, err)
+			}
+			if got := errors.Is(err, ErrUnknownAlgorithm); got != tt.unknown {
+				t.Errorf("errors.Is(%v, ErrUnknownAlgorithm) = %t, 期望 %t", err, got, tt.unknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package password 负责密码哈希和密码策略。
+//
+// Hasher 用配置的算法 (argon2id 或 bcrypt) 生成哈希，并能验证其他已知算法生成的旧哈希，
+// 旧哈希的算法或参数弱于当前配置时提示调用方重新生成。
+//
+// Policy 检查用户设置的新密码，包括最小长度、最大字节数 (bcrypt 只使用密码的前 72 个字节)、
+// 至少包含几类字符 (小写字母、大写字母、数字、其他符号)、不能包含用户名或邮箱，
+// 以及可选的泄露密码列表检查 (见 BreachedList)。
+package password
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxBcryptBytes bcrypt 能处理的最大密码长度，超过时 bcrypt.GenerateFromPassword 返回错误
+const MaxBcryptBytes = 72
+
+// 违反策略时 Violation.Reason 的取值
+const (
+	ReasonTooShort      = "PASSWORD_TOO_SHORT"
+	ReasonTooLong       = "PASSWORD_TOO_LONG"
+	ReasonTooFewClasses = "PASSWORD_TOO_FEW_CHARACTER_CLASSES"
+	ReasonContainsUser  = "PASSWORD_CONTAINS_USER_INFO"
+	ReasonBreached      = "PASSWORD_BREACHED"
+)
+
+// 用户名或邮箱 @ 之前的部分短于该长度时不检查是否包含在密码中，避免误伤
+const minUserInfoRunes = 3
+
+// Policy 密码策略，零值只检查 bcrypt 的长度上限
+type Policy struct {
+	// MinLength 最少字符数 (按 Unicode 字符计算)
+	MinLength int
+	// MaxBytes 最多字节数，0 或超过 MaxBcryptBytes 时按 MaxBcryptBytes 处理。
+	// 使用 argon2id 时同样限制，保证切换回 bcrypt 后仍能重新生成哈希
+	MaxBytes int
+	// MinCharClasses 至少包含几类字符：小写字母、大写字母、数字、其他符号
+	MinCharClasses int
+	// DisallowUserInfo 为 true 时密码不能包含用户名或邮箱 (不区分大小写)
+	DisallowUserInfo bool
+	// Breached 泄露密码列表，为 nil 时不检查
+	Breached *BreachedList
+}
+
+// Violation 一条不符合策略的原因
+type Violation struct {
+	Reason      string
+	Description string
+}
+
+// Validate 检查 password 是否符合策略，返回所有不符合的原因，符合时返回 nil。
+// username 和 email 为该密码所属账号的用户名和邮箱
+func (p Policy) Validate(password, username, email string) []Violation {
+	var violations []Violation
+
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		violations = append(violations, Violation{
+			Reason:      ReasonTooShort,
+			Description: fmt.Sprintf("密码至少需要 %d 个字符", p.MinLength),
+		})
+	}
+	if maxBytes := p.maxBytes(); len(password) > maxBytes {
+		violations = append(violations, Violation{
+			Reason:      ReasonTooLong,
+			Description: fmt.Sprintf("密码不能超过 %d 个字节 (一个汉字占 3 个字节)", maxBytes),
+		})
+	}
+	if p.MinCharClasses > 1 && charClasses(password) < p.MinCharClasses {
+		violations = append(violations, Violation{
+			Reason:      ReasonTooFewClasses,
+			Description: fmt.Sprintf("密码需要包含小写字母、大写字母、数字、符号中的至少 %d 类", p.MinCharClasses),
+		})
+	}
+	if p.DisallowUserInfo && containsUserInfo(password, username, email) {
+		violations = append(violations, Violation{
+			Reason:      ReasonContainsUser,
+			Description: "密码不能包含用户名或邮箱",
+		})
+	}
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			// 列表读取失败时不阻止设置密码
+			log.Printf("检查泄露密码列表失败: %v", err)
+		} else if breached {
+			violations = append(violations, Violation{
+				Reason:      ReasonBreached,
+				Description: "该密码已出现在公开泄露的密码中，请换一个密码",
+			})
+		}
+	}
+	return violations
+}
+
+// maxBytes 返回实际生效的最大字节数
+func (p Policy) maxBytes() int {
+	if p.MaxBytes <= 0 || p.MaxBytes > MaxBcryptBytes {
+		return MaxBcryptBytes
+	}
+	return p.MaxBytes
+}
+
+// charClasses 返回 s 包含的字符类别数
+func charClasses(s string) int {
+	var lower, upper, digit, other bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	n := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// containsUserInfo 返回 password 是否包含用户名、完整邮箱或邮箱 @ 之前的部分
+func containsUserInfo(password, username, email string) bool {
+	lowered := strings.ToLower(password)
+	candidates := []string{username, email}
+	if local, _, ok := strings.Cut(email, "@"); ok {
+		candidates = append(candidates, local)
+	}
+	for _, c := range candidates {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if utf8.RuneCountInString(c) < minUserInfoRunes {
+			continue
+		}
+		if strings.Contains(lowered, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+// Package revocation 记录被撤销的访问令牌 (JWT)。
+//
+// 撤销记录保存在 Redis 中，API 网关直接读取同样的键来拒绝已撤销的令牌：
+//   - revoked_token:<jti> 单个令牌被撤销 (例如登出)，保留到令牌过期
+//   - revoked_user:<user_id> 该用户在此时间 (Unix 毫秒) 之前签发的令牌全部失效 (例如修改密码)，
+//     保留访问令牌的最长有效期
+//
+// 未配置 Redis 时使用进程内的 MemoryStore，此时撤销记录只在 user-service 内可见，网关无法据此拒绝令牌。
+package revocation
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	tokenKeyPrefix = "revoked_token:"
+	userKeyPrefix  = "revoked_user:"
+)
+
+// Store 令牌撤销记录
+type Store interface {
+	// RevokeToken 撤销 jti 对应的令牌，记录保留到 expiresAt
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUser 撤销用户在 before 之前签发的所有令牌，记录保留 ttl
+	RevokeUser(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error
+	// IsRevoked 判断用户 userID 在 issuedAt 签发的令牌 jti 是否已被撤销
+	IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error)
+}
+
+// RedisStore 基于 Redis 的撤销记录，多个服务共享
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore 创建基于 Redis 的撤销记录
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func tokenKey(jti string) string {
+	return tokenKeyPrefix + jti
+}
+
+func userKey(userID uint) string {
+	return userKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func (s *RedisStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.rdb.Set(ctx, tokenKey(jti), 1, ttl).Err()
+}
+
+// RevokeUser 只会推迟已有的撤销时间，不会提前
+func (s *RedisStore) RevokeUser(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error {
+	return revokeUserScript.Run(ctx, s.rdb, []string{userKey(userID)}, before.UnixMilli(), ttl.Milliseconds()).Err()
+}
+
+var revokeUserScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) > current then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+end
+return 0
+`)
+
+func (s *RedisStore) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	values, err := s.rdb.MGet(ctx, tokenKey(jti), userKey(userID)).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil {
+		return true, nil
+	}
+	if before, ok := values[1].(string); ok {
+		ms, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		return issuedAt.UnixMilli() < ms, nil
+	}
+	return false, nil
+}
+
+// MemoryStore 进程内的撤销记录，用于未配置 Redis 的单机开发环境
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	users  map[uint]memoryUserEntry
+}
+
+type memoryUserEntry struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+// NewMemoryStore 创建进程内的撤销记录
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]time.Time), users: make(map[uint]memoryUserEntry)}
+}
+
+func (s *MemoryStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(time.Now())
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryStore) RevokeUser(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	if entry, ok := s.users[userID]; !ok || before.After(entry.before) {
+		s.users[userID] = memoryUserEntry{before: before, expiresAt: now.Add(ttl)}
+	}
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if expiresAt, ok := s.tokens[jti]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+	if entry, ok := s.users[userID]; ok && now.Before(entry.expiresAt) {
+		// 与 RedisStore 一样按毫秒比较，iat 只精确到毫秒
+		return issuedAt.UnixMilli() < entry.before.UnixMilli(), nil
+	}
+	return false, nil
+}
+
+// sweep 删除已过期的记录，调用方需持有 mu
+func (s *MemoryStore) sweep(now time.Time) {
+	for jti, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, entry := range s.users {
+		if !now.Before(entry.expiresAt) {
+			delete(s.users, userID)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// stores 返回待测的 Store 实现，RedisStore 连接到 miniredis
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return map[string]Store{"redis": NewRedisStore(rdb), "memory": NewMemoryStore()}
+}
+
+func TestStoreRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			if err := store.RevokeToken(ctx, "jti-1", now.Add(time.Hour)); err != nil {
+				t.Fatalf("RevokeToken 失败: %v", err)
+			}
+			// 已过期的令牌不需要撤销记录
+			if err := store.RevokeToken(ctx, "jti-expired", now.Add(-time.Second)); err != nil {
+				t.Fatalf("RevokeToken 失败: %v", err)
+			}
+			tests := []struct {
+				jti  string
+				want bool
+			}{
+				{"jti-1", true},
+				{"jti-2", false},
+				{"jti-expired", false},
+			}
+			for _, tt := range tests {
+				if got, err := store.IsRevoked(ctx, tt.jti, 1, now); err != nil || got != tt.want {
+					t.Errorf("IsRevoked(%s) = %t, %v, 期望 %t", tt.jti, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreRevokeUser(t *testing.T) {
+	ctx := context.Background()
+	before := time.Date(2026, time.March, 20, 11, 0, 0, 0, time.UTC).Add(123*time.Millisecond + 456*time.Microsecond)
+	tests := []struct {
+		name     string
+		jti      string
+		userID   uint
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "之前签发", jti: "a", userID: 1, issuedAt: before.Add(-time.Millisecond), want: true},
+		{name: "同一秒但更早签发", jti: "b", userID: 1, issuedAt: before.Truncate(time.Second), want: true},
+		// iat 只精确到毫秒，撤销后同一毫秒内重新登录签发的令牌不能被判为已撤销
+		{name: "同一毫秒签发", jti: "c", userID: 1, issuedAt: before.Truncate(time.Millisecond), want: false},
+		{name: "之后签发", jti: "d", userID: 1, issuedAt: before.Add(time.Millisecond), want: false},
+		{name: "其他用户", jti: "e", userID: 2, issuedAt: before.Add(-time.Hour), want: false},
+		{name: "没有 jti 的旧令牌", userID: 1, issuedAt: before.Add(-time.Second), want: true},
+	}
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.RevokeUser(ctx, 1, before, time.Hour); err != nil {
+				t.Fatalf("RevokeUser 失败: %v", err)
+			}
+			// 较早的撤销时间不会覆盖已有记录
+			if err := store.RevokeUser(ctx, 1, before.Add(-time.Minute), time.Hour); err != nil {
+				t.Fatalf("RevokeUser 失败: %v", err)
+			}
+			for _, tt := range tests {
+				if got, err := store.IsRevoked(ctx, tt.jti, tt.userID, tt.issuedAt); err != nil || got != tt.want {
+					t.Errorf("%s: IsRevoked = %t, %v, 期望 %t", tt.name, got, err, tt.want)
+				}
+			}
+
+			// 更晚的撤销时间生效
+			later := before.Add(time.Minute)
+			if err := store.RevokeUser(ctx, 1, later, time.Hour); err != nil {
+				t.Fatalf("RevokeUser 失败: %v", err)
+			}
+			if got, _ := store.IsRevoked(ctx, "f", 1, before.Add(time.Second)); !got {
+				t.Error("推迟撤销时间后，之前签发的令牌未被撤销")
+			}
+		})
+	}
+}
+
+func TestStoreRecordsExpire(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	store := NewRedisStore(rdb)
+
+	now := time.Now()
+	if err := store.RevokeToken(ctx, "jti-1", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RevokeUser(ctx, 1, now, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL(tokenKey("jti-1")); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("revoked_token 的 TTL = %s, 期望不超过令牌剩余有效期", ttl)
+	}
+	mr.FastForward(time.Minute + time.Second)
+	if got, err := store.IsRevoked(ctx, "jti-1", 1, now.Add(-time.Second)); err != nil || got {
+		t.Errorf("记录过期后 IsRevoked = %t, %v, 期望 false", got, err)
+	}
+
+	memory := NewMemoryStore()
+	memory.RevokeToken(ctx, "jti-1", time.Now().Add(20*time.Millisecond))
+	memory.RevokeUser(ctx, 1, time.Now(), 20*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	if got, _ := memory.IsRevoked(ctx, "jti-1", 1, now.Add(-time.Second)); got {
+		t.Error("MemoryStore 的记录过期后仍然有效")
+	}
+}
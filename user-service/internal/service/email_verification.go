@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/mq"
+	pb "todo-project/user-service/proto/user"
+)
+
+// 邮箱验证策略
+const (
+	// EmailVerificationOff 不验证邮箱，注册后直接发送欢迎邮件
+	EmailVerificationOff = "off"
+	// EmailVerificationOptional 发送验证邮件，未验证的账号不受限制
+	EmailVerificationOptional = "optional"
+	// EmailVerificationRequired 发送验证邮件，注册超过宽限期仍未验证的账号不能登录或刷新令牌
+	EmailVerificationRequired = "required"
+)
+
+// EmailVerificationOptions 邮箱验证配置
+type EmailVerificationOptions struct {
+	Policy string
+	// TokenTTL 验证链接的有效期
+	TokenTTL time.Duration
+	// GracePeriod required 策略下注册后允许未验证账号登录的时间
+	GracePeriod time.Duration
+	// ResendInterval 两次发送验证邮件的最小间隔
+	ResendInterval time.Duration
+	// MaxSendsPerDay 24 小时内最多发送的验证邮件数 (包括注册时的第一封)
+	MaxSendsPerDay int
+}
+
+// errInvalidVerificationToken 验证令牌不存在、已使用或已过期
+var errInvalidVerificationToken = errors.New("invalid email verification token")
+
+// issueEmailVerificationToken 在 tx 中为用户创建新的验证令牌，之前未使用的令牌立即失效。返回令牌明文和过期时间
+func (s *UserService) issueEmailVerificationToken(tx *gorm.DB, userID uint, now time.Time) (string, time.Time, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	// 只让旧令牌过期而不删除，发送记录用于频率限制
+	if err := tx.Model(&models.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, now).
+		Update("expires_at", now).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	record := models.EmailVerificationToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.opts.EmailVerification.TokenTTL),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	return token, record.ExpiresAt, nil
+}
+
+// checkEmailVerified 按 required 策略拒绝超过宽限期仍未验证邮箱的账号。
+// 返回的 FailedPrecondition 错误带有 ErrorInfo (EMAIL_NOT_VERIFIED)，客户端据此提示重新发送验证邮件
+func (s *UserService) checkEmailVerified(user *models.User) error {
+	if s.opts.EmailVerification.Policy != EmailVerificationRequired || user.EmailVerified {
+		return nil
+	}
+	if time.Since(user.CreatedAt) < s.opts.EmailVerification.GracePeriod {
+		return nil
+	}
+	st := status.New(codes.FailedPrecondition, "邮箱尚未验证，请先点击验证邮件中的链接")
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "EMAIL_NOT_VERIFIED",
+		Domain: "user-service",
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// VerifyEmail 使用验证令牌将账号标记为已验证，首次验证成功后发送欢迎邮件
+func (s *UserService) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	log.Printf("Received VerifyEmail request")
+
+	if req.GetToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "验证令牌不能为空")
+	}
+
+	var (
+		user            models.User
+		alreadyVerified bool
+	)
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored models.EmailVerificationToken
+		if err := tx.Where("token_hash = ?", hashToken(req.GetToken())).First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidVerificationToken
+			}
+			return err
+		}
+		now := time.Now()
+		if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+			return errInvalidVerificationToken
+		}
+		// 条件更新保证令牌只能使用一次
+		result := tx.Model(&stored).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvalidVerificationToken
+		}
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidVerificationToken
+			}
+			return err
+		}
+		alreadyVerified = user.EmailVerified
+		return tx.Model(&user).Update("email_verified", true).Error
+	})
+	if errors.Is(err, errInvalidVerificationToken) {
+		return nil, status.Errorf(codes.InvalidArgument, "验证链接无效或已过期，请重新发送验证邮件")
+	}
+	if err != nil {
+		log.Printf("验证邮箱失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "验证邮箱失败")
+	}
+
+	log.Printf("用户 %d 的邮箱 %s 已验证", user.ID, user.Email)
+	if !alreadyVerified {
+		if err := mq.PublishUserRegistered(&user); err != nil {
+			log.Printf("发布用户注册事件失败: %v", err)
+		}
+	}
+	return &pb.VerifyEmailResponse{Username: user.Username}, nil
+}
+
+// ResendVerificationEmail 重新发送验证邮件。用户不存在、已验证或发送过于频繁时直接返回成功，不泄露账号状态
+func (s *UserService) ResendVerificationEmail(ctx context.Context, req *pb.ResendVerificationEmailRequest) (*pb.ResendVerificationEmailResponse, error) {
+	log.Printf("Received ResendVerificationEmail request for user: %s", req.GetUsername())
+
+	if s.opts.EmailVerification.Policy == EmailVerificationOff {
+		return nil, status.Errorf(codes.FailedPrecondition, "未启用邮箱验证")
+	}
+	if req.GetUsername() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "用户名不能为空")
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).Where("username = ?", req.GetUsername()).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("重新发送验证邮件时用户未找到: %s", req.GetUsername())
+			return &pb.ResendVerificationEmailResponse{}, nil
+		}
+		log.Printf("查找用户失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "发送验证邮件失败")
+	}
+	if user.EmailVerified {
+		log.Printf("用户 %d 的邮箱已验证，不再发送验证邮件", user.ID)
+		return &pb.ResendVerificationEmailResponse{}, nil
+	}
+
+	now := time.Now()
+	var (
+		lastSent time.Time
+		sent     int64
+		token    string
+		expires  time.Time
+	)
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var recent []models.EmailVerificationToken
+		if err := tx.Where("user_id = ? AND created_at > ?", user.ID, now.Add(-24*time.Hour)).
+			Order("created_at DESC").Find(&recent).Error; err != nil {
+			return err
+		}
+		sent = int64(len(recent))
+		if sent > 0 {
+			lastSent = recent[0].CreatedAt
+		}
+		if now.Sub(lastSent) < s.opts.EmailVerification.ResendInterval || sent >= int64(s.opts.EmailVerification.MaxSendsPerDay) {
+			return nil
+		}
+		var err error
+		token, expires, err = s.issueEmailVerificationToken(tx, user.ID, now)
+		return err
+	})
+	if err != nil {
+		log.Printf("创建验证令牌失败 for user %d: %v", user.ID, err)
+		return nil, status.Errorf(codes.Internal, "发送验证邮件失败")
+	}
+	if token == "" {
+		// 与用户不存在或已验证时一样返回成功，频率限制只记录在服务端
+		log.Printf("用户 %d 重新发送验证邮件过于频繁 (24 小时内已发送 %d 封)，不发送", user.ID, sent)
+		return &pb.ResendVerificationEmailResponse{}, nil
+	}
+
+	if err := mq.PublishEmailVerification(&user, token, expires); err != nil {
+		log.Printf("发布邮箱验证事件失败: %v", err)
+		return nil, status.Errorf(codes.Unavailable, "发送验证邮件失败，请稍后再试")
+	}
+	log.Printf("已为用户 %d 重新发送验证邮件", user.ID)
+	return &pb.ResendVerificationEmailResponse{}, nil
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invalidFieldsError 返回带 BadRequest 详情的 InvalidArgument 错误，网关据此逐字段显示原因；
+// reason 作为 ErrorInfo 的 Reason 表示整体的错误类型
+func invalidFieldsError(message, reason string, violations []*errdetails.BadRequest_FieldViolation) error {
+	st := status.New(codes.InvalidArgument, message)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}, &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: "user-service",
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/mq"
+	"todo-project/user-service/internal/throttle"
+	pb "todo-project/user-service/proto/user"
+)
+
+// clientIPMetadataKey API 网关通过 gRPC 元数据传递发起请求的客户端 IP
+const clientIPMetadataKey = "x-client-ip"
+
+// clientIP 返回发起登录的客户端 IP。请求经过 API 网关时取元数据中的 IP，否则取 gRPC 对端地址
+func clientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(clientIPMetadataKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+	}
+	return ""
+}
+
+// reserveLoginAttempt 在验证密码或验证码之前预留一次登录尝试，用户名或 IP 处于退避期或已被锁定时
+// 返回 ResourceExhausted 错误。调用方应随即 defer releaseLoginAttempt，得出结果时调用
+// recordLoginFailure 或 recordLoginSuccess。失败记录不可用时只记录日志并放行，不影响正常登录
+func (s *UserService) reserveLoginAttempt(ctx context.Context, username, ip string) (*throttle.Attempt, error) {
+	attempt, decision, err := s.guard.Reserve(ctx, username, ip)
+	if err != nil {
+		log.Printf("警告: 预留登录尝试失败: %v，跳过登录限制", err)
+		return attempt, nil
+	}
+	if decision.Allowed() {
+		return attempt, nil
+	}
+	log.Printf("拒绝用户 %s 从 %s 登录: 需要等待 %v (锁定: %t)", username, ip, decision.RetryAfter.Round(time.Second), decision.Locked)
+
+	retryAfter := int64((decision.RetryAfter + time.Second - 1) / time.Second)
+	reason := "LOGIN_THROTTLED"
+	message := fmt.Sprintf("登录尝试过于频繁，请 %s后再试", formatWait(retryAfter))
+	if decision.Locked {
+		reason = "ACCOUNT_LOCKED"
+		message = fmt.Sprintf("登录失败次数过多，已被临时锁定，请 %s后再试", formatWait(retryAfter))
+	}
+	st := status.New(codes.ResourceExhausted, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   "user-service",
+		Metadata: map[string]string{"retry_after": strconv.FormatInt(retryAfter, 10)},
+	})
+	if err != nil {
+		return nil, st.Err()
+	}
+	return nil, detailed.Err()
+}
+
+// formatWait 将等待的秒数格式化为提示文字
+func formatWait(seconds int64) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%d 秒", seconds)
+	}
+	return fmt.Sprintf("%d 分钟", (seconds+59)/60)
+}
+
+// recordLoginFailure 记录这次尝试的密码或验证码错误。账号因此被锁定时记录安全事件，
+// user 不为空 (用户名存在) 时发布账号锁定事件，由 email-service 通知用户
+func (s *UserService) recordLoginFailure(ctx context.Context, attempt *throttle.Attempt, user *models.User) {
+	lockedUntil, err := s.guard.Fail(ctx, attempt)
+	if err != nil {
+		log.Printf("警告: 记录登录失败失败: %v", err)
+	}
+	if lockedUntil.IsZero() {
+		return
+	}
+	log.Printf("安全事件: 用户名 %s 连续登录失败次数过多，锁定到 %s (最后一次来自 %s)",
+		attempt.Username, lockedUntil.Format(time.RFC3339), attempt.IP)
+	if user == nil {
+		return
+	}
+	if err := mq.PublishAccountLocked(user, lockedUntil, attempt.IP); err != nil {
+		log.Printf("发布账号锁定事件失败: %v", err)
+	}
+}
+
+// recordLoginSuccess 登录成功后清除用户名的失败记录
+func (s *UserService) recordLoginSuccess(ctx context.Context, attempt *throttle.Attempt) {
+	if err := s.guard.Succeed(ctx, attempt); err != nil {
+		log.Printf("警告: 清除用户 %s 的登录失败记录失败: %v", attempt.Username, err)
+	}
+}
+
+// releaseLoginAttempt 归还既没有失败也没有成功的尝试 (例如邮箱未验证、还需要两步验证或内部错误) 预留的次数，
+// 已经记录了结果的尝试不受影响
+func (s *UserService) releaseLoginAttempt(ctx context.Context, attempt *throttle.Attempt) {
+	if err := s.guard.Release(ctx, attempt); err != nil {
+		log.Printf("警告: 归还用户 %s 的登录尝试失败: %v", attempt.Username, err)
+	}
+}
+
+// UnlockAccount 解除账号因登录失败被施加的锁定和退避，并清除失败记录。
+// 供运维直接通过 gRPC 调用，API 网关不暴露此方法
+func (s *UserService) UnlockAccount(ctx context.Context, req *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+	log.Printf("Received UnlockAccount request for user: %s", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "用户名不能为空")
+	}
+	wasLocked, err := s.guard.Unlock(ctx, req.GetUsername())
+	if err != nil {
+		log.Printf("解除账号 %s 的锁定失败: %v", req.GetUsername(), err)
+		return nil, status.Errorf(codes.Internal, "解除锁定失败")
+	}
+
+	log.Printf("安全事件: 账号 %s 已被管理员解除锁定 (解除前锁定: %t)", req.GetUsername(), wasLocked)
+	return &pb.UnlockAccountResponse{WasLocked: wasLocked}, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// checkPasswordPolicy 按密码策略检查新密码，不符合时返回 InvalidArgument 错误，
+// 每条原因作为 BadRequest 中 field 字段的一条 FieldViolation，网关据此逐字段显示
+func (s *UserService) checkPasswordPolicy(field, password, username, email string) error {
+	violations := s.opts.PasswordPolicy.Validate(password, username, email)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	var fieldViolations []*errdetails.BadRequest_FieldViolation
+	for _, v := range violations {
+		fieldViolations = append(fieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: v.Description,
+			Reason:      v.Reason,
+		})
+	}
+	log.Printf("密码不符合策略: user=%s, 原因=%d 条", username, len(violations))
+	return invalidFieldsError("密码不符合要求", "WEAK_PASSWORD", fieldViolations)
+}
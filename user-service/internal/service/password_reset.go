@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/mq"
+	pb "todo-project/user-service/proto/user"
+)
+
+// PasswordResetOptions 找回密码配置
+type PasswordResetOptions struct {
+	// TokenTTL 重置链接的有效期
+	TokenTTL time.Duration
+	// RequestInterval 同一账号两次发送重置邮件的最小间隔，期间的申请被静默忽略
+	RequestInterval time.Duration
+}
+
+// errInvalidResetToken 重置令牌不存在、已使用或已过期
+var errInvalidResetToken = errors.New("invalid password reset token")
+
+// RequestPasswordReset 向邮箱对应的账号发送重置密码链接。同一邮箱可能注册了多个账号，每个账号各发送一封。
+// 为了不泄露邮箱是否注册，邮箱不存在、申请过于频繁或发送失败时同样返回成功，
+// 创建令牌和发布事件在后台进行，响应时间与邮箱是否注册无关
+func (s *UserService) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	log.Printf("Received RequestPasswordReset request")
+
+	email := strings.TrimSpace(req.GetEmail())
+	if email == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "邮箱不能为空")
+	}
+
+	var users []models.User
+	if err := database.DB.WithContext(ctx).Where("email = ?", email).Find(&users).Error; err != nil {
+		log.Printf("查找邮箱对应的用户失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "申请重置密码失败")
+	}
+	if len(users) == 0 {
+		log.Printf("申请重置密码的邮箱未注册，忽略")
+		return &pb.RequestPasswordResetResponse{}, nil
+	}
+
+	go s.sendPasswordResetEmails(context.WithoutCancel(ctx), users)
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+// sendPasswordResetEmails 为每个账号创建重置令牌并发布重置密码事件，失败时只记录日志
+func (s *UserService) sendPasswordResetEmails(ctx context.Context, users []models.User) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for i := range users {
+		user := &users[i]
+		token, expiresAt, err := s.issuePasswordResetToken(ctx, user.ID)
+		if err != nil {
+			log.Printf("创建重置密码令牌失败 for user %d: %v", user.ID, err)
+			continue
+		}
+		if token == "" {
+			log.Printf("用户 %d 申请重置密码过于频繁，忽略", user.ID)
+			continue
+		}
+		if err := mq.PublishPasswordReset(user, token, expiresAt); err != nil {
+			log.Printf("发布重置密码事件失败: %v", err)
+			continue
+		}
+		log.Printf("已为用户 %d 发送重置密码邮件", user.ID)
+	}
+}
+
+// issuePasswordResetToken 为用户创建新的重置令牌，之前未使用的令牌立即过期。
+// 距离上次创建不足 RequestInterval 时不创建，返回空字符串
+func (s *UserService) issuePasswordResetToken(ctx context.Context, userID uint) (string, time.Time, error) {
+	var (
+		token     string
+		expiresAt time.Time
+	)
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var recent int64
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND created_at > ?", userID, now.Add(-s.opts.PasswordReset.RequestInterval)).
+			Count(&recent).Error; err != nil {
+			return err
+		}
+		if recent > 0 {
+			return nil
+		}
+		plain, err := randomToken(32)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, now).
+			Update("expires_at", now).Error; err != nil {
+			return err
+		}
+		record := models.PasswordResetToken{
+			UserID:    userID,
+			TokenHash: hashToken(plain),
+			ExpiresAt: now.Add(s.opts.PasswordReset.TokenTTL),
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		token, expiresAt = plain, record.ExpiresAt
+		return nil
+	})
+	return token, expiresAt, err
+}
+
+// ResetPassword 使用重置令牌设置新密码，成功后撤销该用户的所有会话。
+// 能收到重置邮件说明用户拥有该邮箱，邮箱同时标记为已验证
+func (s *UserService) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	log.Printf("Received ResetPassword request")
+
+	if req.GetToken() == "" || req.GetNewPassword() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "重置令牌和新密码不能为空")
+	}
+
+	// 先找到令牌所属的用户，新密码需要按该用户的用户名和邮箱检查密码策略。
+	// 令牌是否已使用在下面的事务中再次检查
+	var owner models.User
+	err := database.DB.WithContext(ctx).
+		Joins("JOIN password_reset_tokens ON password_reset_tokens.user_id = users.id").
+		Where("password_reset_tokens.token_hash = ?", hashToken(req.GetToken())).
+		First(&owner).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.InvalidArgument, "重置链接无效或已过期，请重新申请")
+	}
+	if err != nil {
+		log.Printf("查询重置令牌失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "重置密码失败")
+	}
+	if err := s.checkPasswordPolicy("new_password", req.GetNewPassword(), owner.Username, owner.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.opts.PasswordHasher.Hash(req.GetNewPassword())
+	if err != nil {
+		log.Printf("新密码加密失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "新密码加密失败")
+	}
+
+	var userID uint
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored models.PasswordResetToken
+		if err := tx.Where("token_hash = ?", hashToken(req.GetToken())).First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidResetToken
+			}
+			return err
+		}
+		now := time.Now()
+		if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+			return errInvalidResetToken
+		}
+		// 条件更新保证令牌只能使用一次
+		result := tx.Model(&stored).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvalidResetToken
+		}
+		result = tx.Model(&models.User{}).Where("id = ?", stored.UserID).Updates(map[string]interface{}{
+			"password":       hashedPassword,
+			"email_verified": true,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvalidResetToken
+		}
+		userID = stored.UserID
+		return nil
+	})
+	if errors.Is(err, errInvalidResetToken) {
+		return nil, status.Errorf(codes.InvalidArgument, "重置链接无效或已过期，请重新申请")
+	}
+	if err != nil {
+		log.Printf("重置密码失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "重置密码失败")
+	}
+
+	log.Printf("用户 %d 已通过重置链接设置新密码", userID)
+	s.revokeAllSessions(ctx, userID)
+	return &pb.ResetPasswordResponse{}, nil
+}
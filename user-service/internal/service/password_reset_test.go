@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/models"
+	pb "todo-project/user-service/proto/user"
+)
+
+const (
+	oldPassword = "old password 1"
+	newPassword = "new password 2"
+)
+
+func issueResetToken(t *testing.T, s *UserService, userID uint) string {
+	t.Helper()
+	token, _, err := s.issuePasswordResetToken(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("issuePasswordResetToken 失败: %v", err)
+	}
+	if token == "" {
+		t.Fatal("issuePasswordResetToken 没有创建令牌")
+	}
+	return token
+}
+
+func resetPassword(s *UserService, token, newPassword string) error {
+	_, err := s.ResetPassword(context.Background(), &pb.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+	return err
+}
+
+// checkPassword 检查数据库中用户 userID 的密码是否为 want
+func checkPassword(t *testing.T, db *gorm.DB, userID uint, want string) {
+	t.Helper()
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ok, _, err := testHasher.Verify(user.Password, want); err != nil || !ok {
+		t.Errorf("用户 %d 的密码不是 %q", userID, want)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	s, db, _ := newTestService(t)
+	user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+	token := issueResetToken(t, s, user.ID)
+
+	if err := resetPassword(s, token, newPassword); err != nil {
+		t.Fatalf("ResetPassword 失败: %v", err)
+	}
+	checkPassword(t, db, user.ID, newPassword)
+	var updated models.User
+	db.First(&updated, user.ID)
+	if !updated.EmailVerified {
+		t.Error("重置密码后邮箱没有标记为已验证")
+	}
+	var stored models.PasswordResetToken
+	db.Where("token_hash = ?", hashToken(token)).First(&stored)
+	if stored.UsedAt == nil {
+		t.Error("重置密码后令牌没有标记为已使用")
+	}
+}
+
+func TestResetPasswordRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		newPassword string
+		// prepare 返回提交的令牌
+		prepare func(t *testing.T, s *UserService, db *gorm.DB, userID uint) string
+	}{
+		{
+			name:        "令牌为空",
+			newPassword: newPassword,
+			prepare:     func(*testing.T, *UserService, *gorm.DB, uint) string { return "" },
+		},
+		{
+			name:        "新密码为空",
+			newPassword: "",
+			prepare: func(t *testing.T, s *UserService, _ *gorm.DB, userID uint) string {
+				return issueResetToken(t, s, userID)
+			},
+		},
+		{
+			name:        "令牌不存在",
+			newPassword: newPassword,
+			prepare:     func(*testing.T, *UserService, *gorm.DB, uint) string { return "not-a-token" },
+		},
+		{
+			name:        "令牌已过期",
+			newPassword: newPassword,
+			prepare: func(t *testing.T, s *UserService, db *gorm.DB, userID uint) string {
+				token := issueResetToken(t, s, userID)
+				db.Model(&models.PasswordResetToken{}).Where("token_hash = ?", hashToken(token)).
+					Update("expires_at", time.Now().Add(-time.Second))
+				return token
+			},
+		},
+		{
+			name:        "令牌已使用",
+			newPassword: "another password 3",
+			prepare: func(t *testing.T, s *UserService, _ *gorm.DB, userID uint) string {
+				token := issueResetToken(t, s, userID)
+				if err := resetPassword(s, token, newPassword); err != nil {
+					t.Fatalf("第一次 ResetPassword 失败: %v", err)
+				}
+				return token
+			},
+		},
+		{
+			name:        "申请新令牌后的旧令牌",
+			newPassword: newPassword,
+			prepare: func(t *testing.T, s *UserService, _ *gorm.DB, userID uint) string {
+				old := issueResetToken(t, s, userID)
+				s.opts.PasswordReset.RequestInterval = 0
+				issueResetToken(t, s, userID)
+				return old
+			},
+		},
+		{
+			name:        "新密码不符合策略",
+			newPassword: "short",
+			prepare: func(t *testing.T, s *UserService, _ *gorm.DB, userID uint) string {
+				return issueResetToken(t, s, userID)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, _ := newTestService(t)
+			user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+			token := tt.prepare(t, s, db, user.ID)
+			before := new(models.User)
+			db.First(before, user.ID)
+
+			err := resetPassword(s, token, tt.newPassword)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("ResetPassword 错误 = %v, 期望 InvalidArgument", err)
+			}
+			var after models.User
+			db.First(&after, user.ID)
+			if after.Password != before.Password {
+				t.Error("重置失败后密码被修改")
+			}
+		})
+	}
+}
+
+// 新密码不符合策略时令牌不会被消耗，用户可以换一个密码重试
+func TestResetPasswordPolicyViolationKeepsToken(t *testing.T) {
+	s, db, _ := newTestService(t)
+	user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+	token := issueResetToken(t, s, user.ID)
+
+	if err := resetPassword(s, token, "short"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ResetPassword 错误 = %v, 期望 InvalidArgument", err)
+	}
+	if err := resetPassword(s, token, newPassword); err != nil {
+		t.Fatalf("换用符合策略的密码后 ResetPassword 失败: %v", err)
+	}
+	checkPassword(t, db, user.ID, newPassword)
+}
+
+func TestIssuePasswordResetToken(t *testing.T) {
+	ctx := context.Background()
+	s, db, _ := newTestService(t)
+	user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+	first := issueResetToken(t, s, user.ID)
+
+	// RequestInterval 内的申请被忽略，已发送的令牌仍然有效
+	if token, _, err := s.issuePasswordResetToken(ctx, user.ID); err != nil || token != "" {
+		t.Fatalf("RequestInterval 内 issuePasswordResetToken = %q, %v, 期望不创建令牌", token, err)
+	}
+
+	// 超过间隔后重新申请，旧令牌立即过期，新令牌可以使用
+	db.Model(&models.PasswordResetToken{}).Where("user_id = ?", user.ID).
+		Update("created_at", time.Now().Add(-s.opts.PasswordReset.RequestInterval-time.Second))
+	second, expiresAt, err := s.issuePasswordResetToken(ctx, user.ID)
+	if err != nil || second == "" {
+		t.Fatalf("超过 RequestInterval 后 issuePasswordResetToken = %q, %v", second, err)
+	}
+	if want := time.Now().Add(s.opts.PasswordReset.TokenTTL); expiresAt.After(want) || expiresAt.Before(want.Add(-time.Minute)) {
+		t.Errorf("新令牌过期时间 = %s, 期望约为 %s", expiresAt, want)
+	}
+	if err := resetPassword(s, first, newPassword); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("使用旧令牌重置密码错误 = %v, 期望 InvalidArgument", err)
+	}
+	if err := resetPassword(s, second, newPassword); err != nil {
+		t.Errorf("使用新令牌重置密码失败: %v", err)
+	}
+
+	// 令牌只保存摘要
+	var count int64
+	db.Model(&models.PasswordResetToken{}).Where("token_hash IN ?", []string{first, second}).Count(&count)
+	if count != 0 {
+		t.Error("数据库中保存了明文令牌")
+	}
+}
+
+func TestResetPasswordRevokesAllSessions(t *testing.T) {
+	ctx := context.Background()
+	s, db, revoked := newTestService(t)
+	user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+	other := createUser(t, db, "bob", "bob@example.com", oldPassword)
+	for i, userID := range []uint{user.ID, user.ID, other.ID} {
+		refresh := models.RefreshToken{
+			UserID:    userID,
+			FamilyID:  "family",
+			TokenHash: hashToken(string(rune('a' + i))),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := db.Create(&refresh).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	issuedBefore := time.Now().Add(-time.Second)
+	token := issueResetToken(t, s, user.ID)
+
+	if err := resetPassword(s, token, newPassword); err != nil {
+		t.Fatalf("ResetPassword 失败: %v", err)
+	}
+
+	var refreshTokens []models.RefreshToken
+	db.Order("id").Find(&refreshTokens)
+	for _, refresh := range refreshTokens {
+		if got, want := refresh.RevokedAt != nil, refresh.UserID == user.ID; got != want {
+			t.Errorf("用户 %d 的刷新令牌 %d: 已撤销 = %t, 期望 %t", refresh.UserID, refresh.ID, got, want)
+		}
+	}
+
+	// 重置前签发的访问令牌被撤销，之后登录签发的不受影响，其他用户不受影响
+	tests := []struct {
+		name     string
+		userID   uint
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "重置前签发", userID: user.ID, issuedAt: issuedBefore, want: true},
+		{name: "重置后签发", userID: user.ID, issuedAt: time.Now().Add(time.Second), want: false},
+		{name: "其他用户", userID: other.ID, issuedAt: issuedBefore, want: false},
+	}
+	for _, tt := range tests {
+		if got, err := revoked.IsRevoked(ctx, "jti", tt.userID, tt.issuedAt); err != nil || got != tt.want {
+			t.Errorf("%s: IsRevoked = %t, %v, 期望 %t", tt.name, got, err, tt.want)
+		}
+	}
+}
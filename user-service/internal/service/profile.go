@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	pb "todo-project/user-service/proto/user"
+)
+
+// 用户资料各字段的长度上限，与 users 表的列定义一致
+const (
+	maxDisplayNameRunes = 64
+	maxAvatarURLLength  = 512
+	maxLocaleLength     = 35
+)
+
+// toProfile 将用户模型转换为返回给客户端的用户资料
+func toProfile(user *models.User) *pb.User {
+	return &pb.User{
+		Id:            uint32(user.ID),
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		DisplayName:   user.DisplayName,
+		AvatarUrl:     user.AvatarURL,
+		TimeZone:      user.TimeZone,
+		Locale:        user.Locale,
+	}
+}
+
+// GetProfile 查询用户资料
+func (s *UserService) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	log.Printf("Received GetProfile request for user ID: %d", req.GetUserId())
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, req.GetUserId()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "用户不存在")
+		}
+		log.Printf("获取用户信息失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "获取用户信息失败")
+	}
+	return &pb.GetProfileResponse{User: toProfile(&user)}, nil
+}
+
+// UpdateProfile 修改用户资料，只修改请求中设置了的字段。
+// 所有字段先校验，任一字段无效时不做任何修改，并在 BadRequest 中列出所有无效的字段
+func (s *UserService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
+	log.Printf("Received UpdateProfile request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+
+	updates := map[string]interface{}{}
+	var violations []*errdetails.BadRequest_FieldViolation
+	invalid := func(field, description string) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+	}
+	if req.DisplayName != nil {
+		name := strings.TrimSpace(req.GetDisplayName())
+		switch {
+		case utf8.RuneCountInString(name) > maxDisplayNameRunes:
+			invalid("display_name", fmt.Sprintf("显示名称不能超过 %d 个字符", maxDisplayNameRunes))
+		case strings.IndexFunc(name, unicode.IsControl) >= 0:
+			invalid("display_name", "显示名称不能包含控制字符")
+		default:
+			updates["display_name"] = name
+		}
+	}
+	if req.AvatarUrl != nil {
+		avatarURL := strings.TrimSpace(req.GetAvatarUrl())
+		if err := validateAvatarURL(avatarURL); err != nil {
+			invalid("avatar_url", err.Error())
+		} else {
+			updates["avatar_url"] = avatarURL
+		}
+	}
+	if req.TimeZone != nil {
+		tz := strings.TrimSpace(req.GetTimeZone())
+		if tz != "" && !validTimeZone(tz) {
+			invalid("time_zone", "未知的时区，请使用 IANA 时区名称，例如 Asia/Shanghai")
+		} else {
+			updates["time_zone"] = tz
+		}
+	}
+	if req.Locale != nil {
+		locale := strings.TrimSpace(req.GetLocale())
+		if locale == "" {
+			updates["locale"] = ""
+		} else if tag, err := language.Parse(locale); err != nil || len(tag.String()) > maxLocaleLength {
+			invalid("locale", "无效的语言标签，例如 zh-CN、en-US")
+		} else {
+			// 保存规范化后的形式，例如 zh-cn 保存为 zh-CN
+			updates["locale"] = tag.String()
+		}
+	}
+	if len(violations) > 0 {
+		return nil, invalidFieldsError("用户资料无效", "INVALID_PROFILE", violations)
+	}
+
+	var user models.User
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			result := tx.Model(&models.User{}).Where("id = ?", req.GetUserId()).Updates(updates)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return tx.First(&user, req.GetUserId()).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "用户不存在")
+	}
+	if err != nil {
+		log.Printf("更新用户资料失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "更新用户资料失败")
+	}
+
+	log.Printf("用户 %d 更新了资料: %d 个字段", user.ID, len(updates))
+	return &pb.UpdateProfileResponse{User: toProfile(&user)}, nil
+}
+
+// validateAvatarURL 检查头像地址，空地址表示清除头像
+func validateAvatarURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if len(raw) > maxAvatarURLLength {
+		return fmt.Errorf("头像地址不能超过 %d 个字符", maxAvatarURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("头像地址必须是 http 或 https 开头的完整网址")
+	}
+	return nil
+}
+
+// validTimeZone 返回 name 是否为 IANA 时区名称。Local 取决于服务器的设置，不允许使用
+func validTimeZone(name string) bool {
+	if name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
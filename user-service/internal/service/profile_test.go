@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/models"
+	pb "todo-project/user-service/proto/user"
+)
+
+func stringPtr(s string) *string { return &s }
+
+// invalidFields 返回错误中 BadRequest 列出的字段，按名称排序
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("错误 = %v, 期望 InvalidArgument", err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// createProfileUser 创建已填写全部资料的用户
+func createProfileUser(t *testing.T, db *gorm.DB) *models.User {
+	t.Helper()
+	user := createUser(t, db, "alice", "alice@example.com", oldPassword)
+	user.DisplayName = "Alice"
+	user.AvatarURL = "https://example.com/alice.png"
+	user.TimeZone = "Asia/Shanghai"
+	user.Locale = "zh-CN"
+	if err := db.Save(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestGetProfile(t *testing.T) {
+	s, db, _ := newTestService(t)
+	user := createProfileUser(t, db)
+
+	res, err := s.GetProfile(context.Background(), &pb.GetProfileRequest{UserId: uint32(user.ID)})
+	if err != nil {
+		t.Fatalf("GetProfile 失败: %v", err)
+	}
+	got := res.GetUser()
+	if got.GetUsername() != "alice" || got.GetEmail() != "alice@example.com" || got.GetDisplayName() != "Alice" ||
+		got.GetAvatarUrl() != user.AvatarURL || got.GetTimeZone() != "Asia/Shanghai" || got.GetLocale() != "zh-CN" {
+		t.Errorf("GetProfile = %+v", got)
+	}
+
+	if _, err := s.GetProfile(context.Background(), &pb.GetProfileRequest{UserId: 999}); status.Code(err) != codes.NotFound {
+		t.Errorf("用户不存在时错误 = %v, 期望 NotFound", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.UpdateProfileRequest
+		want models.User // 只比较资料字段
+	}{
+		{
+			name: "没有设置任何字段",
+			req:  &pb.UpdateProfileRequest{},
+			want: models.User{DisplayName: "Alice", AvatarURL: "https://example.com/alice.png", TimeZone: "Asia/Shanghai", Locale: "zh-CN"},
+		},
+		{
+			name: "只修改设置了的字段",
+			req:  &pb.UpdateProfileRequest{DisplayName: stringPtr("  爱丽丝 "), TimeZone: stringPtr("Europe/Berlin")},
+			want: models.User{DisplayName: "爱丽丝", AvatarURL: "https://example.com/alice.png", TimeZone: "Europe/Berlin", Locale: "zh-CN"},
+		},
+		{
+			name: "空字符串清除字段",
+			req:  &pb.UpdateProfileRequest{DisplayName: stringPtr(""), AvatarUrl: stringPtr(" "), TimeZone: stringPtr(""), Locale: stringPtr("")},
+			want: models.User{},
+		},
+		{
+			name: "语言标签规范化",
+			req:  &pb.UpdateProfileRequest{Locale: stringPtr("en-us")},
+			want: models.User{DisplayName: "Alice", AvatarURL: "https://example.com/alice.png", TimeZone: "Asia/Shanghai", Locale: "en-US"},
+		},
+		{
+			name: "显示名称恰好 64 个字符",
+			req:  &pb.UpdateProfileRequest{DisplayName: stringPtr(strings.Repeat("名", maxDisplayNameRunes)), AvatarUrl: stringPtr("http://example.com/a.png")},
+			want: models.User{DisplayName: strings.Repeat("名", maxDisplayNameRunes), AvatarURL: "http://example.com/a.png", TimeZone: "Asia/Shanghai", Locale: "zh-CN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, _ := newTestService(t)
+			user := createProfileUser(t, db)
+			tt.req.UserId = uint32(user.ID)
+
+			res, err := s.UpdateProfile(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("UpdateProfile 失败: %v", err)
+			}
+			got := res.GetUser()
+			if got.GetDisplayName() != tt.want.DisplayName || got.GetAvatarUrl() != tt.want.AvatarURL ||
+				got.GetTimeZone() != tt.want.TimeZone || got.GetLocale() != tt.want.Locale {
+				t.Errorf("UpdateProfile 返回 %+v, 期望 %+v", got, tt.want)
+			}
+			// 返回的资料与数据库一致
+			profile, err := s.GetProfile(context.Background(), &pb.GetProfileRequest{UserId: uint32(user.ID)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(profile.GetUser(), got) {
+				t.Errorf("数据库中的资料 %+v 与返回的 %+v 不一致", profile.GetUser(), got)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *pb.UpdateProfileRequest
+		wantFields []string
+	}{
+		{name: "显示名称过长", req: &pb.UpdateProfileRequest{DisplayName: stringPtr(strings.Repeat("名", maxDisplayNameRunes+1))}, wantFields: []string{"display_name"}},
+		{name: "显示名称包含控制字符", req: &pb.UpdateProfileRequest{DisplayName: stringPtr("Alice\nBob")}, wantFields: []string{"display_name"}},
+		{name: "头像地址不是 http", req: &pb.UpdateProfileRequest{AvatarUrl: stringPtr("javascript:alert(1)")}, wantFields: []string{"avatar_url"}},
+		{name: "头像地址缺少主机", req: &pb.UpdateProfileRequest{AvatarUrl: stringPtr("https:///a.png")}, wantFields: []string{"avatar_url"}},
+		{name: "头像地址过长", req: &pb.UpdateProfileRequest{AvatarUrl: stringPtr("https://example.com/" + strings.Repeat("a", maxAvatarURLLength))}, wantFields: []string{"avatar_url"}},
+		{name: "未知的时区", req: &pb.UpdateProfileRequest{TimeZone: stringPtr("Mars/Olympus")}, wantFields: []string{"time_zone"}},
+		{name: "服务器本地时区", req: &pb.UpdateProfileRequest{TimeZone: stringPtr("Local")}, wantFields: []string{"time_zone"}},
+		{name: "无效的语言标签", req: &pb.UpdateProfileRequest{Locale: stringPtr("not a locale")}, wantFields: []string{"locale"}},
+		{
+			name: "列出所有无效的字段，有效的字段也不修改",
+			req: &pb.UpdateProfileRequest{
+				DisplayName: stringPtr("Bob"),
+				AvatarUrl:   stringPtr("ftp://example.com/a.png"),
+				TimeZone:    stringPtr("Nowhere"),
+				Locale:      stringPtr("??"),
+			},
+			wantFields: []string{"avatar_url", "locale", "time_zone"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, _ := newTestService(t)
+			user := createProfileUser(t, db)
+			tt.req.UserId = uint32(user.ID)
+
+			_, err := s.UpdateProfile(context.Background(), tt.req)
+			if got := invalidFields(t, err); strings.Join(got, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("无效的字段 = %v, 期望 %v", got, tt.wantFields)
+			}
+			profile, err := s.GetProfile(context.Background(), &pb.GetProfileRequest{UserId: uint32(user.ID)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := profile.GetUser(); got.GetDisplayName() != "Alice" || got.GetAvatarUrl() != user.AvatarURL ||
+				got.GetTimeZone() != "Asia/Shanghai" || got.GetLocale() != "zh-CN" {
+				t.Errorf("校验失败后资料被修改: %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileUnknownUser(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+	if _, err := s.UpdateProfile(ctx, &pb.UpdateProfileRequest{DisplayName: stringPtr("Alice")}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("user_id 为 0 时错误 = %v, 期望 InvalidArgument", err)
+	}
+	for _, req := range []*pb.UpdateProfileRequest{
+		{UserId: 999, DisplayName: stringPtr("Alice")},
+		{UserId: 999},
+	} {
+		if _, err := s.UpdateProfile(ctx, req); status.Code(err) != codes.NotFound {
+			t.Errorf("UpdateProfile(%+v) 错误 = %v, 期望 NotFound", req, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/password"
+	"todo-project/user-service/internal/revocation"
+)
+
+// 测试使用较小的参数，避免每次哈希都占用 64 MiB 内存
+var testHasher = password.NewHasher(password.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1})
+
+var testDBCount atomic.Int64
+
+// openTestDB 把 database.DB 替换为新的内存 SQLite 数据库，测试结束后恢复
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:service%d?mode=memory", testDBCount.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.PasswordResetToken{}); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		sqlDB.Close()
+	})
+	return db
+}
+
+// newTestService 创建使用内存 SQLite 数据库和 MemoryStore 的 UserService
+func newTestService(t *testing.T) (*UserService, *gorm.DB, *revocation.MemoryStore) {
+	t.Helper()
+	db := openTestDB(t)
+	revoked := revocation.NewMemoryStore()
+	s := NewUserService(nil, revoked, nil, Options{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+		PasswordReset: PasswordResetOptions{
+			TokenTTL:        time.Hour,
+			RequestInterval: time.Minute,
+		},
+		PasswordPolicy: password.Policy{MinLength: 8},
+		PasswordHasher: testHasher,
+	})
+	return s, db, revoked
+}
+
+// createUser 创建密码为 oldPassword 的用户
+func createUser(t *testing.T, db *gorm.DB, username, email, oldPassword string) *models.User {
+	t.Helper()
+	hashed, err := testHasher.Hash(oldPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{Username: username, Email: email, Password: hashed}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
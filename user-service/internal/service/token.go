@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	pb "todo-project/user-service/proto/user"
+)
+
+// errInvalidRefreshToken 刷新令牌不存在、已过期或已被撤销
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// randomToken 生成 n 字节的随机数并以 URL 安全的 Base64 编码
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken 返回刷新令牌的 SHA-256 摘要，数据库中只保存摘要
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueAccessToken 为用户签发短期访问令牌 (JWT)，签名算法和密钥由 s.keys 决定。jti 用于撤销单个令牌；
+// iat 精确到毫秒，修改密码后同一秒内重新登录签发的令牌不会被误判为已撤销
+func (s *UserService) issueAccessToken(user *models.User) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &jwt.MapClaims{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"jti":      jti,
+		"iat":      float64(now.UnixMilli()) / 1000,
+		"exp":      now.Add(s.opts.AccessTokenTTL).Unix(),
+	}
+	return s.keys.Sign(claims)
+}
+
+// issueRefreshToken 在 tx 中为令牌家族 familyID 创建新的刷新令牌，返回令牌明文
+func (s *UserService) issueRefreshToken(tx *gorm.DB, userID uint, familyID string, now time.Time) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	record := models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.opts.RefreshTokenTTL),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// revokeTokenFamily 撤销令牌家族中所有未撤销的刷新令牌
+func revokeTokenFamily(tx *gorm.DB, familyID string, now time.Time) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+// RefreshToken 使用刷新令牌换取新的访问令牌。刷新令牌只能使用一次，每次使用后轮换为新令牌；
+// 已使用过的令牌再次出现说明令牌可能已泄露，此时撤销整个令牌家族，要求用户重新登录
+func (s *UserService) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	log.Printf("Received RefreshToken request")
+
+	if req.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "刷新令牌不能为空")
+	}
+
+	var (
+		stored       models.RefreshToken
+		user         models.User
+		refreshToken string
+		reused       bool
+	)
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", hashToken(req.GetRefreshToken())).First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidRefreshToken
+			}
+			return err
+		}
+		now := time.Now()
+		if stored.RevokedAt != nil || now.After(stored.ExpiresAt) {
+			return errInvalidRefreshToken
+		}
+		if stored.UsedAt != nil {
+			reused = true
+			return nil
+		}
+		// 条件更新保证并发使用同一个令牌时只有一个请求能完成轮换
+		result := tx.Model(&stored).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused = true
+			return nil
+		}
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidRefreshToken
+			}
+			return err
+		}
+		var err error
+		refreshToken, err = s.issueRefreshToken(tx, user.ID, stored.FamilyID, now)
+		return err
+	})
+	if errors.Is(err, errInvalidRefreshToken) {
+		return nil, status.Errorf(codes.Unauthenticated, "刷新令牌无效或已过期，请重新登录")
+	}
+	if err != nil {
+		log.Printf("轮换刷新令牌失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "刷新令牌失败")
+	}
+	if reused {
+		log.Printf("警告: 检测到用户 %d 的刷新令牌被重复使用，撤销令牌家族 %s", stored.UserID, stored.FamilyID)
+		if err := revokeTokenFamily(database.DB.WithContext(ctx), stored.FamilyID, time.Now()); err != nil {
+			log.Printf("撤销令牌家族 %s 失败: %v", stored.FamilyID, err)
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "刷新令牌已被使用，会话已撤销，请重新登录")
+	}
+
+	// 宽限期在会话期间结束时，刷新令牌同样失效
+	if err := s.checkEmailVerified(&user); err != nil {
+		log.Printf("用户 %d 的邮箱尚未验证，拒绝刷新令牌", user.ID)
+		return nil, err
+	}
+
+	accessToken, err := s.issueAccessToken(&user)
+	if err != nil {
+		log.Printf("JWT令牌生成失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "生成令牌失败")
+	}
+
+	log.Printf("用户 %d 的刷新令牌已轮换", user.ID)
+	return &pb.RefreshTokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.opts.AccessTokenTTL / time.Second),
+	}, nil
+}
+
+// revokeAllSessions 撤销用户的所有刷新令牌以及此前签发的所有访问令牌。
+// 失败时只记录日志，调用方的操作已经完成
+func (s *UserService) revokeAllSessions(ctx context.Context, userID uint) {
+	now := time.Now()
+	result := database.DB.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		log.Printf("撤销用户 %d 的刷新令牌失败: %v", userID, result.Error)
+	}
+	if err := s.revoked.RevokeUser(ctx, userID, now, s.opts.AccessTokenTTL); err != nil {
+		log.Printf("撤销用户 %d 的访问令牌失败: %v", userID, err)
+		return
+	}
+	log.Printf("用户 %d 的所有会话已撤销 (%d 个刷新令牌)", userID, result.RowsAffected)
+}
+
+// revokeAccessToken 撤销未过期的访问令牌，令牌无效时忽略
+func (s *UserService) revokeAccessToken(ctx context.Context, tokenString string) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc); err != nil {
+		log.Printf("登出时访问令牌无效，忽略: %v", err)
+		return
+	}
+	jti, _ := claims["jti"].(string)
+	exp, _ := claims["exp"].(float64)
+	if jti == "" {
+		return
+	}
+	if err := s.revoked.RevokeToken(ctx, jti, time.Unix(int64(exp), 0)); err != nil {
+		log.Printf("撤销访问令牌 %s 失败: %v", jti, err)
+	}
+}
+
+// Logout 撤销刷新令牌所在的令牌家族，提供了访问令牌时一并撤销。令牌不存在或已撤销时同样返回成功
+func (s *UserService) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	log.Printf("Received Logout request")
+
+	if req.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "刷新令牌不能为空")
+	}
+	if req.GetAccessToken() != "" {
+		s.revokeAccessToken(ctx, req.GetAccessToken())
+	}
+
+	var stored models.RefreshToken
+	err := database.DB.WithContext(ctx).Where("token_hash = ?", hashToken(req.GetRefreshToken())).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("登出时刷新令牌不存在，忽略")
+		return &pb.LogoutResponse{}, nil
+	}
+	if err != nil {
+		log.Printf("查找刷新令牌失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "登出失败")
+	}
+
+	if err := revokeTokenFamily(database.DB.WithContext(ctx), stored.FamilyID, time.Now()); err != nil {
+		log.Printf("撤销令牌家族 %s 失败: %v", stored.FamilyID, err)
+		return nil, status.Errorf(codes.Internal, "登出失败")
+	}
+
+	log.Printf("用户 %d 已登出，令牌家族 %s 已撤销", stored.UserID, stored.FamilyID)
+	return &pb.LogoutResponse{}, nil
+}
+
+// GetJWKS 返回校验访问令牌签名的公钥集合，供 API 网关校验令牌
+func (s *UserService) GetJWKS(ctx context.Context, req *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error) {
+	log.Printf("Received GetJWKS request")
+
+	publicKeys := s.keys.PublicKeys()
+	resp := &pb.GetJWKSResponse{Keys: make([]*pb.JSONWebKey, 0, len(publicKeys))}
+	for _, key := range publicKeys {
+		resp.Keys = append(resp.Keys, &pb.JSONWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/models"
+	"todo-project/user-service/internal/totp"
+	pb "todo-project/user-service/proto/user"
+)
+
+// TwoFactorOptions 两步验证配置
+type TwoFactorOptions struct {
+	// Issuer 身份验证器应用中显示的服务名称
+	Issuer string
+	// ChallengeTTL 密码验证通过后完成第二步验证的期限
+	ChallengeTTL time.Duration
+	// MaxAttempts 每次登录允许输错验证码的次数，超过后需要重新输入密码
+	MaxAttempts int
+}
+
+const (
+	recoveryCodeCount = 10
+	// 恢复码为 10 个 Base32 字符 (50 位随机数)，以 xxxxx-xxxxx 的形式展示
+	recoveryCodeLength = 10
+	// 允许客户端与服务器的时钟相差前后各一个时间步
+	totpSkew = 1
+)
+
+var (
+	// errInvalidChallenge 挑战令牌不存在、已使用、已过期或输错次数过多
+	errInvalidChallenge = errors.New("invalid two-factor challenge")
+	// errInvalidSecondFactor 验证码或恢复码错误
+	errInvalidSecondFactor = errors.New("invalid second factor code")
+	// errTwoFactorEnabled 已启用两步验证
+	errTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+	// errTwoFactorNotEnabled 未启用两步验证或尚未开始绑定
+	errTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// twoFactorEnabled 返回用户是否已确认绑定 TOTP
+func twoFactorEnabled(db *gorm.DB, userID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.UserTOTP{}).Where("user_id = ? AND confirmed_at IS NOT NULL", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// startTwoFactorChallenge 密码验证通过后创建挑战令牌，客户端凭它和验证码调用 VerifySecondFactor 完成登录
+func (s *UserService) startTwoFactorChallenge(ctx context.Context, userID uint) (*pb.LoginResponse, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	record := models.TwoFactorChallenge{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.opts.TwoFactor.ChallengeTTL),
+	}
+	if err := database.DB.WithContext(ctx).Create(&record).Error; err != nil {
+		return nil, err
+	}
+	return &pb.LoginResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresIn: int64(s.opts.TwoFactor.ChallengeTTL / time.Second),
+	}, nil
+}
+
+// isTOTPCode 6 位数字按 TOTP 验证码处理，其余按恢复码处理
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRecoveryCode 忽略恢复码中的分隔符、空白和大小写
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+// verifySecondFactor 在 tx 中检查 TOTP 验证码或恢复码，返回是否有效。
+// 同一时间步的验证码只能使用一次，恢复码使用后立即失效
+func verifySecondFactor(tx *gorm.DB, userID uint, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+	if !isTOTPCode(code) {
+		result := tx.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalizeRecoveryCode(code))).
+			Update("used_at", now)
+		return result.RowsAffected > 0, result.Error
+	}
+
+	var record models.UserTOTP
+	if err := tx.Where("user_id = ? AND confirmed_at IS NOT NULL", userID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	step, ok := totp.Validate(record.Secret, code, now, totpSkew)
+	if !ok {
+		return false, nil
+	}
+	// 条件更新保证同一时间步的验证码不能重放
+	result := tx.Model(&models.UserTOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// replaceRecoveryCodes 在 tx 中删除用户的所有恢复码并生成一组新的，返回恢复码明文
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, (recoveryCodeLength*5+7)/8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := recoveryCodeEncoding.EncodeToString(b)[:recoveryCodeLength]
+		codes = append(codes, strings.ToLower(code[:5]+"-"+code[5:]))
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: hashToken(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifySecondFactor 使用挑战令牌和 TOTP 验证码 (或恢复码) 完成登录。
+// 挑战令牌只能使用一次，验证码输错 MaxAttempts 次后失效，需要重新输入密码
+func (s *UserService) VerifySecondFactor(ctx context.Context, req *pb.VerifySecondFactorRequest) (*pb.LoginResponse, error) {
+	log.Printf("Received VerifySecondFactor request")
+
+	if req.GetChallengeToken() == "" || req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "挑战令牌和验证码不能为空")
+	}
+
+	// 验证码错误与密码错误一样计入登录失败记录，防止反复登录换取新的挑战令牌来穷举验证码
+	var (
+		pending models.TwoFactorChallenge
+		owner   models.User
+	)
+	err := database.DB.WithContext(ctx).Where("token_hash = ?", hashToken(req.GetChallengeToken())).First(&pending).Error
+	if err == nil {
+		err = database.DB.WithContext(ctx).First(&owner, pending.UserID).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.Unauthenticated, "登录验证已失效，请重新输入用户名和密码")
+	}
+	if err != nil {
+		log.Printf("查找两步验证挑战失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "两步验证失败")
+	}
+	attempt, err := s.reserveLoginAttempt(ctx, owner.Username, clientIP(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer s.releaseLoginAttempt(ctx, attempt)
+
+	var (
+		user  models.User
+		valid bool
+	)
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var challenge models.TwoFactorChallenge
+		if err := tx.Where("token_hash = ?", hashToken(req.GetChallengeToken())).First(&challenge).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidChallenge
+			}
+			return err
+		}
+		now := time.Now()
+		if challenge.UsedAt != nil || !now.Before(challenge.ExpiresAt) || challenge.Attempts >= s.opts.TwoFactor.MaxAttempts {
+			return errInvalidChallenge
+		}
+		var err error
+		valid, err = verifySecondFactor(tx, challenge.UserID, req.GetCode(), now)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			log.Printf("用户 %d 两步验证的验证码错误 (第 %d 次)", challenge.UserID, challenge.Attempts+1)
+			// 提交事务以保存错误次数
+			return tx.Model(&challenge).Update("attempts", gorm.Expr("attempts + 1")).Error
+		}
+		// 条件更新保证挑战令牌只能使用一次，失败时回滚已使用的恢复码
+		result := tx.Model(&challenge).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvalidChallenge
+		}
+		if err := tx.First(&user, challenge.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidChallenge
+			}
+			return err
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidChallenge) {
+		return nil, status.Errorf(codes.Unauthenticated, "登录验证已失效，请重新输入用户名和密码")
+	}
+	if err != nil {
+		log.Printf("两步验证失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "两步验证失败")
+	}
+	if !valid {
+		s.recordLoginFailure(ctx, attempt, &owner)
+		return nil, status.Errorf(codes.Unauthenticated, "验证码错误")
+	}
+
+	resp, err := s.startSession(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+	s.recordLoginSuccess(ctx, attempt)
+	log.Printf("用户登录成功 (两步验证): %s", user.Username)
+	return resp, nil
+}
+
+// GetTwoFactorStatus 返回用户是否启用了两步验证以及剩余的恢复码数量
+func (s *UserService) GetTwoFactorStatus(ctx context.Context, req *pb.GetTwoFactorStatusRequest) (*pb.GetTwoFactorStatusResponse, error) {
+	log.Printf("Received GetTwoFactorStatus request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+	db := database.DB.WithContext(ctx)
+	enabled, err := twoFactorEnabled(db, uint(req.GetUserId()))
+	if err != nil {
+		log.Printf("查询两步验证状态失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "查询两步验证状态失败")
+	}
+	resp := &pb.GetTwoFactorStatusResponse{Enabled: enabled}
+	if enabled {
+		var remaining int64
+		if err := db.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", req.GetUserId()).
+			Count(&remaining).Error; err != nil {
+			log.Printf("查询恢复码失败: %v", err)
+			return nil, status.Errorf(codes.Internal, "查询两步验证状态失败")
+		}
+		resp.RecoveryCodesRemaining = int32(remaining)
+	}
+	return resp, nil
+}
+
+// BeginTOTPEnrollment 生成新的 TOTP 密钥，覆盖之前尚未确认的密钥。已启用两步验证时需要先停用
+func (s *UserService) BeginTOTPEnrollment(ctx context.Context, req *pb.BeginTOTPEnrollmentRequest) (*pb.BeginTOTPEnrollmentResponse, error) {
+	log.Printf("Received BeginTOTPEnrollment request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, req.GetUserId()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "用户不存在")
+		}
+		log.Printf("获取用户信息失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "获取用户信息失败")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Printf("生成 TOTP 密钥失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "生成密钥失败")
+	}
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		enabled, err := twoFactorEnabled(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			return errTwoFactorEnabled
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserTOTP{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.UserTOTP{UserID: user.ID, Secret: secret}).Error
+	})
+	if errors.Is(err, errTwoFactorEnabled) {
+		return nil, status.Errorf(codes.FailedPrecondition, "已启用两步验证，如需更换身份验证器请先停用")
+	}
+	if err != nil {
+		log.Printf("保存 TOTP 密钥失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "生成密钥失败")
+	}
+
+	log.Printf("用户 %d 开始绑定身份验证器", user.ID)
+	return &pb.BeginTOTPEnrollmentResponse{
+		Secret:     secret,
+		OtpauthUri: totp.URI(s.opts.TwoFactor.Issuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment 使用身份验证器生成的验证码确认绑定，启用两步验证并生成恢复码
+func (s *UserService) ConfirmTOTPEnrollment(ctx context.Context, req *pb.ConfirmTOTPEnrollmentRequest) (*pb.ConfirmTOTPEnrollmentResponse, error) {
+	log.Printf("Received ConfirmTOTPEnrollment request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+	if req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "验证码不能为空")
+	}
+
+	userID := uint(req.GetUserId())
+	var recoveryCodes []string
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var record models.UserTOTP
+		if err := tx.Where("user_id = ?", userID).First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errTwoFactorNotEnabled
+			}
+			return err
+		}
+		if record.ConfirmedAt != nil {
+			return errTwoFactorEnabled
+		}
+		now := time.Now()
+		step, ok := totp.Validate(record.Secret, strings.TrimSpace(req.GetCode()), now, totpSkew)
+		if !ok {
+			return errInvalidSecondFactor
+		}
+		result := tx.Model(&models.UserTOTP{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{"confirmed_at": now, "last_used_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTwoFactorEnabled
+		}
+		var err error
+		recoveryCodes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	switch {
+	case errors.Is(err, errTwoFactorNotEnabled):
+		return nil, status.Errorf(codes.FailedPrecondition, "请先开始绑定身份验证器")
+	case errors.Is(err, errTwoFactorEnabled):
+		return nil, status.Errorf(codes.FailedPrecondition, "已启用两步验证")
+	case errors.Is(err, errInvalidSecondFactor):
+		return nil, status.Errorf(codes.InvalidArgument, "验证码错误，请确认身份验证器的时间准确")
+	case err != nil:
+		log.Printf("确认绑定身份验证器失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "启用两步验证失败")
+	}
+
+	log.Printf("用户 %d 已启用两步验证", userID)
+	return &pb.ConfirmTOTPEnrollmentResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTwoFactor 验证密码和验证码 (或恢复码) 后停用两步验证，删除密钥、恢复码和未完成的登录
+func (s *UserService) DisableTwoFactor(ctx context.Context, req *pb.DisableTwoFactorRequest) (*pb.DisableTwoFactorResponse, error) {
+	log.Printf("Received DisableTwoFactor request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+	if req.GetPassword() == "" || req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "密码和验证码不能为空")
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, req.GetUserId()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "用户不存在")
+		}
+		log.Printf("获取用户信息失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "获取用户信息失败")
+	}
+	if ok, _, _ := s.opts.PasswordHasher.Verify(user.Password, req.GetPassword()); !ok {
+		log.Printf("停用两步验证时密码验证失败 for user ID %d", user.ID)
+		return nil, status.Errorf(codes.PermissionDenied, "密码或验证码不正确")
+	}
+
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		enabled, err := twoFactorEnabled(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return errTwoFactorNotEnabled
+		}
+		valid, err := verifySecondFactor(tx, user.ID, req.GetCode(), time.Now())
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errInvalidSecondFactor
+		}
+		for _, model := range []interface{}{&models.UserTOTP{}, &models.RecoveryCode{}, &models.TwoFactorChallenge{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errTwoFactorNotEnabled):
+		return nil, status.Errorf(codes.FailedPrecondition, "未启用两步验证")
+	case errors.Is(err, errInvalidSecondFactor):
+		return nil, status.Errorf(codes.PermissionDenied, "密码或验证码不正确")
+	case err != nil:
+		log.Printf("停用两步验证失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "停用两步验证失败")
+	}
+
+	log.Printf("用户 %d 已停用两步验证", user.ID)
+	return &pb.DisableTwoFactorResponse{}, nil
+}
+
+// RegenerateRecoveryCodes 验证 TOTP 验证码后生成一组新的恢复码，旧恢复码全部失效
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, req *pb.RegenerateRecoveryCodesRequest) (*pb.RegenerateRecoveryCodesResponse, error) {
+	log.Printf("Received RegenerateRecoveryCodes request for user ID: %d", req.GetUserId())
+
+	if req.GetUserId() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的用户ID")
+	}
+	code := strings.TrimSpace(req.GetCode())
+	if !isTOTPCode(code) {
+		return nil, status.Errorf(codes.InvalidArgument, "请输入身份验证器中的 6 位验证码")
+	}
+
+	userID := uint(req.GetUserId())
+	var recoveryCodes []string
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		enabled, err := twoFactorEnabled(tx, userID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return errTwoFactorNotEnabled
+		}
+		valid, err := verifySecondFactor(tx, userID, code, time.Now())
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errInvalidSecondFactor
+		}
+		recoveryCodes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	switch {
+	case errors.Is(err, errTwoFactorNotEnabled):
+		return nil, status.Errorf(codes.FailedPrecondition, "未启用两步验证")
+	case errors.Is(err, errInvalidSecondFactor):
+		return nil, status.Errorf(codes.PermissionDenied, "验证码不正确")
+	case err != nil:
+		log.Printf("重新生成恢复码失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "重新生成恢复码失败")
+	}
+
+	log.Printf("用户 %d 已重新生成恢复码", userID)
+	return &pb.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
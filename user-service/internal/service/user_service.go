@@ -5,28 +5,54 @@ import (
 	"log"
 	"time"
 
-	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 
 	"todo-project/user-service/internal/database"
+	"todo-project/user-service/internal/keys"
 	"todo-project/user-service/internal/models"
 	"todo-project/user-service/internal/mq"
+	"todo-project/user-service/internal/password"
+	"todo-project/user-service/internal/revocation"
+	"todo-project/user-service/internal/throttle"
 	pb "todo-project/user-service/proto/user"
 )
 
+// Options UserService 的配置
+type Options struct {
+	// AccessTokenTTL 和 RefreshTokenTTL 分别为访问令牌和刷新令牌的有效期
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// EmailVerification 决定注册时是否需要验证邮箱
+	EmailVerification EmailVerificationOptions
+	// PasswordReset 找回密码的配置
+	PasswordReset PasswordResetOptions
+	// TwoFactor 两步验证的配置
+	TwoFactor TwoFactorOptions
+	// PasswordPolicy 注册、修改密码和重置密码时新密码需要满足的策略
+	PasswordPolicy password.Policy
+	// PasswordHasher 生成和验证密码哈希，登录时把旧算法或弱参数生成的哈希升级为当前配置
+	PasswordHasher *password.Hasher
+}
+
 // UserService 实现UserServiceServer接口
 type UserService struct {
 	pb.UnimplementedUserServiceServer
-	jwtKey []byte
+	keys    *keys.Manager
+	revoked revocation.Store
+	guard   *throttle.Guard
+	opts    Options
 }
 
-// NewUserService 创建UserService实例
-func NewUserService(jwtKey []byte) *UserService {
+// NewUserService 创建UserService实例，访问令牌由 signingKeys 签名，
+// 登出、修改密码和重置密码时被撤销的访问令牌记录到 revoked，guard 限制登录失败后的重试
+func NewUserService(signingKeys *keys.Manager, revoked revocation.Store, guard *throttle.Guard, opts Options) *UserService {
 	return &UserService{
-		jwtKey: jwtKey,
+		keys:    signingKeys,
+		revoked: revoked,
+		guard:   guard,
+		opts:    opts,
 	}
 }
 
@@ -42,6 +68,9 @@ func (s *UserService) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 	if username == "" || password == "" || email == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "用户名、密码和邮箱不能为空")
 	}
+	if err := s.checkPasswordPolicy("password", password, username, email); err != nil {
+		return nil, err
+	}
 
 	// 检查用户名是否已存在
 	var existingUser models.User
@@ -55,29 +84,49 @@ func (s *UserService) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 	}
 
 	// 密码加密
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := s.opts.PasswordHasher.Hash(password)
 	if err != nil {
 		log.Printf("密码加密失败: %v", err)
 		return nil, status.Errorf(codes.Internal, "密码加密失败")
 	}
 
 	newUser := models.User{
-		Username: username,
-		Password: string(hashedPassword),
-		Email:    email,
+		Username:      username,
+		Password:      hashedPassword,
+		Email:         email,
+		EmailVerified: s.opts.EmailVerification.Policy == EmailVerificationOff,
 	}
 
-	// 创建用户
-	result := database.DB.Create(&newUser)
-	if result.Error != nil {
-		log.Printf("创建用户失败: %v", result.Error)
+	// 创建用户，需要验证邮箱时同时创建验证令牌
+	var (
+		verificationToken string
+		tokenExpiresAt    time.Time
+	)
+	err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newUser).Error; err != nil {
+			return err
+		}
+		if newUser.EmailVerified {
+			return nil
+		}
+		var err error
+		verificationToken, tokenExpiresAt, err = s.issueEmailVerificationToken(tx, newUser.ID, time.Now())
+		return err
+	})
+	if err != nil {
+		log.Printf("创建用户失败: %v", err)
 		return nil, status.Errorf(codes.Internal, "创建用户失败")
 	}
 
 	log.Printf("用户注册成功: ID=%d, Username=%s, Email=%s", newUser.ID, newUser.Username, newUser.Email)
 
-	// 发布用户注册事件
-	if err := mq.PublishUserRegistered(&newUser); err != nil {
+	// 发布用户注册事件，需要验证邮箱时先发送验证邮件，验证后再发送欢迎邮件
+	if newUser.EmailVerified {
+		err = mq.PublishUserRegistered(&newUser)
+	} else {
+		err = mq.PublishEmailVerification(&newUser, verificationToken, tokenExpiresAt)
+	}
+	if err != nil {
 		log.Printf("发布用户注册事件失败: %v", err)
 		// 不阻止注册成功
 	}
@@ -96,11 +145,21 @@ func (s *UserService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 		return nil, status.Errorf(codes.InvalidArgument, "用户名和密码不能为空")
 	}
 
+	// 连续失败后在验证密码之前拒绝，退避期和锁定期内即使密码正确也不能登录
+	ip := clientIP(ctx)
+	attempt, err := s.reserveLoginAttempt(ctx, username, ip)
+	if err != nil {
+		return nil, err
+	}
+	defer s.releaseLoginAttempt(ctx, attempt)
+
 	// 查找用户
 	var user models.User
 	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Printf("用户未找到: %s", username)
+			// 不存在的用户名同样计数，锁定行为不泄露用户名是否存在
+			s.recordLoginFailure(ctx, attempt, nil)
 		} else {
 			log.Printf("查找用户失败: %v", err)
 		}
@@ -108,29 +167,98 @@ func (s *UserService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 	}
 
 	// 验证密码
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	ok, rehash, err := s.opts.PasswordHasher.Verify(user.Password, password)
 	if err != nil {
-		log.Printf("密码验证失败 for user %s: %v", username, err)
+		log.Printf("验证密码哈希出错 for user %s: %v", username, err)
+	}
+	if !ok {
+		log.Printf("密码验证失败 for user %s from %s", username, ip)
+		s.recordLoginFailure(ctx, attempt, &user)
 		return nil, status.Errorf(codes.Unauthenticated, "用户名或密码错误")
 	}
+	if rehash {
+		s.upgradePasswordHash(ctx, &user, password)
+	}
+
+	if err := s.checkEmailVerified(&user); err != nil {
+		log.Printf("用户 %s 的邮箱尚未验证，拒绝登录", username)
+		return nil, err
+	}
+
+	// 启用了两步验证时先返回挑战令牌，验证码通过后再签发令牌
+	enabled, err := twoFactorEnabled(database.DB.WithContext(ctx), user.ID)
+	if err != nil {
+		log.Printf("查询两步验证状态失败 for user %s: %v", username, err)
+		return nil, status.Errorf(codes.Internal, "登录失败")
+	}
+	// 启用两步验证时失败记录在第二步验证通过后才清除，知道密码的攻击者不能借此重置计数
+	if enabled {
+		resp, err := s.startTwoFactorChallenge(ctx, user.ID)
+		if err != nil {
+			log.Printf("创建两步验证挑战失败 for user %s: %v", username, err)
+			return nil, status.Errorf(codes.Internal, "登录失败")
+		}
+		log.Printf("用户 %s 密码验证通过，等待两步验证", username)
+		return resp, nil
+	}
+
+	resp, err := s.startSession(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+	s.recordLoginSuccess(ctx, attempt)
+	log.Printf("用户登录成功: %s", username)
+	return resp, nil
+}
 
-	// 生成JWT令牌
-	expirationTime := time.Now().Add(24 * time.Hour) // 24小时过期
-	claims := &jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"exp":      expirationTime.Unix(),
+// upgradePasswordHash 用当前算法和参数重新生成 user 的密码哈希。
+// 只在保存的哈希未被修改时更新，避免覆盖并发设置的新密码；失败只记录日志，不影响登录
+func (s *UserService) upgradePasswordHash(ctx context.Context, user *models.User, password string) {
+	hashed, err := s.opts.PasswordHasher.Hash(password)
+	if err != nil {
+		log.Printf("重新生成用户 %d 的密码哈希失败: %v", user.ID, err)
+		return
+	}
+	result := database.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND password = ?", user.ID, user.Password).
+		Update("password", hashed)
+	if result.Error != nil {
+		log.Printf("保存用户 %d 的新密码哈希失败: %v", user.ID, result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("用户 %d 的密码哈希已升级为 %s", user.ID, s.opts.PasswordHasher.Name())
+		user.Password = hashed
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.jwtKey)
+// startSession 为通过认证的用户签发访问令牌，并开始一个新的刷新令牌家族
+func (s *UserService) startSession(ctx context.Context, user *models.User) (*pb.LoginResponse, error) {
+	// 生成短期访问令牌 (JWT)
+	tokenString, err := s.issueAccessToken(user)
 	if err != nil {
 		log.Printf("JWT令牌生成失败: %v", err)
 		return nil, status.Errorf(codes.Internal, "生成令牌失败")
 	}
 
-	log.Printf("用户登录成功: %s", username)
-	return &pb.LoginResponse{Token: tokenString}, nil
+	// 每次登录开始一个新的刷新令牌家族
+	familyID, err := randomToken(16)
+	if err != nil {
+		log.Printf("生成令牌家族 ID 失败: %v", err)
+		return nil, status.Errorf(codes.Internal, "生成令牌失败")
+	}
+	refreshToken, err := s.issueRefreshToken(database.DB.WithContext(ctx), user.ID, familyID, time.Now())
+	if err != nil {
+		log.Printf("创建刷新令牌失败 for user %s: %v", user.Username, err)
+		return nil, status.Errorf(codes.Internal, "生成令牌失败")
+	}
+
+	return &pb.LoginResponse{
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.opts.AccessTokenTTL / time.Second),
+		User:         toProfile(user),
+	}, nil
 }
 
 // ChangePassword 实现修改密码方法
@@ -164,20 +292,23 @@ func (s *UserService) ChangePassword(ctx context.Context, req *pb.ChangePassword
 	}
 
 	// 验证旧密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+	if ok, _, err := s.opts.PasswordHasher.Verify(user.Password, oldPassword); !ok {
 		log.Printf("旧密码验证失败 for user ID %d: %v", userID, err)
 		return nil, status.Errorf(codes.Unauthenticated, "旧密码不正确")
 	}
+	if err := s.checkPasswordPolicy("new_password", newPassword, user.Username, user.Email); err != nil {
+		return nil, err
+	}
 
 	// 加密新密码
-	hashedNewPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedNewPassword, err := s.opts.PasswordHasher.Hash(newPassword)
 	if err != nil {
 		log.Printf("新密码加密失败: %v", err)
 		return nil, status.Errorf(codes.Internal, "新密码加密失败")
 	}
 
 	// 更新密码
-	result := database.DB.Model(&user).Update("password", string(hashedNewPassword))
+	result := database.DB.Model(&user).Update("password", hashedNewPassword)
 	if result.Error != nil {
 		log.Printf("更新密码失败: %v", result.Error)
 		return nil, status.Errorf(codes.Internal, "更新密码失败")
@@ -188,5 +319,6 @@ func (s *UserService) ChangePassword(ctx context.Context, req *pb.ChangePassword
 	}
 
 	log.Printf("用户ID %d 密码修改成功", userID)
+	s.revokeAllSessions(ctx, user.ID)
 	return &pb.ChangePasswordResponse{}, nil
 }
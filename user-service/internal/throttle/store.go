@@ -0,0 +1,229 @@
+package throttle
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const keyPrefix = "login_failures:"
+
+// Entry 一个用户名或 IP 的登录失败记录
+type Entry struct {
+	// Failures 自上次成功、锁定或记录过期以来连续失败的次数，包括尚无结果的尝试预留的次数
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// Store 登录失败记录
+type Store interface {
+	// Get 返回 key 的记录，不存在时返回零值
+	Get(ctx context.Context, key string) (Entry, error)
+	// Reserve 在 key 的记录仍与 expect 相同时增加一次失败、将最后一次失败时间设为 at，
+	// 并返回更新后的记录，记录至少保留 ttl；记录已被修改时不做改动，返回 false 和当前的记录
+	Reserve(ctx context.Context, key string, expect Entry, at time.Time, ttl time.Duration) (Entry, bool, error)
+	// Release 归还一次预留的失败，失败次数不会小于 0
+	Release(ctx context.Context, key string) error
+	// Lock 锁定 key 到 until 并清零失败次数
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset 删除 key 的记录，同时解除锁定
+	Reset(ctx context.Context, key string) error
+}
+
+// RedisStore 基于 Redis 的失败记录，多个 user-service 实例共享。每个 key 保存为一个哈希
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore 创建基于 Redis 的失败记录
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (Entry, error) {
+	values, err := s.rdb.HMGet(ctx, keyPrefix+key, "failures", "last_failure", "locked_until").Result()
+	if err != nil {
+		return Entry{}, err
+	}
+	return parseEntry(values[0], values[1], values[2]), nil
+}
+
+// reserveScript 比较记录后原子地增加失败次数，只会延长记录的过期时间，不会缩短锁定期。
+// 返回 {是否预留, failures, last_failure, locked_until}
+var reserveScript = redis.NewScript(`
+local current = redis.call('HMGET', KEYS[1], 'failures', 'last_failure', 'locked_until')
+for i = 1, 3 do
+  if (tonumber(current[i]) or 0) ~= tonumber(ARGV[i]) then
+    return {0, current[1], current[2], current[3]}
+  end
+end
+redis.call('HINCRBY', KEYS[1], 'failures', 1)
+redis.call('HSET', KEYS[1], 'last_failure', ARGV[4])
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[5]) then
+  redis.call('PEXPIRE', KEYS[1], ARGV[5])
+end
+local updated = redis.call('HMGET', KEYS[1], 'failures', 'last_failure', 'locked_until')
+return {1, updated[1], updated[2], updated[3]}
+`)
+
+func (s *RedisStore) Reserve(ctx context.Context, key string, expect Entry, at time.Time, ttl time.Duration) (Entry, bool, error) {
+	result, err := reserveScript.Run(ctx, s.rdb, []string{keyPrefix + key},
+		expect.Failures, unixMilli(expect.LastFailure), unixMilli(expect.LockedUntil), at.UnixMilli(), ttl.Milliseconds()).Slice()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	return parseEntry(result[1], result[2], result[3]), parseInt(result[0]) == 1, nil
+}
+
+// releaseScript 减少一次失败。记录不存在 (已过期或已清除) 时不创建新记录
+var releaseScript = redis.NewScript(`
+local failures = tonumber(redis.call('HGET', KEYS[1], 'failures') or '0')
+if failures > 0 then
+  redis.call('HINCRBY', KEYS[1], 'failures', -1)
+end
+return 0
+`)
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return releaseScript.Run(ctx, s.rdb, []string{keyPrefix + key}).Err()
+}
+
+var lockScript = redis.NewScript(`
+redis.call('HSET', KEYS[1], 'locked_until', ARGV[1], 'failures', 0)
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[2]) then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+func (s *RedisStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return lockScript.Run(ctx, s.rdb, []string{keyPrefix + key}, until.UnixMilli(), ttl.Milliseconds()).Err()
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.rdb.Del(ctx, keyPrefix+key).Err()
+}
+
+// parseEntry 解析 HMGET 返回的字段，缺失或无法解析的字段视为零值
+func parseEntry(failures, lastFailure, lockedUntil interface{}) Entry {
+	var entry Entry
+	entry.Failures = int(parseInt(failures))
+	if ms := parseInt(lastFailure); ms > 0 {
+		entry.LastFailure = time.UnixMilli(ms)
+	}
+	if ms := parseInt(lockedUntil); ms > 0 {
+		entry.LockedUntil = time.UnixMilli(ms)
+	}
+	return entry
+}
+
+// unixMilli 返回 t 的毫秒时间戳，零值返回 0，与 parseEntry 的解析方式对应
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func parseInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case int64:
+		return v
+	}
+	return 0
+}
+
+// MemoryStore 进程内的失败记录，用于未配置 Redis 的单机开发环境
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	Entry
+	expiresAt time.Time
+}
+
+// NewMemoryStore 创建进程内的失败记录
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return Entry{}, nil
+	}
+	return entry.Entry, nil
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key string, expect Entry, at time.Time, ttl time.Duration) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	entry := s.entries[key]
+	if entry.Failures != expect.Failures || !entry.LastFailure.Equal(expect.LastFailure) || !entry.LockedUntil.Equal(expect.LockedUntil) {
+		return entry.Entry, false, nil
+	}
+	entry.Failures++
+	entry.LastFailure = at
+	if expiresAt := now.Add(ttl); expiresAt.After(entry.expiresAt) {
+		entry.expiresAt = expiresAt
+	}
+	s.entries[key] = entry
+	return entry.Entry, true, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if ok && entry.Failures > 0 {
+		entry.Failures--
+		s.entries[key] = entry
+	}
+	return nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.entries[key]
+	entry.Failures = 0
+	entry.LockedUntil = until
+	if until.After(entry.expiresAt) {
+		entry.expiresAt = until
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep 删除已过期的记录，调用方需持有 mu
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
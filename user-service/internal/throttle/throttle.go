@@ -0,0 +1,231 @@
+// Package throttle 限制登录时猜测密码的速度。
+//
+// 分别按用户名和客户端 IP 记录连续失败的次数：失败达到 BackoffAfter 次后，
+// 下一次尝试至少要等待 BaseDelay，此后每失败一次等待时间翻倍 (最长 MaxDelay)；
+// 失败达到 LockAfter 次后锁定 LockDuration，期间即使密码正确也拒绝登录。
+// 用户名的记录在登录成功后清除，IP 的记录只随时间过期，避免攻击者用自己的账号重置计数。
+//
+// 每次尝试在验证密码之前先原子地预留一次失败 (Reserve)，并发的猜测因此不能在任何一次失败记录之前
+// 全部通过检查；验证失败时保留预留的计数，成功时清除用户名的记录并归还 IP 的预留。
+package throttle
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Policy 一类 key (用户名或 IP) 的限制策略
+type Policy struct {
+	// BackoffAfter 连续失败多少次后开始退避，0 表示不退避
+	BackoffAfter int
+	// BaseDelay 第一次退避的等待时间，之后每失败一次翻倍，最长 MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockAfter 连续失败多少次后锁定，0 表示不锁定
+	LockAfter    int
+	LockDuration time.Duration
+	// Window 最后一次失败之后多久清除失败记录
+	Window time.Duration
+}
+
+// delay 返回连续失败 failures 次之后需要等待的时间
+func (p Policy) delay(failures int) time.Duration {
+	if p.BackoffAfter <= 0 || failures < p.BackoffAfter {
+		return 0
+	}
+	d := p.BaseDelay
+	for i := p.BackoffAfter; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Decision Reserve 的结果，RetryAfter 为 0 表示允许尝试
+type Decision struct {
+	RetryAfter time.Duration
+	// Locked 为 true 表示已被锁定，否则只是退避期内尝试过快
+	Locked bool
+}
+
+// Allowed 返回是否允许尝试
+func (d Decision) Allowed() bool {
+	return d.RetryAfter <= 0
+}
+
+const (
+	// pendingRetryAfter 失败次数已达到锁定阈值、最后一次尝试尚无结果时建议的等待时间
+	pendingRetryAfter = time.Second
+	// maxReserveRetries 记录被并发修改时重新预留的次数，超过后按尝试过快拒绝
+	maxReserveRetries = 10
+)
+
+// wait 返回 entry 在 now 时的限制状态
+func (p Policy) wait(entry Entry, now time.Time) Decision {
+	if now.Before(entry.LockedUntil) {
+		return Decision{RetryAfter: entry.LockedUntil.Sub(now), Locked: true}
+	}
+	if next := entry.LastFailure.Add(p.delay(entry.Failures)); now.Before(next) {
+		return Decision{RetryAfter: next.Sub(now)}
+	}
+	// 预留的次数已达到锁定阈值：最后一次尝试失败就会锁定，结果出来之前不再接受新的尝试
+	if p.LockAfter > 0 && entry.Failures >= p.LockAfter {
+		return Decision{RetryAfter: pendingRetryAfter}
+	}
+	return Decision{}
+}
+
+// Guard 按用户名和 IP 限制登录尝试，可并发使用
+type Guard struct {
+	store Store
+	user  Policy
+	ip    Policy
+}
+
+// New 创建登录限制，user 和 ip 分别为按用户名和按客户端 IP 的策略
+func New(store Store, user, ip Policy) *Guard {
+	return &Guard{store: store, user: user, ip: ip}
+}
+
+func userKey(username string) string {
+	// 用户名比较在 MySQL 中不区分大小写，统一小写避免换个大小写就绕过限制
+	return "user:" + strings.ToLower(username)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Attempt 一次已预留的登录尝试，必须以 Fail、Succeed 或 Release 之一结束
+type Attempt struct {
+	Username string
+	IP       string
+
+	// 预留后的失败次数，0 表示没有预留
+	userFailures int
+	ipFailures   int
+	done         bool
+}
+
+// Reserve 检查 username 是否允许从 ip 尝试登录，允许时为用户名和 IP 各预留一次失败。ip 为空时只限制用户名。
+// 用户名和 IP 都受限时返回等待时间较长的一个。返回的 Attempt 始终不为 nil，出错时其中只包含预留成功的部分
+func (g *Guard) Reserve(ctx context.Context, username, ip string) (*Attempt, Decision, error) {
+	attempt := &Attempt{Username: username, IP: ip}
+	now := time.Now()
+	entry, decision, err := g.reserve(ctx, g.user, userKey(username), now)
+	if err != nil || !decision.Allowed() {
+		return attempt, decision, err
+	}
+	attempt.userFailures = entry.Failures
+	if ip == "" {
+		return attempt, decision, nil
+	}
+	entry, decision, err = g.reserve(ctx, g.ip, ipKey(ip), now)
+	if err != nil {
+		return attempt, decision, err
+	}
+	if !decision.Allowed() {
+		// IP 受限时这次尝试不会发生，归还用户名的预留
+		attempt.done = true
+		return attempt, decision, g.store.Release(ctx, userKey(username))
+	}
+	attempt.ipFailures = entry.Failures
+	return attempt, decision, nil
+}
+
+// reserve 在记录未被并发修改的前提下检查并增加失败次数，返回预留后的记录
+func (g *Guard) reserve(ctx context.Context, policy Policy, key string, now time.Time) (Entry, Decision, error) {
+	entry, err := g.store.Get(ctx, key)
+	if err != nil {
+		return Entry{}, Decision{}, err
+	}
+	for i := 0; i < maxReserveRetries; i++ {
+		if d := policy.wait(entry, now); !d.Allowed() {
+			return entry, d, nil
+		}
+		reserved, ok, err := g.store.Reserve(ctx, key, entry, now, policy.Window)
+		if err != nil {
+			return Entry{}, Decision{}, err
+		}
+		if ok {
+			return reserved, Decision{}, nil
+		}
+		entry = reserved
+	}
+	return entry, Decision{RetryAfter: pendingRetryAfter}, nil
+}
+
+// Fail 记录这次尝试失败。预留的失败次数达到阈值时锁定用户名或 IP，
+// 返回的 lockedUntil 非零表示用户名在这次失败后被锁定，调用方据此发出安全事件
+func (g *Guard) Fail(ctx context.Context, a *Attempt) (lockedUntil time.Time, err error) {
+	if a.done {
+		return time.Time{}, nil
+	}
+	a.done = true
+	now := time.Now()
+	lockedUntil, err = g.lock(ctx, g.user, userKey(a.Username), a.userFailures, now)
+	if err != nil || a.ipFailures == 0 {
+		return lockedUntil, err
+	}
+	_, err = g.lock(ctx, g.ip, ipKey(a.IP), a.ipFailures, now)
+	return lockedUntil, err
+}
+
+func (g *Guard) lock(ctx context.Context, policy Policy, key string, failures int, now time.Time) (time.Time, error) {
+	if policy.LockAfter <= 0 || failures < policy.LockAfter {
+		return time.Time{}, nil
+	}
+	until := now.Add(policy.LockDuration)
+	if err := g.store.Lock(ctx, key, until); err != nil {
+		return time.Time{}, err
+	}
+	return until, nil
+}
+
+// Succeed 登录成功后清除用户名的失败记录，并归还 IP 的预留
+func (g *Guard) Succeed(ctx context.Context, a *Attempt) error {
+	if a.done {
+		return nil
+	}
+	a.done = true
+	if err := g.store.Reset(ctx, userKey(a.Username)); err != nil {
+		return err
+	}
+	if a.ipFailures == 0 {
+		return nil
+	}
+	return g.store.Release(ctx, ipKey(a.IP))
+}
+
+// Release 归还这次尝试的预留，用于既不算失败也不算成功的结果 (例如密码正确但还需要两步验证)。
+// 尝试已经结束时什么也不做，调用方可以在 Reserve 之后直接 defer
+func (g *Guard) Release(ctx context.Context, a *Attempt) error {
+	if a.done {
+		return nil
+	}
+	a.done = true
+	if a.userFailures > 0 {
+		if err := g.store.Release(ctx, userKey(a.Username)); err != nil {
+			return err
+		}
+	}
+	if a.ipFailures > 0 {
+		return g.store.Release(ctx, ipKey(a.IP))
+	}
+	return nil
+}
+
+// Unlock 清除用户名的失败记录并解除锁定，返回解除前是否处于锁定状态
+func (g *Guard) Unlock(ctx context.Context, username string) (bool, error) {
+	entry, err := g.store.Get(ctx, userKey(username))
+	if err != nil {
+		return false, err
+	}
+	if err := g.store.Reset(ctx, userKey(username)); err != nil {
+		return false, err
+	}
+	return time.Now().Before(entry.LockedUntil), nil
+}
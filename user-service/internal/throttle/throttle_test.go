@@ -0,0 +1,211 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	BackoffAfter: 3,
+	BaseDelay:    time.Second,
+	MaxDelay:     10 * time.Second,
+	LockAfter:    8,
+	LockDuration: 15 * time.Minute,
+	Window:       time.Hour,
+}
+
+func TestPolicyDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, 8 * time.Second},
+		{7, 10 * time.Second}, // 翻倍后超过 MaxDelay
+		{100, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := testPolicy.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %s, 期望 %s", tt.failures, got, tt.want)
+		}
+	}
+
+	disabled := testPolicy
+	disabled.BackoffAfter = 0
+	if got := disabled.delay(100); got != 0 {
+		t.Errorf("BackoffAfter 为 0 时 delay(100) = %s, 期望 0", got)
+	}
+}
+
+func TestPolicyWait(t *testing.T) {
+	now := time.Date(2026, time.March, 11, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		entry Entry
+		want  Decision
+	}{
+		{name: "没有记录", entry: Entry{}, want: Decision{}},
+		{name: "未达到退避次数", entry: Entry{Failures: 2, LastFailure: now}, want: Decision{}},
+		{name: "退避期内", entry: Entry{Failures: 4, LastFailure: now.Add(-500 * time.Millisecond)}, want: Decision{RetryAfter: 1500 * time.Millisecond}},
+		{name: "退避期已过", entry: Entry{Failures: 4, LastFailure: now.Add(-2 * time.Second)}, want: Decision{}},
+		{name: "锁定期内", entry: Entry{LockedUntil: now.Add(time.Minute)}, want: Decision{RetryAfter: time.Minute, Locked: true}},
+		{name: "锁定已过期", entry: Entry{LockedUntil: now.Add(-time.Second)}, want: Decision{}},
+		{
+			name:  "达到锁定次数但结果未出",
+			entry: Entry{Failures: 8, LastFailure: now.Add(-time.Hour)},
+			want:  Decision{RetryAfter: pendingRetryAfter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testPolicy.wait(tt.entry, now); got != tt.want {
+				t.Errorf("wait = %+v, 期望 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGuardLockout 逐次失败直到锁定：前 BackoffAfter 次不受限制，之后需要等待，第 LockAfter 次失败后锁定
+func TestGuardLockout(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	g := New(store, testPolicy, Policy{})
+
+	for i := 1; i <= testPolicy.LockAfter; i++ {
+		attempt, decision, err := g.Reserve(ctx, "Alice", "10.0.0.1")
+		if err != nil || !decision.Allowed() {
+			t.Fatalf("第 %d 次尝试: %+v, %v, 期望允许", i, decision, err)
+		}
+		lockedUntil, err := g.Fail(ctx, attempt)
+		if err != nil {
+			t.Fatalf("第 %d 次 Fail 失败: %v", i, err)
+		}
+		if locked := !lockedUntil.IsZero(); locked != (i == testPolicy.LockAfter) {
+			t.Fatalf("第 %d 次失败后锁定 = %t", i, locked)
+		}
+
+		// 立即重试：用户名不区分大小写；允许时归还这次探测的预留
+		probe, decision, _ := g.Reserve(ctx, "alice", "10.0.0.1")
+		g.Release(ctx, probe)
+		if wantWait := testPolicy.delay(i) > 0 || i == testPolicy.LockAfter; decision.Allowed() == wantWait {
+			t.Fatalf("第 %d 次失败后立即重试: %+v, 期望受限 = %t", i, decision, wantWait)
+		}
+		if decision.Locked != (i == testPolicy.LockAfter) {
+			t.Fatalf("第 %d 次失败后 Locked = %t", i, decision.Locked)
+		}
+		// 跳过退避期，模拟用户等待后重试
+		if entry, _ := store.Get(ctx, userKey("alice")); entry.Failures > 0 {
+			entry.LastFailure = entry.LastFailure.Add(-testPolicy.MaxDelay)
+			store.entries[userKey("alice")] = memoryEntry{Entry: entry, expiresAt: time.Now().Add(time.Hour)}
+		}
+	}
+
+	wasLocked, err := g.Unlock(ctx, "ALICE")
+	if err != nil || !wasLocked {
+		t.Fatalf("Unlock = %t, %v, 期望 true", wasLocked, err)
+	}
+	if _, decision, _ := g.Reserve(ctx, "alice", ""); !decision.Allowed() {
+		t.Errorf("解除锁定后仍受限: %+v", decision)
+	}
+}
+
+// TestGuardReserveConcurrent 并发的猜测在任何一次失败记录之前也只有 BackoffAfter 次能通过
+func TestGuardReserveConcurrent(t *testing.T) {
+	ctx := context.Background()
+	g := New(NewMemoryStore(), testPolicy, testPolicy)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		attempts []*Attempt
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt, decision, err := g.Reserve(ctx, "bob", "10.0.0.2")
+			if err != nil {
+				t.Errorf("Reserve 失败: %v", err)
+				return
+			}
+			if decision.Allowed() {
+				mu.Lock()
+				attempts = append(attempts, attempt)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(attempts) != testPolicy.BackoffAfter {
+		t.Fatalf("%d 次尝试通过, 期望 %d 次", len(attempts), testPolicy.BackoffAfter)
+	}
+	for _, attempt := range attempts {
+		g.Fail(ctx, attempt)
+	}
+	if entry, _ := g.store.Get(ctx, userKey("bob")); entry.Failures != testPolicy.BackoffAfter {
+		t.Errorf("失败次数 = %d, 期望 %d", entry.Failures, testPolicy.BackoffAfter)
+	}
+}
+
+func TestGuardSucceedAndRelease(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	g := New(store, testPolicy, testPolicy)
+	failures := func(key string) int {
+		entry, _ := store.Get(ctx, key)
+		return entry.Failures
+	}
+
+	// 一次失败之后成功：用户名的记录被清除，IP 只保留之前的失败
+	attempt, _, _ := g.Reserve(ctx, "carol", "10.0.0.3")
+	g.Fail(ctx, attempt)
+	attempt, _, _ = g.Reserve(ctx, "carol", "10.0.0.3")
+	if err := g.Succeed(ctx, attempt); err != nil {
+		t.Fatalf("Succeed 失败: %v", err)
+	}
+	if got := failures(userKey("carol")); got != 0 {
+		t.Errorf("成功后用户名的失败次数 = %d, 期望 0", got)
+	}
+	if got := failures(ipKey("10.0.0.3")); got != 1 {
+		t.Errorf("成功后 IP 的失败次数 = %d, 期望 1", got)
+	}
+
+	// 既不算失败也不算成功的尝试归还预留，已结束的尝试再次结束不产生影响
+	attempt, _, _ = g.Reserve(ctx, "carol", "10.0.0.3")
+	g.Release(ctx, attempt)
+	g.Release(ctx, attempt)
+	g.Fail(ctx, attempt)
+	if got := failures(userKey("carol")); got != 0 {
+		t.Errorf("归还后用户名的失败次数 = %d, 期望 0", got)
+	}
+	if got := failures(ipKey("10.0.0.3")); got != 1 {
+		t.Errorf("归还后 IP 的失败次数 = %d, 期望 1", got)
+	}
+}
+
+// TestGuardIPLimitReleasesUser IP 受限时这次尝试不会发生，不应占用用户名的次数
+func TestGuardIPLimitReleasesUser(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	ipPolicy := testPolicy
+	ipPolicy.LockAfter = 1
+	g := New(store, testPolicy, ipPolicy)
+
+	attempt, _, _ := g.Reserve(ctx, "dave", "10.0.0.4")
+	if lockedUntil, _ := g.Fail(ctx, attempt); !lockedUntil.IsZero() {
+		t.Fatalf("IP 被锁定时 Fail 返回了用户名的锁定时间")
+	}
+	_, decision, _ := g.Reserve(ctx, "erin", "10.0.0.4")
+	if !decision.Locked {
+		t.Fatalf("IP 锁定后 Reserve = %+v, 期望锁定", decision)
+	}
+	if entry, _ := store.Get(ctx, userKey("erin")); entry.Failures != 0 {
+		t.Errorf("IP 受限时用户名的失败次数 = %d, 期望 0", entry.Failures)
+	}
+}
@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors RFC 6238 附录 B 的 SHA1 测试向量，取 8 位验证码的后 6 位
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestGenerateRFC6238(t *testing.T) {
+	key, err := encoding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatalf("解码密钥失败: %v", err)
+	}
+	for _, v := range rfcVectors {
+		if got := generate(key, Step(time.Unix(v.unix, 0))); got != v.code {
+			t.Errorf("generate(T=%d) = %s, 期望 %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidateRFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := Validate(rfcSecret, v.code, at, 0)
+		if !ok || step != Step(at) {
+			t.Errorf("Validate(T=%d, %s) = (%d, %t), 期望 (%d, true)", v.unix, v.code, step, ok, Step(at))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// T=1111111109 属于时间步 37037036，验证码 081804
+	at := time.Unix(1111111109, 0)
+	step := Step(at)
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		t        time.Time
+		skew     int
+		wantOK   bool
+		wantStep int64
+	}{
+		{name: "当前时间步", secret: rfcSecret, code: "081804", t: at, skew: 1, wantOK: true, wantStep: step},
+		{name: "前后空白", secret: rfcSecret, code: " 081804\n", t: at, skew: 0, wantOK: true, wantStep: step},
+		{name: "小写密钥", secret: strings.ToLower(rfcSecret), code: "081804", t: at, skew: 0, wantOK: true, wantStep: step},
+		{name: "晚一个步长在 skew 内", secret: rfcSecret, code: "081804", t: at.Add(Period), skew: 1, wantOK: true, wantStep: step},
+		{name: "早一个步长在 skew 内", secret: rfcSecret, code: "081804", t: at.Add(-Period), skew: 1, wantOK: true, wantStep: step},
+		{name: "晚两个步长超出 skew", secret: rfcSecret, code: "081804", t: at.Add(2 * Period), skew: 1},
+		{name: "skew 为 0 时不接受相邻步长", secret: rfcSecret, code: "081804", t: at.Add(Period), skew: 0},
+		{name: "错误的验证码", secret: rfcSecret, code: "081805", t: at, skew: 1},
+		{name: "位数不对", secret: rfcSecret, code: "81804", t: at, skew: 1},
+		{name: "无效的密钥", secret: "not base32!", code: "081804", t: at, skew: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := Validate(tt.secret, tt.code, tt.t, tt.skew)
+			if ok != tt.wantOK || (ok && gotStep != tt.wantStep) {
+				t.Errorf("Validate = (%d, %t), 期望 (%d, %t)", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// 同一个验证码在 skew 范围内的不同时间验证时返回相同的时间步，
+// 调用方只接受大于上次使用的时间步 (last_used_step) 的验证码，据此拒绝重放
+func TestValidateReplayReturnsSameStep(t *testing.T) {
+	at := time.Unix(1234567890, 0)
+	first, ok := Validate(rfcSecret, "005924", at, 1)
+	if !ok {
+		t.Fatalf("第一次验证失败")
+	}
+	for _, later := range []time.Time{at.Add(time.Second), at.Add(Period)} {
+		step, ok := Validate(rfcSecret, "005924", later, 1)
+		if !ok {
+			t.Fatalf("在 %s 重放时验证失败，期望匹配后由 last_used_step 拒绝", later.Sub(at))
+		}
+		if step != first {
+			t.Errorf("在 %s 重放时返回时间步 %d, 期望第一次的 %d，否则 last_used_step 无法拒绝重放", later.Sub(at), step, first)
+		}
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret 失败: %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != secretSize {
+		t.Fatalf("密钥 %s 解码为 %d 字节 (%v), 期望 %d 字节", secret, len(key), err, secretSize)
+	}
+	now := time.Now()
+	if _, ok := Validate(secret, generate(key, Step(now)), now, 0); !ok {
+		t.Errorf("新密钥生成的验证码未通过验证")
+	}
+}
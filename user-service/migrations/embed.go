@@ -0,0 +1,12 @@
+// Package migrations 包含 user-service 的数据库迁移文件，按方言分目录存放，由 internal/migrate 执行。
+//
+// 新增迁移时需要为 mysql 和 postgres 各写一组 up/down 文件，版本号递增，不要修改已发布的迁移。
+// 0001_init 使用 IF NOT EXISTS 创建表和索引，因此也适用于之前由 GORM AutoMigrate 创建的数据库。
+package migrations
+
+import "embed"
+
+// FS 内嵌的迁移文件
+//
+//go:embed mysql postgres
+var FS embed.FS
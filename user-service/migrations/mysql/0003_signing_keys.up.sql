@@ -0,0 +1,11 @@
+-- 签发访问令牌的非对称密钥 (JWT_SIGNING_ALG 为 RS256 或 EdDSA 时使用)
+
+CREATE TABLE `signing_keys` (
+  `id` varchar(64) NOT NULL,
+  `algorithm` varchar(16) NOT NULL,
+  `private_key` text NOT NULL,
+  `created_at` datetime(3) NOT NULL,
+  `retired_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_signing_keys_retired_at` (`retired_at`)
+);
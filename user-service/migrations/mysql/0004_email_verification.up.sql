@@ -0,0 +1,18 @@
+-- 邮箱验证：users.email_verified 标记以及一次性的验证令牌 (只保存 SHA-256 摘要)
+
+ALTER TABLE `users` ADD COLUMN `email_verified` boolean NOT NULL DEFAULT false;
+
+-- 已有账号注册时还没有邮箱验证，视为已验证，避免启用 required 策略后无法登录
+UPDATE `users` SET `email_verified` = true;
+
+CREATE TABLE `email_verification_tokens` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `used_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_email_verification_tokens_token_hash` (`token_hash`),
+  INDEX `idx_email_verification_tokens_user_id` (`user_id`)
+);
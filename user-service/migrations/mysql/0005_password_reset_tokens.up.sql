@@ -0,0 +1,13 @@
+-- 重置密码令牌，只保存令牌的 SHA-256 摘要
+
+CREATE TABLE `password_reset_tokens` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `used_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_password_reset_tokens_token_hash` (`token_hash`),
+  INDEX `idx_password_reset_tokens_user_id` (`user_id`)
+);
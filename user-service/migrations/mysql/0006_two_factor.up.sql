@@ -0,0 +1,35 @@
+-- 两步验证 (TOTP)：用户的密钥、恢复码 (只保存 SHA-256 摘要) 以及等待第二步验证的登录
+
+CREATE TABLE `user_totps` (
+  `user_id` bigint unsigned NOT NULL,
+  `secret` varchar(64) NOT NULL,
+  `confirmed_at` datetime(3) NULL,
+  `last_used_step` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`)
+);
+
+CREATE TABLE `recovery_codes` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `code_hash` varchar(64) NOT NULL,
+  `used_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_recovery_codes_code_hash` (`code_hash`),
+  INDEX `idx_recovery_codes_user_id` (`user_id`)
+);
+
+CREATE TABLE `two_factor_challenges` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `used_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_two_factor_challenges_token_hash` (`token_hash`),
+  INDEX `idx_two_factor_challenges_user_id` (`user_id`)
+);
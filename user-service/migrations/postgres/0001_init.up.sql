@@ -0,0 +1,12 @@
+-- 初始表结构，与之前 GORM AutoMigrate 创建的表一致
+
+CREATE TABLE IF NOT EXISTS "users" (
+  "id" bigserial,
+  "username" varchar(255) NOT NULL,
+  "email" varchar(255) NOT NULL,
+  "password" varchar(255) NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
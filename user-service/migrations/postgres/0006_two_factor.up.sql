@@ -0,0 +1,35 @@
+-- 两步验证 (TOTP)：用户的密钥、恢复码 (只保存 SHA-256 摘要) 以及等待第二步验证的登录
+
+CREATE TABLE "user_totps" (
+  "user_id" bigint NOT NULL,
+  "secret" varchar(64) NOT NULL,
+  "confirmed_at" timestamptz,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("user_id")
+);
+
+CREATE TABLE "recovery_codes" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar(64) NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_recovery_codes_code_hash" ON "recovery_codes" ("code_hash");
+CREATE INDEX "idx_recovery_codes_user_id" ON "recovery_codes" ("user_id");
+
+CREATE TABLE "two_factor_challenges" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "token_hash" varchar(64) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "used_at" timestamptz,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_two_factor_challenges_token_hash" ON "two_factor_challenges" ("token_hash");
+CREATE INDEX "idx_two_factor_challenges_user_id" ON "two_factor_challenges" ("user_id");
@@ -0,0 +1,6 @@
+-- 用户资料：显示名称、头像地址、时区和语言，为空表示未设置
+
+ALTER TABLE "users" ADD COLUMN "display_name" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "avatar_url" varchar(512) NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "time_zone" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "locale" varchar(35) NOT NULL DEFAULT '';